@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/golang-migrate/migrate/v4"
@@ -19,8 +20,14 @@ import (
 	"github.com/shalfey088/team-task-nexus/internal/adapter/cache/redis"
 	apphttp "github.com/shalfey088/team-task-nexus/internal/adapter/http"
 	"github.com/shalfey088/team-task-nexus/internal/adapter/http/handler"
+	localmail "github.com/shalfey088/team-task-nexus/internal/adapter/mail/local"
 	"github.com/shalfey088/team-task-nexus/internal/adapter/repository/mysql"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/storage/local"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/storage/s3"
 	"github.com/shalfey088/team-task-nexus/internal/config"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/jwtkeys"
+	"github.com/shalfey088/team-task-nexus/internal/port"
 	"github.com/shalfey088/team-task-nexus/internal/service"
 )
 
@@ -29,6 +36,11 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
+	// jwt.secret signs invitation, reset and verification tokens, so a
+	// missing or sample value must not reach a deployment.
+	if cfg.JWT.Secret == "" || cfg.JWT.Secret == sampleJWTSecret {
+		log.Fatal("jwt.secret is not set; configure it or APP_JWT_SECRET")
+	}
 
 	db, err := sqlx.Connect("mysql", cfg.Database.DSN)
 	if err != nil {
@@ -59,35 +71,149 @@ func main() {
 	taskRepo := mysql.NewTaskRepo(db)
 	historyRepo := mysql.NewTaskHistoryRepo(db)
 	commentRepo := mysql.NewCommentRepo(db)
+	activityRepo := mysql.NewActivityRepo(db)
+	mentionRepo := mysql.NewMentionRepo(db)
+	reactionRepo := mysql.NewReactionRepo(db)
+	attachmentRepo := mysql.NewAttachmentRepo(db)
+	transferRepo := mysql.NewOwnershipTransferRepo(db)
+	invitationRepo := mysql.NewInvitationRepo(db)
+	joinLinkRepo := mysql.NewJoinLinkRepo(db)
+	orgRepo := mysql.NewOrganizationRepo(db)
+	orgInvitationRepo := mysql.NewOrgInvitationRepo(db)
+	roleRepo := mysql.NewTeamRoleRepo(db)
+	auditRepo := mysql.NewAdminAuditRepo(db)
+	refreshRepo := mysql.NewRefreshTokenRepo(db)
+	personalTokenRepo := mysql.NewPersonalTokenRepo(db)
+	passwordResetRepo := mysql.NewPasswordResetRepo(db)
+	emailVerificationRepo := mysql.NewEmailVerificationRepo(db)
+	recoveryCodeRepo := mysql.NewRecoveryCodeRepo(db)
 	txManager := mysql.NewTransactionManager(db)
 
 	// Cache & rate limiter
 	taskCache := redis.NewTaskCache(rdb)
 	rateLimiter := redis.NewRateLimiter(rdb, cfg.RateLimit.RequestsPerMinute)
+	loginLimiter := redis.NewLoginRateLimiter(rdb, cfg.RateLimit.LoginAttemptsPerMinute)
+	addressLimiter := redis.NewAttemptLimiter(rdb, "auth", cfg.RateLimit.AuthRequestsPerMinute, time.Minute)
+	emailLimiter := redis.NewAttemptLimiter(rdb, "email", cfg.RateLimit.EmailRequestsPerHour, time.Hour)
+	denylist := redis.NewTokenDenylist(rdb)
+
+	// Blob storage
+	var blobStore port.BlobStore
+	switch cfg.Attachments.Storage {
+	case "s3":
+		s3Store, err := s3.NewStore(s3.Config{
+			Endpoint:  cfg.Attachments.S3.Endpoint,
+			Region:    cfg.Attachments.S3.Region,
+			Bucket:    cfg.Attachments.S3.Bucket,
+			AccessKey: cfg.Attachments.S3.AccessKey,
+			SecretKey: cfg.Attachments.S3.SecretKey,
+		})
+		if err != nil {
+			log.Fatalf("failed to configure attachment storage: %v", err)
+		}
+		if err := s3Store.EnsureBucket(context.Background()); err != nil {
+			log.Fatalf("failed to prepare attachment bucket: %v", err)
+		}
+		blobStore = s3Store
+	default:
+		localStore, err := local.NewStore(cfg.Attachments.LocalDir)
+		if err != nil {
+			log.Fatalf("failed to prepare attachment directory: %v", err)
+		}
+		blobStore = localStore
+	}
+
+	mailer, err := localmail.NewMailer(cfg.Mail.From, cfg.Mail.Dir)
+	if err != nil {
+		log.Fatalf("failed to prepare mail directory: %v", err)
+	}
+
+	keyRing := loadKeyRing(cfg.JWT)
 
 	// Services
 	notifSvc := service.NewNotificationService()
-	authSvc := service.NewAuthService(userRepo, cfg.JWT.Secret, cfg.JWT.Expiration)
-	teamSvc := service.NewTeamService(teamRepo, userRepo, txManager, notifSvc)
-	taskSvc := service.NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, taskCache, txManager, notifSvc)
-	commentSvc := service.NewCommentService(commentRepo, taskRepo, teamRepo, notifSvc)
+	authz := service.NewAuthorizer(teamRepo)
+	inviteSecret := cfg.Invitations.Secret
+	if inviteSecret == "" {
+		inviteSecret = cfg.JWT.Secret
+	}
+	verificationPolicy := domain.EmailVerificationPolicy{
+		BlockLogin:       cfg.EmailVerification.BlockLogin,
+		BlockInvitations: cfg.EmailVerification.BlockInvitations,
+	}
+	invitationSvc := service.NewInvitationService(teamRepo, authz, userRepo, invitationRepo, activityRepo, txManager, mailer, inviteSecret, cfg.Invitations.TTL, cfg.Invitations.AcceptURL, verificationPolicy)
+	verificationSvc := service.NewEmailVerificationService(userRepo, emailVerificationRepo, txManager, mailer, cfg.JWT.Secret, cfg.EmailVerification.TTL, cfg.EmailVerification.VerifyURL)
+	twoFactorSvc := service.NewTwoFactorService(userRepo, recoveryCodeRepo, txManager, cfg.TwoFactor.Issuer)
+	joinLinkSvc := service.NewJoinLinkService(teamRepo, authz, userRepo, joinLinkRepo, activityRepo, txManager)
+	authSvc := service.NewAuthService(userRepo, refreshRepo, txManager, invitationSvc, verificationSvc, twoFactorSvc, denylist, keyRing, cfg.JWT.Secret, cfg.JWT.Expiration, cfg.JWT.RefreshExpiration, verificationPolicy)
+	ownershipSvc := service.NewOwnershipService(teamRepo, authz, userRepo, transferRepo, activityRepo, txManager, notifSvc)
+	mentionSvc := service.NewMentionService(mentionRepo, teamRepo, authz, notifSvc)
+	attachmentSvc := service.NewAttachmentService(attachmentRepo, taskRepo, authz, commentRepo, blobStore, cfg.Attachments.MaxSize, cfg.Attachments.AllowedTypes)
+	teamSvc := service.NewTeamService(teamRepo, orgRepo, authz, userRepo, activityRepo, txManager, notifSvc, taskCache, attachmentSvc, cfg.JWT.Secret)
+	taskSvc := service.NewTaskService(taskRepo, authz, userRepo, historyRepo, taskCache, txManager, notifSvc, mentionSvc, attachmentSvc)
+	commentSvc := service.NewCommentService(commentRepo, taskRepo, authz, userRepo, reactionRepo, txManager, notifSvc, mentionSvc, attachmentSvc)
+	orgSvc := service.NewOrganizationService(orgRepo, userRepo, orgInvitationRepo, txManager, mailer, inviteSecret, cfg.Invitations.TTL, cfg.Invitations.OrgAcceptURL, verificationPolicy)
+	permissionSvc := service.NewPermissionService(authz, teamRepo, roleRepo, taskRepo, commentRepo, activityRepo, txManager)
+	activitySvc := service.NewActivityService(activityRepo, authz)
+	markdownSvc := service.NewMarkdownService(taskRepo, authz, cfg.Markdown.TaskURLFormat, cfg.Markdown.ExcerptLength)
+	reactionSvc := service.NewReactionService(reactionRepo, taskRepo, authz, commentRepo, txManager)
+	personalTokenSvc := service.NewPersonalTokenService(personalTokenRepo, authz)
+	passwordSvc := service.NewPasswordService(userRepo, passwordResetRepo, txManager, authSvc, mailer, cfg.JWT.Secret, cfg.Password.ResetTTL, cfg.Password.ResetURL)
+	adminSvc := service.NewAdminService(userRepo, teamRepo, taskRepo, historyRepo, auditRepo, authSvc, taskCache, txManager)
 
 	// Handlers
 	authHandler := handler.NewAuthHandler(authSvc)
 	teamHandler := handler.NewTeamHandler(teamSvc)
-	taskHandler := handler.NewTaskHandler(taskSvc)
-	commentHandler := handler.NewCommentHandler(commentSvc)
+	ownershipHandler := handler.NewOwnershipHandler(ownershipSvc)
+	invitationHandler := handler.NewInvitationHandler(invitationSvc)
+	joinLinkHandler := handler.NewJoinLinkHandler(joinLinkSvc)
+	orgHandler := handler.NewOrganizationHandler(orgSvc)
+	permissionHandler := handler.NewPermissionHandler(permissionSvc)
+	taskHandler := handler.NewTaskHandler(taskSvc, markdownSvc)
+	commentHandler := handler.NewCommentHandler(commentSvc, markdownSvc)
+	activityHandler := handler.NewActivityHandler(activitySvc)
+	mentionHandler := handler.NewMentionHandler(mentionSvc)
+	reactionHandler := handler.NewReactionHandler(reactionSvc)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentSvc, cfg.Attachments.MaxSize)
+	adminHandler := handler.NewAdminHandler(adminSvc)
+	tokenHandler := handler.NewPersonalTokenHandler(personalTokenSvc)
+	passwordHandler := handler.NewPasswordHandler(passwordSvc)
+	verificationHandler := handler.NewEmailVerificationHandler(verificationSvc)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorSvc)
 	healthHandler := handler.NewHealthHandler()
+	keysHandler := handler.NewKeysHandler(keyRing)
 
 	// Router
 	router := apphttp.NewRouter(apphttp.RouterDeps{
-		AuthHandler:    authHandler,
-		TeamHandler:    teamHandler,
-		TaskHandler:    taskHandler,
-		CommentHandler: commentHandler,
-		HealthHandler:  healthHandler,
-		JWTSecret:      cfg.JWT.Secret,
-		RateLimiter:    rateLimiter,
+		AuthHandler:       authHandler,
+		TeamHandler:       teamHandler,
+		TaskHandler:       taskHandler,
+		CommentHandler:    commentHandler,
+		ActivityHandler:   activityHandler,
+		MentionHandler:    mentionHandler,
+		ReactionHandler:   reactionHandler,
+		AttachmentHandler: attachmentHandler,
+		OwnershipHandler:  ownershipHandler,
+		InvitationHandler: invitationHandler,
+		JoinLinkHandler:   joinLinkHandler,
+		OrgHandler:        orgHandler,
+		PermissionHandler: permissionHandler,
+		AdminHandler:      adminHandler,
+		TokenHandler:      tokenHandler,
+		PasswordHandler:   passwordHandler,
+		VerifyHandler:     verificationHandler,
+		TwoFactorHandler:  twoFactorHandler,
+		HealthHandler:     healthHandler,
+		KeysHandler:       keysHandler,
+		Keys:              keyRing,
+		Accounts:          authSvc,
+		Impersonation:     adminSvc,
+		Denylist:          denylist,
+		PersonalTokens:    personalTokenSvc,
+		RateLimiter:       rateLimiter,
+		LoginRateLimiter:  loginLimiter,
+		AddressLimiter:    addressLimiter,
+		EmailLimiter:      emailLimiter,
 	})
 
 	srv := &http.Server{
@@ -138,3 +264,50 @@ func runMigrations(db *sqlx.DB) {
 
 	log.Println("migrations applied successfully")
 }
+
+// sampleJWTSecret is the placeholder older config files shipped with.
+const sampleJWTSecret = "change-me-in-production"
+
+// loadKeyRing reads jwt.keys once at startup. Scheduled rotations (a key
+// with a future not_before) switch over without a restart, but adding or
+// removing keys takes effect only after the server restarts.
+func loadKeyRing(cfg config.JWTConfig) *jwtkeys.KeyRing {
+	var keys []jwtkeys.Key
+	for _, kc := range cfg.Keys {
+		var notBefore time.Time
+		if kc.NotBefore != "" {
+			t, err := time.Parse(time.RFC3339, kc.NotBefore)
+			if err != nil {
+				log.Fatalf("invalid not_before for jwt key %q: %v", kc.KID, err)
+			}
+			notBefore = t
+		}
+		key, err := jwtkeys.LoadFile(kc.KID, kc.File, notBefore)
+		if err != nil {
+			log.Fatalf("failed to load jwt key: %v", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		if !cfg.AllowEphemeralKey {
+			log.Fatal("no jwt.keys configured; add a signing key or set jwt.allow_ephemeral_key for development")
+		}
+		key, err := jwtkeys.Generate("ephemeral")
+		if err != nil {
+			log.Fatalf("failed to generate jwt key: %v", err)
+		}
+		log.Println("no jwt.keys configured, signing with an ephemeral key; tokens will not survive a restart")
+		keys = append(keys, key)
+	}
+
+	ring, err := jwtkeys.NewKeyRing(keys, jwtkeys.Options{
+		Issuer:   cfg.Issuer,
+		Audience: cfg.Audience,
+		Grace:    cfg.RotationGrace,
+	})
+	if err != nil {
+		log.Fatalf("failed to build jwt key ring: %v", err)
+	}
+	return ring
+}
@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AttemptLimiter counts attempts per key in a sliding window. It guards
+// endpoints where the caller is only known by address or by the email they
+// submit.
+type AttemptLimiter struct {
+	client *redis.Client
+	scope  string
+	limit  int
+	window time.Duration
+}
+
+func NewAttemptLimiter(client *redis.Client, scope string, limit int, window time.Duration) *AttemptLimiter {
+	return &AttemptLimiter{
+		client: client,
+		scope:  scope,
+		limit:  limit,
+		window: window,
+	}
+}
+
+func (l *AttemptLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	redisKey := "rate_limit:" + l.scope + ":" + key
+	now := time.Now()
+	windowStart := now.Add(-l.window).UnixNano()
+
+	pipe := l.client.Pipeline()
+	pipe.ZRemRangeByScore(ctx, redisKey, "0", fmt.Sprintf("%d", windowStart))
+	countCmd := pipe.ZCard(ctx, redisKey)
+	// Nanosecond members, so attempts within one second all count.
+	pipe.ZAdd(ctx, redisKey, redis.Z{Score: float64(now.UnixNano()), Member: now.UnixNano()})
+	pipe.Expire(ctx, redisKey, 2*l.window)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return countCmd.Val() < int64(l.limit), nil
+}
+
+// LoginRateLimiter counts sign-in attempts per client address in a sliding
+// one-minute window.
+type LoginRateLimiter struct {
+	attempts *AttemptLimiter
+}
+
+func NewLoginRateLimiter(client *redis.Client, attemptsPerMinute int) *LoginRateLimiter {
+	return &LoginRateLimiter{attempts: NewAttemptLimiter(client, "login", attemptsPerMinute, time.Minute)}
+}
+
+func (r *LoginRateLimiter) AllowLogin(ctx context.Context, ip string) (bool, error) {
+	return r.attempts.Allow(ctx, ip)
+}
@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenDenylist keeps revoked token and refresh family ids until the access
+// tokens carrying them would have expired anyway.
+type TokenDenylist struct {
+	client *redis.Client
+}
+
+func NewTokenDenylist(client *redis.Client) *TokenDenylist {
+	return &TokenDenylist{client: client}
+}
+
+func (d *TokenDenylist) key(id string) string {
+	return "revoked_token:" + id
+}
+
+func (d *TokenDenylist) Revoke(ctx context.Context, id string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return d.client.Set(ctx, d.key(id), 1, ttl).Err()
+}
+
+func (d *TokenDenylist) Claim(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	return d.client.SetNX(ctx, d.key(id), 1, ttl).Result()
+}
+
+func (d *TokenDenylist) IsRevoked(ctx context.Context, ids ...string) (bool, error) {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != "" {
+			keys = append(keys, d.key(id))
+		}
+	}
+	if len(keys) == 0 {
+		return false, nil
+	}
+	n, err := d.client.Exists(ctx, keys...).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type ActivityHandler struct {
+	activitySvc port.ActivityService
+}
+
+func NewActivityHandler(activitySvc port.ActivityService) *ActivityHandler {
+	return &ActivityHandler{activitySvc: activitySvc}
+}
+
+func (h *ActivityHandler) TeamActivity(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	query := domain.ActivityQuery{
+		Types:  r.URL.Query().Get("types"),
+		Cursor: r.URL.Query().Get("cursor"),
+	}
+	if v := r.URL.Query().Get("actor_id"); v != "" {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+			query.ActorID = id
+		}
+	}
+	if v := r.URL.Query().Get("task_id"); v != "" {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+			query.TaskID = id
+		}
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if l, err := strconv.Atoi(v); err == nil {
+			query.Limit = l
+		}
+	}
+
+	feed, err := h.activitySvc.GetTeamActivity(r.Context(), userID, teamID, query)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, feed)
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type AdminHandler struct {
+	adminSvc port.AdminService
+}
+
+func NewAdminHandler(adminSvc port.AdminService) *AdminHandler {
+	return &AdminHandler{adminSvc: adminSvc}
+}
+
+func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserID(r.Context())
+
+	page, pageSize := parsePage(r)
+	filter := domain.UserSearchFilter{Query: r.URL.Query().Get("q"), Page: page, PageSize: pageSize}
+
+	users, err := h.adminSvc.ListUsers(r.Context(), adminID, filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, users)
+}
+
+func (h *AdminHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, true)
+}
+
+func (h *AdminHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, false)
+}
+
+func (h *AdminHandler) setUserDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	adminID := middleware.GetUserID(r.Context())
+	userID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid user id"))
+		return
+	}
+
+	user, err := h.adminSvc.SetUserDisabled(r.Context(), adminID, userID, disabled)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, user)
+}
+
+func (h *AdminHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserID(r.Context())
+	userID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid user id"))
+		return
+	}
+
+	resp, err := h.adminSvc.Impersonate(r.Context(), adminID, userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, resp)
+}
+
+func (h *AdminHandler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserID(r.Context())
+
+	page, pageSize := parsePage(r)
+	teams, err := h.adminSvc.ListTeams(r.Context(), adminID, domain.AdminListFilter{Page: page, PageSize: pageSize})
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, teams)
+}
+
+func (h *AdminHandler) ListOrphanedAssignees(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserID(r.Context())
+
+	result, err := h.adminSvc.ListOrphanedAssignees(r.Context(), adminID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
+
+func (h *AdminHandler) RepairOrphanedAssignees(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserID(r.Context())
+
+	result, err := h.adminSvc.RepairOrphanedAssignees(r.Context(), adminID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
+
+func (h *AdminHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	adminID := middleware.GetUserID(r.Context())
+
+	page, pageSize := parsePage(r)
+	entries, err := h.adminSvc.ListAudit(r.Context(), adminID, domain.AdminListFilter{Page: page, PageSize: pageSize})
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, entries)
+}
+
+// parsePage reads page and page_size, leaving zero for missing or malformed
+// values so the service applies its defaults.
+func parsePage(r *http.Request) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	return page, pageSize
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+// multipartMemory is how much of an upload is buffered in memory before
+// spilling to a temp file.
+const multipartMemory = 8 << 20
+
+type AttachmentHandler struct {
+	attachmentSvc port.AttachmentService
+	maxSize       int64
+}
+
+func NewAttachmentHandler(attachmentSvc port.AttachmentService, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{attachmentSvc: attachmentSvc, maxSize: maxSize}
+}
+
+func (h *AttachmentHandler) UploadToTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid task id"))
+		return
+	}
+	h.upload(w, r, taskID, nil)
+}
+
+func (h *AttachmentHandler) UploadToComment(w http.ResponseWriter, r *http.Request) {
+	taskID, commentID, ok := commentPathIDs(w, r)
+	if !ok {
+		return
+	}
+	h.upload(w, r, taskID, &commentID)
+}
+
+func (h *AttachmentHandler) upload(w http.ResponseWriter, r *http.Request, taskID int64, commentID *int64) {
+	userID := middleware.GetUserID(r.Context())
+
+	// Leave headroom for multipart boundaries and other form fields.
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSize+1<<20)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.Error(w, apperror.New(http.StatusRequestEntityTooLarge, "file is too large"))
+			return
+		}
+		response.Error(w, apperror.BadRequest("invalid multipart form"))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.Error(w, apperror.BadRequest("file is required"))
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentSvc.Upload(r.Context(), userID, taskID, commentID, domain.AttachmentUpload{
+		FileName: header.Filename,
+		Size:     header.Size,
+		Content:  file,
+	})
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, attachment)
+}
+
+func (h *AttachmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid task id"))
+		return
+	}
+
+	attachments, err := h.attachmentSvc.List(r.Context(), userID, taskID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, attachments)
+}
+
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	taskID, attachmentID, ok := attachmentPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	attachment, body, err := h.attachmentSvc.Open(r.Context(), userID, taskID, attachmentID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": attachment.FileName,
+	}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, body); err != nil {
+		log.Printf("failed to stream attachment %d: %v", attachment.ID, err)
+	}
+}
+
+func (h *AttachmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	taskID, attachmentID, ok := attachmentPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.attachmentSvc.Delete(r.Context(), userID, taskID, attachmentID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "attachment deleted"})
+}
+
+func attachmentPathIDs(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid task id"))
+		return 0, 0, false
+	}
+	attachmentID, err := strconv.ParseInt(chi.URLParam(r, "attachmentID"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid attachment id"))
+		return 0, 0, false
+	}
+	return taskID, attachmentID, true
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
 	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
@@ -49,3 +50,57 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	response.JSON(w, http.StatusOK, result)
 }
+
+func (h *AuthHandler) CompleteLogin(w http.ResponseWriter, r *http.Request) {
+	var req domain.LoginChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	result, err := h.authSvc.CompleteLogin(r.Context(), req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
+
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req domain.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	result, err := h.authSvc.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
+
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	if err := h.authSvc.Logout(r.Context(), *claims); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "logged out"})
+}
+
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	if err := h.authSvc.LogoutEverywhere(r.Context(), *claims); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "logged out of all sessions"})
+}
@@ -14,11 +14,12 @@ import (
 )
 
 type CommentHandler struct {
-	commentSvc port.CommentService
+	commentSvc  port.CommentService
+	markdownSvc port.MarkdownService
 }
 
-func NewCommentHandler(commentSvc port.CommentService) *CommentHandler {
-	return &CommentHandler{commentSvc: commentSvc}
+func NewCommentHandler(commentSvc port.CommentService, markdownSvc port.MarkdownService) *CommentHandler {
+	return &CommentHandler{commentSvc: commentSvc, markdownSvc: markdownSvc}
 }
 
 func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -40,6 +41,10 @@ func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, err)
 		return
 	}
+	if err := h.markdownSvc.RenderComments(r.Context(), userID, []*domain.TaskComment{comment}, wantsHTML(r)); err != nil {
+		response.Error(w, err)
+		return
+	}
 
 	response.JSON(w, http.StatusCreated, comment)
 }
@@ -52,11 +57,162 @@ func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Pagination is opt-in so existing clients keep receiving a plain array.
+	q := r.URL.Query()
+	if q.Has("limit") || q.Has("cursor") || q.Has("since") {
+		h.listPage(w, r, userID, taskID)
+		return
+	}
+
 	comments, err := h.commentSvc.ListByTaskID(r.Context(), userID, taskID)
 	if err != nil {
 		response.Error(w, err)
 		return
 	}
+	if err := h.markdownSvc.RenderComments(r.Context(), userID, commentPointers(comments), wantsHTML(r)); err != nil {
+		response.Error(w, err)
+		return
+	}
 
 	response.JSON(w, http.StatusOK, comments)
 }
+
+func (h *CommentHandler) listPage(w http.ResponseWriter, r *http.Request, userID, taskID int64) {
+	query := domain.CommentQuery{
+		Cursor: r.URL.Query().Get("cursor"),
+		Since:  r.URL.Query().Get("since"),
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if l, err := strconv.Atoi(v); err == nil {
+			query.Limit = l
+		}
+	}
+
+	page, err := h.commentSvc.ListPage(r.Context(), userID, taskID, query)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := h.markdownSvc.RenderComments(r.Context(), userID, commentPointers(page.Comments), wantsHTML(r)); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, page)
+}
+
+func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	taskID, commentID, ok := commentPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	comment, err := h.commentSvc.Update(r.Context(), userID, taskID, commentID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := h.markdownSvc.RenderComments(r.Context(), userID, []*domain.TaskComment{comment}, wantsHTML(r)); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, comment)
+}
+
+func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	taskID, commentID, ok := commentPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.commentSvc.Delete(r.Context(), userID, taskID, commentID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "comment deleted"})
+}
+
+func (h *CommentHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	taskID, commentID, ok := commentPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.commentSvc.ListRevisions(r.Context(), userID, taskID, commentID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, revisions)
+}
+
+func (h *CommentHandler) ListThreads(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid task id"))
+		return
+	}
+
+	threads, err := h.commentSvc.ListThreads(r.Context(), userID, taskID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := h.markdownSvc.RenderComments(r.Context(), userID, threadPointers(threads), wantsHTML(r)); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, threads)
+}
+
+func (h *CommentHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	h.setResolved(w, r, true)
+}
+
+func (h *CommentHandler) Unresolve(w http.ResponseWriter, r *http.Request) {
+	h.setResolved(w, r, false)
+}
+
+func (h *CommentHandler) setResolved(w http.ResponseWriter, r *http.Request, resolved bool) {
+	userID := middleware.GetUserID(r.Context())
+	taskID, commentID, ok := commentPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	comment, err := h.commentSvc.SetThreadResolved(r.Context(), userID, taskID, commentID, resolved)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, comment)
+}
+
+func commentPathIDs(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid task id"))
+		return 0, 0, false
+	}
+	commentID, err := strconv.ParseInt(chi.URLParam(r, "commentID"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid comment id"))
+		return 0, 0, false
+	}
+	return taskID, commentID, true
+}
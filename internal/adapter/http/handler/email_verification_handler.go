@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type EmailVerificationHandler struct {
+	verificationSvc port.EmailVerificationService
+}
+
+func NewEmailVerificationHandler(verificationSvc port.EmailVerificationService) *EmailVerificationHandler {
+	return &EmailVerificationHandler{verificationSvc: verificationSvc}
+}
+
+func (h *EmailVerificationHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	var req domain.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.verificationSvc.Verify(r.Context(), req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "email address verified"})
+}
+
+func (h *EmailVerificationHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	var req domain.ResendVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.verificationSvc.Resend(r.Context(), req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusAccepted, map[string]string{"message": "if the address needs verification, a new link has been sent"})
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type InvitationHandler struct {
+	invitationSvc port.InvitationService
+}
+
+func NewInvitationHandler(invitationSvc port.InvitationService) *InvitationHandler {
+	return &InvitationHandler{invitationSvc: invitationSvc}
+}
+
+func (h *InvitationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	var req domain.InviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	invitation, err := h.invitationSvc.Invite(r.Context(), userID, teamID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, invitation)
+}
+
+func (h *InvitationHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	invitations, err := h.invitationSvc.ListPending(r.Context(), userID, teamID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, invitations)
+}
+
+func (h *InvitationHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, invitationID, err := invitationPathIDs(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := h.invitationSvc.Revoke(r.Context(), userID, teamID, invitationID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "invitation revoked"})
+}
+
+func (h *InvitationHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, invitationID, err := invitationPathIDs(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	invitation, err := h.invitationSvc.Resend(r.Context(), userID, teamID, invitationID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, invitation)
+}
+
+func (h *InvitationHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var req domain.InvitationTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	team, err := h.invitationSvc.Accept(r.Context(), userID, req.Token)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, team)
+}
+
+func (h *InvitationHandler) Decline(w http.ResponseWriter, r *http.Request) {
+	var req domain.InvitationTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.invitationSvc.Decline(r.Context(), req.Token); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "invitation declined"})
+}
+
+func invitationPathIDs(r *http.Request) (int64, int64, error) {
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, 0, apperror.BadRequest("invalid team id")
+	}
+	invitationID, err := strconv.ParseInt(chi.URLParam(r, "invitationID"), 10, 64)
+	if err != nil {
+		return 0, 0, apperror.BadRequest("invalid invitation id")
+	}
+	return teamID, invitationID, nil
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type JoinLinkHandler struct {
+	joinLinkSvc port.JoinLinkService
+}
+
+func NewJoinLinkHandler(joinLinkSvc port.JoinLinkService) *JoinLinkHandler {
+	return &JoinLinkHandler{joinLinkSvc: joinLinkSvc}
+}
+
+func (h *JoinLinkHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	var req domain.CreateJoinLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	link, err := h.joinLinkSvc.Create(r.Context(), userID, teamID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, link)
+}
+
+func (h *JoinLinkHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	links, err := h.joinLinkSvc.List(r.Context(), userID, teamID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, links)
+}
+
+func (h *JoinLinkHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, linkID, err := joinLinkPathIDs(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	if err := h.joinLinkSvc.Revoke(r.Context(), userID, teamID, linkID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "join link revoked"})
+}
+
+func (h *JoinLinkHandler) ListUses(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, linkID, err := joinLinkPathIDs(r)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	uses, err := h.joinLinkSvc.ListUses(r.Context(), userID, teamID, linkID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, uses)
+}
+
+func (h *JoinLinkHandler) Join(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	team, err := h.joinLinkSvc.Join(r.Context(), userID, chi.URLParam(r, "code"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, team)
+}
+
+func joinLinkPathIDs(r *http.Request) (int64, int64, error) {
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, 0, apperror.BadRequest("invalid team id")
+	}
+	linkID, err := strconv.ParseInt(chi.URLParam(r, "linkID"), 10, 64)
+	if err != nil {
+		return 0, 0, apperror.BadRequest("invalid join link id")
+	}
+	return teamID, linkID, nil
+}
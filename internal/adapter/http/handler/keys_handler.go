@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/shalfey088/team-task-nexus/internal/pkg/jwtkeys"
+)
+
+type KeysHandler struct {
+	keys *jwtkeys.KeyRing
+}
+
+func NewKeysHandler(keys *jwtkeys.KeyRing) *KeysHandler {
+	return &KeysHandler{keys: keys}
+}
+
+// JWKS serves the public signing keys. JWKS clients expect the bare key set,
+// so the response is not wrapped in the API envelope.
+func (h *KeysHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if err := json.NewEncoder(w).Encode(h.keys.JWKS()); err != nil {
+		log.Printf("failed to encode response: %v", err)
+	}
+}
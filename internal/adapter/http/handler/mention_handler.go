@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type MentionHandler struct {
+	mentionSvc port.MentionService
+}
+
+func NewMentionHandler(mentionSvc port.MentionService) *MentionHandler {
+	return &MentionHandler{mentionSvc: mentionSvc}
+}
+
+func (h *MentionHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var filter domain.MentionFilter
+	if v := r.URL.Query().Get("page"); v != "" {
+		if p, err := strconv.Atoi(v); err == nil {
+			filter.Page = p
+		}
+	}
+	if v := r.URL.Query().Get("page_size"); v != "" {
+		if ps, err := strconv.Atoi(v); err == nil {
+			filter.PageSize = ps
+		}
+	}
+
+	mentions, err := h.mentionSvc.ListForUser(r.Context(), userID, filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, mentions)
+}
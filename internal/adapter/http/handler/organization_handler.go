@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type OrganizationHandler struct {
+	orgSvc port.OrganizationService
+}
+
+func NewOrganizationHandler(orgSvc port.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{orgSvc: orgSvc}
+}
+
+func (h *OrganizationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var req domain.CreateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	org, err := h.orgSvc.Create(r.Context(), userID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, org)
+}
+
+func (h *OrganizationHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	orgs, err := h.orgSvc.List(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, orgs)
+}
+
+func (h *OrganizationHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	orgID, ok := orgPathID(w, r)
+	if !ok {
+		return
+	}
+
+	org, err := h.orgSvc.GetByID(r.Context(), userID, orgID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, org)
+}
+
+func (h *OrganizationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	orgID, ok := orgPathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	org, err := h.orgSvc.Update(r.Context(), userID, orgID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, org)
+}
+
+func (h *OrganizationHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	orgID, ok := orgPathID(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := h.orgSvc.ListMembers(r.Context(), userID, orgID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, members)
+}
+
+func (h *OrganizationHandler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	orgID, ok := orgPathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.InviteOrgMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	invitation, err := h.orgSvc.InviteMember(r.Context(), userID, orgID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusAccepted, invitation)
+}
+
+func (h *OrganizationHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var req domain.InvitationTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	org, err := h.orgSvc.AcceptInvitation(r.Context(), userID, req.Token)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, org)
+}
+
+func (h *OrganizationHandler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	var req domain.InvitationTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.orgSvc.DeclineInvitation(r.Context(), req.Token); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "invitation declined"})
+}
+
+func (h *OrganizationHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	orgID, memberID, ok := orgMemberPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateOrgMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	member, err := h.orgSvc.ChangeMemberRole(r.Context(), userID, orgID, memberID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, member)
+}
+
+func (h *OrganizationHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	orgID, memberID, ok := orgMemberPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orgSvc.RemoveMember(r.Context(), userID, orgID, memberID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "member removed"})
+}
+
+func (h *OrganizationHandler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	orgID, ok := orgPathID(w, r)
+	if !ok {
+		return
+	}
+
+	teams, err := h.orgSvc.ListTeams(r.Context(), userID, orgID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, teams)
+}
+
+func (h *OrganizationHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	orgID, ok := orgPathID(w, r)
+	if !ok {
+		return
+	}
+
+	users, err := h.orgSvc.SearchUsers(r.Context(), userID, orgID, r.URL.Query().Get("q"))
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, users)
+}
+
+func orgPathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	orgID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid organization id"))
+		return 0, false
+	}
+	return orgID, true
+}
+
+func orgMemberPathIDs(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	orgID, ok := orgPathID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	memberID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid user id"))
+		return 0, 0, false
+	}
+	return orgID, memberID, true
+}
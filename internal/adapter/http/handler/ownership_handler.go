@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type OwnershipHandler struct {
+	ownershipSvc port.OwnershipService
+}
+
+func NewOwnershipHandler(ownershipSvc port.OwnershipService) *OwnershipHandler {
+	return &OwnershipHandler{ownershipSvc: ownershipSvc}
+}
+
+func (h *OwnershipHandler) Nominate(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	var req domain.NominateOwnerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	transfer, err := h.ownershipSvc.Nominate(r.Context(), userID, teamID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, transfer)
+}
+
+func (h *OwnershipHandler) GetPending(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	transfer, err := h.ownershipSvc.GetPending(r.Context(), userID, teamID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, transfer)
+}
+
+func (h *OwnershipHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	team, err := h.ownershipSvc.Accept(r.Context(), userID, teamID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, team)
+}
+
+func (h *OwnershipHandler) Decline(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	if err := h.ownershipSvc.Decline(r.Context(), userID, teamID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "ownership transfer declined"})
+}
+
+func (h *OwnershipHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	if err := h.ownershipSvc.Cancel(r.Context(), userID, teamID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "ownership transfer cancelled"})
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type PasswordHandler struct {
+	passwordSvc port.PasswordService
+}
+
+func NewPasswordHandler(passwordSvc port.PasswordService) *PasswordHandler {
+	return &PasswordHandler{passwordSvc: passwordSvc}
+}
+
+func (h *PasswordHandler) Forgot(w http.ResponseWriter, r *http.Request) {
+	var req domain.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.passwordSvc.Forgot(r.Context(), req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusAccepted, map[string]string{"message": "if the account exists, a reset link has been sent"})
+}
+
+func (h *PasswordHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	var req domain.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.passwordSvc.Reset(r.Context(), req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "password has been reset"})
+}
+
+func (h *PasswordHandler) Change(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	var req domain.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.passwordSvc.Change(r.Context(), *claims, req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "password changed; sign in again"})
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type PermissionHandler struct {
+	permSvc port.PermissionService
+}
+
+func NewPermissionHandler(permSvc port.PermissionService) *PermissionHandler {
+	return &PermissionHandler{permSvc: permSvc}
+}
+
+func (h *PermissionHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	roles, err := h.permSvc.ListRoles(r.Context(), userID, teamID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, roles)
+}
+
+func (h *PermissionHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	var req domain.CustomRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	role, err := h.permSvc.CreateRole(r.Context(), userID, teamID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, role)
+}
+
+func (h *PermissionHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, roleID, ok := rolePathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.CustomRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	role, err := h.permSvc.UpdateRole(r.Context(), userID, teamID, roleID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, role)
+}
+
+func (h *PermissionHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, roleID, ok := rolePathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.permSvc.DeleteRole(r.Context(), userID, teamID, roleID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "role deleted"})
+}
+
+func (h *PermissionHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, memberID, ok := memberPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.AssignCustomRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.permSvc.AssignRole(r.Context(), userID, teamID, memberID, req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "custom role updated"})
+}
+
+func (h *PermissionHandler) SetGuestScope(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, memberID, ok := memberPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.GuestScopeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.permSvc.SetGuestScope(r.Context(), userID, teamID, memberID, req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "guest task scope updated"})
+}
+
+// Describe answers GET /permissions?resource=task&id=5.
+func (h *PermissionHandler) Describe(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	resourceID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid resource id"))
+		return
+	}
+
+	summary, err := h.permSvc.Describe(r.Context(), userID, r.URL.Query().Get("resource"), resourceID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, summary)
+}
+
+func rolePathIDs(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return 0, 0, false
+	}
+	roleID, err := strconv.ParseInt(chi.URLParam(r, "roleID"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid role id"))
+		return 0, 0, false
+	}
+	return teamID, roleID, true
+}
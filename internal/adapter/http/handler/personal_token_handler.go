@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type PersonalTokenHandler struct {
+	tokenSvc port.PersonalTokenService
+}
+
+func NewPersonalTokenHandler(tokenSvc port.PersonalTokenService) *PersonalTokenHandler {
+	return &PersonalTokenHandler{tokenSvc: tokenSvc}
+}
+
+func (h *PersonalTokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	var req domain.CreatePersonalAccessTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	token, err := h.tokenSvc.Create(r.Context(), *claims, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, token)
+}
+
+func (h *PersonalTokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	tokens, err := h.tokenSvc.List(r.Context(), userID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, tokens)
+}
+
+func (h *PersonalTokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	tokenID, err := strconv.ParseInt(chi.URLParam(r, "tokenID"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid token id"))
+		return
+	}
+
+	if err := h.tokenSvc.Revoke(r.Context(), userID, tokenID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "token revoked"})
+}
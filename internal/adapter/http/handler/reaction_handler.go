@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type ReactionHandler struct {
+	reactionSvc port.ReactionService
+}
+
+func NewReactionHandler(reactionSvc port.ReactionService) *ReactionHandler {
+	return &ReactionHandler{reactionSvc: reactionSvc}
+}
+
+func (h *ReactionHandler) ToggleTask(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid task id"))
+		return
+	}
+
+	var req domain.ToggleReactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	result, err := h.reactionSvc.ToggleTaskReaction(r.Context(), userID, taskID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
+
+func (h *ReactionHandler) ListTask(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid task id"))
+		return
+	}
+
+	reactions, err := h.reactionSvc.ListTaskReactions(r.Context(), userID, taskID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, reactions)
+}
+
+func (h *ReactionHandler) ToggleComment(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	taskID, commentID, ok := commentPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.ToggleReactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	result, err := h.reactionSvc.ToggleCommentReaction(r.Context(), userID, taskID, commentID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
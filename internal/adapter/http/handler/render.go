@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+)
+
+// wantsHTML reports whether the caller asked for rendered Markdown via
+// ?render=html.
+func wantsHTML(r *http.Request) bool {
+	return r.URL.Query().Get("render") == "html"
+}
+
+func taskPointers(tasks []domain.Task) []*domain.Task {
+	ptrs := make([]*domain.Task, len(tasks))
+	for i := range tasks {
+		ptrs[i] = &tasks[i]
+	}
+	return ptrs
+}
+
+func commentPointers(comments []domain.TaskComment) []*domain.TaskComment {
+	ptrs := make([]*domain.TaskComment, len(comments))
+	for i := range comments {
+		ptrs[i] = &comments[i]
+	}
+	return ptrs
+}
+
+func threadPointers(nodes []domain.CommentNode) []*domain.TaskComment {
+	var ptrs []*domain.TaskComment
+	for i := range nodes {
+		ptrs = append(ptrs, &nodes[i].TaskComment)
+		ptrs = append(ptrs, threadPointers(nodes[i].Replies)...)
+	}
+	return ptrs
+}
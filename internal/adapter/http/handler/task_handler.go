@@ -10,15 +10,17 @@ import (
 	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
 	"github.com/shalfey088/team-task-nexus/internal/port"
 )
 
 type TaskHandler struct {
-	taskSvc port.TaskService
+	taskSvc     port.TaskService
+	markdownSvc port.MarkdownService
 }
 
-func NewTaskHandler(taskSvc port.TaskService) *TaskHandler {
-	return &TaskHandler{taskSvc: taskSvc}
+func NewTaskHandler(taskSvc port.TaskService, markdownSvc port.MarkdownService) *TaskHandler {
+	return &TaskHandler{taskSvc: taskSvc, markdownSvc: markdownSvc}
 }
 
 func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -35,10 +37,39 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, err)
 		return
 	}
+	if err := h.markdownSvc.RenderTasks(r.Context(), userID, []*domain.Task{task}, wantsHTML(r)); err != nil {
+		response.Error(w, err)
+		return
+	}
 
 	response.JSON(w, http.StatusCreated, task)
 }
 
+// Import runs under the import change source, so status changes made while
+// bringing tasks over are told apart from edits in the task history.
+func (h *TaskHandler) Import(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var req domain.ImportTasksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	ctx := requestctx.WithSource(r.Context(), domain.ChangeSourceImport)
+	result, err := h.taskSvc.Import(ctx, userID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	if err := h.markdownSvc.RenderTasks(ctx, userID, taskPointers(result.Tasks), wantsHTML(r)); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, result)
+}
+
 func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 
@@ -76,6 +107,10 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, err)
 		return
 	}
+	if err := h.markdownSvc.RenderTasks(r.Context(), userID, taskPointers(result.Tasks), wantsHTML(r)); err != nil {
+		response.Error(w, err)
+		return
+	}
 
 	response.JSON(w, http.StatusOK, result)
 }
@@ -99,11 +134,15 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, err)
 		return
 	}
+	if err := h.markdownSvc.RenderTasks(r.Context(), userID, []*domain.Task{task}, wantsHTML(r)); err != nil {
+		response.Error(w, err)
+		return
+	}
 
 	response.JSON(w, http.StatusOK, task)
 }
 
-func (h *TaskHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
@@ -111,21 +150,42 @@ func (h *TaskHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	history, err := h.taskSvc.GetHistory(r.Context(), userID, taskID)
-	if err != nil {
+	if err := h.taskSvc.Delete(r.Context(), userID, taskID); err != nil {
 		response.Error(w, err)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, history)
+	response.JSON(w, http.StatusOK, map[string]string{"message": "task deleted"})
 }
 
-func (h *TaskHandler) GetOrphanedAssignees(w http.ResponseWriter, r *http.Request) {
-	result, err := h.taskSvc.GetOrphanedAssignees(r.Context())
+func (h *TaskHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid task id"))
+		return
+	}
+
+	filter := domain.HistoryFilter{
+		Page:     1,
+		PageSize: 20,
+	}
+	if v := r.URL.Query().Get("page"); v != "" {
+		if p, err := strconv.Atoi(v); err == nil {
+			filter.Page = p
+		}
+	}
+	if v := r.URL.Query().Get("page_size"); v != "" {
+		if ps, err := strconv.Atoi(v); err == nil {
+			filter.PageSize = ps
+		}
+	}
+
+	history, err := h.taskSvc.GetHistory(r.Context(), userID, taskID, filter)
 	if err != nil {
 		response.Error(w, err)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, result)
+	response.JSON(w, http.StatusOK, history)
 }
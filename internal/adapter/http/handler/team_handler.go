@@ -42,7 +42,8 @@ func (h *TeamHandler) Create(w http.ResponseWriter, r *http.Request) {
 func (h *TeamHandler) List(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 
-	teams, err := h.teamSvc.ListByUserID(r.Context(), userID)
+	includeArchived := r.URL.Query().Get("include_archived") == "true"
+	teams, err := h.teamSvc.ListByUserID(r.Context(), userID, includeArchived)
 	if err != nil {
 		response.Error(w, err)
 		return
@@ -68,7 +69,7 @@ func (h *TeamHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, team)
 }
 
-func (h *TeamHandler) Invite(w http.ResponseWriter, r *http.Request) {
+func (h *TeamHandler) Update(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
@@ -76,24 +77,131 @@ func (h *TeamHandler) Invite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req domain.InviteRequest
+	var req domain.UpdateTeamRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.Error(w, apperror.BadRequest("invalid request body"))
 		return
 	}
 
-	if err := h.teamSvc.InviteUser(r.Context(), userID, teamID, req); err != nil {
+	team, err := h.teamSvc.Update(r.Context(), userID, teamID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, team)
+}
+
+func (h *TeamHandler) SetParent(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	var req domain.SetParentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	team, err := h.teamSvc.SetParent(r.Context(), userID, teamID, req)
+	if err != nil {
 		response.Error(w, err)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, map[string]string{"message": "user invited successfully"})
+	response.JSON(w, http.StatusOK, team)
+}
+
+func (h *TeamHandler) GetTree(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	tree, err := h.teamSvc.GetTree(r.Context(), userID, teamID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, tree)
+}
+
+func (h *TeamHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, true)
+}
+
+func (h *TeamHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, false)
+}
+
+func (h *TeamHandler) setArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	team, err := h.teamSvc.SetArchived(r.Context(), userID, teamID, archived)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, team)
+}
+
+func (h *TeamHandler) IssueDeletionToken(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	token, err := h.teamSvc.IssueDeletionToken(r.Context(), userID, teamID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, token)
+}
+
+func (h *TeamHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	var req domain.DeleteTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.teamSvc.Delete(r.Context(), userID, teamID, req); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "team deleted"})
 }
 
 func (h *TeamHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 
-	stats, err := h.teamSvc.GetStats(r.Context(), userID)
+	rollup := r.URL.Query().Get("rollup") == "true"
+
+	stats, err := h.teamSvc.GetStats(r.Context(), userID, rollup)
 	if err != nil {
 		response.Error(w, err)
 		return
@@ -118,3 +226,87 @@ func (h *TeamHandler) GetTopContributors(w http.ResponseWriter, r *http.Request)
 
 	response.JSON(w, http.StatusOK, contributors)
 }
+
+func (h *TeamHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	members, err := h.teamSvc.ListMembers(r.Context(), userID, teamID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, members)
+}
+
+func (h *TeamHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, memberID, ok := memberPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	member, err := h.teamSvc.ChangeMemberRole(r.Context(), userID, teamID, memberID, req)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, member)
+}
+
+func (h *TeamHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, memberID, ok := memberPathIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.teamSvc.RemoveMember(r.Context(), userID, teamID, memberID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "member removed"})
+}
+
+func (h *TeamHandler) Leave(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return
+	}
+
+	if err := h.teamSvc.Leave(r.Context(), userID, teamID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "left the team"})
+}
+
+func memberPathIDs(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid team id"))
+		return 0, 0, false
+	}
+	memberID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		response.Error(w, apperror.BadRequest("invalid user id"))
+		return 0, 0, false
+	}
+	return teamID, memberID, true
+}
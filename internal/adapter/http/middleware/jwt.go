@@ -2,19 +2,29 @@ package middleware
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/shalfey088/team-task-nexus/internal/adapter/http/response"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/jwtkeys"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
+	"github.com/shalfey088/team-task-nexus/internal/port"
 )
 
 type ctxKey string
 
-const UserIDKey ctxKey = "user_id"
+const (
+	ClaimsKey ctxKey = "claims"
+)
 
-func JWTAuth(secret string) func(http.Handler) http.Handler {
+// Authenticate accepts either a JWT access token or a personal access
+// token. JWTs must be signed by a key of the ring, issued for this API and
+// not revoked by a logout, either one by one or for their whole session.
+// Changes made with a personal access token are recorded as automation.
+func Authenticate(keys *jwtkeys.KeyRing, denylist port.TokenDenylist, pats port.PersonalTokenAuthenticator) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -29,38 +39,176 @@ func JWTAuth(secret string) func(http.Handler) http.Handler {
 				return
 			}
 
-			token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, apperror.ErrUnauthorized
-				}
-				return []byte(secret), nil
-			})
-			if err != nil || !token.Valid {
-				response.Error(w, apperror.ErrUnauthorized)
+			var access *domain.AccessClaims
+			var err error
+			if strings.HasPrefix(parts[1], domain.PersonalTokenPrefix) {
+				access, err = pats.AuthenticatePersonalToken(r.Context(), parts[1])
+			} else {
+				access, err = parseAccessToken(r.Context(), keys, denylist, parts[1])
+			}
+			if err != nil {
+				response.Error(w, err)
 				return
 			}
 
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				response.Error(w, apperror.ErrUnauthorized)
-				return
+			ctx := context.WithValue(r.Context(), ClaimsKey, access)
+			if access.IsPersonalToken() {
+				ctx = requestctx.WithSource(ctx, domain.ChangeSourceAutomation)
 			}
+			if access.TeamID != nil {
+				ctx = requestctx.WithTeamRestriction(ctx, *access.TeamID)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
 
-			userIDFloat, ok := claims["user_id"].(float64)
-			if !ok {
-				response.Error(w, apperror.ErrUnauthorized)
+func parseAccessToken(ctx context.Context, keys *jwtkeys.KeyRing, denylist port.TokenDenylist, token string) (*domain.AccessClaims, error) {
+	claims, err := keys.Parse(token)
+	if err != nil {
+		return nil, apperror.ErrUnauthorized
+	}
+	// Tokens with a typ, such as login challenges, are not access tokens.
+	if _, ok := claims["typ"]; ok {
+		return nil, apperror.ErrUnauthorized
+	}
+
+	userIDFloat, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, apperror.ErrUnauthorized
+	}
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return nil, apperror.ErrUnauthorized
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return nil, apperror.ErrUnauthorized
+	}
+
+	access := &domain.AccessClaims{
+		UserID:    int64(userIDFloat),
+		TokenID:   jti,
+		ExpiresAt: exp.Time,
+	}
+	access.FamilyID, _ = claims["fam"].(string)
+	if adminID, ok := claims["impersonator_id"].(float64); ok {
+		access.ImpersonatorID = int64(adminID)
+	}
+
+	// Without the denylist a revoked token cannot be told apart, so the
+	// request is refused rather than let through.
+	revoked, err := denylist.IsRevoked(ctx, access.TokenID, access.FamilyID)
+	if err != nil {
+		return nil, apperror.Internal("check token", err)
+	}
+	if revoked {
+		return nil, apperror.ErrUnauthorized
+	}
+	return access, nil
+}
+
+// RequireScope limits personal access tokens on a group of routes: any
+// token may read, but other methods need scope. Session tokens pass.
+func RequireScope(scope domain.TokenScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+			default:
+				if claims := GetClaims(r.Context()); claims != nil && !claims.HasScope(scope) {
+					response.Error(w, apperror.Forbidden("token lacks the "+string(scope)+" scope"))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SessionOnly keeps personal access tokens away from account management:
+// token administration, logout and the admin API.
+func SessionOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if claims := GetClaims(r.Context()); claims != nil && claims.IsPersonalToken() {
+			response.Error(w, apperror.Forbidden("not available with a personal access token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Unrestricted rejects tokens limited to one team on routes that are not
+// about a single team, such as organizations or creating teams.
+func Unrestricted(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := requestctx.TeamRestriction(r.Context()); ok {
+			response.Error(w, apperror.Forbidden("token is restricted to a single team"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AuditImpersonation writes every change made with an impersonation token
+// to the admin audit log before it runs; if the entry cannot be written the
+// request is refused. Reads are only logged.
+func AuditImpersonation(auditor port.ImpersonationAuditor) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := GetClaims(r.Context())
+			if claims == nil || claims.ImpersonatorID == 0 {
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), UserIDKey, int64(userIDFloat))
-			next.ServeHTTP(w, r.WithContext(ctx))
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				log.Printf("impersonation: admin %d acting as user %d: %s %s",
+					claims.ImpersonatorID, claims.UserID, r.Method, r.URL.Path)
+			default:
+				err := auditor.RecordImpersonatedRequest(r.Context(), claims.ImpersonatorID, claims.UserID, r.Method, r.URL.Path)
+				if err != nil {
+					response.Error(w, err)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
 		})
 	}
 }
 
+// ActiveAccount rejects requests from accounts disabled after their token
+// was issued.
+func ActiveAccount(checker port.AccountChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := checker.CheckActive(r.Context(), GetUserID(r.Context())); err != nil {
+				response.Error(w, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GetClaims returns the access token claims of the request, or nil.
+func GetClaims(ctx context.Context) *domain.AccessClaims {
+	claims, _ := ctx.Value(ClaimsKey).(*domain.AccessClaims)
+	return claims
+}
+
 func GetUserID(ctx context.Context) int64 {
-	if id, ok := ctx.Value(UserIDKey).(int64); ok {
-		return id
+	if claims := GetClaims(ctx); claims != nil {
+		return claims.UserID
+	}
+	return 0
+}
+
+// GetImpersonatorID returns the admin acting as the user, or 0.
+func GetImpersonatorID(ctx context.Context) int64 {
+	if claims := GetClaims(ctx); claims != nil {
+		return claims.ImpersonatorID
 	}
 	return 0
 }
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
+)
+
+func RequestContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestctx.WithRequestID(r.Context(), chimiddleware.GetReqID(r.Context()))
+		ctx = requestctx.WithSource(ctx, domain.ChangeSourceAPI)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -6,17 +6,41 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/shalfey088/team-task-nexus/internal/adapter/http/handler"
 	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/jwtkeys"
 	"github.com/shalfey088/team-task-nexus/internal/port"
 )
 
 type RouterDeps struct {
-	AuthHandler    *handler.AuthHandler
-	TeamHandler    *handler.TeamHandler
-	TaskHandler    *handler.TaskHandler
-	CommentHandler *handler.CommentHandler
-	HealthHandler  *handler.HealthHandler
-	JWTSecret      string
-	RateLimiter    port.RateLimiter
+	AuthHandler       *handler.AuthHandler
+	TeamHandler       *handler.TeamHandler
+	TaskHandler       *handler.TaskHandler
+	CommentHandler    *handler.CommentHandler
+	ActivityHandler   *handler.ActivityHandler
+	MentionHandler    *handler.MentionHandler
+	ReactionHandler   *handler.ReactionHandler
+	AttachmentHandler *handler.AttachmentHandler
+	OwnershipHandler  *handler.OwnershipHandler
+	InvitationHandler *handler.InvitationHandler
+	JoinLinkHandler   *handler.JoinLinkHandler
+	OrgHandler        *handler.OrganizationHandler
+	PermissionHandler *handler.PermissionHandler
+	AdminHandler      *handler.AdminHandler
+	TokenHandler      *handler.PersonalTokenHandler
+	PasswordHandler   *handler.PasswordHandler
+	VerifyHandler     *handler.EmailVerificationHandler
+	TwoFactorHandler  *handler.TwoFactorHandler
+	HealthHandler     *handler.HealthHandler
+	KeysHandler       *handler.KeysHandler
+	Keys              *jwtkeys.KeyRing
+	Accounts          port.AccountChecker
+	Impersonation     port.ImpersonationAuditor
+	Denylist          port.TokenDenylist
+	PersonalTokens    port.PersonalTokenAuthenticator
+	RateLimiter       port.RateLimiter
+	LoginRateLimiter  port.LoginRateLimiter
+	AddressLimiter    port.AttemptLimiter
+	EmailLimiter      port.AttemptLimiter
 }
 
 func NewRouter(deps RouterDeps) *chi.Mux {
@@ -25,38 +49,159 @@ func NewRouter(deps RouterDeps) *chi.Mux {
 	r.Use(chimiddleware.Recoverer)
 	r.Use(chimiddleware.RequestID)
 	r.Use(chimiddleware.RealIP)
+	r.Use(middleware.RequestContext)
 	r.Use(middleware.Logging)
 	r.Use(middleware.Metrics)
 
 	r.Get("/health", deps.HealthHandler.Health)
+	r.Get("/.well-known/jwks.json", deps.KeysHandler.JWKS)
 	r.Handle("/metrics", promhttp.Handler())
 
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Post("/register", deps.AuthHandler.Register)
-		r.Post("/login", deps.AuthHandler.Login)
+		r.With(middleware.LoginRateLimit(deps.LoginRateLimiter)).Post("/login", deps.AuthHandler.Login)
+		r.With(middleware.LoginRateLimit(deps.LoginRateLimiter)).Post("/login/2fa", deps.AuthHandler.CompleteLogin)
 
 		r.Group(func(r chi.Router) {
-			r.Use(middleware.JWTAuth(deps.JWTSecret))
+			r.Use(middleware.AddressRateLimit(deps.AddressLimiter))
+
+			r.With(middleware.EmailRateLimit(deps.EmailLimiter)).Post("/register", deps.AuthHandler.Register)
+			r.With(middleware.EmailRateLimit(deps.EmailLimiter)).Post("/password/forgot", deps.PasswordHandler.Forgot)
+			r.With(middleware.EmailRateLimit(deps.EmailLimiter)).Post("/email/verify/resend", deps.VerifyHandler.Resend)
+			r.Post("/token/refresh", deps.AuthHandler.Refresh)
+			r.Post("/password/reset", deps.PasswordHandler.Reset)
+			r.Post("/email/verify", deps.VerifyHandler.Verify)
+			r.Post("/invitations/decline", deps.InvitationHandler.Decline)
+			r.Post("/org-invitations/decline", deps.OrgHandler.DeclineInvitation)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Authenticate(deps.Keys, deps.Denylist, deps.PersonalTokens))
+			r.Use(middleware.ActiveAccount(deps.Accounts))
+			r.Use(middleware.AuditImpersonation(deps.Impersonation))
 			r.Use(middleware.RateLimit(deps.RateLimiter))
 
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.SessionOnly)
+
+				r.Post("/logout", deps.AuthHandler.Logout)
+				r.Post("/logout/all", deps.AuthHandler.LogoutAll)
+				r.Post("/password/change", deps.PasswordHandler.Change)
+
+				r.Post("/me/2fa/enroll", deps.TwoFactorHandler.Enroll)
+				r.Post("/me/2fa/confirm", deps.TwoFactorHandler.Confirm)
+				r.Post("/me/2fa/disable", deps.TwoFactorHandler.Disable)
+				r.Post("/me/2fa/recovery-codes", deps.TwoFactorHandler.RegenerateRecoveryCodes)
+
+				r.Post("/me/tokens", deps.TokenHandler.Create)
+				r.Get("/me/tokens", deps.TokenHandler.List)
+				r.Delete("/me/tokens/{tokenID}", deps.TokenHandler.Revoke)
+
+				r.Route("/admin", func(r chi.Router) {
+					r.Get("/users", deps.AdminHandler.ListUsers)
+					r.Post("/users/{userID}/disable", deps.AdminHandler.DisableUser)
+					r.Post("/users/{userID}/enable", deps.AdminHandler.EnableUser)
+					r.Post("/users/{userID}/impersonate", deps.AdminHandler.Impersonate)
+					r.Get("/teams", deps.AdminHandler.ListTeams)
+					r.Get("/orphaned-assignees", deps.AdminHandler.ListOrphanedAssignees)
+					r.Post("/orphaned-assignees/repair", deps.AdminHandler.RepairOrphanedAssignees)
+					r.Get("/audit", deps.AdminHandler.ListAudit)
+				})
+			})
+
+			r.Route("/orgs", func(r chi.Router) {
+				r.Use(middleware.Unrestricted)
+				r.Use(middleware.RequireScope(domain.ScopeTeamsAdmin))
+
+				r.Post("/", deps.OrgHandler.Create)
+				r.Get("/", deps.OrgHandler.List)
+				r.Get("/{id}", deps.OrgHandler.GetByID)
+				r.Patch("/{id}", deps.OrgHandler.Update)
+				r.Get("/{id}/members", deps.OrgHandler.ListMembers)
+				r.Post("/{id}/members", deps.OrgHandler.InviteMember)
+				r.Patch("/{id}/members/{userID}", deps.OrgHandler.UpdateMemberRole)
+				r.Delete("/{id}/members/{userID}", deps.OrgHandler.RemoveMember)
+				r.Get("/{id}/teams", deps.OrgHandler.ListTeams)
+				r.Get("/{id}/users", deps.OrgHandler.SearchUsers)
+			})
+
 			r.Route("/teams", func(r chi.Router) {
-				r.Post("/", deps.TeamHandler.Create)
+				r.Use(middleware.RequireScope(domain.ScopeTeamsAdmin))
+
+				r.With(middleware.Unrestricted).Post("/", deps.TeamHandler.Create)
 				r.Get("/", deps.TeamHandler.List)
 				r.Get("/stats", deps.TeamHandler.GetStats)
 				r.Get("/{id}", deps.TeamHandler.GetByID)
-				r.Post("/{id}/invite", deps.TeamHandler.Invite)
+				r.Patch("/{id}", deps.TeamHandler.Update)
+				r.Delete("/{id}", deps.TeamHandler.Delete)
+				r.Post("/{id}/delete-token", deps.TeamHandler.IssueDeletionToken)
+				r.Post("/{id}/archive", deps.TeamHandler.Archive)
+				r.Post("/{id}/unarchive", deps.TeamHandler.Unarchive)
+				r.Put("/{id}/parent", deps.TeamHandler.SetParent)
+				r.Get("/{id}/tree", deps.TeamHandler.GetTree)
+				r.Post("/{id}/invite", deps.InvitationHandler.Create)
+				r.Get("/{id}/invitations", deps.InvitationHandler.List)
+				r.Delete("/{id}/invitations/{invitationID}", deps.InvitationHandler.Revoke)
+				r.Post("/{id}/invitations/{invitationID}/resend", deps.InvitationHandler.Resend)
+				r.Post("/{id}/join-links", deps.JoinLinkHandler.Create)
+				r.Get("/{id}/join-links", deps.JoinLinkHandler.List)
+				r.Delete("/{id}/join-links/{linkID}", deps.JoinLinkHandler.Revoke)
+				r.Get("/{id}/join-links/{linkID}/uses", deps.JoinLinkHandler.ListUses)
+				r.Get("/{id}/members", deps.TeamHandler.ListMembers)
+				r.Patch("/{id}/members/{userID}", deps.TeamHandler.UpdateMemberRole)
+				r.Delete("/{id}/members/{userID}", deps.TeamHandler.RemoveMember)
+				r.Put("/{id}/members/{userID}/custom-role", deps.PermissionHandler.AssignRole)
+				r.Put("/{id}/members/{userID}/task-scope", deps.PermissionHandler.SetGuestScope)
+				r.Get("/{id}/roles", deps.PermissionHandler.ListRoles)
+				r.Post("/{id}/roles", deps.PermissionHandler.CreateRole)
+				r.Put("/{id}/roles/{roleID}", deps.PermissionHandler.UpdateRole)
+				r.Delete("/{id}/roles/{roleID}", deps.PermissionHandler.DeleteRole)
+				r.Post("/{id}/leave", deps.TeamHandler.Leave)
+				r.Post("/{id}/ownership-transfer", deps.OwnershipHandler.Nominate)
+				r.Get("/{id}/ownership-transfer", deps.OwnershipHandler.GetPending)
+				r.Delete("/{id}/ownership-transfer", deps.OwnershipHandler.Cancel)
+				r.Post("/{id}/ownership-transfer/accept", deps.OwnershipHandler.Accept)
+				r.Post("/{id}/ownership-transfer/decline", deps.OwnershipHandler.Decline)
 				r.Get("/{id}/top-contributors", deps.TeamHandler.GetTopContributors)
+				r.Get("/{id}/activity", deps.ActivityHandler.TeamActivity)
 			})
 
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.Unrestricted)
+				r.Use(middleware.RequireScope(domain.ScopeTeamsAdmin))
+
+				r.Post("/invitations/accept", deps.InvitationHandler.Accept)
+				r.Post("/org-invitations/accept", deps.OrgHandler.AcceptInvitation)
+				r.Post("/join/{code}", deps.JoinLinkHandler.Join)
+			})
+			r.With(middleware.Unrestricted).Get("/me/mentions", deps.MentionHandler.ListMine)
+			r.Get("/permissions", deps.PermissionHandler.Describe)
+
 			r.Route("/tasks", func(r chi.Router) {
+				r.Use(middleware.RequireScope(domain.ScopeTasksWrite))
+
 				r.Post("/", deps.TaskHandler.Create)
+				r.Post("/import", deps.TaskHandler.Import)
 				r.Get("/", deps.TaskHandler.List)
 				r.Put("/{id}", deps.TaskHandler.Update)
+				r.Delete("/{id}", deps.TaskHandler.Delete)
 				r.Get("/{id}/history", deps.TaskHandler.GetHistory)
-				r.Get("/orphaned-assignees", deps.TaskHandler.GetOrphanedAssignees)
+				r.Get("/{id}/reactions", deps.ReactionHandler.ListTask)
+				r.Post("/{id}/reactions", deps.ReactionHandler.ToggleTask)
+				r.Post("/{id}/attachments", deps.AttachmentHandler.UploadToTask)
+				r.Get("/{id}/attachments", deps.AttachmentHandler.List)
+				r.Get("/{id}/attachments/{attachmentID}", deps.AttachmentHandler.Download)
+				r.Delete("/{id}/attachments/{attachmentID}", deps.AttachmentHandler.Delete)
 
 				r.Post("/{id}/comments", deps.CommentHandler.Create)
 				r.Get("/{id}/comments", deps.CommentHandler.List)
+				r.Get("/{id}/comments/threads", deps.CommentHandler.ListThreads)
+				r.Put("/{id}/comments/{commentID}", deps.CommentHandler.Update)
+				r.Delete("/{id}/comments/{commentID}", deps.CommentHandler.Delete)
+				r.Get("/{id}/comments/{commentID}/revisions", deps.CommentHandler.ListRevisions)
+				r.Post("/{id}/comments/{commentID}/resolve", deps.CommentHandler.Resolve)
+				r.Post("/{id}/comments/{commentID}/unresolve", deps.CommentHandler.Unresolve)
+				r.Post("/{id}/comments/{commentID}/reactions", deps.ReactionHandler.ToggleComment)
+				r.Post("/{id}/comments/{commentID}/attachments", deps.AttachmentHandler.UploadToComment)
 			})
 		})
 	})
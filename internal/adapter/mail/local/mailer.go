@@ -0,0 +1,63 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+// Mailer is for local runs: instead of sending, it writes each message to a
+// file in dir and logs where it went. With an empty dir it only logs, body
+// included.
+type Mailer struct {
+	from string
+	dir  string
+	seq  atomic.Int64
+}
+
+func NewMailer(from, dir string) (*Mailer, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, err
+		}
+	}
+	return &Mailer{from: from, dir: dir}, nil
+}
+
+func (m *Mailer) Send(ctx context.Context, msg domain.MailMessage) error {
+	content := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\n\r\n%s\r\n",
+		m.from, msg.To, msg.Subject, time.Now().Format(time.RFC1123Z), msg.Body)
+
+	if m.dir == "" {
+		log.Printf("mail to %s:\n%s", msg.To, content)
+		return nil
+	}
+
+	name := fmt.Sprintf("%s-%d-%s.eml", time.Now().Format("20060102T150405"), m.seq.Add(1), sanitize(msg.To))
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o640); err != nil {
+		return apperror.Internal("write mail", err)
+	}
+	log.Printf("mail to %s written to %s", msg.To, path)
+	return nil
+}
+
+func sanitize(addr string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		case r == '@':
+			return '_'
+		}
+		return -1
+	}, addr)
+}
@@ -0,0 +1,148 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type ActivityRepo struct {
+	db *sqlx.DB
+}
+
+func NewActivityRepo(db *sqlx.DB) *ActivityRepo {
+	return &ActivityRepo{db: db}
+}
+
+func (r *ActivityRepo) CreateTeamEvent(ctx context.Context, event *domain.TeamEvent) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		`INSERT INTO team_events (team_id, actor_id, target_user_id, type, details)
+		 VALUES (?, ?, ?, ?, ?)`,
+		event.TeamID, event.ActorID, event.TargetUserID, event.Type, event.Details,
+	)
+	if err != nil {
+		return apperror.Internal("create team event", err)
+	}
+	return nil
+}
+
+// activitySource is one branch of the team feed. The column expressions are
+// used to push filters, the cursor and the limit into the branch, so each
+// one reads at most a page from its index instead of the team's history.
+type activitySource struct {
+	eventType  domain.ActivityType // empty when the branch has several types
+	typeExpr   string
+	subjectCol string
+	actorCol   string
+	taskCol    string // empty when events are not tied to a task
+	timeCol    string
+	selectList string
+	from       string
+}
+
+var activitySources = []activitySource{
+	{
+		eventType: domain.ActivityTaskCreated, typeExpr: "'task_created'",
+		subjectCol: "t.id", actorCol: "t.creator_id", taskCol: "t.id", timeCol: "t.created_at",
+		selectList: `'task_created' AS type, t.id AS subject_id, t.creator_id AS actor_id,
+			t.id AS task_id, t.title AS task_title, NULL AS target_user_id,
+			'' AS details, t.created_at AS occurred_at`,
+		from: "tasks t WHERE t.team_id = ?",
+	},
+	{
+		eventType: domain.ActivityTaskUpdated, typeExpr: "'task_updated'",
+		subjectCol: "cs.id", actorCol: "cs.user_id", taskCol: "t.id", timeCol: "cs.created_at",
+		selectList: `'task_updated', cs.id, cs.user_id, t.id, t.title, NULL,
+			COALESCE((SELECT GROUP_CONCAT(h.field ORDER BY h.id SEPARATOR ',')
+				FROM task_history h WHERE h.change_set_id = cs.id), ''),
+			cs.created_at`,
+		from: "task_change_sets cs JOIN tasks t ON t.id = cs.task_id WHERE t.team_id = ?",
+	},
+	{
+		eventType: domain.ActivityCommentAdded, typeExpr: "'comment_added'",
+		subjectCol: "c.id", actorCol: "c.user_id", taskCol: "t.id", timeCol: "c.created_at",
+		selectList: `'comment_added', c.id, c.user_id, t.id, t.title, NULL,
+			IF(c.deleted_at IS NULL, LEFT(c.content, 200), ''), c.created_at`,
+		from: "task_comments c JOIN tasks t ON t.id = c.task_id WHERE t.team_id = ?",
+	},
+	{
+		typeExpr:   "e.type",
+		subjectCol: "e.id", actorCol: "e.actor_id", timeCol: "e.created_at",
+		selectList: `e.type, e.id, e.actor_id, NULL, NULL, e.target_user_id,
+			e.details, e.created_at`,
+		from: "team_events e WHERE e.team_id = ?",
+	},
+}
+
+func (r *ActivityRepo) ListByTeam(ctx context.Context, filter domain.ActivityFilter) ([]domain.ActivityEvent, error) {
+	q := getQuerier(ctx, r.db)
+
+	wanted := make(map[domain.ActivityType]bool, len(filter.Types))
+	for _, t := range filter.Types {
+		wanted[t] = true
+	}
+
+	var branches []string
+	var args []interface{}
+	for _, src := range activitySources {
+		if src.eventType != "" && len(wanted) > 0 && !wanted[src.eventType] {
+			continue
+		}
+		if filter.TaskID > 0 && src.taskCol == "" {
+			continue
+		}
+
+		conditions := ""
+		args = append(args, filter.TeamID)
+		if src.eventType == domain.ActivityCommentAdded && filter.ExcludeInternal {
+			conditions += " AND c.internal = FALSE"
+		}
+		if filter.ActorID > 0 {
+			conditions += " AND " + src.actorCol + " = ?"
+			args = append(args, filter.ActorID)
+		}
+		if filter.TaskID > 0 {
+			conditions += " AND " + src.taskCol + " = ?"
+			args = append(args, filter.TaskID)
+		}
+		if src.eventType == "" && len(filter.Types) > 0 {
+			conditions += " AND e.type IN (?" + strings.Repeat(", ?", len(filter.Types)-1) + ")"
+			for _, t := range filter.Types {
+				args = append(args, t)
+			}
+		}
+		if c := filter.Cursor; c != nil {
+			conditions += fmt.Sprintf(" AND (%[1]s < ? OR (%[1]s = ? AND (%[2]s < ? OR (%[2]s = ? AND %[3]s < ?))))",
+				src.timeCol, src.typeExpr, src.subjectCol)
+			args = append(args, c.OccurredAt, c.OccurredAt, c.Type, c.Type, c.SubjectID)
+		}
+		args = append(args, filter.Limit)
+
+		branches = append(branches, fmt.Sprintf("(SELECT %s FROM %s%s ORDER BY %s DESC, %s DESC, %s DESC LIMIT ?)",
+			src.selectList, src.from, conditions, src.timeCol, src.typeExpr, src.subjectCol))
+	}
+	// Task-only filters can rule out every branch.
+	if len(branches) == 0 {
+		return nil, nil
+	}
+	args = append(args, filter.Limit)
+
+	query := fmt.Sprintf(`
+		SELECT a.*, u.full_name AS actor_name FROM (
+			%s
+		) a
+		JOIN users u ON u.id = a.actor_id
+		ORDER BY a.occurred_at DESC, a.type DESC, a.subject_id DESC
+		LIMIT ?`, strings.Join(branches, "\nUNION ALL\n"))
+
+	var events []domain.ActivityEvent
+	if err := q.SelectContext(ctx, &events, query, args...); err != nil {
+		return nil, apperror.Internal("list team activity", err)
+	}
+	return events, nil
+}
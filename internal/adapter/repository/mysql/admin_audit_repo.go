@@ -0,0 +1,48 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type AdminAuditRepo struct {
+	db *sqlx.DB
+}
+
+func NewAdminAuditRepo(db *sqlx.DB) *AdminAuditRepo {
+	return &AdminAuditRepo{db: db}
+}
+
+func (r *AdminAuditRepo) Create(ctx context.Context, entry *domain.AdminAuditEntry) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"INSERT INTO admin_audit_log (admin_id, action, target_user_id, details) VALUES (?, ?, ?, ?)",
+		entry.AdminID, entry.Action, entry.TargetUserID, entry.Details,
+	)
+	if err != nil {
+		return apperror.Internal("create admin audit entry", err)
+	}
+	return nil
+}
+
+func (r *AdminAuditRepo) List(ctx context.Context, filter domain.AdminListFilter) ([]domain.AdminAuditEntry, int, error) {
+	q := getQuerier(ctx, r.db)
+	var total int
+	if err := q.GetContext(ctx, &total, "SELECT COUNT(*) FROM admin_audit_log"); err != nil {
+		return nil, 0, apperror.Internal("count admin audit entries", err)
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	var entries []domain.AdminAuditEntry
+	err := q.SelectContext(ctx, &entries,
+		"SELECT * FROM admin_audit_log ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?",
+		filter.PageSize, offset,
+	)
+	if err != nil {
+		return nil, 0, apperror.Internal("list admin audit entries", err)
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,111 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type AttachmentRepo struct {
+	db *sqlx.DB
+}
+
+func NewAttachmentRepo(db *sqlx.DB) *AttachmentRepo {
+	return &AttachmentRepo{db: db}
+}
+
+func (r *AttachmentRepo) Create(ctx context.Context, a *domain.Attachment) (int64, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		`INSERT INTO attachments (task_id, comment_id, uploader_id, file_name, content_type, size, storage_key)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		a.TaskID, a.CommentID, a.UploaderID, a.FileName, a.ContentType, a.Size, a.StorageKey,
+	)
+	if err != nil {
+		return 0, apperror.Internal("create attachment", err)
+	}
+	return result.LastInsertId()
+}
+
+func (r *AttachmentRepo) GetByID(ctx context.Context, id int64) (*domain.Attachment, error) {
+	q := getQuerier(ctx, r.db)
+	var a domain.Attachment
+	err := q.GetContext(ctx, &a, "SELECT * FROM attachments WHERE id = ?", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("attachment not found")
+		}
+		return nil, apperror.Internal("get attachment", err)
+	}
+	return &a, nil
+}
+
+func (r *AttachmentRepo) ListByTaskID(ctx context.Context, taskID int64) ([]domain.Attachment, error) {
+	q := getQuerier(ctx, r.db)
+	var attachments []domain.Attachment
+	err := q.SelectContext(ctx, &attachments,
+		"SELECT * FROM attachments WHERE task_id = ? ORDER BY created_at ASC, id ASC",
+		taskID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list attachments", err)
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepo) ListByCommentID(ctx context.Context, commentID int64) ([]domain.Attachment, error) {
+	q := getQuerier(ctx, r.db)
+	var attachments []domain.Attachment
+	err := q.SelectContext(ctx, &attachments,
+		"SELECT * FROM attachments WHERE comment_id = ? ORDER BY created_at ASC, id ASC",
+		commentID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list comment attachments", err)
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepo) ListByTeamID(ctx context.Context, teamID int64) ([]domain.Attachment, error) {
+	q := getQuerier(ctx, r.db)
+	var attachments []domain.Attachment
+	err := q.SelectContext(ctx, &attachments,
+		`SELECT a.* FROM attachments a
+		 JOIN tasks t ON t.id = a.task_id
+		 WHERE t.team_id = ?`,
+		teamID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list team attachments", err)
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepo) Delete(ctx context.Context, id int64) error {
+	q := getQuerier(ctx, r.db)
+	if _, err := q.ExecContext(ctx, "DELETE FROM attachments WHERE id = ?", id); err != nil {
+		return apperror.Internal("delete attachment", err)
+	}
+	return nil
+}
+
+func (r *AttachmentRepo) DeleteByIDs(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In("DELETE FROM attachments WHERE id IN (?)", ids)
+	if err != nil {
+		return apperror.Internal("delete attachments", err)
+	}
+
+	q := getQuerier(ctx, r.db)
+	if _, err := q.ExecContext(ctx, r.db.Rebind(query), args...); err != nil {
+		return apperror.Internal("delete attachments", err)
+	}
+	return nil
+}
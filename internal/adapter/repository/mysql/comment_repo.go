@@ -2,6 +2,10 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/shalfey088/team-task-nexus/internal/domain"
@@ -19,8 +23,9 @@ func NewCommentRepo(db *sqlx.DB) *CommentRepo {
 func (r *CommentRepo) Create(ctx context.Context, comment *domain.TaskComment) (int64, error) {
 	q := getQuerier(ctx, r.db)
 	result, err := q.ExecContext(ctx,
-		"INSERT INTO task_comments (task_id, user_id, content) VALUES (?, ?, ?)",
-		comment.TaskID, comment.UserID, comment.Content,
+		`INSERT INTO task_comments (task_id, user_id, parent_id, root_id, depth, internal, content)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		comment.TaskID, comment.UserID, comment.ParentID, comment.RootID, comment.Depth, comment.Internal, comment.Content,
 	)
 	if err != nil {
 		return 0, apperror.Internal("create comment", err)
@@ -28,6 +33,19 @@ func (r *CommentRepo) Create(ctx context.Context, comment *domain.TaskComment) (
 	return result.LastInsertId()
 }
 
+func (r *CommentRepo) GetByID(ctx context.Context, id int64) (*domain.TaskComment, error) {
+	q := getQuerier(ctx, r.db)
+	var comment domain.TaskComment
+	err := q.GetContext(ctx, &comment, "SELECT * FROM task_comments WHERE id = ?", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("comment not found")
+		}
+		return nil, apperror.Internal("get comment", err)
+	}
+	return &comment, nil
+}
+
 func (r *CommentRepo) ListByTaskID(ctx context.Context, taskID int64) ([]domain.TaskComment, error) {
 	q := getQuerier(ctx, r.db)
 	var comments []domain.TaskComment
@@ -40,3 +58,137 @@ func (r *CommentRepo) ListByTaskID(ctx context.Context, taskID int64) ([]domain.
 	}
 	return comments, nil
 }
+
+// ListPage returns up to filter.Limit comments in ascending (created_at, id)
+// order together with the number of comments matching the filter, ignoring
+// the cursor.
+func (r *CommentRepo) ListPage(ctx context.Context, filter domain.CommentFilter) ([]domain.TaskComment, int, error) {
+	q := getQuerier(ctx, r.db)
+
+	conditions := []string{"task_id = ?"}
+	args := []interface{}{filter.TaskID}
+	if filter.Since != nil {
+		conditions = append(conditions, "updated_at >= ?")
+		args = append(args, *filter.Since)
+	}
+	if filter.ExcludeInternal {
+		conditions = append(conditions, "internal = FALSE")
+	}
+
+	var total int
+	err := q.GetContext(ctx, &total,
+		"SELECT COUNT(*) FROM task_comments WHERE "+strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		return nil, 0, apperror.Internal("count comments", err)
+	}
+
+	order := "ASC"
+	if c := filter.Cursor; c != nil {
+		if c.Before {
+			conditions = append(conditions, "(created_at < ? OR (created_at = ? AND id < ?))")
+			order = "DESC"
+		} else {
+			conditions = append(conditions, "(created_at > ? OR (created_at = ? AND id > ?))")
+		}
+		args = append(args, c.CreatedAt, c.CreatedAt, c.ID)
+	}
+	args = append(args, filter.Limit)
+
+	var comments []domain.TaskComment
+	err = q.SelectContext(ctx, &comments, fmt.Sprintf(
+		"SELECT * FROM task_comments WHERE %s ORDER BY created_at %s, id %s LIMIT ?",
+		strings.Join(conditions, " AND "), order, order,
+	), args...)
+	if err != nil {
+		return nil, 0, apperror.Internal("list comments", err)
+	}
+
+	if order == "DESC" {
+		for i, j := 0, len(comments)-1; i < j; i, j = i+1, j-1 {
+			comments[i], comments[j] = comments[j], comments[i]
+		}
+	}
+	return comments, total, nil
+}
+
+func (r *CommentRepo) UpdateContent(ctx context.Context, id int64, content string) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE task_comments SET content = ?, edited_at = NOW() WHERE id = ?",
+		content, id,
+	)
+	if err != nil {
+		return apperror.Internal("update comment", err)
+	}
+	return nil
+}
+
+func (r *CommentRepo) SoftDelete(ctx context.Context, id, deletedBy int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE task_comments SET deleted_at = NOW(), deleted_by = ? WHERE id = ? AND deleted_at IS NULL",
+		deletedBy, id,
+	)
+	if err != nil {
+		return apperror.Internal("delete comment", err)
+	}
+	return nil
+}
+
+func (r *CommentRepo) SetResolved(ctx context.Context, id int64, resolvedBy *int64) error {
+	q := getQuerier(ctx, r.db)
+	var err error
+	if resolvedBy != nil {
+		_, err = q.ExecContext(ctx,
+			"UPDATE task_comments SET resolved_at = NOW(), resolved_by = ? WHERE id = ?",
+			*resolvedBy, id,
+		)
+	} else {
+		_, err = q.ExecContext(ctx,
+			"UPDATE task_comments SET resolved_at = NULL, resolved_by = NULL WHERE id = ?",
+			id,
+		)
+	}
+	if err != nil {
+		return apperror.Internal("update comment thread state", err)
+	}
+	return nil
+}
+
+func (r *CommentRepo) ListThreadParticipants(ctx context.Context, rootID int64) ([]int64, error) {
+	q := getQuerier(ctx, r.db)
+	var userIDs []int64
+	err := q.SelectContext(ctx, &userIDs,
+		"SELECT DISTINCT user_id FROM task_comments WHERE id = ? OR root_id = ?",
+		rootID, rootID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list thread participants", err)
+	}
+	return userIDs, nil
+}
+
+func (r *CommentRepo) CreateRevision(ctx context.Context, rev *domain.CommentRevision) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"INSERT INTO task_comment_revisions (comment_id, content, edited_by) VALUES (?, ?, ?)",
+		rev.CommentID, rev.Content, rev.EditedBy,
+	)
+	if err != nil {
+		return apperror.Internal("create comment revision", err)
+	}
+	return nil
+}
+
+func (r *CommentRepo) ListRevisions(ctx context.Context, commentID int64) ([]domain.CommentRevision, error) {
+	q := getQuerier(ctx, r.db)
+	var revisions []domain.CommentRevision
+	err := q.SelectContext(ctx, &revisions,
+		"SELECT * FROM task_comment_revisions WHERE comment_id = ? ORDER BY created_at DESC, id DESC",
+		commentID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list comment revisions", err)
+	}
+	return revisions, nil
+}
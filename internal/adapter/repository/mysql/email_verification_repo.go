@@ -0,0 +1,55 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type EmailVerificationRepo struct {
+	db *sqlx.DB
+}
+
+func NewEmailVerificationRepo(db *sqlx.DB) *EmailVerificationRepo {
+	return &EmailVerificationRepo{db: db}
+}
+
+func (r *EmailVerificationRepo) Create(ctx context.Context, token *domain.EmailVerificationToken) (int64, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		"INSERT INTO email_verification_tokens (user_id, token_hash, expires_at) VALUES (?, ?, ?)",
+		token.UserID, token.TokenHash, token.ExpiresAt,
+	)
+	if err != nil {
+		return 0, apperror.Internal("create email verification token", err)
+	}
+	return result.LastInsertId()
+}
+
+func (r *EmailVerificationRepo) GetByHashForUpdate(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error) {
+	q := getQuerier(ctx, r.db)
+	var token domain.EmailVerificationToken
+	err := q.GetContext(ctx, &token, "SELECT * FROM email_verification_tokens WHERE token_hash = ? FOR UPDATE", tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("email verification token not found")
+		}
+		return nil, apperror.Internal("get email verification token", err)
+	}
+	return &token, nil
+}
+
+// InvalidateForUser marks every unused token of the user as used.
+func (r *EmailVerificationRepo) InvalidateForUser(ctx context.Context, userID int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE email_verification_tokens SET used_at = NOW() WHERE user_id = ? AND used_at IS NULL", userID)
+	if err != nil {
+		return apperror.Internal("invalidate email verification tokens", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type InvitationRepo struct {
+	db *sqlx.DB
+}
+
+func NewInvitationRepo(db *sqlx.DB) *InvitationRepo {
+	return &InvitationRepo{db: db}
+}
+
+func (r *InvitationRepo) Create(ctx context.Context, invitation *domain.Invitation) (int64, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		`INSERT INTO team_invitations (team_id, email, role, inviter_id, token_hash, expires_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		invitation.TeamID, invitation.Email, invitation.Role, invitation.InviterID,
+		invitation.TokenHash, invitation.ExpiresAt,
+	)
+	if err != nil {
+		return 0, apperror.Internal("create invitation", err)
+	}
+	return result.LastInsertId()
+}
+
+func (r *InvitationRepo) GetByID(ctx context.Context, id int64) (*domain.Invitation, error) {
+	return r.get(ctx, "SELECT * FROM team_invitations WHERE id = ?", id)
+}
+
+func (r *InvitationRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	return r.get(ctx, "SELECT * FROM team_invitations WHERE token_hash = ?", tokenHash)
+}
+
+// GetPendingByEmail returns nil when the email has no unexpired pending
+// invitation to the team.
+func (r *InvitationRepo) GetPendingByEmail(ctx context.Context, teamID int64, email string) (*domain.Invitation, error) {
+	q := getQuerier(ctx, r.db)
+	var invitation domain.Invitation
+	err := q.GetContext(ctx, &invitation,
+		`SELECT * FROM team_invitations
+		 WHERE team_id = ? AND email = ? AND status = 'pending' AND expires_at > NOW()
+		 ORDER BY id DESC LIMIT 1`,
+		teamID, email,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, apperror.Internal("get pending invitation", err)
+	}
+	return &invitation, nil
+}
+
+func (r *InvitationRepo) ListPending(ctx context.Context, teamID int64) ([]domain.Invitation, error) {
+	q := getQuerier(ctx, r.db)
+	var invitations []domain.Invitation
+	err := q.SelectContext(ctx, &invitations,
+		`SELECT * FROM team_invitations
+		 WHERE team_id = ? AND status = 'pending' AND expires_at > NOW()
+		 ORDER BY created_at DESC, id DESC`,
+		teamID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list invitations", err)
+	}
+	return invitations, nil
+}
+
+// Resolve moves a pending invitation to its final status and reports whether
+// it was still pending, so concurrent accepts cannot both succeed.
+func (r *InvitationRepo) Resolve(ctx context.Context, id int64, status domain.InvitationStatus, acceptedBy *int64) (bool, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		`UPDATE team_invitations SET status = ?, accepted_by = ?, resolved_at = NOW()
+		 WHERE id = ? AND status = 'pending'`,
+		status, acceptedBy, id,
+	)
+	if err != nil {
+		return false, apperror.Internal("resolve invitation", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, apperror.Internal("resolve invitation", err)
+	}
+	return n > 0, nil
+}
+
+func (r *InvitationRepo) Rotate(ctx context.Context, id int64, tokenHash string, expiresAt time.Time) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE team_invitations SET token_hash = ?, expires_at = ? WHERE id = ?",
+		tokenHash, expiresAt, id,
+	)
+	if err != nil {
+		return apperror.Internal("rotate invitation token", err)
+	}
+	return nil
+}
+
+func (r *InvitationRepo) get(ctx context.Context, query string, args ...interface{}) (*domain.Invitation, error) {
+	q := getQuerier(ctx, r.db)
+	var invitation domain.Invitation
+	if err := q.GetContext(ctx, &invitation, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("invitation not found")
+		}
+		return nil, apperror.Internal("get invitation", err)
+	}
+	return &invitation, nil
+}
@@ -0,0 +1,127 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type JoinLinkRepo struct {
+	db *sqlx.DB
+}
+
+func NewJoinLinkRepo(db *sqlx.DB) *JoinLinkRepo {
+	return &JoinLinkRepo{db: db}
+}
+
+func (r *JoinLinkRepo) Create(ctx context.Context, link *domain.JoinLink) (int64, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		`INSERT INTO team_join_links (team_id, code, role, creator_id, max_uses, allowed_domain, expires_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		link.TeamID, link.Code, link.Role, link.CreatorID, link.MaxUses, link.AllowedDomain, link.ExpiresAt,
+	)
+	if err != nil {
+		return 0, apperror.Internal("create join link", err)
+	}
+	return result.LastInsertId()
+}
+
+func (r *JoinLinkRepo) GetByID(ctx context.Context, id int64) (*domain.JoinLink, error) {
+	return r.get(ctx, "SELECT * FROM team_join_links WHERE id = ?", id)
+}
+
+func (r *JoinLinkRepo) GetByCode(ctx context.Context, code string) (*domain.JoinLink, error) {
+	return r.get(ctx, "SELECT * FROM team_join_links WHERE code = ?", code)
+}
+
+func (r *JoinLinkRepo) ListByTeam(ctx context.Context, teamID int64) ([]domain.JoinLink, error) {
+	q := getQuerier(ctx, r.db)
+	var links []domain.JoinLink
+	err := q.SelectContext(ctx, &links,
+		"SELECT * FROM team_join_links WHERE team_id = ? ORDER BY created_at DESC, id DESC",
+		teamID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list join links", err)
+	}
+	return links, nil
+}
+
+func (r *JoinLinkRepo) Revoke(ctx context.Context, id int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE team_join_links SET revoked_at = NOW() WHERE id = ? AND revoked_at IS NULL",
+		id,
+	)
+	if err != nil {
+		return apperror.Internal("revoke join link", err)
+	}
+	return nil
+}
+
+// IncrementUses claims one use of the link and reports false when the link
+// was revoked, expired or used up in the meantime.
+func (r *JoinLinkRepo) IncrementUses(ctx context.Context, id int64) (bool, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		`UPDATE team_join_links SET use_count = use_count + 1
+		 WHERE id = ? AND revoked_at IS NULL
+		   AND (expires_at IS NULL OR expires_at > NOW())
+		   AND (max_uses IS NULL OR use_count < max_uses)`,
+		id,
+	)
+	if err != nil {
+		return false, apperror.Internal("claim join link", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, apperror.Internal("claim join link", err)
+	}
+	return n > 0, nil
+}
+
+func (r *JoinLinkRepo) RecordUse(ctx context.Context, linkID, userID int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"INSERT INTO team_join_link_uses (link_id, user_id) VALUES (?, ?)",
+		linkID, userID,
+	)
+	if err != nil {
+		return apperror.Internal("record join link use", err)
+	}
+	return nil
+}
+
+func (r *JoinLinkRepo) ListUses(ctx context.Context, linkID int64) ([]domain.JoinLinkUse, error) {
+	q := getQuerier(ctx, r.db)
+	var uses []domain.JoinLinkUse
+	err := q.SelectContext(ctx, &uses,
+		`SELECT lu.id, lu.link_id, lu.user_id, u.email, u.full_name, lu.used_at
+		 FROM team_join_link_uses lu
+		 JOIN users u ON u.id = lu.user_id
+		 WHERE lu.link_id = ?
+		 ORDER BY lu.used_at DESC, lu.id DESC`,
+		linkID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list join link uses", err)
+	}
+	return uses, nil
+}
+
+func (r *JoinLinkRepo) get(ctx context.Context, query string, args ...interface{}) (*domain.JoinLink, error) {
+	q := getQuerier(ctx, r.db)
+	var link domain.JoinLink
+	if err := q.GetContext(ctx, &link, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("join link not found")
+		}
+		return nil, apperror.Internal("get join link", err)
+	}
+	return &link, nil
+}
@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type MentionRepo struct {
+	db *sqlx.DB
+}
+
+func NewMentionRepo(db *sqlx.DB) *MentionRepo {
+	return &MentionRepo{db: db}
+}
+
+func (r *MentionRepo) CreateBatch(ctx context.Context, mentions []domain.Mention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	q := getQuerier(ctx, r.db)
+	placeholders := make([]string, 0, len(mentions))
+	args := make([]interface{}, 0, len(mentions)*6)
+	for _, m := range mentions {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?)")
+		args = append(args, m.UserID, m.AuthorID, m.TeamID, m.TaskID, m.CommentID, m.Source)
+	}
+
+	_, err := q.ExecContext(ctx,
+		`INSERT INTO mentions (user_id, author_id, team_id, task_id, comment_id, source)
+		 VALUES `+strings.Join(placeholders, ", "),
+		args...,
+	)
+	if err != nil {
+		return apperror.Internal("create mentions", err)
+	}
+	return nil
+}
+
+func (r *MentionRepo) ListByUser(ctx context.Context, access domain.TaskAccess, filter domain.MentionFilter) ([]domain.Mention, int, error) {
+	q := getQuerier(ctx, r.db)
+
+	// Mentions from teams the user can no longer read, on tasks a scoped
+	// guest no longer has access to, or in comments that were deleted are
+	// not shown.
+	cond, args := taskAccessCondition("m.team_id", "m.task_id", access)
+	from := `
+		FROM mentions m
+		JOIN users u ON u.id = m.author_id
+		JOIN tasks t ON t.id = m.task_id
+		LEFT JOIN task_comments c ON c.id = m.comment_id
+		WHERE m.user_id = ?
+		  AND ` + cond + `
+		  AND c.deleted_at IS NULL`
+	args = append([]interface{}{access.UserID}, args...)
+
+	var total int
+	if err := q.GetContext(ctx, &total, "SELECT COUNT(*)"+from, args...); err != nil {
+		return nil, 0, apperror.Internal("count mentions", err)
+	}
+
+	var mentions []domain.Mention
+	err := q.SelectContext(ctx, &mentions,
+		`SELECT m.*, u.full_name AS author_name, t.title AS task_title`+from+`
+		 ORDER BY m.created_at DESC, m.id DESC
+		 LIMIT ? OFFSET ?`,
+		append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)...,
+	)
+	if err != nil {
+		return nil, 0, apperror.Internal("list mentions", err)
+	}
+	return mentions, total, nil
+}
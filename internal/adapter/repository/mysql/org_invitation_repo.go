@@ -0,0 +1,105 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type OrgInvitationRepo struct {
+	db *sqlx.DB
+}
+
+func NewOrgInvitationRepo(db *sqlx.DB) *OrgInvitationRepo {
+	return &OrgInvitationRepo{db: db}
+}
+
+func (r *OrgInvitationRepo) Create(ctx context.Context, invitation *domain.OrgInvitation) (int64, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		`INSERT INTO org_invitations (org_id, email, role, inviter_id, token_hash, expires_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		invitation.OrgID, invitation.Email, invitation.Role, invitation.InviterID,
+		invitation.TokenHash, invitation.ExpiresAt,
+	)
+	if err != nil {
+		return 0, apperror.Internal("create organization invitation", err)
+	}
+	return result.LastInsertId()
+}
+
+func (r *OrgInvitationRepo) GetByID(ctx context.Context, id int64) (*domain.OrgInvitation, error) {
+	return r.get(ctx, "SELECT * FROM org_invitations WHERE id = ?", id)
+}
+
+func (r *OrgInvitationRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.OrgInvitation, error) {
+	return r.get(ctx, "SELECT * FROM org_invitations WHERE token_hash = ?", tokenHash)
+}
+
+// GetPendingByEmail returns nil when the email has no unexpired pending
+// invitation to the organization.
+func (r *OrgInvitationRepo) GetPendingByEmail(ctx context.Context, orgID int64, email string) (*domain.OrgInvitation, error) {
+	q := getQuerier(ctx, r.db)
+	var invitation domain.OrgInvitation
+	err := q.GetContext(ctx, &invitation,
+		`SELECT * FROM org_invitations
+		 WHERE org_id = ? AND email = ? AND status = 'pending' AND expires_at > NOW()
+		 ORDER BY id DESC LIMIT 1`,
+		orgID, email,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, apperror.Internal("get pending organization invitation", err)
+	}
+	return &invitation, nil
+}
+
+// Resolve moves a pending invitation to its final status and reports whether
+// it was still pending, so concurrent accepts cannot both succeed.
+func (r *OrgInvitationRepo) Resolve(ctx context.Context, id int64, status domain.InvitationStatus, acceptedBy *int64) (bool, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		`UPDATE org_invitations SET status = ?, accepted_by = ?, resolved_at = NOW()
+		 WHERE id = ? AND status = 'pending'`,
+		status, acceptedBy, id,
+	)
+	if err != nil {
+		return false, apperror.Internal("resolve organization invitation", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, apperror.Internal("resolve organization invitation", err)
+	}
+	return n > 0, nil
+}
+
+func (r *OrgInvitationRepo) Rotate(ctx context.Context, id int64, tokenHash string, expiresAt time.Time) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE org_invitations SET token_hash = ?, expires_at = ? WHERE id = ?",
+		tokenHash, expiresAt, id,
+	)
+	if err != nil {
+		return apperror.Internal("rotate organization invitation token", err)
+	}
+	return nil
+}
+
+func (r *OrgInvitationRepo) get(ctx context.Context, query string, args ...interface{}) (*domain.OrgInvitation, error) {
+	q := getQuerier(ctx, r.db)
+	var invitation domain.OrgInvitation
+	if err := q.GetContext(ctx, &invitation, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("invitation not found")
+		}
+		return nil, apperror.Internal("get organization invitation", err)
+	}
+	return &invitation, nil
+}
@@ -0,0 +1,232 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type OrganizationRepo struct {
+	db *sqlx.DB
+}
+
+func NewOrganizationRepo(db *sqlx.DB) *OrganizationRepo {
+	return &OrganizationRepo{db: db}
+}
+
+func (r *OrganizationRepo) Create(ctx context.Context, org *domain.Organization) (int64, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		"INSERT INTO organizations (name, personal, created_by) VALUES (?, ?, ?)",
+		org.Name, org.Personal, org.CreatedBy,
+	)
+	if err != nil {
+		return 0, apperror.Internal("create organization", err)
+	}
+	return result.LastInsertId()
+}
+
+func (r *OrganizationRepo) GetByID(ctx context.Context, id int64) (*domain.Organization, error) {
+	q := getQuerier(ctx, r.db)
+	var org domain.Organization
+	err := q.GetContext(ctx, &org, "SELECT * FROM organizations WHERE id = ?", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("organization not found")
+		}
+		return nil, apperror.Internal("get organization", err)
+	}
+	return &org, nil
+}
+
+func (r *OrganizationRepo) GetPersonal(ctx context.Context, userID int64) (*domain.Organization, error) {
+	q := getQuerier(ctx, r.db)
+	var org domain.Organization
+	err := q.GetContext(ctx, &org,
+		"SELECT * FROM organizations WHERE created_by = ? AND personal = TRUE ORDER BY id LIMIT 1",
+		userID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, apperror.Internal("get personal organization", err)
+	}
+	return &org, nil
+}
+
+func (r *OrganizationRepo) ListByUserID(ctx context.Context, userID int64) ([]domain.Organization, error) {
+	q := getQuerier(ctx, r.db)
+	var orgs []domain.Organization
+	err := q.SelectContext(ctx, &orgs,
+		`SELECT o.* FROM organizations o
+		 JOIN organization_members om ON om.org_id = o.id
+		 WHERE om.user_id = ?
+		 ORDER BY o.personal DESC, o.name, o.id`, userID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list organizations", err)
+	}
+	return orgs, nil
+}
+
+func (r *OrganizationRepo) Update(ctx context.Context, org *domain.Organization) error {
+	q := getQuerier(ctx, r.db)
+	if _, err := q.ExecContext(ctx, "UPDATE organizations SET name = ? WHERE id = ?", org.Name, org.ID); err != nil {
+		return apperror.Internal("update organization", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepo) AddMember(ctx context.Context, member *domain.OrgMember) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"INSERT INTO organization_members (org_id, user_id, role) VALUES (?, ?, ?)",
+		member.OrgID, member.UserID, member.Role,
+	)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			return apperror.New(409, "user is already a member of this organization")
+		}
+		return apperror.Internal("add organization member", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepo) GetMember(ctx context.Context, orgID, userID int64) (*domain.OrgMember, error) {
+	q := getQuerier(ctx, r.db)
+	var member domain.OrgMember
+	err := q.GetContext(ctx, &member,
+		"SELECT * FROM organization_members WHERE org_id = ? AND user_id = ?",
+		orgID, userID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, apperror.Internal("get organization member", err)
+	}
+	return &member, nil
+}
+
+func (r *OrganizationRepo) ListMembers(ctx context.Context, orgID int64) ([]domain.OrgMemberDetails, error) {
+	q := getQuerier(ctx, r.db)
+	var members []domain.OrgMemberDetails
+	err := q.SelectContext(ctx, &members,
+		`SELECT om.org_id, om.user_id, om.role, u.email, u.full_name, om.joined_at
+		 FROM organization_members om
+		 JOIN users u ON u.id = om.user_id
+		 WHERE om.org_id = ?
+		 ORDER BY u.full_name, u.id`, orgID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list organization members", err)
+	}
+	return members, nil
+}
+
+// SearchMembers matches the query as a prefix of the member's email or
+// full name.
+func (r *OrganizationRepo) SearchMembers(ctx context.Context, orgID int64, query string, limit int) ([]domain.OrgMemberDetails, error) {
+	q := getQuerier(ctx, r.db)
+	pattern := escapeLike(query) + "%"
+	var members []domain.OrgMemberDetails
+	err := q.SelectContext(ctx, &members,
+		`SELECT om.org_id, om.user_id, om.role, u.email, u.full_name, om.joined_at
+		 FROM organization_members om
+		 JOIN users u ON u.id = om.user_id
+		 WHERE om.org_id = ? AND (u.email LIKE ? OR u.full_name LIKE ?)
+		 ORDER BY u.full_name, u.id
+		 LIMIT ?`, orgID, pattern, pattern, limit,
+	)
+	if err != nil {
+		return nil, apperror.Internal("search organization members", err)
+	}
+	return members, nil
+}
+
+func (r *OrganizationRepo) UpdateMemberRole(ctx context.Context, orgID, userID int64, role domain.OrgRole) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE organization_members SET role = ? WHERE org_id = ? AND user_id = ?",
+		role, orgID, userID,
+	)
+	if err != nil {
+		return apperror.Internal("update organization member role", err)
+	}
+	return nil
+}
+
+// RemoveMember also drops the user from every team of the organization.
+func (r *OrganizationRepo) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		`DELETE tm FROM team_members tm
+		 JOIN teams t ON t.id = tm.team_id
+		 WHERE t.org_id = ? AND tm.user_id = ?`,
+		orgID, userID,
+	)
+	if err != nil {
+		return apperror.Internal("remove organization member", err)
+	}
+	_, err = q.ExecContext(ctx,
+		"DELETE FROM organization_members WHERE org_id = ? AND user_id = ?",
+		orgID, userID,
+	)
+	if err != nil {
+		return apperror.Internal("remove organization member", err)
+	}
+	return nil
+}
+
+// CountMembersByRole locks the matching rows, see TeamRepo.CountMembersByRole.
+func (r *OrganizationRepo) CountMembersByRole(ctx context.Context, orgID int64, role domain.OrgRole) (int, error) {
+	q := getQuerier(ctx, r.db)
+	var ids []int64
+	err := q.SelectContext(ctx, &ids,
+		"SELECT user_id FROM organization_members WHERE org_id = ? AND role = ? FOR UPDATE",
+		orgID, role,
+	)
+	if err != nil {
+		return 0, apperror.Internal("count organization members", err)
+	}
+	return len(ids), nil
+}
+
+func (r *OrganizationRepo) CountOwnedTeams(ctx context.Context, orgID, userID int64) (int, error) {
+	q := getQuerier(ctx, r.db)
+	var count int
+	err := q.GetContext(ctx, &count,
+		"SELECT COUNT(*) FROM teams WHERE org_id = ? AND owner_id = ?",
+		orgID, userID,
+	)
+	if err != nil {
+		return 0, apperror.Internal("count owned teams", err)
+	}
+	return count, nil
+}
+
+func (r *OrganizationRepo) ListTeams(ctx context.Context, orgID int64) ([]domain.Team, error) {
+	q := getQuerier(ctx, r.db)
+	var teams []domain.Team
+	err := q.SelectContext(ctx, &teams,
+		"SELECT * FROM teams WHERE org_id = ? ORDER BY created_at DESC, id DESC", orgID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list organization teams", err)
+	}
+	return teams, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
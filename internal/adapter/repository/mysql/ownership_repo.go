@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type OwnershipTransferRepo struct {
+	db *sqlx.DB
+}
+
+func NewOwnershipTransferRepo(db *sqlx.DB) *OwnershipTransferRepo {
+	return &OwnershipTransferRepo{db: db}
+}
+
+func (r *OwnershipTransferRepo) Create(ctx context.Context, transfer *domain.OwnershipTransfer) (int64, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		`INSERT INTO team_ownership_transfers (team_id, from_user_id, to_user_id, status, expires_at)
+		 VALUES (?, ?, ?, ?, ?)`,
+		transfer.TeamID, transfer.FromUserID, transfer.ToUserID, domain.TransferPending, transfer.ExpiresAt,
+	)
+	if err != nil {
+		return 0, apperror.Internal("create ownership transfer", err)
+	}
+	return result.LastInsertId()
+}
+
+func (r *OwnershipTransferRepo) GetByID(ctx context.Context, id int64) (*domain.OwnershipTransfer, error) {
+	q := getQuerier(ctx, r.db)
+	var transfer domain.OwnershipTransfer
+	err := q.GetContext(ctx, &transfer, "SELECT * FROM team_ownership_transfers WHERE id = ?", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("ownership transfer not found")
+		}
+		return nil, apperror.Internal("get ownership transfer", err)
+	}
+	return &transfer, nil
+}
+
+// GetPending returns nil when the team has no open nomination. The row is
+// locked so concurrent accept and cancel calls are serialized.
+func (r *OwnershipTransferRepo) GetPending(ctx context.Context, teamID int64) (*domain.OwnershipTransfer, error) {
+	q := getQuerier(ctx, r.db)
+	var transfer domain.OwnershipTransfer
+	err := q.GetContext(ctx, &transfer,
+		`SELECT * FROM team_ownership_transfers
+		 WHERE team_id = ? AND status = ?
+		 ORDER BY id DESC LIMIT 1 FOR UPDATE`,
+		teamID, domain.TransferPending,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, apperror.Internal("get pending ownership transfer", err)
+	}
+	return &transfer, nil
+}
+
+func (r *OwnershipTransferRepo) Resolve(ctx context.Context, id int64, status domain.TransferStatus) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE team_ownership_transfers SET status = ?, resolved_at = NOW() WHERE id = ? AND status = ?",
+		status, id, domain.TransferPending,
+	)
+	if err != nil {
+		return apperror.Internal("resolve ownership transfer", err)
+	}
+	return nil
+}
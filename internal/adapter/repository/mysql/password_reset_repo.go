@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type PasswordResetRepo struct {
+	db *sqlx.DB
+}
+
+func NewPasswordResetRepo(db *sqlx.DB) *PasswordResetRepo {
+	return &PasswordResetRepo{db: db}
+}
+
+func (r *PasswordResetRepo) Create(ctx context.Context, token *domain.PasswordResetToken) (int64, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		"INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES (?, ?, ?)",
+		token.UserID, token.TokenHash, token.ExpiresAt,
+	)
+	if err != nil {
+		return 0, apperror.Internal("create password reset token", err)
+	}
+	return result.LastInsertId()
+}
+
+// GetByHashForUpdate locks the token row so it cannot be used twice by
+// concurrent requests.
+func (r *PasswordResetRepo) GetByHashForUpdate(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	q := getQuerier(ctx, r.db)
+	var token domain.PasswordResetToken
+	err := q.GetContext(ctx, &token, "SELECT * FROM password_reset_tokens WHERE token_hash = ? FOR UPDATE", tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("password reset token not found")
+		}
+		return nil, apperror.Internal("get password reset token", err)
+	}
+	return &token, nil
+}
+
+// InvalidateForUser marks every unused token of the user as used.
+func (r *PasswordResetRepo) InvalidateForUser(ctx context.Context, userID int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = ? AND used_at IS NULL", userID)
+	if err != nil {
+		return apperror.Internal("invalidate password reset tokens", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type PersonalTokenRepo struct {
+	db *sqlx.DB
+}
+
+func NewPersonalTokenRepo(db *sqlx.DB) *PersonalTokenRepo {
+	return &PersonalTokenRepo{db: db}
+}
+
+// personalTokenRow stores scopes as a comma-separated list.
+type personalTokenRow struct {
+	ID         int64      `db:"id"`
+	UserID     int64      `db:"user_id"`
+	TeamID     *int64     `db:"team_id"`
+	Name       string     `db:"name"`
+	TokenHash  string     `db:"token_hash"`
+	Scopes     string     `db:"scopes"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+func (row personalTokenRow) toDomain() domain.PersonalAccessToken {
+	return domain.PersonalAccessToken{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		TeamID:     row.TeamID,
+		Name:       row.Name,
+		TokenHash:  row.TokenHash,
+		Scopes:     domain.ParseScopes(row.Scopes),
+		ExpiresAt:  row.ExpiresAt,
+		LastUsedAt: row.LastUsedAt,
+		RevokedAt:  row.RevokedAt,
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+func (r *PersonalTokenRepo) Create(ctx context.Context, token *domain.PersonalAccessToken) (int64, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		"INSERT INTO personal_access_tokens (user_id, team_id, name, token_hash, scopes, expires_at) VALUES (?, ?, ?, ?, ?, ?)",
+		token.UserID, token.TeamID, token.Name, token.TokenHash, domain.JoinScopes(token.Scopes), token.ExpiresAt,
+	)
+	if err != nil {
+		return 0, apperror.Internal("create personal access token", err)
+	}
+	return result.LastInsertId()
+}
+
+func (r *PersonalTokenRepo) GetByID(ctx context.Context, id int64) (*domain.PersonalAccessToken, error) {
+	return r.get(ctx, "SELECT * FROM personal_access_tokens WHERE id = ?", id)
+}
+
+func (r *PersonalTokenRepo) GetByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	return r.get(ctx, "SELECT * FROM personal_access_tokens WHERE token_hash = ?", tokenHash)
+}
+
+func (r *PersonalTokenRepo) get(ctx context.Context, query string, arg interface{}) (*domain.PersonalAccessToken, error) {
+	q := getQuerier(ctx, r.db)
+	var row personalTokenRow
+	if err := q.GetContext(ctx, &row, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("personal access token not found")
+		}
+		return nil, apperror.Internal("get personal access token", err)
+	}
+	token := row.toDomain()
+	return &token, nil
+}
+
+// ListByUser returns the user's tokens that were not revoked, newest first.
+func (r *PersonalTokenRepo) ListByUser(ctx context.Context, userID int64) ([]domain.PersonalAccessToken, error) {
+	q := getQuerier(ctx, r.db)
+	var rows []personalTokenRow
+	err := q.SelectContext(ctx, &rows,
+		"SELECT * FROM personal_access_tokens WHERE user_id = ? AND revoked_at IS NULL ORDER BY created_at DESC, id DESC",
+		userID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list personal access tokens", err)
+	}
+	tokens := make([]domain.PersonalAccessToken, 0, len(rows))
+	for _, row := range rows {
+		tokens = append(tokens, row.toDomain())
+	}
+	return tokens, nil
+}
+
+func (r *PersonalTokenRepo) Revoke(ctx context.Context, id int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE personal_access_tokens SET revoked_at = NOW() WHERE id = ? AND revoked_at IS NULL", id)
+	if err != nil {
+		return apperror.Internal("revoke personal access token", err)
+	}
+	return nil
+}
+
+func (r *PersonalTokenRepo) TouchLastUsed(ctx context.Context, id int64) error {
+	q := getQuerier(ctx, r.db)
+	if _, err := q.ExecContext(ctx, "UPDATE personal_access_tokens SET last_used_at = NOW() WHERE id = ?", id); err != nil {
+		return apperror.Internal("update personal access token", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type reactionTable struct {
+	name   string
+	column string
+}
+
+var reactionTables = map[domain.ReactionTarget]reactionTable{
+	domain.ReactionTargetTask:    {name: "task_reactions", column: "task_id"},
+	domain.ReactionTargetComment: {name: "comment_reactions", column: "comment_id"},
+}
+
+type ReactionRepo struct {
+	db *sqlx.DB
+}
+
+func NewReactionRepo(db *sqlx.DB) *ReactionRepo {
+	return &ReactionRepo{db: db}
+}
+
+func (r *ReactionRepo) Add(ctx context.Context, target domain.ReactionTarget, targetID, userID int64, emoji string) error {
+	t, err := tableFor(target)
+	if err != nil {
+		return err
+	}
+
+	q := getQuerier(ctx, r.db)
+	_, err = q.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO %s (%s, user_id, emoji) VALUES (?, ?, ?)", t.name, t.column),
+		targetID, userID, emoji,
+	)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			return nil
+		}
+		return apperror.Internal("add reaction", err)
+	}
+	return nil
+}
+
+func (r *ReactionRepo) Remove(ctx context.Context, target domain.ReactionTarget, targetID, userID int64, emoji string) (bool, error) {
+	t, err := tableFor(target)
+	if err != nil {
+		return false, err
+	}
+
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE %s = ? AND user_id = ? AND emoji = ?", t.name, t.column),
+		targetID, userID, emoji,
+	)
+	if err != nil {
+		return false, apperror.Internal("remove reaction", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, apperror.Internal("remove reaction", err)
+	}
+	return affected > 0, nil
+}
+
+func (r *ReactionRepo) Summaries(ctx context.Context, target domain.ReactionTarget, targetIDs []int64, userID int64) (map[int64][]domain.ReactionSummary, error) {
+	result := make(map[int64][]domain.ReactionSummary)
+	if len(targetIDs) == 0 {
+		return result, nil
+	}
+
+	t, err := tableFor(target)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := sqlx.In(fmt.Sprintf(
+		`SELECT %[2]s AS target_id, emoji, COUNT(*) AS count, MAX(user_id = ?) AS reacted_by_me
+		 FROM %[1]s
+		 WHERE %[2]s IN (?)
+		 GROUP BY %[2]s, emoji
+		 ORDER BY MIN(created_at), emoji`, t.name, t.column),
+		userID, targetIDs,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list reactions", err)
+	}
+
+	var rows []struct {
+		TargetID int64 `db:"target_id"`
+		domain.ReactionSummary
+	}
+	q := getQuerier(ctx, r.db)
+	if err := q.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, apperror.Internal("list reactions", err)
+	}
+
+	for _, row := range rows {
+		result[row.TargetID] = append(result[row.TargetID], row.ReactionSummary)
+	}
+	return result, nil
+}
+
+func tableFor(target domain.ReactionTarget) (reactionTable, error) {
+	t, ok := reactionTables[target]
+	if !ok {
+		return reactionTable{}, apperror.BadRequest("unknown reaction target")
+	}
+	return t, nil
+}
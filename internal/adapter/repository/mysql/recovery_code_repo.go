@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type RecoveryCodeRepo struct {
+	db *sqlx.DB
+}
+
+func NewRecoveryCodeRepo(db *sqlx.DB) *RecoveryCodeRepo {
+	return &RecoveryCodeRepo{db: db}
+}
+
+// Replace drops the user's codes, used or not, and stores a new set.
+func (r *RecoveryCodeRepo) Replace(ctx context.Context, userID int64, codeHashes []string) error {
+	if err := r.DeleteForUser(ctx, userID); err != nil {
+		return err
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	q := getQuerier(ctx, r.db)
+	args := make([]interface{}, 0, len(codeHashes)*2)
+	for _, h := range codeHashes {
+		args = append(args, userID, h)
+	}
+	_, err := q.ExecContext(ctx,
+		"INSERT INTO recovery_codes (user_id, code_hash) VALUES (?, ?)"+strings.Repeat(", (?, ?)", len(codeHashes)-1),
+		args...,
+	)
+	if err != nil {
+		return apperror.Internal("create recovery codes", err)
+	}
+	return nil
+}
+
+// Use marks an unused code as used and reports whether there was one.
+func (r *RecoveryCodeRepo) Use(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		"UPDATE recovery_codes SET used_at = NOW() WHERE user_id = ? AND code_hash = ? AND used_at IS NULL",
+		userID, codeHash,
+	)
+	if err != nil {
+		return false, apperror.Internal("use recovery code", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, apperror.Internal("use recovery code", err)
+	}
+	return n > 0, nil
+}
+
+func (r *RecoveryCodeRepo) DeleteForUser(ctx context.Context, userID int64) error {
+	q := getQuerier(ctx, r.db)
+	if _, err := q.ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ?", userID); err != nil {
+		return apperror.Internal("delete recovery codes", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type RefreshTokenRepo struct {
+	db *sqlx.DB
+}
+
+func NewRefreshTokenRepo(db *sqlx.DB) *RefreshTokenRepo {
+	return &RefreshTokenRepo{db: db}
+}
+
+func (r *RefreshTokenRepo) Create(ctx context.Context, token *domain.RefreshToken) (int64, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		"INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at) VALUES (?, ?, ?, ?)",
+		token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt,
+	)
+	if err != nil {
+		return 0, apperror.Internal("create refresh token", err)
+	}
+	return result.LastInsertId()
+}
+
+// GetByHashForUpdate locks the token row so concurrent refreshes with the
+// same token are serialized.
+func (r *RefreshTokenRepo) GetByHashForUpdate(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	q := getQuerier(ctx, r.db)
+	var token domain.RefreshToken
+	err := q.GetContext(ctx, &token, "SELECT * FROM refresh_tokens WHERE token_hash = ? FOR UPDATE", tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("refresh token not found")
+		}
+		return nil, apperror.Internal("get refresh token", err)
+	}
+	return &token, nil
+}
+
+func (r *RefreshTokenRepo) MarkRotated(ctx context.Context, id int64) error {
+	q := getQuerier(ctx, r.db)
+	if _, err := q.ExecContext(ctx, "UPDATE refresh_tokens SET rotated_at = NOW() WHERE id = ?", id); err != nil {
+		return apperror.Internal("rotate refresh token", err)
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = ? AND revoked_at IS NULL",
+		familyID,
+	)
+	if err != nil {
+		return apperror.Internal("revoke refresh tokens", err)
+	}
+	return nil
+}
+
+// RevokeForUser revokes every live token of the user and returns the
+// families that were still active.
+func (r *RefreshTokenRepo) RevokeForUser(ctx context.Context, userID int64) ([]string, error) {
+	q := getQuerier(ctx, r.db)
+	var families []string
+	err := q.SelectContext(ctx, &families,
+		`SELECT DISTINCT family_id FROM refresh_tokens
+		 WHERE user_id = ? AND revoked_at IS NULL AND expires_at > NOW()
+		 FOR UPDATE`,
+		userID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list refresh token families", err)
+	}
+	_, err = q.ExecContext(ctx,
+		"UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = ? AND revoked_at IS NULL",
+		userID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("revoke refresh tokens", err)
+	}
+	return families, nil
+}
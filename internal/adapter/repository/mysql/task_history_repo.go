@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/shalfey088/team-task-nexus/internal/domain"
@@ -16,27 +17,80 @@ func NewTaskHistoryRepo(db *sqlx.DB) *TaskHistoryRepo {
 	return &TaskHistoryRepo{db: db}
 }
 
-func (r *TaskHistoryRepo) Create(ctx context.Context, history *domain.TaskHistory) error {
+func (r *TaskHistoryRepo) CreateChangeSet(ctx context.Context, set *domain.TaskChangeSet, entries []domain.TaskHistory) (int64, error) {
 	q := getQuerier(ctx, r.db)
-	_, err := q.ExecContext(ctx,
-		`INSERT INTO task_history (task_id, user_id, field, old_value, new_value)
-		 VALUES (?, ?, ?, ?, ?)`,
-		history.TaskID, history.UserID, history.Field, history.OldValue, history.NewValue,
+	result, err := q.ExecContext(ctx,
+		"INSERT INTO task_change_sets (task_id, user_id, request_id, source) VALUES (?, ?, ?, ?)",
+		set.TaskID, set.UserID, set.RequestID, set.Source,
 	)
 	if err != nil {
-		return apperror.Internal("create task history", err)
+		return 0, apperror.Internal("create task change set", err)
 	}
-	return nil
+	setID, err := result.LastInsertId()
+	if err != nil {
+		return 0, apperror.Internal("create task change set", err)
+	}
+	if len(entries) == 0 {
+		return setID, nil
+	}
+
+	placeholders := make([]string, 0, len(entries))
+	args := make([]interface{}, 0, len(entries)*7)
+	for _, e := range entries {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, setID, set.TaskID, set.UserID, e.Field, e.ValueType, e.OldValue, e.NewValue)
+	}
+	_, err = q.ExecContext(ctx,
+		`INSERT INTO task_history (change_set_id, task_id, user_id, field, value_type, old_value, new_value)
+		 VALUES `+strings.Join(placeholders, ", "),
+		args...,
+	)
+	if err != nil {
+		return 0, apperror.Internal("create task history", err)
+	}
+	return setID, nil
 }
 
-func (r *TaskHistoryRepo) ListByTaskID(ctx context.Context, taskID int64) ([]domain.TaskHistory, error) {
+func (r *TaskHistoryRepo) ListChangeSets(ctx context.Context, taskID int64, filter domain.HistoryFilter) ([]domain.TaskChangeSet, int, error) {
 	q := getQuerier(ctx, r.db)
-	var history []domain.TaskHistory
-	err := q.SelectContext(ctx, &history,
-		"SELECT * FROM task_history WHERE task_id = ? ORDER BY changed_at DESC",
-		taskID,
+
+	var total int
+	err := q.GetContext(ctx, &total, "SELECT COUNT(*) FROM task_change_sets WHERE task_id = ?", taskID)
+	if err != nil {
+		return nil, 0, apperror.Internal("count task change sets", err)
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	var sets []domain.TaskChangeSet
+	err = q.SelectContext(ctx, &sets, `
+		SELECT cs.id, cs.task_id, cs.user_id, u.full_name AS user_name,
+			cs.request_id, cs.source, cs.created_at
+		FROM task_change_sets cs
+		JOIN users u ON u.id = cs.user_id
+		WHERE cs.task_id = ?
+		ORDER BY cs.created_at DESC, cs.id DESC
+		LIMIT ? OFFSET ?`,
+		taskID, filter.PageSize, offset,
 	)
 	if err != nil {
+		return nil, 0, apperror.Internal("list task change sets", err)
+	}
+	return sets, total, nil
+}
+
+func (r *TaskHistoryRepo) ListByChangeSetIDs(ctx context.Context, ids []int64) ([]domain.TaskHistory, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In("SELECT * FROM task_history WHERE change_set_id IN (?) ORDER BY id ASC", ids)
+	if err != nil {
+		return nil, apperror.Internal("list task history", err)
+	}
+
+	q := getQuerier(ctx, r.db)
+	var history []domain.TaskHistory
+	if err := q.SelectContext(ctx, &history, r.db.Rebind(query), args...); err != nil {
 		return nil, apperror.Internal("list task history", err)
 	}
 	return history, nil
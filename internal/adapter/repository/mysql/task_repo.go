@@ -80,6 +80,15 @@ func (r *TaskRepo) List(ctx context.Context, filter domain.TaskFilter) ([]domain
 		conditions = append(conditions, "assignee_id = ?")
 		args = append(args, filter.AssigneeID)
 	}
+	if filter.GuestID > 0 {
+		conditions = append(conditions, "id IN (SELECT task_id FROM team_guest_tasks WHERE user_id = ?)")
+		args = append(args, filter.GuestID)
+	}
+	if filter.Access != nil {
+		cond, condArgs := taskAccessCondition("team_id", "id", *filter.Access)
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+	}
 
 	where := ""
 	if len(conditions) > 0 {
@@ -120,7 +129,7 @@ func (r *TaskRepo) GetOrphanedAssignees(ctx context.Context) ([]domain.OrphanedA
 	q := getQuerier(ctx, r.db)
 	var result []domain.OrphanedAssignee
 	err := q.SelectContext(ctx, &result, `
-		SELECT tk.id, tk.title, tk.assignee_id, u.full_name
+		SELECT tk.id, tk.title, tk.team_id, tk.assignee_id, u.full_name
 		FROM tasks tk
 		JOIN users u ON u.id = tk.assignee_id
 		WHERE tk.assignee_id IS NOT NULL
@@ -134,3 +143,92 @@ func (r *TaskRepo) GetOrphanedAssignees(ctx context.Context) ([]domain.OrphanedA
 	}
 	return result, nil
 }
+
+// ClearAssignee unassigns the task only if it is still assigned to
+// assigneeID, reporting whether it was.
+func (r *TaskRepo) ClearAssignee(ctx context.Context, taskID, assigneeID int64) (bool, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		"UPDATE tasks SET assignee_id = NULL WHERE id = ? AND assignee_id = ?",
+		taskID, assigneeID,
+	)
+	if err != nil {
+		return false, apperror.Internal("clear task assignee", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, apperror.Internal("clear task assignee", err)
+	}
+	return n > 0, nil
+}
+
+// ListTeamIDs returns the distinct teams the given tasks belong to.
+func (r *TaskRepo) ListTeamIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In("SELECT DISTINCT team_id FROM tasks WHERE id IN (?)", ids)
+	if err != nil {
+		return nil, apperror.Internal("list task teams", err)
+	}
+
+	q := getQuerier(ctx, r.db)
+	var teamIDs []int64
+	if err := q.SelectContext(ctx, &teamIDs, r.db.Rebind(query), args...); err != nil {
+		return nil, apperror.Internal("list task teams", err)
+	}
+	return teamIDs, nil
+}
+
+func (r *TaskRepo) ListVisibleIDs(ctx context.Context, access domain.TaskAccess, ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	cond, condArgs := taskAccessCondition("team_id", "id", access)
+	query, args, err := sqlx.In("SELECT id FROM tasks WHERE id IN (?) AND "+cond, append([]interface{}{ids}, condArgs...)...)
+	if err != nil {
+		return nil, apperror.Internal("list visible tasks", err)
+	}
+
+	q := getQuerier(ctx, r.db)
+	var visible []int64
+	if err := q.SelectContext(ctx, &visible, r.db.Rebind(query), args...); err != nil {
+		return nil, apperror.Internal("list visible tasks", err)
+	}
+	return visible, nil
+}
+
+// taskAccessCondition limits rows to the tasks access allows: every task of
+// a full team, and only granted tasks of a team the user is scoped in.
+func taskAccessCondition(teamColumn, taskColumn string, access domain.TaskAccess) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+	if len(access.Teams) > 0 {
+		parts = append(parts, teamColumn+" IN (?"+strings.Repeat(", ?", len(access.Teams)-1)+")")
+		for _, id := range access.Teams {
+			args = append(args, id)
+		}
+	}
+	if len(access.ScopedTeams) > 0 {
+		parts = append(parts, "("+teamColumn+" IN (?"+strings.Repeat(", ?", len(access.ScopedTeams)-1)+")"+
+			" AND "+taskColumn+" IN (SELECT task_id FROM team_guest_tasks WHERE user_id = ?))")
+		for _, id := range access.ScopedTeams {
+			args = append(args, id)
+		}
+		args = append(args, access.UserID)
+	}
+	if len(parts) == 0 {
+		return "FALSE", nil
+	}
+	return "(" + strings.Join(parts, " OR ") + ")", args
+}
+
+func (r *TaskRepo) Delete(ctx context.Context, id int64) error {
+	q := getQuerier(ctx, r.db)
+	if _, err := q.ExecContext(ctx, "DELETE FROM tasks WHERE id = ?", id); err != nil {
+		return apperror.Internal("delete task", err)
+	}
+	return nil
+}
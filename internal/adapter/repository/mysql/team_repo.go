@@ -22,8 +22,8 @@ func NewTeamRepo(db *sqlx.DB) *TeamRepo {
 func (r *TeamRepo) Create(ctx context.Context, team *domain.Team) (int64, error) {
 	q := getQuerier(ctx, r.db)
 	result, err := q.ExecContext(ctx,
-		"INSERT INTO teams (name, description, owner_id) VALUES (?, ?, ?)",
-		team.Name, team.Description, team.OwnerID,
+		"INSERT INTO teams (org_id, parent_id, name, description, owner_id) VALUES (?, ?, ?, ?, ?)",
+		team.OrgID, team.ParentID, team.Name, team.Description, team.OwnerID,
 	)
 	if err != nil {
 		return 0, apperror.Internal("create team", err)
@@ -44,21 +44,134 @@ func (r *TeamRepo) GetByID(ctx context.Context, id int64) (*domain.Team, error)
 	return &team, nil
 }
 
-func (r *TeamRepo) ListByUserID(ctx context.Context, userID int64) ([]domain.Team, error) {
+// ListByUserID returns the teams the user belongs to directly, inherits
+// from a parent team or reaches through an organization admin role.
+func (r *TeamRepo) ListByUserID(ctx context.Context, userID int64, includeArchived bool) ([]domain.Team, error) {
 	q := getQuerier(ctx, r.db)
+	query := `WITH RECURSIVE reach (id, scoped) AS (
+			SELECT team_id, task_scoped FROM team_members WHERE user_id = ?
+			UNION
+			SELECT c.id, FALSE FROM reach r
+			JOIN teams c ON c.parent_id = r.id AND c.inherited_role IS NOT NULL
+			WHERE NOT r.scoped
+		)
+		SELECT t.* FROM teams t
+		WHERE (t.id IN (SELECT id FROM reach)
+		   OR t.org_id IN (SELECT org_id FROM organization_members WHERE user_id = ? AND role = 'admin'))`
+	if !includeArchived {
+		query += " AND t.archived_at IS NULL"
+	}
+	query += " ORDER BY t.created_at DESC"
+
+	var teams []domain.Team
+	if err := q.SelectContext(ctx, &teams, query, userID, userID); err != nil {
+		return nil, apperror.Internal("list teams", err)
+	}
+	return teams, nil
+}
+
+// ListAll pages through every team in the installation, archived ones
+// included.
+func (r *TeamRepo) ListAll(ctx context.Context, filter domain.AdminListFilter) ([]domain.Team, int, error) {
+	q := getQuerier(ctx, r.db)
+	var total int
+	if err := q.GetContext(ctx, &total, "SELECT COUNT(*) FROM teams"); err != nil {
+		return nil, 0, apperror.Internal("count teams", err)
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
 	var teams []domain.Team
 	err := q.SelectContext(ctx, &teams,
-		`SELECT t.* FROM teams t
-		 JOIN team_members tm ON tm.team_id = t.id
-		 WHERE tm.user_id = ?
-		 ORDER BY t.created_at DESC`, userID,
+		"SELECT * FROM teams ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?",
+		filter.PageSize, offset,
 	)
 	if err != nil {
-		return nil, apperror.Internal("list teams", err)
+		return nil, 0, apperror.Internal("list teams", err)
+	}
+	return teams, total, nil
+}
+
+func (r *TeamRepo) Update(ctx context.Context, team *domain.Team) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE teams SET name = ?, description = ?, require_2fa = ? WHERE id = ?",
+		team.Name, team.Description, team.Require2FA, team.ID,
+	)
+	if err != nil {
+		return apperror.Internal("update team", err)
+	}
+	return nil
+}
+
+func (r *TeamRepo) SetArchived(ctx context.Context, teamID int64, archived bool) error {
+	q := getQuerier(ctx, r.db)
+	query := "UPDATE teams SET archived_at = NULL WHERE id = ?"
+	if archived {
+		query = "UPDATE teams SET archived_at = NOW() WHERE id = ? AND archived_at IS NULL"
+	}
+	if _, err := q.ExecContext(ctx, query, teamID); err != nil {
+		return apperror.Internal("archive team", err)
+	}
+	return nil
+}
+
+// Delete removes the team; members, tasks and everything below them go with
+// it through ON DELETE CASCADE.
+func (r *TeamRepo) Delete(ctx context.Context, teamID int64) error {
+	q := getQuerier(ctx, r.db)
+	if _, err := q.ExecContext(ctx, "DELETE FROM teams WHERE id = ?", teamID); err != nil {
+		return apperror.Internal("delete team", err)
+	}
+	return nil
+}
+
+func (r *TeamRepo) SetParent(ctx context.Context, teamID int64, parentID *int64, inheritedRole *domain.TeamRole) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE teams SET parent_id = ?, inherited_role = ? WHERE id = ?",
+		parentID, inheritedRole, teamID,
+	)
+	if err != nil {
+		return apperror.Internal("move team", err)
+	}
+	return nil
+}
+
+// ListDescendants returns every team below teamID, parents before their
+// children.
+func (r *TeamRepo) ListDescendants(ctx context.Context, teamID int64) ([]domain.Team, error) {
+	q := getQuerier(ctx, r.db)
+	var teams []domain.Team
+	err := q.SelectContext(ctx, &teams,
+		`WITH RECURSIVE tree (id, depth) AS (
+			SELECT id, 1 FROM teams WHERE parent_id = ?
+			UNION ALL
+			SELECT c.id, t.depth + 1 FROM tree t JOIN teams c ON c.parent_id = t.id
+		)
+		SELECT tm.* FROM teams tm
+		JOIN tree ON tree.id = tm.id
+		ORDER BY tree.depth, tm.name, tm.id`, teamID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list sub-teams", err)
 	}
 	return teams, nil
 }
 
+// LockHierarchy locks every team of the organization until the transaction
+// ends, so concurrent moves see each other's parent changes and cannot close
+// a cycle between them.
+func (r *TeamRepo) LockHierarchy(ctx context.Context, orgID int64) error {
+	q := getQuerier(ctx, r.db)
+	var ids []int64
+	if err := q.SelectContext(ctx, &ids, "SELECT id FROM teams WHERE org_id = ? FOR UPDATE", orgID); err != nil {
+		return apperror.Internal("lock teams", err)
+	}
+	return nil
+}
+
+// AddMember also enrolls the user in the team's organization unless they
+// already belong to it. Guests stay outside the organization.
 func (r *TeamRepo) AddMember(ctx context.Context, member *domain.TeamMember) error {
 	q := getQuerier(ctx, r.db)
 	_, err := q.ExecContext(ctx,
@@ -72,15 +185,64 @@ func (r *TeamRepo) AddMember(ctx context.Context, member *domain.TeamMember) err
 		}
 		return apperror.Internal("add team member", err)
 	}
+	if member.Role == domain.TeamRoleGuest {
+		return nil
+	}
+	_, err = q.ExecContext(ctx,
+		`INSERT IGNORE INTO organization_members (org_id, user_id, role)
+		 SELECT org_id, ?, 'member' FROM teams WHERE id = ?`,
+		member.UserID, member.TeamID,
+	)
+	if err != nil {
+		return apperror.Internal("add organization member", err)
+	}
 	return nil
 }
 
+// GetMember resolves the user's effective role in the team. It walks up the
+// parent chain: membership of an ancestor grants its role capped by the
+// inherited_role of every team on the way down, and a team without one stops
+// inheritance. Organization admins act as team admins. The highest role
+// wins, direct membership breaking ties.
 func (r *TeamRepo) GetMember(ctx context.Context, teamID, userID int64) (*domain.TeamMember, error) {
 	q := getQuerier(ctx, r.db)
 	var member domain.TeamMember
 	err := q.GetContext(ctx, &member,
-		"SELECT * FROM team_members WHERE team_id = ? AND user_id = ?",
-		teamID, userID,
+		`WITH RECURSIVE chain (id, parent_id, inherited_role, depth, cap) AS (
+			SELECT id, parent_id, inherited_role, 0, 4 FROM teams WHERE id = ?
+			UNION ALL
+			SELECT p.id, p.parent_id, p.inherited_role, c.depth + 1,
+				LEAST(c.cap, FIELD(c.inherited_role, 'guest', 'member', 'admin'))
+			FROM chain c
+			JOIN teams p ON p.id = c.parent_id
+			WHERE c.cap > 0
+		)
+		SELECT m.*,
+			(SELECT archived_at IS NOT NULL FROM teams WHERE id = ?) AS team_archived,
+			(SELECT require_2fa FROM teams WHERE id = ?) AS team_require_2fa,
+			(SELECT totp_enabled_at IS NOT NULL FROM users WHERE id = ?) AS two_factor_enabled
+		FROM (
+			SELECT ? AS team_id, tm.user_id,
+				ELT(LEAST(FIELD(tm.role, 'guest', 'member', 'admin', 'owner'), c.cap),
+					'guest', 'member', 'admin', 'owner') AS role,
+				IF(c.depth = 0, tm.custom_role_id, NULL) AS custom_role_id,
+				IF(c.depth = 0, tr.permissions, NULL) AS custom_permissions,
+				c.depth = 0 AND tm.task_scoped AS task_scoped,
+				FALSE AS via_org,
+				IF(c.depth = 0, NULL, c.id) AS inherited_from
+			FROM chain c
+			JOIN team_members tm ON tm.team_id = c.id AND tm.user_id = ?
+			LEFT JOIN team_roles tr ON tr.id = tm.custom_role_id
+			WHERE c.cap > 0 AND (c.depth = 0 OR NOT tm.task_scoped)
+			UNION ALL
+			SELECT t.id, om.user_id, 'admin', NULL, NULL, FALSE, TRUE, NULL
+			FROM teams t
+			JOIN organization_members om ON om.org_id = t.org_id AND om.role = 'admin'
+			WHERE t.id = ? AND om.user_id = ?
+		) m
+		ORDER BY FIELD(m.role, 'owner', 'admin', 'member', 'guest'), m.via_org, m.inherited_from IS NOT NULL
+		LIMIT 1`,
+		teamID, teamID, teamID, userID, teamID, userID, teamID, userID,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -91,18 +253,28 @@ func (r *TeamRepo) GetMember(ctx context.Context, teamID, userID int64) (*domain
 	return &member, nil
 }
 
-func (r *TeamRepo) GetStats(ctx context.Context, userID int64) ([]domain.TeamStats, error) {
+// GetStats reports on the teams the user belongs to. With rollup, each
+// team's figures also cover all of its sub-teams, counting a person who is
+// in several of them once.
+func (r *TeamRepo) GetStats(ctx context.Context, userID int64, rollup bool) ([]domain.TeamStats, error) {
 	q := getQuerier(ctx, r.db)
 	var stats []domain.TeamStats
 	err := q.SelectContext(ctx, &stats, `
-		SELECT t.id, t.name,
+		WITH RECURSIVE subtree (root_id, team_id) AS (
+			SELECT team_id, team_id FROM team_members WHERE user_id = ?
+			UNION ALL
+			SELECT s.root_id, c.id FROM subtree s
+			JOIN teams c ON c.parent_id = s.team_id
+			WHERE ?
+		)
+		SELECT t.id, t.parent_id, t.name,
 			COUNT(DISTINCT tm.user_id) AS member_count,
 			COUNT(DISTINCT CASE WHEN tk.status='done' AND tk.updated_at >= NOW() - INTERVAL 7 DAY THEN tk.id END) AS done_last_7d
-		FROM teams t
-		LEFT JOIN team_members tm ON tm.team_id = t.id
-		LEFT JOIN tasks tk ON tk.team_id = t.id
-		WHERE t.id IN (SELECT team_id FROM team_members WHERE user_id = ?)
-		GROUP BY t.id, t.name`, userID,
+		FROM subtree s
+		JOIN teams t ON t.id = s.root_id
+		LEFT JOIN team_members tm ON tm.team_id = s.team_id
+		LEFT JOIN tasks tk ON tk.team_id = s.team_id
+		GROUP BY t.id, t.parent_id, t.name`, userID, rollup,
 	)
 	if err != nil {
 		return nil, apperror.Internal("get team stats", err)
@@ -132,3 +304,163 @@ func (r *TeamRepo) GetTopContributors(ctx context.Context, teamID int64) ([]doma
 	}
 	return contributors, nil
 }
+
+func (r *TeamRepo) ListMembers(ctx context.Context, teamID int64) ([]domain.TeamMemberDetails, error) {
+	q := getQuerier(ctx, r.db)
+	var members []domain.TeamMemberDetails
+	err := q.SelectContext(ctx, &members,
+		`SELECT tm.team_id, tm.user_id, tm.role, tm.custom_role_id, tm.task_scoped, u.email, u.full_name,
+			u.totp_enabled_at IS NOT NULL AS two_factor_enabled
+		 FROM team_members tm
+		 JOIN users u ON u.id = tm.user_id
+		 WHERE tm.team_id = ?
+		 ORDER BY u.full_name, u.id`, teamID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list team members", err)
+	}
+	return members, nil
+}
+
+// ListMentionCandidates returns everyone who may reach the team: its own
+// members, members of ancestors it inherits from and organization admins.
+// Callers still check each candidate through the authorizer, which applies
+// the inheritance caps.
+func (r *TeamRepo) ListMentionCandidates(ctx context.Context, teamID int64) ([]domain.TeamMemberDetails, error) {
+	q := getQuerier(ctx, r.db)
+	var members []domain.TeamMemberDetails
+	err := q.SelectContext(ctx, &members,
+		`WITH RECURSIVE chain (id, parent_id, inherited_role, depth) AS (
+			SELECT id, parent_id, inherited_role, 0 FROM teams WHERE id = ?
+			UNION ALL
+			SELECT p.id, p.parent_id, p.inherited_role, c.depth + 1
+			FROM chain c
+			JOIN teams p ON p.id = c.parent_id
+			WHERE c.inherited_role IS NOT NULL
+		)
+		SELECT ? AS team_id, u.id AS user_id, u.email, u.full_name,
+			u.totp_enabled_at IS NOT NULL AS two_factor_enabled
+		FROM users u
+		WHERE u.id IN (
+			SELECT tm.user_id FROM chain c
+			JOIN team_members tm ON tm.team_id = c.id
+			WHERE c.depth = 0 OR NOT tm.task_scoped
+			UNION
+			SELECT om.user_id FROM teams t
+			JOIN organization_members om ON om.org_id = t.org_id AND om.role = 'admin'
+			WHERE t.id = ?
+		)
+		ORDER BY u.full_name, u.id`, teamID, teamID, teamID,
+	)
+	if err != nil {
+		return nil, apperror.Internal("list mention candidates", err)
+	}
+	return members, nil
+}
+
+// UpdateMemberRole also drops any custom role, which only applies to the
+// member role, and any guest task scope. A guest promoted to a full role
+// joins the team's organization.
+func (r *TeamRepo) UpdateMemberRole(ctx context.Context, teamID, userID int64, role domain.TeamRole) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		`UPDATE team_members SET role = ?, custom_role_id = NULL,
+			task_scoped = task_scoped AND role = 'guest'
+		 WHERE team_id = ? AND user_id = ?`,
+		role, teamID, userID,
+	)
+	if err != nil {
+		return apperror.Internal("update team member role", err)
+	}
+	if role == domain.TeamRoleGuest {
+		return nil
+	}
+	_, err = q.ExecContext(ctx,
+		`INSERT IGNORE INTO organization_members (org_id, user_id, role)
+		 SELECT org_id, ?, 'member' FROM teams WHERE id = ?`,
+		userID, teamID,
+	)
+	if err != nil {
+		return apperror.Internal("add organization member", err)
+	}
+	return nil
+}
+
+// SetGuestTasks replaces the tasks a guest may see. A nil list removes the
+// limit; an empty one leaves the guest with no tasks.
+func (r *TeamRepo) SetGuestTasks(ctx context.Context, teamID, userID int64, taskIDs []int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"DELETE FROM team_guest_tasks WHERE team_id = ? AND user_id = ?",
+		teamID, userID,
+	)
+	if err != nil {
+		return apperror.Internal("clear guest tasks", err)
+	}
+	for _, taskID := range taskIDs {
+		_, err := q.ExecContext(ctx,
+			"INSERT IGNORE INTO team_guest_tasks (team_id, user_id, task_id) VALUES (?, ?, ?)",
+			teamID, userID, taskID,
+		)
+		if err != nil {
+			return apperror.Internal("grant guest task", err)
+		}
+	}
+	_, err = q.ExecContext(ctx,
+		"UPDATE team_members SET task_scoped = ? WHERE team_id = ? AND user_id = ?",
+		taskIDs != nil, teamID, userID,
+	)
+	if err != nil {
+		return apperror.Internal("update guest scope", err)
+	}
+	return nil
+}
+
+func (r *TeamRepo) IsTaskGranted(ctx context.Context, userID, taskID int64) (bool, error) {
+	q := getQuerier(ctx, r.db)
+	var n int
+	err := q.GetContext(ctx, &n,
+		"SELECT COUNT(*) FROM team_guest_tasks WHERE user_id = ? AND task_id = ?",
+		userID, taskID,
+	)
+	if err != nil {
+		return false, apperror.Internal("check guest task", err)
+	}
+	return n > 0, nil
+}
+
+func (r *TeamRepo) RemoveMember(ctx context.Context, teamID, userID int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"DELETE FROM team_members WHERE team_id = ? AND user_id = ?",
+		teamID, userID,
+	)
+	if err != nil {
+		return apperror.Internal("remove team member", err)
+	}
+	return nil
+}
+
+// CountMembersByRole locks the matching rows so concurrent removals cannot
+// both see a second owner when called inside a transaction.
+func (r *TeamRepo) CountMembersByRole(ctx context.Context, teamID int64, role domain.TeamRole) (int, error) {
+	q := getQuerier(ctx, r.db)
+	var ids []int64
+	err := q.SelectContext(ctx, &ids,
+		"SELECT user_id FROM team_members WHERE team_id = ? AND role = ? FOR UPDATE",
+		teamID, role,
+	)
+	if err != nil {
+		return 0, apperror.Internal("count team members", err)
+	}
+	return len(ids), nil
+}
+
+func (r *TeamRepo) UpdateOwner(ctx context.Context, teamID, ownerID int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx, "UPDATE teams SET owner_id = ? WHERE id = ?", ownerID, teamID)
+	if err != nil {
+		return apperror.Internal("update team owner", err)
+	}
+	return nil
+}
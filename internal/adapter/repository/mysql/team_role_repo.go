@@ -0,0 +1,127 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+type TeamRoleRepo struct {
+	db *sqlx.DB
+}
+
+func NewTeamRoleRepo(db *sqlx.DB) *TeamRoleRepo {
+	return &TeamRoleRepo{db: db}
+}
+
+// customRoleRow stores permissions as a comma-separated list.
+type customRoleRow struct {
+	ID          int64     `db:"id"`
+	TeamID      int64     `db:"team_id"`
+	Name        string    `db:"name"`
+	Permissions string    `db:"permissions"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (row customRoleRow) toDomain() domain.CustomRole {
+	return domain.CustomRole{
+		ID:          row.ID,
+		TeamID:      row.TeamID,
+		Name:        row.Name,
+		Permissions: domain.ParsePermissions(row.Permissions),
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
+func (r *TeamRoleRepo) Create(ctx context.Context, role *domain.CustomRole) (int64, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		"INSERT INTO team_roles (team_id, name, permissions) VALUES (?, ?, ?)",
+		role.TeamID, role.Name, domain.JoinPermissions(role.Permissions),
+	)
+	if err != nil {
+		if isDuplicate(err) {
+			return 0, apperror.New(409, "a role with this name already exists")
+		}
+		return 0, apperror.Internal("create team role", err)
+	}
+	return result.LastInsertId()
+}
+
+func (r *TeamRoleRepo) GetByID(ctx context.Context, id int64) (*domain.CustomRole, error) {
+	q := getQuerier(ctx, r.db)
+	var row customRoleRow
+	err := q.GetContext(ctx, &row, "SELECT * FROM team_roles WHERE id = ?", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.NotFound("role not found")
+		}
+		return nil, apperror.Internal("get team role", err)
+	}
+	role := row.toDomain()
+	return &role, nil
+}
+
+func (r *TeamRoleRepo) ListByTeam(ctx context.Context, teamID int64) ([]domain.CustomRole, error) {
+	q := getQuerier(ctx, r.db)
+	var rows []customRoleRow
+	err := q.SelectContext(ctx, &rows, "SELECT * FROM team_roles WHERE team_id = ? ORDER BY name", teamID)
+	if err != nil {
+		return nil, apperror.Internal("list team roles", err)
+	}
+	roles := make([]domain.CustomRole, 0, len(rows))
+	for _, row := range rows {
+		roles = append(roles, row.toDomain())
+	}
+	return roles, nil
+}
+
+func (r *TeamRoleRepo) Update(ctx context.Context, role *domain.CustomRole) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE team_roles SET name = ?, permissions = ? WHERE id = ?",
+		role.Name, domain.JoinPermissions(role.Permissions), role.ID,
+	)
+	if err != nil {
+		if isDuplicate(err) {
+			return apperror.New(409, "a role with this name already exists")
+		}
+		return apperror.Internal("update team role", err)
+	}
+	return nil
+}
+
+// Delete removes the role; members holding it fall back to the default
+// member permissions through ON DELETE SET NULL.
+func (r *TeamRoleRepo) Delete(ctx context.Context, id int64) error {
+	q := getQuerier(ctx, r.db)
+	if _, err := q.ExecContext(ctx, "DELETE FROM team_roles WHERE id = ?", id); err != nil {
+		return apperror.Internal("delete team role", err)
+	}
+	return nil
+}
+
+func (r *TeamRoleRepo) Assign(ctx context.Context, teamID, userID int64, roleID *int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE team_members SET custom_role_id = ? WHERE team_id = ? AND user_id = ?",
+		roleID, teamID, userID,
+	)
+	if err != nil {
+		return apperror.Internal("assign team role", err)
+	}
+	return nil
+}
+
+func isDuplicate(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
@@ -20,7 +20,13 @@ func NewTransactionManager(db *sqlx.DB) *TransactionManager {
 	return &TransactionManager{db: db}
 }
 
+// WithTransaction runs fn in a new transaction, or in the caller's when one is
+// already open so services can compose transactional operations.
 func (m *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
 	tx, err := m.db.BeginTxx(ctx, &sql.TxOptions{})
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
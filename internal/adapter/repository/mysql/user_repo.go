@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
@@ -60,3 +61,132 @@ func (r *UserRepo) GetByEmail(ctx context.Context, email string) (*domain.User,
 	}
 	return &user, nil
 }
+
+func (r *UserRepo) GetByIDs(ctx context.Context, ids []int64) ([]domain.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In("SELECT * FROM users WHERE id IN (?)", ids)
+	if err != nil {
+		return nil, apperror.Internal("get users by ids", err)
+	}
+
+	q := getQuerier(ctx, r.db)
+	var users []domain.User
+	if err := q.SelectContext(ctx, &users, r.db.Rebind(query), args...); err != nil {
+		return nil, apperror.Internal("get users by ids", err)
+	}
+	return users, nil
+}
+
+// Search pages through users whose email or name starts with filter.Query.
+func (r *UserRepo) Search(ctx context.Context, filter domain.UserSearchFilter) ([]domain.User, int, error) {
+	q := getQuerier(ctx, r.db)
+
+	var conditions []string
+	var args []interface{}
+	if filter.Query != "" {
+		pattern := escapeLike(filter.Query) + "%"
+		conditions = append(conditions, "(email LIKE ? OR full_name LIKE ?)")
+		args = append(args, pattern, pattern)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := q.GetContext(ctx, &total, "SELECT COUNT(*) FROM users "+where, args...); err != nil {
+		return nil, 0, apperror.Internal("count users", err)
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	args = append(args, filter.PageSize, offset)
+	var users []domain.User
+	err := q.SelectContext(ctx, &users,
+		"SELECT * FROM users "+where+" ORDER BY email, id LIMIT ? OFFSET ?", args...)
+	if err != nil {
+		return nil, 0, apperror.Internal("search users", err)
+	}
+	return users, total, nil
+}
+
+func (r *UserRepo) SetDisabled(ctx context.Context, id int64, disabled bool) error {
+	q := getQuerier(ctx, r.db)
+	query := "UPDATE users SET disabled_at = NULL WHERE id = ?"
+	if disabled {
+		query = "UPDATE users SET disabled_at = NOW() WHERE id = ? AND disabled_at IS NULL"
+	}
+	if _, err := q.ExecContext(ctx, query, id); err != nil {
+		return apperror.Internal("update user status", err)
+	}
+	return nil
+}
+
+func (r *UserRepo) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	q := getQuerier(ctx, r.db)
+	if _, err := q.ExecContext(ctx, "UPDATE users SET password_hash = ? WHERE id = ?", passwordHash, id); err != nil {
+		return apperror.Internal("update password", err)
+	}
+	return nil
+}
+
+// SetTOTPSecret stores a secret awaiting confirmation; 2FA stays off until
+// EnableTOTP.
+func (r *UserRepo) SetTOTPSecret(ctx context.Context, id int64, secret string) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE users SET totp_secret = ?, totp_enabled_at = NULL, totp_last_counter = 0 WHERE id = ?", secret, id)
+	if err != nil {
+		return apperror.Internal("set totp secret", err)
+	}
+	return nil
+}
+
+func (r *UserRepo) EnableTOTP(ctx context.Context, id int64, counter int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE users SET totp_enabled_at = NOW(), totp_last_counter = ? WHERE id = ?", counter, id)
+	if err != nil {
+		return apperror.Internal("enable totp", err)
+	}
+	return nil
+}
+
+func (r *UserRepo) DisableTOTP(ctx context.Context, id int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_counter = 0 WHERE id = ?", id)
+	if err != nil {
+		return apperror.Internal("disable totp", err)
+	}
+	return nil
+}
+
+// AdvanceTOTPCounter records the time step of an accepted code. It reports
+// false when that step or a later one was already used, so a code cannot be
+// replayed even by concurrent requests.
+func (r *UserRepo) AdvanceTOTPCounter(ctx context.Context, id int64, counter int64) (bool, error) {
+	q := getQuerier(ctx, r.db)
+	result, err := q.ExecContext(ctx,
+		"UPDATE users SET totp_last_counter = ? WHERE id = ? AND totp_last_counter < ?", counter, id, counter)
+	if err != nil {
+		return false, apperror.Internal("update totp counter", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, apperror.Internal("update totp counter", err)
+	}
+	return n > 0, nil
+}
+
+func (r *UserRepo) MarkEmailVerified(ctx context.Context, id int64) error {
+	q := getQuerier(ctx, r.db)
+	_, err := q.ExecContext(ctx,
+		"UPDATE users SET email_verified_at = NOW() WHERE id = ? AND email_verified_at IS NULL", id)
+	if err != nil {
+		return apperror.Internal("mark email verified", err)
+	}
+	return nil
+}
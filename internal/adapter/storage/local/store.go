@@ -0,0 +1,98 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+)
+
+// Store keeps blobs as files below a root directory.
+type Store struct {
+	root string
+}
+
+func NewStore(root string) (*Store, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, err
+	}
+	return &Store{root: root}, nil
+}
+
+func (s *Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return apperror.Internal("store file", err)
+	}
+
+	// Write to a temp file first so readers never see a partial blob.
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return apperror.Internal("store file", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return apperror.Internal("store file", err)
+	}
+	if written != size {
+		return apperror.Internal("store file", fmt.Errorf("wrote %d of %d bytes", written, size))
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return apperror.Internal("store file", err)
+	}
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, apperror.NotFound("file not found")
+		}
+		return nil, apperror.Internal("open file", err)
+	}
+	return f, nil
+}
+
+func (s *Store) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return apperror.Internal("delete file", err)
+	}
+	return nil
+}
+
+func (s *Store) path(key string) (string, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, s.root+string(os.PathSeparator)) {
+		return "", apperror.BadRequest("invalid storage key")
+	}
+	return path, nil
+}
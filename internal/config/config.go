@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -12,6 +13,13 @@ type Config struct {
 	Redis    RedisConfig    `mapstructure:"redis"`
 	JWT      JWTConfig      `mapstructure:"jwt"`
 	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Markdown MarkdownConfig `mapstructure:"markdown"`
+	Attachments AttachmentsConfig `mapstructure:"attachments"`
+	Invitations InvitationsConfig `mapstructure:"invitations"`
+	Mail MailConfig `mapstructure:"mail"`
+	Password PasswordConfig `mapstructure:"password"`
+	EmailVerification EmailVerificationConfig `mapstructure:"email_verification"`
+	TwoFactor TwoFactorConfig `mapstructure:"two_factor"`
 }
 
 type ServerConfig struct {
@@ -35,12 +43,95 @@ type RedisConfig struct {
 }
 
 type JWTConfig struct {
-	Secret     string        `mapstructure:"secret"`
-	Expiration time.Duration `mapstructure:"expiration"`
+	Secret            string         `mapstructure:"secret"`
+	Expiration        time.Duration  `mapstructure:"expiration"`
+	RefreshExpiration time.Duration  `mapstructure:"refresh_expiration"`
+	Issuer            string         `mapstructure:"issuer"`
+	Audience          string         `mapstructure:"audience"`
+	RotationGrace     time.Duration  `mapstructure:"rotation_grace"`
+	Keys              []JWTKeyConfig `mapstructure:"keys"`
+	// AllowEphemeralKey lets the server start without Keys by generating a
+	// throwaway signing key. Meant for development only.
+	AllowEphemeralKey bool `mapstructure:"allow_ephemeral_key"`
 }
 
+// JWTKeyConfig is one signing key. NotBefore (RFC 3339) schedules when the
+// key takes over signing; keys without it are active from the start.
+type JWTKeyConfig struct {
+	KID       string `mapstructure:"kid"`
+	File      string `mapstructure:"file"`
+	NotBefore string `mapstructure:"not_before"`
+}
+
+// RateLimitConfig sets the per-user request limit and the per-address limit
+// on sign-in attempts, which also covers second-factor codes. The other
+// unauthenticated endpoints are limited per address and, where an email is
+// submitted, per email.
 type RateLimitConfig struct {
-	RequestsPerMinute int `mapstructure:"requests_per_minute"`
+	RequestsPerMinute      int `mapstructure:"requests_per_minute"`
+	LoginAttemptsPerMinute int `mapstructure:"login_attempts_per_minute"`
+	AuthRequestsPerMinute  int `mapstructure:"auth_requests_per_minute"`
+	EmailRequestsPerHour   int `mapstructure:"email_requests_per_hour"`
+}
+
+type MarkdownConfig struct {
+	TaskURLFormat string `mapstructure:"task_url_format"`
+	ExcerptLength int    `mapstructure:"excerpt_length"`
+}
+
+type AttachmentsConfig struct {
+	MaxSize      int64    `mapstructure:"max_size"`
+	AllowedTypes []string `mapstructure:"allowed_types"`
+	Storage      string   `mapstructure:"storage"`
+	LocalDir     string   `mapstructure:"local_dir"`
+	S3           S3Config `mapstructure:"s3"`
+}
+
+type S3Config struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+}
+
+// InvitationsConfig configures team and organization invitations. AcceptURL
+// and OrgAcceptURL are the frontend pages that receive the token through %s.
+type InvitationsConfig struct {
+	Secret       string        `mapstructure:"secret"`
+	TTL          time.Duration `mapstructure:"ttl"`
+	AcceptURL    string        `mapstructure:"accept_url"`
+	OrgAcceptURL string        `mapstructure:"org_accept_url"`
+}
+
+// MailConfig configures outgoing mail. Messages are written to Dir as .eml
+// files, or only logged when it is empty.
+type MailConfig struct {
+	From string `mapstructure:"from"`
+	Dir  string `mapstructure:"dir"`
+}
+
+// PasswordConfig configures password resets. ResetURL is the frontend page
+// that receives the token through %s.
+type PasswordConfig struct {
+	ResetTTL time.Duration `mapstructure:"reset_ttl"`
+	ResetURL string        `mapstructure:"reset_url"`
+}
+
+// EmailVerificationConfig configures confirmation of email addresses.
+// BlockLogin and BlockInvitations make unverified accounts unable to sign in
+// or to invite others.
+type EmailVerificationConfig struct {
+	TTL              time.Duration `mapstructure:"ttl"`
+	VerifyURL        string        `mapstructure:"verify_url"`
+	BlockLogin       bool          `mapstructure:"block_login"`
+	BlockInvitations bool          `mapstructure:"block_invitations"`
+}
+
+// TwoFactorConfig configures TOTP two-factor authentication. Issuer is the
+// name authenticator apps show next to the account.
+type TwoFactorConfig struct {
+	Issuer string `mapstructure:"issuer"`
 }
 
 func Load() (*Config, error) {
@@ -60,10 +151,42 @@ func Load() (*Config, error) {
 	v.SetDefault("database.conn_max_lifetime", 5*time.Minute)
 	v.SetDefault("redis.addr", "localhost:6379")
 	v.SetDefault("redis.db", 0)
-	v.SetDefault("jwt.expiration", 24*time.Hour)
+	v.SetDefault("jwt.expiration", 15*time.Minute)
+	v.SetDefault("jwt.refresh_expiration", 30*24*time.Hour)
+	v.SetDefault("jwt.issuer", "team-task-nexus")
+	v.SetDefault("jwt.audience", "team-task-nexus-api")
+	v.SetDefault("jwt.rotation_grace", time.Hour)
+	v.SetDefault("jwt.allow_ephemeral_key", false)
 	v.SetDefault("rate_limit.requests_per_minute", 100)
+	v.SetDefault("rate_limit.login_attempts_per_minute", 10)
+	v.SetDefault("rate_limit.auth_requests_per_minute", 20)
+	v.SetDefault("rate_limit.email_requests_per_hour", 5)
+	v.SetDefault("markdown.task_url_format", "/tasks/%d")
+	v.SetDefault("markdown.excerpt_length", 160)
+	v.SetDefault("attachments.max_size", 10<<20)
+	v.SetDefault("attachments.allowed_types", []string{
+		"image/png", "image/jpeg", "image/gif", "image/webp",
+		"text/plain", "application/pdf", "application/zip", "application/x-gzip",
+	})
+	v.SetDefault("attachments.storage", "local")
+	v.SetDefault("attachments.local_dir", "./data/attachments")
+	v.SetDefault("attachments.s3.region", "us-east-1")
+	v.SetDefault("invitations.ttl", 7*24*time.Hour)
+	v.SetDefault("invitations.accept_url", "http://localhost:3000/invitations/accept?token=%s")
+	v.SetDefault("invitations.org_accept_url", "http://localhost:3000/org-invitations/accept?token=%s")
+	v.SetDefault("mail.from", "Team Task Nexus <no-reply@localhost>")
+	v.SetDefault("mail.dir", "./data/mail")
+	v.SetDefault("password.reset_ttl", time.Hour)
+	v.SetDefault("password.reset_url", "http://localhost:3000/reset-password?token=%s")
+	v.SetDefault("email_verification.ttl", 48*time.Hour)
+	v.SetDefault("email_verification.verify_url", "http://localhost:3000/verify-email?token=%s")
+	v.SetDefault("email_verification.block_login", false)
+	v.SetDefault("email_verification.block_invitations", true)
+	v.SetDefault("two_factor.issuer", "Team Task Nexus")
 
+	// Nested keys map to variables like APP_JWT_SECRET.
 	v.SetEnvPrefix("APP")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
 	if err := v.ReadInConfig(); err != nil {
@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+type ActivityType string
+
+const (
+	ActivityTaskCreated        ActivityType = "task_created"
+	ActivityTaskUpdated        ActivityType = "task_updated"
+	ActivityCommentAdded       ActivityType = "comment_added"
+	ActivityMemberAdded        ActivityType = "member_added"
+	ActivityMemberRemoved      ActivityType = "member_removed"
+	ActivityMemberLeft         ActivityType = "member_left"
+	ActivityMemberRoleChanged  ActivityType = "member_role_changed"
+	ActivityOwnerTransferred   ActivityType = "ownership_transferred"
+	ActivityTeamUpdated        ActivityType = "team_updated"
+	ActivityTeamArchived       ActivityType = "team_archived"
+	ActivityTeamUnarchived     ActivityType = "team_unarchived"
+	ActivityCustomRoleChanged  ActivityType = "custom_role_changed"
+	ActivityCustomRoleCreated  ActivityType = "custom_role_created"
+	ActivityCustomRoleUpdated  ActivityType = "custom_role_updated"
+	ActivityCustomRoleDeleted  ActivityType = "custom_role_deleted"
+	ActivityInvitationSent     ActivityType = "invitation_sent"
+	ActivityInvitationRevoked  ActivityType = "invitation_revoked"
+	ActivityInvitationAccepted ActivityType = "invitation_accepted"
+	ActivityInvitationDeclined ActivityType = "invitation_declined"
+)
+
+// TeamEvent is a membership-level change stored in team_events; task and
+// comment activity is derived from their own tables.
+type TeamEvent struct {
+	ID           int64        `json:"id" db:"id"`
+	TeamID       int64        `json:"team_id" db:"team_id"`
+	ActorID      int64        `json:"actor_id" db:"actor_id"`
+	TargetUserID *int64       `json:"target_user_id,omitempty" db:"target_user_id"`
+	Type         ActivityType `json:"type" db:"type"`
+	Details      string       `json:"details" db:"details"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+}
+
+type ActivityEvent struct {
+	Type         ActivityType `json:"type" db:"type"`
+	SubjectID    int64        `json:"subject_id" db:"subject_id"`
+	ActorID      int64        `json:"actor_id" db:"actor_id"`
+	ActorName    string       `json:"actor_name" db:"actor_name"`
+	TaskID       *int64       `json:"task_id,omitempty" db:"task_id"`
+	TaskTitle    *string      `json:"task_title,omitempty" db:"task_title"`
+	TargetUserID *int64       `json:"target_user_id,omitempty" db:"target_user_id"`
+	Details      string       `json:"details,omitempty" db:"details"`
+	OccurredAt   time.Time    `json:"occurred_at" db:"occurred_at"`
+}
+
+// ActivityCursor points at the last event of a page; the feed is ordered by
+// (occurred_at, type, subject_id) descending.
+type ActivityCursor struct {
+	OccurredAt time.Time
+	Type       ActivityType
+	SubjectID  int64
+}
+
+type ActivityFilter struct {
+	TeamID          int64
+	ActorID         int64
+	TaskID          int64
+	Types           []ActivityType
+	Cursor          *ActivityCursor
+	Limit           int
+	ExcludeInternal bool
+}
+
+type ActivityQuery struct {
+	ActorID int64  `json:"actor_id"`
+	TaskID  int64  `json:"task_id"`
+	Types   string `json:"types"`
+	Cursor  string `json:"cursor"`
+	Limit   int    `json:"limit"`
+}
+
+type ActivityFeed struct {
+	Events     []ActivityEvent `json:"events"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+type AdminAction string
+
+const (
+	AdminActionUserDisabled    AdminAction = "user_disabled"
+	AdminActionUserEnabled     AdminAction = "user_enabled"
+	AdminActionImpersonated    AdminAction = "impersonated"
+	AdminActionImpersonatedReq AdminAction = "impersonated_request"
+	AdminActionOrphansRepaired AdminAction = "orphaned_assignees_repaired"
+)
+
+// AdminAuditEntry records an action taken through the admin API.
+type AdminAuditEntry struct {
+	ID           int64       `json:"id" db:"id"`
+	AdminID      int64       `json:"admin_id" db:"admin_id"`
+	Action       AdminAction `json:"action" db:"action"`
+	TargetUserID *int64      `json:"target_user_id,omitempty" db:"target_user_id"`
+	Details      string      `json:"details" db:"details"`
+	CreatedAt    time.Time   `json:"created_at" db:"created_at"`
+}
+
+// UserSearchFilter matches Query against the start of a user's email or
+// name; an empty Query lists everyone.
+type UserSearchFilter struct {
+	Query    string `json:"query"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
+
+type UserListResponse struct {
+	Users      []User `json:"users"`
+	Total      int    `json:"total"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+	TotalPages int    `json:"total_pages"`
+}
+
+type AdminListFilter struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+}
+
+type TeamListResponse struct {
+	Teams      []Team `json:"teams"`
+	Total      int    `json:"total"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+	TotalPages int    `json:"total_pages"`
+}
+
+type AdminAuditListResponse struct {
+	Entries    []AdminAuditEntry `json:"entries"`
+	Total      int               `json:"total"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// RepairResult reports how many tasks lost an assignee who was no longer in
+// the task's team.
+type RepairResult struct {
+	Unassigned int `json:"unassigned"`
+}
+
+// ImpersonationResponse carries a short-lived token acting as User. Requests
+// made with it are attributed to the admin in the request log.
+type ImpersonationResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      User      `json:"user"`
+}
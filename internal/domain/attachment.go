@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"io"
+	"time"
+)
+
+type Attachment struct {
+	ID          int64     `json:"id" db:"id"`
+	TaskID      int64     `json:"task_id" db:"task_id"`
+	CommentID   *int64    `json:"comment_id,omitempty" db:"comment_id"`
+	UploaderID  int64     `json:"uploader_id" db:"uploader_id"`
+	FileName    string    `json:"file_name" db:"file_name"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	StorageKey  string    `json:"-" db:"storage_key"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// AttachmentUpload is a file received from a multipart request.
+type AttachmentUpload struct {
+	FileName string
+	Size     int64
+	Content  io.Reader
+}
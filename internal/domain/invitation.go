@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+	InvitationRevoked  InvitationStatus = "revoked"
+)
+
+type Invitation struct {
+	ID         int64            `json:"id" db:"id"`
+	TeamID     int64            `json:"team_id" db:"team_id"`
+	Email      string           `json:"email" db:"email"`
+	Role       TeamRole         `json:"role" db:"role"`
+	InviterID  int64            `json:"inviter_id" db:"inviter_id"`
+	TokenHash  string           `json:"-" db:"token_hash"`
+	Status     InvitationStatus `json:"status" db:"status"`
+	ExpiresAt  time.Time        `json:"expires_at" db:"expires_at"`
+	AcceptedBy *int64           `json:"accepted_by,omitempty" db:"accepted_by"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	ResolvedAt *time.Time       `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+type InvitationTokenRequest struct {
+	Token string `json:"token"`
+}
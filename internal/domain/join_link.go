@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// JoinLink lets anyone holding the code join a team, within the limits set
+// by the owner or admin who created it.
+type JoinLink struct {
+	ID            int64      `json:"id" db:"id"`
+	TeamID        int64      `json:"team_id" db:"team_id"`
+	Code          string     `json:"code" db:"code"`
+	Role          TeamRole   `json:"role" db:"role"`
+	CreatorID     int64      `json:"creator_id" db:"creator_id"`
+	MaxUses       *int       `json:"max_uses,omitempty" db:"max_uses"`
+	UseCount      int        `json:"use_count" db:"use_count"`
+	AllowedDomain *string    `json:"allowed_domain,omitempty" db:"allowed_domain"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+func (l *JoinLink) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+func (l *JoinLink) Exhausted() bool {
+	return l.MaxUses != nil && l.UseCount >= *l.MaxUses
+}
+
+type JoinLinkUse struct {
+	ID       int64     `json:"id" db:"id"`
+	LinkID   int64     `json:"link_id" db:"link_id"`
+	UserID   int64     `json:"user_id" db:"user_id"`
+	Email    string    `json:"email" db:"email"`
+	FullName string    `json:"full_name" db:"full_name"`
+	UsedAt   time.Time `json:"used_at" db:"used_at"`
+}
+
+type CreateJoinLinkRequest struct {
+	Role          string     `json:"role"`
+	MaxUses       *int       `json:"max_uses"`
+	AllowedDomain string     `json:"allowed_domain"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,8 @@
+package domain
+
+// MailMessage is a plain-text email.
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+type MentionSourceType string
+
+const (
+	MentionSourceTask    MentionSourceType = "task"
+	MentionSourceComment MentionSourceType = "comment"
+)
+
+type Mention struct {
+	ID         int64             `json:"id" db:"id"`
+	UserID     int64             `json:"user_id" db:"user_id"`
+	AuthorID   int64             `json:"author_id" db:"author_id"`
+	AuthorName string            `json:"author_name" db:"author_name"`
+	TeamID     int64             `json:"team_id" db:"team_id"`
+	TaskID     int64             `json:"task_id" db:"task_id"`
+	TaskTitle  string            `json:"task_title" db:"task_title"`
+	CommentID  *int64            `json:"comment_id,omitempty" db:"comment_id"`
+	Source     MentionSourceType `json:"source" db:"source"`
+	CreatedAt  time.Time         `json:"created_at" db:"created_at"`
+}
+
+// MentionSource describes the text that contained the mentions.
+type MentionSource struct {
+	AuthorID  int64
+	Task      *Task
+	CommentID *int64
+	Internal  bool
+}
+
+type MentionResult struct {
+	Mentioned  []User
+	Unresolved []string
+}
+
+type MentionFilter struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+}
+
+type MentionListResponse struct {
+	Mentions   []Mention `json:"mentions"`
+	Total      int       `json:"total"`
+	Page       int       `json:"page"`
+	PageSize   int       `json:"page_size"`
+	TotalPages int       `json:"total_pages"`
+}
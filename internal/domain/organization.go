@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+type OrgRole string
+
+const (
+	OrgRoleAdmin   OrgRole = "admin"
+	OrgRoleMember  OrgRole = "member"
+	OrgRoleBilling OrgRole = "billing"
+)
+
+// Organization groups teams. Personal organizations are created for users
+// who make a team without choosing one.
+type Organization struct {
+	ID        int64     `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Personal  bool      `json:"personal" db:"personal"`
+	CreatedBy int64     `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type OrgMember struct {
+	OrgID    int64     `json:"org_id" db:"org_id"`
+	UserID   int64     `json:"user_id" db:"user_id"`
+	Role     OrgRole   `json:"role" db:"role"`
+	JoinedAt time.Time `json:"joined_at" db:"joined_at"`
+}
+
+type OrgMemberDetails struct {
+	OrgID    int64     `json:"org_id" db:"org_id"`
+	UserID   int64     `json:"user_id" db:"user_id"`
+	Role     OrgRole   `json:"role" db:"role"`
+	Email    string    `json:"email" db:"email"`
+	FullName string    `json:"full_name" db:"full_name"`
+	JoinedAt time.Time `json:"joined_at" db:"joined_at"`
+}
+
+// OrgInvitation is a pending offer to join an organization, sent by email
+// whether or not the address belongs to a registered user.
+type OrgInvitation struct {
+	ID         int64            `json:"id" db:"id"`
+	OrgID      int64            `json:"org_id" db:"org_id"`
+	Email      string           `json:"email" db:"email"`
+	Role       OrgRole          `json:"role" db:"role"`
+	InviterID  int64            `json:"inviter_id" db:"inviter_id"`
+	TokenHash  string           `json:"-" db:"token_hash"`
+	Status     InvitationStatus `json:"status" db:"status"`
+	ExpiresAt  time.Time        `json:"expires_at" db:"expires_at"`
+	AcceptedBy *int64           `json:"accepted_by,omitempty" db:"accepted_by"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	ResolvedAt *time.Time       `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name *string `json:"name"`
+}
+
+type InviteOrgMemberRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type UpdateOrgMemberRoleRequest struct {
+	Role string `json:"role"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+type TransferStatus string
+
+const (
+	TransferPending   TransferStatus = "pending"
+	TransferAccepted  TransferStatus = "accepted"
+	TransferDeclined  TransferStatus = "declined"
+	TransferCancelled TransferStatus = "cancelled"
+	TransferExpired   TransferStatus = "expired"
+)
+
+// OwnershipTransfer records a nomination of a new team owner. Resolved rows
+// are kept as the audit trail of who handed the team to whom.
+type OwnershipTransfer struct {
+	ID         int64          `json:"id" db:"id"`
+	TeamID     int64          `json:"team_id" db:"team_id"`
+	FromUserID int64          `json:"from_user_id" db:"from_user_id"`
+	ToUserID   int64          `json:"to_user_id" db:"to_user_id"`
+	Status     TransferStatus `json:"status" db:"status"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+	ExpiresAt  time.Time      `json:"expires_at" db:"expires_at"`
+	ResolvedAt *time.Time     `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+func (t *OwnershipTransfer) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+type NominateOwnerRequest struct {
+	UserID int64 `json:"user_id"`
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Permission names an action a team member may perform. Viewing the team and
+// its tasks needs no permission beyond membership.
+type Permission string
+
+const (
+	PermTeamUpdate       Permission = "team.update"
+	PermTeamArchive      Permission = "team.archive"
+	PermTeamDelete       Permission = "team.delete"
+	PermTeamInvite       Permission = "team.invite"
+	PermMemberManage     Permission = "member.manage"
+	PermRoleManage       Permission = "role.manage"
+	PermTaskCreate       Permission = "task.create"
+	PermTaskUpdate       Permission = "task.update"
+	PermTaskDelete       Permission = "task.delete"
+	PermCommentCreate    Permission = "comment.create"
+	PermCommentModerate  Permission = "comment.moderate"
+	PermAttachmentUpload Permission = "attachment.upload"
+	PermAttachmentDelete Permission = "attachment.delete"
+)
+
+// CustomRole is a team-defined set of permissions that replaces the default
+// permissions of a member it is assigned to.
+type CustomRole struct {
+	ID          int64        `json:"id"`
+	TeamID      int64        `json:"team_id"`
+	Name        string       `json:"name"`
+	Permissions []Permission `json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type CustomRoleRequest struct {
+	Name        string       `json:"name"`
+	Permissions []Permission `json:"permissions"`
+}
+
+type AssignCustomRoleRequest struct {
+	RoleID *int64 `json:"role_id"`
+}
+
+// PermissionSummary lists what the caller may do on a team, task or comment,
+// including rights that come from authoring the resource.
+type PermissionSummary struct {
+	Resource     string       `json:"resource"`
+	ResourceID   int64        `json:"resource_id"`
+	TeamID       int64        `json:"team_id"`
+	Role         TeamRole     `json:"role"`
+	CustomRoleID *int64       `json:"custom_role_id,omitempty"`
+	Permissions  []Permission `json:"permissions"`
+}
+
+// ParsePermissions reads a comma-separated permission list as stored in
+// team_roles.permissions.
+func ParsePermissions(s string) []Permission {
+	perms := []Permission{}
+	for _, p := range strings.Split(s, ",") {
+		if p != "" {
+			perms = append(perms, Permission(p))
+		}
+	}
+	return perms
+}
+
+func JoinPermissions(perms []Permission) string {
+	parts := make([]string, len(perms))
+	for i, p := range perms {
+		parts[i] = string(p)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,25 @@
+package domain
+
+type ReactionTarget string
+
+const (
+	ReactionTargetTask    ReactionTarget = "task"
+	ReactionTargetComment ReactionTarget = "comment"
+)
+
+// ReactionSummary aggregates one emoji on a task or comment.
+type ReactionSummary struct {
+	Emoji       string `json:"emoji" db:"emoji"`
+	Count       int    `json:"count" db:"count"`
+	ReactedByMe bool   `json:"reacted_by_me" db:"reacted_by_me"`
+}
+
+type ToggleReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+type ReactionToggleResult struct {
+	Emoji     string            `json:"emoji"`
+	Reacted   bool              `json:"reacted"`
+	Reactions []ReactionSummary `json:"reactions"`
+}
@@ -34,6 +34,10 @@ type Task struct {
 	DueDate     sql.NullTime   `json:"due_date" db:"due_date"`
 	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+
+	UnresolvedMentions []string `json:"unresolved_mentions,omitempty" db:"-"`
+	DescriptionHTML    string   `json:"description_html,omitempty" db:"-"`
+	Excerpt            string   `json:"excerpt,omitempty" db:"-"`
 }
 
 type CreateTaskRequest struct {
@@ -54,12 +58,47 @@ type UpdateTaskRequest struct {
 	DueDate     *string `json:"due_date,omitempty"`
 }
 
+// ImportTasksRequest brings tasks over from another tracker. Status, when
+// set, is applied after creation so the task history records where it came
+// from.
+type ImportTasksRequest struct {
+	TeamID int64          `json:"team_id"`
+	Tasks  []ImportedTask `json:"tasks"`
+}
+
+type ImportedTask struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Priority    int    `json:"priority"`
+	Status      string `json:"status,omitempty"`
+	AssigneeID  *int64 `json:"assignee_id,omitempty"`
+	DueDate     string `json:"due_date,omitempty"`
+}
+
+type ImportTasksResponse struct {
+	Tasks []Task `json:"tasks"`
+}
+
 type TaskFilter struct {
 	TeamID     int64  `json:"team_id"`
 	Status     string `json:"status"`
 	AssigneeID int64  `json:"assignee_id"`
 	Page       int    `json:"page"`
 	PageSize   int    `json:"page_size"`
+
+	// GuestID restricts the list to the tasks granted to a task-scoped guest.
+	GuestID int64 `json:"-"`
+
+	// Access restricts a list without TeamID to the tasks the caller can read.
+	Access *TaskAccess `json:"-"`
+}
+
+// TaskAccess lists the teams whose tasks a user may read. In ScopedTeams
+// the user is a task-scoped guest and only sees the tasks granted to them.
+type TaskAccess struct {
+	UserID      int64
+	Teams       []int64
+	ScopedTeams []int64
 }
 
 type TaskListResponse struct {
@@ -73,6 +112,7 @@ type TaskListResponse struct {
 type OrphanedAssignee struct {
 	TaskID       int64  `json:"task_id" db:"id"`
 	TaskTitle    string `json:"task_title" db:"title"`
+	TeamID       int64  `json:"team_id" db:"team_id"`
 	AssigneeID   int64  `json:"assignee_id" db:"assignee_id"`
 	AssigneeName string `json:"assignee_name" db:"full_name"`
 }
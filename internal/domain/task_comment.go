@@ -3,14 +3,93 @@ package domain
 import "time"
 
 type TaskComment struct {
+	ID         int64      `json:"id" db:"id"`
+	TaskID     int64      `json:"task_id" db:"task_id"`
+	UserID     int64      `json:"user_id" db:"user_id"`
+	ParentID   *int64     `json:"parent_id,omitempty" db:"parent_id"`
+	RootID     *int64     `json:"root_id,omitempty" db:"root_id"`
+	Depth      int        `json:"depth" db:"depth"`
+	Internal   bool       `json:"internal" db:"internal"`
+	Content    string     `json:"content" db:"content"`
+	EditedAt   *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	DeletedBy  *int64     `json:"deleted_by,omitempty" db:"deleted_by"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+	ResolvedBy *int64     `json:"resolved_by,omitempty" db:"resolved_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+
+	UnresolvedMentions []string          `json:"unresolved_mentions,omitempty" db:"-"`
+	Reactions          []ReactionSummary `json:"reactions,omitempty" db:"-"`
+	ContentHTML        string            `json:"content_html,omitempty" db:"-"`
+	Excerpt            string            `json:"excerpt,omitempty" db:"-"`
+}
+
+// MaxCommentDepth limits reply nesting; top-level comments have depth 0.
+const MaxCommentDepth = 3
+
+func (c *TaskComment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// ThreadID returns the id of the top-level comment the comment belongs to.
+func (c *TaskComment) ThreadID() int64 {
+	if c.RootID != nil {
+		return *c.RootID
+	}
+	return c.ID
+}
+
+type CommentNode struct {
+	TaskComment
+	ReplyCount int           `json:"reply_count"`
+	Replies    []CommentNode `json:"replies"`
+}
+
+// CommentRevision holds the content a comment had before an edit.
+type CommentRevision struct {
 	ID        int64     `json:"id" db:"id"`
-	TaskID    int64     `json:"task_id" db:"task_id"`
-	UserID    int64     `json:"user_id" db:"user_id"`
+	CommentID int64     `json:"comment_id" db:"comment_id"`
 	Content   string    `json:"content" db:"content"`
+	EditedBy  int64     `json:"edited_by" db:"edited_by"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type CreateCommentRequest struct {
+	Content  string `json:"content"`
+	ParentID *int64 `json:"parent_id,omitempty"`
+	Internal bool   `json:"internal,omitempty"`
+}
+
+type UpdateCommentRequest struct {
 	Content string `json:"content"`
 }
+
+// CommentCursor points at a comment in (created_at, id) order. Before selects
+// the page preceding the comment instead of the one following it.
+type CommentCursor struct {
+	CreatedAt time.Time
+	ID        int64
+	Before    bool
+}
+
+type CommentFilter struct {
+	TaskID          int64
+	Since           *time.Time
+	Cursor          *CommentCursor
+	Limit           int
+	ExcludeInternal bool
+}
+
+type CommentQuery struct {
+	Limit  int
+	Cursor string
+	Since  string
+}
+
+type CommentPage struct {
+	Comments   []TaskComment `json:"comments"`
+	Total      int           `json:"total"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	PrevCursor string        `json:"prev_cursor,omitempty"`
+}
@@ -2,12 +2,72 @@ package domain
 
 import "time"
 
+type ChangeSource string
+
+const (
+	ChangeSourceAPI        ChangeSource = "api"
+	ChangeSourceAutomation ChangeSource = "automation"
+	ChangeSourceImport     ChangeSource = "import"
+)
+
+type HistoryValueType string
+
+const (
+	HistoryValueString   HistoryValueType = "string"
+	HistoryValueText     HistoryValueType = "text"
+	HistoryValueStatus   HistoryValueType = "status"
+	HistoryValuePriority HistoryValueType = "priority"
+	HistoryValueUser     HistoryValueType = "user"
+	HistoryValueDate     HistoryValueType = "date"
+)
+
 type TaskHistory struct {
-	ID        int64     `json:"id" db:"id"`
-	TaskID    int64     `json:"task_id" db:"task_id"`
-	UserID    int64     `json:"user_id" db:"user_id"`
-	Field     string    `json:"field" db:"field"`
-	OldValue  string    `json:"old_value" db:"old_value"`
-	NewValue  string    `json:"new_value" db:"new_value"`
-	ChangedAt time.Time `json:"changed_at" db:"changed_at"`
+	ID          int64            `json:"id" db:"id"`
+	ChangeSetID int64            `json:"change_set_id" db:"change_set_id"`
+	TaskID      int64            `json:"task_id" db:"task_id"`
+	UserID      int64            `json:"user_id" db:"user_id"`
+	Field       string           `json:"field" db:"field"`
+	ValueType   HistoryValueType `json:"value_type" db:"value_type"`
+	OldValue    string           `json:"old_value" db:"old_value"`
+	NewValue    string           `json:"new_value" db:"new_value"`
+	ChangedAt   time.Time        `json:"changed_at" db:"changed_at"`
+}
+
+// TaskChangeSet groups all field changes made by a single edit of a task.
+type TaskChangeSet struct {
+	ID        int64             `json:"id" db:"id"`
+	TaskID    int64             `json:"task_id" db:"task_id"`
+	UserID    int64             `json:"user_id" db:"user_id"`
+	UserName  string            `json:"user_name" db:"user_name"`
+	RequestID string            `json:"request_id,omitempty" db:"request_id"`
+	Source    ChangeSource      `json:"source" db:"source"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	Changes   []TaskFieldChange `json:"changes" db:"-"`
+}
+
+// TaskFieldChange carries old and new values decoded according to Type:
+// users become HistoryUserRef, priorities become ints, empty values become null.
+type TaskFieldChange struct {
+	Field    string           `json:"field"`
+	Type     HistoryValueType `json:"type"`
+	OldValue interface{}      `json:"old_value"`
+	NewValue interface{}      `json:"new_value"`
+}
+
+type HistoryUserRef struct {
+	ID       int64  `json:"id"`
+	FullName string `json:"full_name"`
+}
+
+type HistoryFilter struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+}
+
+type TaskHistoryResponse struct {
+	ChangeSets []TaskChangeSet `json:"change_sets"`
+	Total      int             `json:"total"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"page_size"`
+	TotalPages int             `json:"total_pages"`
 }
@@ -8,26 +8,131 @@ const (
 	TeamRoleOwner  TeamRole = "owner"
 	TeamRoleAdmin  TeamRole = "admin"
 	TeamRoleMember TeamRole = "member"
+	TeamRoleGuest  TeamRole = "guest"
 )
 
+// Rank orders roles so that a higher rank outranks a lower one.
+func (r TeamRole) Rank() int {
+	switch r {
+	case TeamRoleOwner:
+		return 4
+	case TeamRoleAdmin:
+		return 3
+	case TeamRoleMember:
+		return 2
+	case TeamRoleGuest:
+		return 1
+	}
+	return 0
+}
+
+// Team may sit under a parent team, whose members get at most InheritedRole
+// here; a nil InheritedRole turns inheritance off.
 type Team struct {
-	ID          int64     `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name"`
-	Description string    `json:"description" db:"description"`
-	OwnerID     int64     `json:"owner_id" db:"owner_id"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID            int64      `json:"id" db:"id"`
+	OrgID         int64      `json:"org_id" db:"org_id"`
+	ParentID      *int64     `json:"parent_id,omitempty" db:"parent_id"`
+	InheritedRole *TeamRole  `json:"inherited_role,omitempty" db:"inherited_role"`
+	Name          string     `json:"name" db:"name"`
+	Description   string     `json:"description" db:"description"`
+	OwnerID       int64      `json:"owner_id" db:"owner_id"`
+	Require2FA    bool       `json:"require_2fa" db:"require_2fa"`
+	ArchivedAt    *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// TeamTree is a team with its sub-teams, as returned by the tree listing.
+type TeamTree struct {
+	Team
+	Children []TeamTree `json:"children"`
 }
 
 type TeamMember struct {
 	TeamID int64    `json:"team_id" db:"team_id"`
 	UserID int64    `json:"user_id" db:"user_id"`
 	Role   TeamRole `json:"role" db:"role"`
+
+	// CustomRoleID points at a team-defined role; CustomPermissions is its
+	// comma-separated permission list, loaded with the membership.
+	CustomRoleID      *int64  `json:"custom_role_id,omitempty" db:"custom_role_id"`
+	CustomPermissions *string `json:"-" db:"custom_permissions"`
+
+	// TaskScoped limits a guest to the tasks granted in team_guest_tasks.
+	TaskScoped bool `json:"task_scoped,omitempty" db:"task_scoped"`
+
+	// ViaOrg marks an org admin who is not a direct member; such users act
+	// as team admins.
+	ViaOrg bool `json:"via_org,omitempty" db:"via_org"`
+
+	// InheritedFrom is the ancestor team whose membership grants access when
+	// the user is not a direct member.
+	InheritedFrom *int64 `json:"inherited_from,omitempty" db:"inherited_from"`
+
+	// TeamArchived is loaded with the membership so write paths can reject
+	// changes to archived teams without another query.
+	TeamArchived bool `json:"-" db:"team_archived"`
+
+	// TeamRequire2FA and TwoFactorEnabled are loaded with the membership
+	// too, so access to teams that require 2FA is checked without another
+	// query.
+	TeamRequire2FA   bool `json:"-" db:"team_require_2fa"`
+	TwoFactorEnabled bool `json:"-" db:"two_factor_enabled"`
+}
+
+// IsDirect reports whether the user has a membership row in the team itself
+// rather than access through the organization or a parent team.
+func (m *TeamMember) IsDirect() bool {
+	return !m.ViaOrg && m.InheritedFrom == nil
+}
+
+// IsGuest reports whether the member is an external collaborator, who may
+// not see internal comments.
+func (m *TeamMember) IsGuest() bool {
+	return m.Role == TeamRoleGuest
+}
+
+type TeamMemberDetails struct {
+	TeamID           int64    `json:"team_id" db:"team_id"`
+	UserID           int64    `json:"user_id" db:"user_id"`
+	Role             TeamRole `json:"role" db:"role"`
+	CustomRoleID     *int64   `json:"custom_role_id,omitempty" db:"custom_role_id"`
+	TaskScoped       bool     `json:"task_scoped,omitempty" db:"task_scoped"`
+	Email            string   `json:"email" db:"email"`
+	FullName         string   `json:"full_name" db:"full_name"`
+	TwoFactorEnabled bool     `json:"two_factor_enabled" db:"two_factor_enabled"`
 }
 
 type CreateTeamRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	OrgID       int64  `json:"org_id"`
+	ParentID    *int64 `json:"parent_id,omitempty"`
+}
+
+type UpdateTeamRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Require2FA  *bool   `json:"require_2fa"`
+}
+
+// SetParentRequest moves a team under ParentID, or to the top level when it
+// is null. InheritedRole is admin, member, guest or none; empty keeps the
+// current setting.
+type SetParentRequest struct {
+	ParentID      *int64 `json:"parent_id"`
+	InheritedRole string `json:"inherited_role"`
+}
+
+type DeleteTeamRequest struct {
+	ConfirmationToken string `json:"confirmation_token"`
+}
+
+// TeamDeletionToken must be echoed back to delete the team; it is bound to
+// the team and the owner who requested it.
+type TeamDeletionToken struct {
+	Token     string    `json:"confirmation_token"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type InviteRequest struct {
@@ -35,8 +140,19 @@ type InviteRequest struct {
 	Role  string `json:"role"`
 }
 
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// GuestScopeRequest limits a guest to the listed tasks; a null task_ids
+// lifts the limit.
+type GuestScopeRequest struct {
+	TaskIDs []int64 `json:"task_ids"`
+}
+
 type TeamStats struct {
 	ID          int64  `json:"id" db:"id"`
+	ParentID    *int64 `json:"parent_id,omitempty" db:"parent_id"`
 	Name        string `json:"name" db:"name"`
 	MemberCount int    `json:"member_count" db:"member_count"`
 	DoneLast7D  int    `json:"done_last_7d" db:"done_last_7d"`
@@ -0,0 +1,38 @@
+package domain
+
+// TwoFactorEnrollment is returned when enrollment starts. The secret is
+// shown so it can be typed in when the URI cannot be scanned.
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// EnrollTwoFactorRequest asks for the password again, so a stolen session
+// cannot bind the account to an attacker's authenticator.
+type EnrollTwoFactorRequest struct {
+	Password string `json:"password"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password     string `json:"password"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// RecoveryCodesResponse lists freshly generated recovery codes. They are
+// shown only once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// LoginChallengeRequest completes a login with either a TOTP code or a
+// recovery code.
+type LoginChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
@@ -2,19 +2,54 @@ package domain
 
 import "time"
 
+// SystemRole is a user's role across the whole installation, independent of
+// any team or organization.
+type SystemRole string
+
+const (
+	SystemRoleUser  SystemRole = "user"
+	SystemRoleAdmin SystemRole = "admin"
+)
+
 type User struct {
-	ID           int64     `json:"id" db:"id"`
-	Email        string    `json:"email" db:"email"`
-	PasswordHash string    `json:"-" db:"password_hash"`
-	FullName     string    `json:"full_name" db:"full_name"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID              int64      `json:"id" db:"id"`
+	Email           string     `json:"email" db:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+	PasswordHash    string     `json:"-" db:"password_hash"`
+	TOTPSecret      *string    `json:"-" db:"totp_secret"`
+	TOTPEnabledAt   *time.Time `json:"totp_enabled_at,omitempty" db:"totp_enabled_at"`
+	TOTPLastCounter int64      `json:"-" db:"totp_last_counter"`
+	FullName        string     `json:"full_name" db:"full_name"`
+	SystemRole      SystemRole `json:"system_role" db:"system_role"`
+	DisabledAt      *time.Time `json:"disabled_at,omitempty" db:"disabled_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+func (u *User) IsSystemAdmin() bool {
+	return u.SystemRole == SystemRoleAdmin
+}
+
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) TwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// EmailVerificationPolicy says what an account cannot do until its email
+// address is verified.
+type EmailVerificationPolicy struct {
+	BlockLogin       bool
+	BlockInvitations bool
 }
 
 type RegisterRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	FullName string `json:"full_name"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	FullName    string `json:"full_name"`
+	InviteToken string `json:"invite_token,omitempty"`
 }
 
 type LoginRequest struct {
@@ -22,7 +57,112 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// AuthResponse carries a short-lived access token and the refresh token
+// that renews it. Impersonation tokens come without a refresh token.
+// Registration returns no tokens at all when the account has to verify its
+// email before signing in, and a login with two-factor authentication
+// returns only a challenge token for the second step.
 type AuthResponse struct {
+	Token                string    `json:"token,omitempty"`
+	ExpiresAt            time.Time `json:"expires_at,omitzero"`
+	RefreshToken         string    `json:"refresh_token,omitempty"`
+	VerificationRequired bool      `json:"verification_required,omitempty"`
+	TwoFactorRequired    bool      `json:"two_factor_required,omitempty"`
+	ChallengeToken       string    `json:"challenge_token,omitempty"`
+	User                 User      `json:"user"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RefreshToken is one link of a rotation chain. All tokens descending from
+// one login share FamilyID; presenting a token that was already rotated
+// revokes the whole family.
+type RefreshToken struct {
+	ID        int64      `db:"id"`
+	UserID    int64      `db:"user_id"`
+	FamilyID  string     `db:"family_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RotatedAt *time.Time `db:"rotated_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// AccessClaims identify the access token a request was made with.
+// FamilyID is empty for impersonation tokens. For a personal access token
+// PersonalTokenID is set, TokenID and FamilyID are empty and Scopes and
+// TeamID carry its limits.
+type AccessClaims struct {
+	UserID          int64
+	TokenID         string
+	FamilyID        string
+	ExpiresAt       time.Time
+	ImpersonatorID  int64
+	PersonalTokenID int64
+	Scopes          []TokenScope
+	TeamID          *int64
+}
+
+func (c *AccessClaims) IsPersonalToken() bool {
+	return c.PersonalTokenID != 0
+}
+
+// HasScope reports whether the token may perform writes of scope. Session
+// tokens are not scoped.
+func (c *AccessClaims) HasScope(scope TokenScope) bool {
+	if !c.IsPersonalToken() {
+		return true
+	}
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PasswordResetToken is a single-use token mailed by the forgot-password
+// flow; only its hash is stored.
+type PasswordResetToken struct {
+	ID        int64      `db:"id"`
+	UserID    int64      `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+// EmailVerificationToken is a single-use token mailed to confirm an email
+// address; only its hash is stored.
+type EmailVerificationToken struct {
+	ID        int64      `db:"id"`
+	UserID    int64      `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+type VerifyEmailRequest struct {
 	Token string `json:"token"`
-	User  User   `json:"user"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
 }
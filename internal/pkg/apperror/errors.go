@@ -53,6 +53,10 @@ func NotFound(msg string) *AppError {
 	return New(http.StatusNotFound, msg)
 }
 
+func Forbidden(msg string) *AppError {
+	return New(http.StatusForbidden, msg)
+}
+
 func Internal(msg string, err error) *AppError {
 	return Wrap(http.StatusInternalServerError, msg, err)
 }
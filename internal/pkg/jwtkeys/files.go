@@ -0,0 +1,52 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+)
+
+// LoadFile reads a PEM private key: PKCS#8 (RSA or Ed25519) or PKCS#1 RSA.
+func LoadFile(kid, path string, notBefore time.Time) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, fmt.Errorf("read key %q: %w", kid, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, fmt.Errorf("key %q: no PEM block in %s", kid, path)
+	}
+
+	var parsed any
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("key %q: unsupported PEM block %q", kid, block.Type)
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("parse key %q: %w", kid, err)
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return Key{}, fmt.Errorf("key %q: not a private key", kid)
+	}
+	return Key{KID: kid, NotBefore: notBefore, Private: signer}, nil
+}
+
+// Generate returns a fresh Ed25519 key, for tests and local runs without
+// configured keys. Tokens signed with it do not survive a restart.
+func Generate(kid string) (Key, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return Key{}, fmt.Errorf("generate key: %w", err)
+	}
+	return Key{KID: kid, Private: private}, nil
+}
@@ -0,0 +1,88 @@
+package jwtkeys
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pkcs8 := func(key any) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		return der
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+
+	garbage := filepath.Join(t.TempDir(), "garbage.pem")
+	require.NoError(t, os.WriteFile(garbage, []byte("not a key"), 0o600))
+
+	tests := []struct {
+		name string
+		path string
+		want any
+		ok   bool
+	}{
+		{"pkcs8 ed25519", writePEM(t, "PRIVATE KEY", pkcs8(edKey)), edKey, true},
+		{"pkcs8 rsa", writePEM(t, "PRIVATE KEY", pkcs8(rsaKey)), rsaKey, true},
+		{"pkcs1 rsa", writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), rsaKey, true},
+		{"unsupported block", writePEM(t, "EC PRIVATE KEY", ecDER), nil, false},
+		{"corrupt der", writePEM(t, "PRIVATE KEY", []byte("junk")), nil, false},
+		{"no pem block", garbage, nil, false},
+		{"missing file", filepath.Join(t.TempDir(), "missing.pem"), nil, false},
+	}
+	notBefore := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadFile("k1", tt.path, notBefore)
+			if !tt.ok {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "k1", key.KID)
+			assert.Equal(t, notBefore, key.NotBefore)
+			assert.Equal(t, tt.want, key.Private)
+		})
+	}
+}
+
+// An EC key loads as a signer but the key ring refuses it.
+func TestLoadFile_UnsupportedKeyRejectedByRing(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+
+	key, err := LoadFile("ec", writePEM(t, "PRIVATE KEY", der), time.Time{})
+	require.NoError(t, err)
+
+	_, err = NewKeyRing([]Key{key}, Options{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyRing_JWKS(t *testing.T) {
+	now := time.Now()
+	retired := newEd25519(t, "retired", now.Add(-3*time.Hour))
+	rsaKey := newRSA(t, "rsa", 2048)
+	rsaKey.NotBefore = now.Add(-2 * time.Hour)
+	edKey := newEd25519(t, "ed", now.Add(time.Hour))
+	ring, err := NewKeyRing([]Key{retired, rsaKey, edKey}, Options{Grace: 30 * time.Minute})
+	require.NoError(t, err)
+
+	set := ring.JWKS()
+
+	// The retired key is past its grace period; the scheduled one is
+	// published ahead of use.
+	require.Len(t, set.Keys, 2)
+	rsaPub := rsaKey.Private.Public().(*rsa.PublicKey)
+	edPub := edKey.Private.Public().(ed25519.PublicKey)
+
+	tests := []struct {
+		name string
+		got  JWK
+		want JWK
+	}{
+		{"rsa", set.Keys[0], JWK{
+			KID: "rsa", Kty: "RSA", Alg: "RS256", Use: "sig",
+			N: base64.RawURLEncoding.EncodeToString(rsaPub.N.Bytes()),
+			E: "AQAB",
+		}},
+		{"ed25519", set.Keys[1], JWK{
+			KID: "ed", Kty: "OKP", Alg: "EdDSA", Use: "sig", Crv: "Ed25519",
+			X: base64.RawURLEncoding.EncodeToString(edPub),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+
+	n, err := base64.RawURLEncoding.DecodeString(set.Keys[0].N)
+	require.NoError(t, err)
+	assert.Equal(t, 0, new(big.Int).SetBytes(n).Cmp(rsaPub.N))
+}
+
+func TestKeyRing_JWKS_JSON(t *testing.T) {
+	ring, err := NewKeyRing([]Key{newEd25519(t, "ed", time.Time{})}, Options{})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(ring.JWKS())
+	require.NoError(t, err)
+
+	var decoded map[string][]map[string]string
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded["keys"], 1)
+	key := decoded["keys"][0]
+	assert.Equal(t, "ed", key["kid"])
+	assert.NotContains(t, key, "n")
+	assert.NotContains(t, key, "e")
+	assert.Len(t, key["x"], 43)
+}
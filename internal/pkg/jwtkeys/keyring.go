@@ -0,0 +1,147 @@
+// Package jwtkeys signs and verifies access tokens with asymmetric keys
+// (RS256 or EdDSA). Keys are identified by kid and follow a schedule: the
+// newest key whose NotBefore has passed signs new tokens, and a replaced key
+// keeps verifying for a grace period so tokens it signed run out naturally.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrNoActiveKey = errors.New("no active signing key")
+
+type Key struct {
+	KID       string
+	NotBefore time.Time
+	Private   crypto.Signer
+}
+
+type Options struct {
+	Issuer   string
+	Audience string
+	// Grace is how long a replaced key is still accepted. It should be at
+	// least the access token lifetime.
+	Grace time.Duration
+}
+
+type KeyRing struct {
+	keys []Key
+	opts Options
+}
+
+func NewKeyRing(keys []Key, opts Options) (*KeyRing, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("jwtkeys: at least one key is required")
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.KID == "" {
+			return nil, errors.New("jwtkeys: key without kid")
+		}
+		if seen[k.KID] {
+			return nil, fmt.Errorf("jwtkeys: duplicate kid %q", k.KID)
+		}
+		seen[k.KID] = true
+		if _, err := signingMethod(k.Private); err != nil {
+			return nil, fmt.Errorf("jwtkeys: key %q: %w", k.KID, err)
+		}
+	}
+
+	sorted := append([]Key(nil), keys...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].NotBefore.Before(sorted[j].NotBefore) })
+	return &KeyRing{keys: sorted, opts: opts}, nil
+}
+
+// Sign adds iss and aud to claims and signs them with the active key.
+func (r *KeyRing) Sign(claims jwt.MapClaims) (string, error) {
+	key, ok := r.active(time.Now())
+	if !ok {
+		return "", ErrNoActiveKey
+	}
+	method, _ := signingMethod(key.Private)
+
+	claims["iss"] = r.opts.Issuer
+	claims["aud"] = r.opts.Audience
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = key.KID
+	return token.SignedString(key.Private)
+}
+
+// Parse verifies token with the key named by its kid and checks the
+// expiry, issuer and audience.
+func (r *KeyRing) Parse(token string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, r.keyfunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(r.opts.Issuer),
+		jwt.WithAudience(r.opts.Audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (r *KeyRing) keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	for _, k := range r.published(time.Now()) {
+		if k.KID != kid {
+			continue
+		}
+		method, _ := signingMethod(k.Private)
+		if token.Method.Alg() != method.Alg() {
+			return nil, errors.New("signing method does not match key")
+		}
+		return k.Private.Public(), nil
+	}
+	return nil, fmt.Errorf("unknown kid %q", kid)
+}
+
+// active is the newest key that has reached its NotBefore.
+func (r *KeyRing) active(now time.Time) (Key, bool) {
+	for i := len(r.keys) - 1; i >= 0; i-- {
+		if !r.keys[i].NotBefore.After(now) {
+			return r.keys[i], true
+		}
+	}
+	return Key{}, false
+}
+
+// published are the keys verifiers should know about: scheduled ones, so
+// they are cached before use, the active one and the ones still in grace.
+func (r *KeyRing) published(now time.Time) []Key {
+	var keys []Key
+	for i, k := range r.keys {
+		if i+1 < len(r.keys) {
+			replacedAt := r.keys[i+1].NotBefore
+			if !replacedAt.After(now) && !replacedAt.Add(r.opts.Grace).After(now) {
+				continue
+			}
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func signingMethod(key crypto.Signer) (jwt.SigningMethod, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return nil, errors.New("RSA key must be at least 2048 bits")
+		}
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
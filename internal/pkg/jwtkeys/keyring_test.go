@@ -0,0 +1,194 @@
+package jwtkeys
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEd25519(t *testing.T, kid string, notBefore time.Time) Key {
+	t.Helper()
+	key, err := Generate(kid)
+	require.NoError(t, err)
+	key.NotBefore = notBefore
+	return key
+}
+
+func newRSA(t *testing.T, kid string, bits int) Key {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	return Key{KID: kid, Private: private}
+}
+
+func kids(keys []Key) []string {
+	var out []string
+	for _, k := range keys {
+		out = append(out, k.KID)
+	}
+	return out
+}
+
+func TestNewKeyRing_Validation(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ed := newEd25519(t, "a", time.Time{})
+
+	tests := []struct {
+		name string
+		keys []Key
+		ok   bool
+	}{
+		{"one key", []Key{ed}, true},
+		{"rsa 2048", []Key{newRSA(t, "r", 2048)}, true},
+		{"no keys", nil, false},
+		{"missing kid", []Key{{Private: ed.Private}}, false},
+		{"duplicate kid", []Key{ed, ed}, false},
+		{"short rsa", []Key{newRSA(t, "r", 1024)}, false},
+		{"unsupported type", []Key{{KID: "ec", Private: ecKey}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring, err := NewKeyRing(tt.keys, Options{})
+			if tt.ok {
+				assert.NoError(t, err)
+				assert.NotNil(t, ring)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestKeyRing_Schedule(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	// Passed out of order: the ring sorts keys by NotBefore.
+	ring, err := NewKeyRing([]Key{
+		newEd25519(t, "c", t0.Add(time.Hour)),
+		newEd25519(t, "a", t0.Add(-2*time.Hour)),
+		newEd25519(t, "b", t0),
+	}, Options{Grace: 30 * time.Minute})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		active    string
+		published []string
+	}{
+		{"before any key", t0.Add(-3 * time.Hour), "", []string{"a", "b", "c"}},
+		{"first key", t0.Add(-time.Hour), "a", []string{"a", "b", "c"}},
+		{"replaced key in grace", t0.Add(10 * time.Minute), "b", []string{"a", "b", "c"}},
+		{"grace ends", t0.Add(30 * time.Minute), "b", []string{"b", "c"}},
+		{"scheduled key takes over", t0.Add(time.Hour), "c", []string{"b", "c"}},
+		{"only the last key left", t0.Add(2 * time.Hour), "c", []string{"c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ok := ring.active(tt.now)
+			assert.Equal(t, tt.active != "", ok)
+			assert.Equal(t, tt.active, key.KID)
+			assert.Equal(t, tt.published, kids(ring.published(tt.now)))
+		})
+	}
+}
+
+func TestKeyRing_SignNoActiveKey(t *testing.T) {
+	ring, err := NewKeyRing([]Key{newEd25519(t, "later", time.Now().Add(time.Hour))}, Options{})
+	require.NoError(t, err)
+
+	_, err = ring.Sign(jwt.MapClaims{"exp": time.Now().Add(time.Minute).Unix()})
+
+	assert.ErrorIs(t, err, ErrNoActiveKey)
+}
+
+func TestKeyRing_SignAndParse(t *testing.T) {
+	now := time.Now()
+	retired := newEd25519(t, "retired", now.Add(-3*time.Hour))
+	current := newRSA(t, "current", 2048)
+	current.NotBefore = now.Add(-2 * time.Hour)
+	opts := Options{Issuer: "nexus", Audience: "api", Grace: 30 * time.Minute}
+	ring, err := NewKeyRing([]Key{retired, current}, opts)
+	require.NoError(t, err)
+
+	other, err := NewKeyRing([]Key{newEd25519(t, "other", time.Time{})}, opts)
+	require.NoError(t, err)
+	foreignAudience, err := NewKeyRing([]Key{current}, Options{Issuer: "nexus", Audience: "web"})
+	require.NoError(t, err)
+
+	signWith := func(key Key, method jwt.SigningMethod, kid string, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key.Private)
+		require.NoError(t, err)
+		return signed
+	}
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{"iss": "nexus", "aud": "api", "exp": now.Add(time.Minute).Unix()}
+	}
+
+	signed, err := ring.Sign(jwt.MapClaims{"user_id": 7, "exp": now.Add(time.Minute).Unix()})
+	require.NoError(t, err)
+	fromOther, err := other.Sign(jwt.MapClaims{"exp": now.Add(time.Minute).Unix()})
+	require.NoError(t, err)
+	forWeb, err := foreignAudience.Sign(jwt.MapClaims{"exp": now.Add(time.Minute).Unix()})
+	require.NoError(t, err)
+	noExp := valid()
+	delete(noExp, "exp")
+	expired := valid()
+	expired["exp"] = now.Add(-time.Minute).Unix()
+	impostor := newEd25519(t, "current", time.Time{})
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"signed by the ring", signed, true},
+		{"unknown kid", fromOther, false},
+		{"key past its grace period", signWith(retired, jwt.SigningMethodEdDSA, "retired", valid()), false},
+		{"other audience", forWeb, false},
+		{"missing expiry", signWith(current, jwt.SigningMethodRS256, "current", noExp), false},
+		{"expired", signWith(current, jwt.SigningMethodRS256, "current", expired), false},
+		{"algorithm does not match kid", signWith(impostor, jwt.SigningMethodEdDSA, "current", valid()), false},
+		{"garbage", "not.a.token", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ring.Parse(tt.token)
+			if tt.ok {
+				require.NoError(t, err)
+				assert.Equal(t, "nexus", claims["iss"])
+				assert.Equal(t, "api", claims["aud"])
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(signed, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "current", parsed.Header["kid"])
+	assert.Equal(t, "RS256", parsed.Header["alg"])
+}
+
+func TestSigningMethod(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	method, err := signingMethod(edKey)
+	assert.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodEdDSA, method)
+
+	method, err = signingMethod(newRSA(t, "r", 2048).Private)
+	assert.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodRS256, method)
+}
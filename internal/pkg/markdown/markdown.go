@@ -0,0 +1,170 @@
+// Package markdown renders user-supplied Markdown to sanitized HTML.
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	taskRefPattern = regexp.MustCompile(`(?:^|[^\w&])#(\d{1,18})\b`)
+	visibleKey     = parser.NewContextKey()
+)
+
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+	strip  *bluemonday.Policy
+}
+
+// NewRenderer builds a renderer that links visible task references using
+// taskURLFormat, e.g. "/tasks/%d".
+func NewRenderer(taskURLFormat string) *Renderer {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(&taskRefTransformer{format: taskURLFormat}, 500)),
+		),
+	)
+	return &Renderer{
+		md:     md,
+		policy: bluemonday.UGCPolicy(),
+		strip:  bluemonday.StrictPolicy(),
+	}
+}
+
+// HTML renders src and sanitizes the result. References like #123 become
+// links only when visible reports the task as accessible.
+func (r *Renderer) HTML(src string, visible map[int64]bool) string {
+	pc := parser.NewContext()
+	pc.Set(visibleKey, visible)
+
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(src), &buf, parser.WithContext(pc)); err != nil {
+		return "<p>" + html.EscapeString(src) + "</p>"
+	}
+	return r.policy.Sanitize(buf.String())
+}
+
+// Excerpt returns the first maxRunes characters of src as plain text.
+func (r *Renderer) Excerpt(src string, maxRunes int) string {
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(src), &buf); err != nil {
+		buf.Reset()
+		buf.WriteString(html.EscapeString(src))
+	}
+
+	// Block elements are newline separated in the output, so stripping tags
+	// keeps words apart.
+	plain := r.strip.Sanitize(buf.String())
+	plain = strings.Join(strings.Fields(html.UnescapeString(plain)), " ")
+
+	if utf8.RuneCountInString(plain) <= maxRunes {
+		return plain
+	}
+	runes := []rune(plain)
+	return strings.TrimRightFunc(string(runes[:maxRunes]), unicode.IsSpace) + "…"
+}
+
+// TaskRefs returns the distinct task IDs referenced as #123 in src.
+func TaskRefs(src string) []int64 {
+	seen := make(map[int64]bool)
+	var ids []int64
+	for _, m := range taskRefPattern.FindAllStringSubmatch(src, -1) {
+		id, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// taskRefTransformer links references in text once the document is parsed,
+// so #123 inside code, links and images stays as written.
+type taskRefTransformer struct {
+	format string
+}
+
+func (t *taskRefTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	visible, _ := pc.Get(visibleKey).(map[int64]bool)
+	if len(visible) == 0 {
+		return
+	}
+
+	var texts []*ast.Text
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Link, *ast.AutoLink, *ast.Image, *ast.CodeSpan:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			texts = append(texts, n)
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, n := range texts {
+		t.link(n, reader.Source(), visible)
+	}
+}
+
+// link splits n around each visible reference, leaving n as the tail so
+// its line break flags are kept.
+func (t *taskRefTransformer) link(n *ast.Text, source []byte, visible map[int64]bool) {
+	parent := n.Parent()
+	start, stop := n.Segment.Start, n.Segment.Stop
+	for i := start; i < stop; i++ {
+		if source[i] != '#' {
+			continue
+		}
+		if prev, _ := utf8.DecodeLastRune(source[:i]); i > 0 && (isWordRune(prev) || prev == '&' || prev == '\\') {
+			continue
+		}
+		end := i + 1
+		for end < stop && source[end] >= '0' && source[end] <= '9' {
+			end++
+		}
+		if end == i+1 || end-i > 19 {
+			continue
+		}
+		if next, _ := utf8.DecodeRune(source[end:]); end < len(source) && isWordRune(next) {
+			continue
+		}
+		id, err := strconv.ParseInt(string(source[i+1:end]), 10, 64)
+		if err != nil || !visible[id] {
+			continue
+		}
+
+		if i > start {
+			parent.InsertBefore(parent, n, ast.NewTextSegment(text.NewSegment(start, i)))
+		}
+		link := ast.NewLink()
+		link.Destination = []byte(fmt.Sprintf(t.format, id))
+		link.AppendChild(link, ast.NewTextSegment(text.NewSegment(i, end)))
+		parent.InsertBefore(parent, n, link)
+		start = end
+		i = end - 1
+	}
+	n.Segment = text.NewSegment(start, stop)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,88 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderer_HTML_Sanitizes(t *testing.T) {
+	r := NewRenderer("/tasks/%d")
+
+	tests := []struct {
+		name   string
+		src    string
+		banned []string
+	}{
+		{"script tag", "<script>alert(1)</script>\n\nhello", []string{"<script", "alert(1)"}},
+		{"inline script", "hi <script>alert(1)</script> there", []string{"<script"}},
+		{"javascript link", "[click](javascript:alert(1))", []string{"javascript:", "href"}},
+		{"javascript autolink", "<javascript:alert(1)>", []string{"href=\"javascript:"}},
+		{"raw anchor", `<a href="javascript:alert(1)">x</a>`, []string{"javascript:", "<a"}},
+		{"event handler", `<img src="x" onerror="alert(1)">`, []string{"onerror", "<img"}},
+		{"raw block", `<div onclick="steal()">raw</div>`, []string{"<div", "onclick"}},
+		{"iframe", `<iframe src="https://evil.test"></iframe>`, []string{"<iframe"}},
+		{"style", "<style>body{display:none}</style>", []string{"<style"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := r.HTML(tt.src, nil)
+			for _, b := range tt.banned {
+				assert.NotContains(t, out, b)
+			}
+		})
+	}
+}
+
+func TestRenderer_HTML_KeepsSafeMarkup(t *testing.T) {
+	r := NewRenderer("/tasks/%d")
+
+	out := r.HTML("**bold** [docs](https://example.com)\n\n- [x] done", nil)
+
+	assert.Contains(t, out, "<strong>bold</strong>")
+	assert.Contains(t, out, `<a href="https://example.com" rel="nofollow">docs</a>`)
+	assert.Contains(t, out, "<li>")
+}
+
+func TestRenderer_HTML_TaskRefs(t *testing.T) {
+	r := NewRenderer("/tasks/%d")
+	visible := map[int64]bool{5: true, 7: true}
+
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"visible ref", "see #5", `<p>see <a href="/tasks/5" rel="nofollow">#5</a></p>`},
+		{"hidden ref", "see #6", `<p>see #6</p>`},
+		{"two refs", "#5 and #7.", `<p><a href="/tasks/5" rel="nofollow">#5</a> and <a href="/tasks/7" rel="nofollow">#7</a>.</p>`},
+		{"inside word", "a#5 #5b", `<p>a#5 #5b</p>`},
+		{"escaped", `\#5`, `<p>#5</p>`},
+		{"code span", "run `#5` first", `<p>run <code>#5</code> first</p>`},
+		{"code block", "```\n#5\n```", "<pre><code>#5\n</code></pre>"},
+		{"link text", "[about #5](https://example.com)", `<p><a href="https://example.com" rel="nofollow">about #5</a></p>`},
+		{"link url", "<https://example.com/#5>", `<p><a href="https://example.com/#5" rel="nofollow">https://example.com/#5</a></p>`},
+		{"emphasis", "*#5*", `<p><em><a href="/tasks/5" rel="nofollow">#5</a></em></p>`},
+		{"heading", "# #7", `<h1><a href="/tasks/7" rel="nofollow">#7</a></h1>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want+"\n", r.HTML(tt.src, visible))
+		})
+	}
+}
+
+func TestRenderer_Excerpt(t *testing.T) {
+	r := NewRenderer("/tasks/%d")
+
+	assert.Equal(t, "Title some bold text", r.Excerpt("# Title\n\nsome **bold** text", 100))
+	assert.Equal(t, "a < b", r.Excerpt("a < b", 100))
+	assert.Equal(t, "one two…", r.Excerpt("one two three", 8))
+	assert.NotContains(t, r.Excerpt("<script>alert(1)</script>\n\nafter", 100), "alert")
+}
+
+func TestTaskRefs(t *testing.T) {
+	assert.Equal(t, []int64{5, 7}, TaskRefs("#5, #7 and #5 again"))
+	assert.Empty(t, TaskRefs("issue#5 &#7; #x"))
+	assert.Equal(t, []int64{12}, TaskRefs("(#12)"))
+}
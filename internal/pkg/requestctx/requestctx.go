@@ -0,0 +1,49 @@
+package requestctx
+
+import (
+	"context"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+)
+
+type ctxKey string
+
+const (
+	requestIDKey       ctxKey = "request_id"
+	sourceKey          ctxKey = "change_source"
+	teamRestrictionKey ctxKey = "team_restriction"
+)
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey).(string); ok {
+		return id
+	}
+	return ""
+}
+
+func WithSource(ctx context.Context, source domain.ChangeSource) context.Context {
+	return context.WithValue(ctx, sourceKey, source)
+}
+
+// Source reports who initiated the current operation, defaulting to the API.
+func Source(ctx context.Context) domain.ChangeSource {
+	if src, ok := ctx.Value(sourceKey).(domain.ChangeSource); ok && src != "" {
+		return src
+	}
+	return domain.ChangeSourceAPI
+}
+
+// WithTeamRestriction limits the operation to one team, for personal access
+// tokens created for a single team.
+func WithTeamRestriction(ctx context.Context, teamID int64) context.Context {
+	return context.WithValue(ctx, teamRestrictionKey, teamID)
+}
+
+func TeamRestriction(ctx context.Context) (int64, bool) {
+	teamID, ok := ctx.Value(teamRestrictionKey).(int64)
+	return teamID, ok
+}
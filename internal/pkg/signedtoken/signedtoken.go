@@ -0,0 +1,83 @@
+// Package signedtoken issues opaque, HMAC-signed tokens bound to a purpose
+// and an expiry. A token carries no record id: callers store Hash(token) and
+// look the record up by it, so rotating a token invalidates the old one.
+package signedtoken
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalid = errors.New("invalid token")
+	ErrExpired = errors.New("token expired")
+)
+
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+func (s *Signer) Sign(purpose string, expiresAt time.Time) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	payload := purpose + "|" + strconv.FormatInt(expiresAt.Unix(), 10) + "|" + hex.EncodeToString(nonce)
+	return encode([]byte(payload)) + "." + encode(s.mac([]byte(payload))), nil
+}
+
+// Verify checks the signature, purpose and expiry of token.
+func (s *Signer) Verify(purpose, token string, now time.Time) error {
+	encPayload, encSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encPayload)
+	if err != nil {
+		return ErrInvalid
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encSig)
+	if err != nil || !hmac.Equal(sig, s.mac(payload)) {
+		return ErrInvalid
+	}
+
+	parts := strings.Split(string(payload), "|")
+	if len(parts) != 3 || parts[0] != purpose {
+		return ErrInvalid
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return ErrInvalid
+	}
+	if now.Unix() >= exp {
+		return ErrExpired
+	}
+	return nil
+}
+
+// Hash is the value to persist for a token.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *Signer) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,74 @@
+package signedtoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Verify(t *testing.T) {
+	signer := NewSigner("secret")
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+
+	token, err := signer.Sign("invite", expiresAt)
+	require.NoError(t, err)
+	otherSecret, err := NewSigner("other").Sign("invite", expiresAt)
+	require.NoError(t, err)
+	payload, sig, _ := strings.Cut(token, ".")
+	otherPayload, _, _ := strings.Cut(otherSecret, ".")
+
+	tests := []struct {
+		name    string
+		purpose string
+		token   string
+		now     time.Time
+		want    error
+	}{
+		{"valid", "invite", token, now, nil},
+		{"just before expiry", "invite", token, expiresAt.Add(-time.Second), nil},
+		{"at expiry", "invite", token, expiresAt, ErrExpired},
+		{"after expiry", "invite", token, expiresAt.Add(time.Minute), ErrExpired},
+		{"other purpose", "refresh", token, now, ErrInvalid},
+		{"other secret", "invite", otherSecret, now, ErrInvalid},
+		{"swapped payload", "invite", otherPayload + "." + sig, now, ErrInvalid},
+		{"truncated signature", "invite", payload + "." + sig[:len(sig)-2], now, ErrInvalid},
+		{"no separator", "invite", payload, now, ErrInvalid},
+		{"bad encoding", "invite", "!!!." + sig, now, ErrInvalid},
+		{"empty", "invite", "", now, ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, signer.Verify(tt.purpose, tt.token, tt.now))
+		})
+	}
+}
+
+func TestSigner_SignIsUnique(t *testing.T) {
+	signer := NewSigner("secret")
+	expiresAt := time.Now().Add(time.Hour)
+
+	first, err := signer.Sign("invite", expiresAt)
+	require.NoError(t, err)
+	second, err := signer.Sign("invite", expiresAt)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+	assert.NotEqual(t, Hash(first), Hash(second))
+}
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Hash(tt.in), tt.in)
+	}
+}
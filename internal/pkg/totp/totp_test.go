@@ -0,0 +1,98 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA-1 seed of the RFC 6238 test vectors,
+// "12345678901234567890", base32-encoded.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// The RFC lists 8-digit codes; these are their last 6 digits.
+func TestCode_RFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		code, err := Code(rfcSecret, Counter(time.Unix(tt.unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, code, tt.unix)
+	}
+}
+
+func TestCode_LowercaseSecret(t *testing.T) {
+	code, err := Code(strings.ToLower(rfcSecret), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "287082", code)
+}
+
+func TestCode_InvalidSecret(t *testing.T) {
+	_, err := Code("not base32!", 1)
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Counter(now)
+	codeAt := func(step int64) string {
+		code, err := Code(rfcSecret, step)
+		require.NoError(t, err)
+		return code
+	}
+
+	tests := []struct {
+		name string
+		code string
+		step int64
+		ok   bool
+	}{
+		{"current step", codeAt(current), current, true},
+		{"previous step", codeAt(current - 1), current - 1, true},
+		{"next step", codeAt(current + 1), current + 1, true},
+		{"with spaces", codeAt(current)[:3] + " " + codeAt(current)[3:], current, true},
+		{"two steps back", codeAt(current - 2), 0, false},
+		{"two steps ahead", codeAt(current + 2), 0, false},
+		{"too short", codeAt(current)[:5], 0, false},
+		{"too long", codeAt(current) + "0", 0, false},
+		{"empty", "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(rfcSecret, tt.code, now)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.step, step)
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	_, err = Code(secret, 0)
+	assert.NoError(t, err)
+
+	other, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Team Nexus", "user@example.com", rfcSecret)
+
+	assert.Equal(t, "otpauth://totp/Team%20Nexus:user@example.com?algorithm=SHA1&digits=6&issuer=Team%20Nexus&period=30&secret="+rfcSecret, uri)
+}
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 )
@@ -15,3 +16,25 @@ type TaskCache interface {
 type RateLimiter interface {
 	Allow(ctx context.Context, userID int64) (bool, error)
 }
+
+// LoginRateLimiter limits sign-in attempts per client address, before the
+// caller is known.
+type LoginRateLimiter interface {
+	AllowLogin(ctx context.Context, ip string) (bool, error)
+}
+
+// AttemptLimiter limits other unauthenticated requests, such as sign-up and
+// password resets, per client address or per submitted email.
+type AttemptLimiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
+
+// TokenDenylist holds ids of revoked access tokens and refresh token
+// families; an access token is rejected if its jti or family is listed.
+type TokenDenylist interface {
+	Revoke(ctx context.Context, id string, ttl time.Duration) error
+	IsRevoked(ctx context.Context, ids ...string) (bool, error)
+	// Claim lists id unless it already is, atomically; it reports whether
+	// this call listed it. Single-use ids are claimed before being acted on.
+	Claim(ctx context.Context, id string, ttl time.Duration) (bool, error)
+}
@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+)
+
+type Mailer interface {
+	Send(ctx context.Context, msg domain.MailMessage) error
+}
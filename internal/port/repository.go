@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 )
@@ -10,15 +11,39 @@ type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) (int64, error)
 	GetByID(ctx context.Context, id int64) (*domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]domain.User, error)
+	Search(ctx context.Context, filter domain.UserSearchFilter) ([]domain.User, int, error)
+	SetDisabled(ctx context.Context, id int64, disabled bool) error
+	UpdatePassword(ctx context.Context, id int64, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, id int64) error
+	SetTOTPSecret(ctx context.Context, id int64, secret string) error
+	EnableTOTP(ctx context.Context, id int64, counter int64) error
+	DisableTOTP(ctx context.Context, id int64) error
+	AdvanceTOTPCounter(ctx context.Context, id int64, counter int64) (bool, error)
 }
 
 type TeamRepository interface {
 	Create(ctx context.Context, team *domain.Team) (int64, error)
 	GetByID(ctx context.Context, id int64) (*domain.Team, error)
-	ListByUserID(ctx context.Context, userID int64) ([]domain.Team, error)
+	ListByUserID(ctx context.Context, userID int64, includeArchived bool) ([]domain.Team, error)
+	ListAll(ctx context.Context, filter domain.AdminListFilter) ([]domain.Team, int, error)
+	Update(ctx context.Context, team *domain.Team) error
+	SetArchived(ctx context.Context, teamID int64, archived bool) error
+	Delete(ctx context.Context, teamID int64) error
+	SetParent(ctx context.Context, teamID int64, parentID *int64, inheritedRole *domain.TeamRole) error
+	ListDescendants(ctx context.Context, teamID int64) ([]domain.Team, error)
+	LockHierarchy(ctx context.Context, orgID int64) error
 	AddMember(ctx context.Context, member *domain.TeamMember) error
 	GetMember(ctx context.Context, teamID, userID int64) (*domain.TeamMember, error)
-	GetStats(ctx context.Context, userID int64) ([]domain.TeamStats, error)
+	ListMembers(ctx context.Context, teamID int64) ([]domain.TeamMemberDetails, error)
+	ListMentionCandidates(ctx context.Context, teamID int64) ([]domain.TeamMemberDetails, error)
+	UpdateMemberRole(ctx context.Context, teamID, userID int64, role domain.TeamRole) error
+	SetGuestTasks(ctx context.Context, teamID, userID int64, taskIDs []int64) error
+	IsTaskGranted(ctx context.Context, userID, taskID int64) (bool, error)
+	RemoveMember(ctx context.Context, teamID, userID int64) error
+	CountMembersByRole(ctx context.Context, teamID int64, role domain.TeamRole) (int, error)
+	UpdateOwner(ctx context.Context, teamID, ownerID int64) error
+	GetStats(ctx context.Context, userID int64, rollup bool) ([]domain.TeamStats, error)
 	GetTopContributors(ctx context.Context, teamID int64) ([]domain.TopContributor, error)
 }
 
@@ -28,16 +53,158 @@ type TaskRepository interface {
 	Update(ctx context.Context, task *domain.Task) error
 	List(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, int, error)
 	GetOrphanedAssignees(ctx context.Context) ([]domain.OrphanedAssignee, error)
+	ClearAssignee(ctx context.Context, taskID, assigneeID int64) (bool, error)
+	ListTeamIDs(ctx context.Context, ids []int64) ([]int64, error)
+	ListVisibleIDs(ctx context.Context, access domain.TaskAccess, ids []int64) ([]int64, error)
+	Delete(ctx context.Context, id int64) error
 }
 
 type TaskHistoryRepository interface {
-	Create(ctx context.Context, history *domain.TaskHistory) error
-	ListByTaskID(ctx context.Context, taskID int64) ([]domain.TaskHistory, error)
+	CreateChangeSet(ctx context.Context, set *domain.TaskChangeSet, entries []domain.TaskHistory) (int64, error)
+	ListChangeSets(ctx context.Context, taskID int64, filter domain.HistoryFilter) ([]domain.TaskChangeSet, int, error)
+	ListByChangeSetIDs(ctx context.Context, ids []int64) ([]domain.TaskHistory, error)
 }
 
 type CommentRepository interface {
 	Create(ctx context.Context, comment *domain.TaskComment) (int64, error)
+	GetByID(ctx context.Context, id int64) (*domain.TaskComment, error)
 	ListByTaskID(ctx context.Context, taskID int64) ([]domain.TaskComment, error)
+	ListPage(ctx context.Context, filter domain.CommentFilter) ([]domain.TaskComment, int, error)
+	UpdateContent(ctx context.Context, id int64, content string) error
+	SoftDelete(ctx context.Context, id, deletedBy int64) error
+	SetResolved(ctx context.Context, id int64, resolvedBy *int64) error
+	ListThreadParticipants(ctx context.Context, rootID int64) ([]int64, error)
+	CreateRevision(ctx context.Context, rev *domain.CommentRevision) error
+	ListRevisions(ctx context.Context, commentID int64) ([]domain.CommentRevision, error)
+}
+
+type ActivityRepository interface {
+	CreateTeamEvent(ctx context.Context, event *domain.TeamEvent) error
+	ListByTeam(ctx context.Context, filter domain.ActivityFilter) ([]domain.ActivityEvent, error)
+}
+
+type MentionRepository interface {
+	CreateBatch(ctx context.Context, mentions []domain.Mention) error
+	ListByUser(ctx context.Context, access domain.TaskAccess, filter domain.MentionFilter) ([]domain.Mention, int, error)
+}
+
+type ReactionRepository interface {
+	Add(ctx context.Context, target domain.ReactionTarget, targetID, userID int64, emoji string) error
+	Remove(ctx context.Context, target domain.ReactionTarget, targetID, userID int64, emoji string) (bool, error)
+	Summaries(ctx context.Context, target domain.ReactionTarget, targetIDs []int64, userID int64) (map[int64][]domain.ReactionSummary, error)
+}
+
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *domain.Attachment) (int64, error)
+	GetByID(ctx context.Context, id int64) (*domain.Attachment, error)
+	ListByTaskID(ctx context.Context, taskID int64) ([]domain.Attachment, error)
+	ListByCommentID(ctx context.Context, commentID int64) ([]domain.Attachment, error)
+	ListByTeamID(ctx context.Context, teamID int64) ([]domain.Attachment, error)
+	Delete(ctx context.Context, id int64) error
+	DeleteByIDs(ctx context.Context, ids []int64) error
+}
+
+type OwnershipTransferRepository interface {
+	Create(ctx context.Context, transfer *domain.OwnershipTransfer) (int64, error)
+	GetByID(ctx context.Context, id int64) (*domain.OwnershipTransfer, error)
+	GetPending(ctx context.Context, teamID int64) (*domain.OwnershipTransfer, error)
+	Resolve(ctx context.Context, id int64, status domain.TransferStatus) error
+}
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *domain.Invitation) (int64, error)
+	GetByID(ctx context.Context, id int64) (*domain.Invitation, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invitation, error)
+	GetPendingByEmail(ctx context.Context, teamID int64, email string) (*domain.Invitation, error)
+	ListPending(ctx context.Context, teamID int64) ([]domain.Invitation, error)
+	Resolve(ctx context.Context, id int64, status domain.InvitationStatus, acceptedBy *int64) (bool, error)
+	Rotate(ctx context.Context, id int64, tokenHash string, expiresAt time.Time) error
+}
+
+type JoinLinkRepository interface {
+	Create(ctx context.Context, link *domain.JoinLink) (int64, error)
+	GetByID(ctx context.Context, id int64) (*domain.JoinLink, error)
+	GetByCode(ctx context.Context, code string) (*domain.JoinLink, error)
+	ListByTeam(ctx context.Context, teamID int64) ([]domain.JoinLink, error)
+	Revoke(ctx context.Context, id int64) error
+	IncrementUses(ctx context.Context, id int64) (bool, error)
+	RecordUse(ctx context.Context, linkID, userID int64) error
+	ListUses(ctx context.Context, linkID int64) ([]domain.JoinLinkUse, error)
+}
+
+type TeamRoleRepository interface {
+	Create(ctx context.Context, role *domain.CustomRole) (int64, error)
+	GetByID(ctx context.Context, id int64) (*domain.CustomRole, error)
+	ListByTeam(ctx context.Context, teamID int64) ([]domain.CustomRole, error)
+	Update(ctx context.Context, role *domain.CustomRole) error
+	Delete(ctx context.Context, id int64) error
+	Assign(ctx context.Context, teamID, userID int64, roleID *int64) error
+}
+
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *domain.Organization) (int64, error)
+	GetByID(ctx context.Context, id int64) (*domain.Organization, error)
+	GetPersonal(ctx context.Context, userID int64) (*domain.Organization, error)
+	ListByUserID(ctx context.Context, userID int64) ([]domain.Organization, error)
+	Update(ctx context.Context, org *domain.Organization) error
+	AddMember(ctx context.Context, member *domain.OrgMember) error
+	GetMember(ctx context.Context, orgID, userID int64) (*domain.OrgMember, error)
+	ListMembers(ctx context.Context, orgID int64) ([]domain.OrgMemberDetails, error)
+	SearchMembers(ctx context.Context, orgID int64, query string, limit int) ([]domain.OrgMemberDetails, error)
+	UpdateMemberRole(ctx context.Context, orgID, userID int64, role domain.OrgRole) error
+	RemoveMember(ctx context.Context, orgID, userID int64) error
+	CountMembersByRole(ctx context.Context, orgID int64, role domain.OrgRole) (int, error)
+	CountOwnedTeams(ctx context.Context, orgID, userID int64) (int, error)
+	ListTeams(ctx context.Context, orgID int64) ([]domain.Team, error)
+}
+
+type OrgInvitationRepository interface {
+	Create(ctx context.Context, invitation *domain.OrgInvitation) (int64, error)
+	GetByID(ctx context.Context, id int64) (*domain.OrgInvitation, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.OrgInvitation, error)
+	GetPendingByEmail(ctx context.Context, orgID int64, email string) (*domain.OrgInvitation, error)
+	Resolve(ctx context.Context, id int64, status domain.InvitationStatus, acceptedBy *int64) (bool, error)
+	Rotate(ctx context.Context, id int64, tokenHash string, expiresAt time.Time) error
+}
+
+type PersonalTokenRepository interface {
+	Create(ctx context.Context, token *domain.PersonalAccessToken) (int64, error)
+	GetByID(ctx context.Context, id int64) (*domain.PersonalAccessToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error)
+	ListByUser(ctx context.Context, userID int64) ([]domain.PersonalAccessToken, error)
+	Revoke(ctx context.Context, id int64) error
+	TouchLastUsed(ctx context.Context, id int64) error
+}
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *domain.PasswordResetToken) (int64, error)
+	GetByHashForUpdate(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error)
+	InvalidateForUser(ctx context.Context, userID int64) error
+}
+
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, token *domain.EmailVerificationToken) (int64, error)
+	GetByHashForUpdate(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error)
+	InvalidateForUser(ctx context.Context, userID int64) error
+}
+
+type RecoveryCodeRepository interface {
+	Replace(ctx context.Context, userID int64, codeHashes []string) error
+	Use(ctx context.Context, userID int64, codeHash string) (bool, error)
+	DeleteForUser(ctx context.Context, userID int64) error
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *domain.RefreshToken) (int64, error)
+	GetByHashForUpdate(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	MarkRotated(ctx context.Context, id int64) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeForUser(ctx context.Context, userID int64) ([]string, error)
+}
+
+type AdminAuditRepository interface {
+	Create(ctx context.Context, entry *domain.AdminAuditEntry) error
+	List(ctx context.Context, filter domain.AdminListFilter) ([]domain.AdminAuditEntry, int, error)
 }
 
 type TransactionManager interface {
@@ -2,6 +2,8 @@ package port
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 )
@@ -9,31 +11,220 @@ import (
 type AuthService interface {
 	Register(ctx context.Context, req domain.RegisterRequest) (*domain.AuthResponse, error)
 	Login(ctx context.Context, req domain.LoginRequest) (*domain.AuthResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*domain.AuthResponse, error)
+	Logout(ctx context.Context, claims domain.AccessClaims) error
+	LogoutEverywhere(ctx context.Context, claims domain.AccessClaims) error
+	CompleteLogin(ctx context.Context, req domain.LoginChallengeRequest) (*domain.AuthResponse, error)
+	IssueImpersonationToken(ctx context.Context, userID, adminID int64) (string, time.Time, error)
+	SessionRevoker
+}
+
+// SecondFactorVerifier checks a TOTP or recovery code during login.
+type SecondFactorVerifier interface {
+	VerifySecondFactor(ctx context.Context, user *domain.User, code, recoveryCode string) error
+}
+
+type TwoFactorService interface {
+	SecondFactorVerifier
+	Enroll(ctx context.Context, claims domain.AccessClaims, req domain.EnrollTwoFactorRequest) (*domain.TwoFactorEnrollment, error)
+	Confirm(ctx context.Context, claims domain.AccessClaims, req domain.TwoFactorCodeRequest) (*domain.RecoveryCodesResponse, error)
+	Disable(ctx context.Context, claims domain.AccessClaims, req domain.DisableTwoFactorRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, claims domain.AccessClaims, req domain.TwoFactorCodeRequest) (*domain.RecoveryCodesResponse, error)
+}
+
+// SessionRevoker ends all sessions of a user, e.g. after a password change.
+type SessionRevoker interface {
+	RevokeSessions(ctx context.Context, userID int64) error
+}
+
+type PasswordService interface {
+	Forgot(ctx context.Context, req domain.ForgotPasswordRequest) error
+	Reset(ctx context.Context, req domain.ResetPasswordRequest) error
+	Change(ctx context.Context, claims domain.AccessClaims, req domain.ChangePasswordRequest) error
+}
+
+// EmailVerifier mails a link that confirms the user's email address.
+type EmailVerifier interface {
+	SendVerification(ctx context.Context, user *domain.User) error
+}
+
+type EmailVerificationService interface {
+	EmailVerifier
+	Verify(ctx context.Context, req domain.VerifyEmailRequest) error
+	Resend(ctx context.Context, req domain.ResendVerificationRequest) error
+}
+
+// AccountChecker rejects tokens of accounts that were disabled after the
+// token was issued.
+type AccountChecker interface {
+	CheckActive(ctx context.Context, userID int64) error
+}
+
+// ImpersonationAuditor records the changes an administrator makes while
+// acting as another user.
+type ImpersonationAuditor interface {
+	RecordImpersonatedRequest(ctx context.Context, adminID, userID int64, method, path string) error
+}
+
+// PersonalTokenAuthenticator resolves a personal access token presented as a
+// bearer token.
+type PersonalTokenAuthenticator interface {
+	AuthenticatePersonalToken(ctx context.Context, token string) (*domain.AccessClaims, error)
+}
+
+type PersonalTokenService interface {
+	PersonalTokenAuthenticator
+	Create(ctx context.Context, claims domain.AccessClaims, req domain.CreatePersonalAccessTokenRequest) (*domain.CreatedPersonalAccessToken, error)
+	List(ctx context.Context, userID int64) ([]domain.PersonalAccessToken, error)
+	Revoke(ctx context.Context, userID, tokenID int64) error
+}
+
+type AdminService interface {
+	ListUsers(ctx context.Context, adminID int64, filter domain.UserSearchFilter) (*domain.UserListResponse, error)
+	SetUserDisabled(ctx context.Context, adminID, userID int64, disabled bool) (*domain.User, error)
+	ListTeams(ctx context.Context, adminID int64, filter domain.AdminListFilter) (*domain.TeamListResponse, error)
+	ListOrphanedAssignees(ctx context.Context, adminID int64) ([]domain.OrphanedAssignee, error)
+	RepairOrphanedAssignees(ctx context.Context, adminID int64) (*domain.RepairResult, error)
+	Impersonate(ctx context.Context, adminID, userID int64) (*domain.ImpersonationResponse, error)
+	ListAudit(ctx context.Context, adminID int64, filter domain.AdminListFilter) (*domain.AdminAuditListResponse, error)
 }
 
 type TeamService interface {
 	Create(ctx context.Context, userID int64, req domain.CreateTeamRequest) (*domain.Team, error)
 	GetByID(ctx context.Context, userID, teamID int64) (*domain.Team, error)
-	ListByUserID(ctx context.Context, userID int64) ([]domain.Team, error)
-	InviteUser(ctx context.Context, inviterID, teamID int64, req domain.InviteRequest) error
-	GetStats(ctx context.Context, userID int64) ([]domain.TeamStats, error)
+	ListByUserID(ctx context.Context, userID int64, includeArchived bool) ([]domain.Team, error)
+	Update(ctx context.Context, userID, teamID int64, req domain.UpdateTeamRequest) (*domain.Team, error)
+	SetArchived(ctx context.Context, userID, teamID int64, archived bool) (*domain.Team, error)
+	IssueDeletionToken(ctx context.Context, userID, teamID int64) (*domain.TeamDeletionToken, error)
+	Delete(ctx context.Context, userID, teamID int64, req domain.DeleteTeamRequest) error
+	SetParent(ctx context.Context, userID, teamID int64, req domain.SetParentRequest) (*domain.Team, error)
+	GetTree(ctx context.Context, userID, teamID int64) (*domain.TeamTree, error)
+	ListMembers(ctx context.Context, userID, teamID int64) ([]domain.TeamMemberDetails, error)
+	ChangeMemberRole(ctx context.Context, actorID, teamID, targetID int64, req domain.UpdateMemberRoleRequest) (*domain.TeamMember, error)
+	RemoveMember(ctx context.Context, actorID, teamID, targetID int64) error
+	Leave(ctx context.Context, userID, teamID int64) error
+	GetStats(ctx context.Context, userID int64, rollup bool) ([]domain.TeamStats, error)
 	GetTopContributors(ctx context.Context, userID, teamID int64) ([]domain.TopContributor, error)
 }
 
+type InvitationService interface {
+	Invite(ctx context.Context, inviterID, teamID int64, req domain.InviteRequest) (*domain.Invitation, error)
+	ListPending(ctx context.Context, userID, teamID int64) ([]domain.Invitation, error)
+	Revoke(ctx context.Context, userID, teamID, invitationID int64) error
+	Resend(ctx context.Context, userID, teamID, invitationID int64) (*domain.Invitation, error)
+	Accept(ctx context.Context, userID int64, token string) (*domain.Team, error)
+	Decline(ctx context.Context, token string) error
+}
+
+type JoinLinkService interface {
+	Create(ctx context.Context, userID, teamID int64, req domain.CreateJoinLinkRequest) (*domain.JoinLink, error)
+	List(ctx context.Context, userID, teamID int64) ([]domain.JoinLink, error)
+	Revoke(ctx context.Context, userID, teamID, linkID int64) error
+	ListUses(ctx context.Context, userID, teamID, linkID int64) ([]domain.JoinLinkUse, error)
+	Join(ctx context.Context, userID int64, code string) (*domain.Team, error)
+}
+
+// Authorizer is the single place that decides what a team member may do.
+// Member and Authorize return apperror.ErrNotTeamMember for outsiders;
+// TaskMember also hides tasks outside a scoped guest's grants. Access
+// applies the same rules to many teams at once for cross-team queries.
+type Authorizer interface {
+	Member(ctx context.Context, userID, teamID int64) (*domain.TeamMember, error)
+	TaskMember(ctx context.Context, userID int64, task *domain.Task) (*domain.TeamMember, error)
+	Access(ctx context.Context, userID int64, teamIDs []int64) (*domain.TaskAccess, error)
+	Authorize(ctx context.Context, userID, teamID int64, perm domain.Permission) (*domain.TeamMember, error)
+	Can(member *domain.TeamMember, perm domain.Permission) bool
+	Permissions(member *domain.TeamMember) []domain.Permission
+}
+
+type PermissionService interface {
+	ListRoles(ctx context.Context, userID, teamID int64) ([]domain.CustomRole, error)
+	CreateRole(ctx context.Context, userID, teamID int64, req domain.CustomRoleRequest) (*domain.CustomRole, error)
+	UpdateRole(ctx context.Context, userID, teamID, roleID int64, req domain.CustomRoleRequest) (*domain.CustomRole, error)
+	DeleteRole(ctx context.Context, userID, teamID, roleID int64) error
+	AssignRole(ctx context.Context, actorID, teamID, targetID int64, req domain.AssignCustomRoleRequest) error
+	SetGuestScope(ctx context.Context, actorID, teamID, targetID int64, req domain.GuestScopeRequest) error
+	Describe(ctx context.Context, userID int64, resource string, resourceID int64) (*domain.PermissionSummary, error)
+}
+
+type OrganizationService interface {
+	Create(ctx context.Context, userID int64, req domain.CreateOrganizationRequest) (*domain.Organization, error)
+	List(ctx context.Context, userID int64) ([]domain.Organization, error)
+	GetByID(ctx context.Context, userID, orgID int64) (*domain.Organization, error)
+	Update(ctx context.Context, userID, orgID int64, req domain.UpdateOrganizationRequest) (*domain.Organization, error)
+	ListMembers(ctx context.Context, userID, orgID int64) ([]domain.OrgMemberDetails, error)
+	InviteMember(ctx context.Context, userID, orgID int64, req domain.InviteOrgMemberRequest) (*domain.OrgInvitation, error)
+	AcceptInvitation(ctx context.Context, userID int64, token string) (*domain.Organization, error)
+	DeclineInvitation(ctx context.Context, token string) error
+	ChangeMemberRole(ctx context.Context, actorID, orgID, targetID int64, req domain.UpdateOrgMemberRoleRequest) (*domain.OrgMember, error)
+	RemoveMember(ctx context.Context, actorID, orgID, targetID int64) error
+	ListTeams(ctx context.Context, userID, orgID int64) ([]domain.Team, error)
+	SearchUsers(ctx context.Context, userID, orgID int64, query string) ([]domain.OrgMemberDetails, error)
+}
+
+type OwnershipService interface {
+	Nominate(ctx context.Context, ownerID, teamID int64, req domain.NominateOwnerRequest) (*domain.OwnershipTransfer, error)
+	GetPending(ctx context.Context, userID, teamID int64) (*domain.OwnershipTransfer, error)
+	Accept(ctx context.Context, userID, teamID int64) (*domain.Team, error)
+	Decline(ctx context.Context, userID, teamID int64) error
+	Cancel(ctx context.Context, ownerID, teamID int64) error
+}
+
 type TaskService interface {
 	Create(ctx context.Context, userID int64, req domain.CreateTaskRequest) (*domain.Task, error)
+	Import(ctx context.Context, userID int64, req domain.ImportTasksRequest) (*domain.ImportTasksResponse, error)
 	Update(ctx context.Context, userID, taskID int64, req domain.UpdateTaskRequest) (*domain.Task, error)
 	List(ctx context.Context, userID int64, filter domain.TaskFilter) (*domain.TaskListResponse, error)
-	GetHistory(ctx context.Context, userID, taskID int64) ([]domain.TaskHistory, error)
-	GetOrphanedAssignees(ctx context.Context) ([]domain.OrphanedAssignee, error)
+	GetHistory(ctx context.Context, userID, taskID int64, filter domain.HistoryFilter) (*domain.TaskHistoryResponse, error)
+	Delete(ctx context.Context, userID, taskID int64) error
 }
 
 type CommentService interface {
 	Create(ctx context.Context, userID, taskID int64, req domain.CreateCommentRequest) (*domain.TaskComment, error)
 	ListByTaskID(ctx context.Context, userID, taskID int64) ([]domain.TaskComment, error)
+	ListPage(ctx context.Context, userID, taskID int64, query domain.CommentQuery) (*domain.CommentPage, error)
+	Update(ctx context.Context, userID, taskID, commentID int64, req domain.UpdateCommentRequest) (*domain.TaskComment, error)
+	Delete(ctx context.Context, userID, taskID, commentID int64) error
+	ListRevisions(ctx context.Context, userID, taskID, commentID int64) ([]domain.CommentRevision, error)
+	ListThreads(ctx context.Context, userID, taskID int64) ([]domain.CommentNode, error)
+	SetThreadResolved(ctx context.Context, userID, taskID, commentID int64, resolved bool) (*domain.TaskComment, error)
+}
+
+type ActivityService interface {
+	GetTeamActivity(ctx context.Context, userID, teamID int64, query domain.ActivityQuery) (*domain.ActivityFeed, error)
+}
+
+type ReactionService interface {
+	ToggleTaskReaction(ctx context.Context, userID, taskID int64, req domain.ToggleReactionRequest) (*domain.ReactionToggleResult, error)
+	ToggleCommentReaction(ctx context.Context, userID, taskID, commentID int64, req domain.ToggleReactionRequest) (*domain.ReactionToggleResult, error)
+	ListTaskReactions(ctx context.Context, userID, taskID int64) ([]domain.ReactionSummary, error)
+}
+
+type AttachmentService interface {
+	Upload(ctx context.Context, userID, taskID int64, commentID *int64, upload domain.AttachmentUpload) (*domain.Attachment, error)
+	List(ctx context.Context, userID, taskID int64) ([]domain.Attachment, error)
+	Open(ctx context.Context, userID, taskID, attachmentID int64) (*domain.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, userID, taskID, attachmentID int64) error
+	DeleteForTask(ctx context.Context, taskID int64) error
+	DeleteForComment(ctx context.Context, commentID int64) error
+	DeleteForTeam(ctx context.Context, teamID int64) error
+}
+
+type MarkdownService interface {
+	RenderTasks(ctx context.Context, userID int64, tasks []*domain.Task, withHTML bool) error
+	RenderComments(ctx context.Context, userID int64, comments []*domain.TaskComment, withHTML bool) error
+}
+
+type MentionService interface {
+	Record(ctx context.Context, src domain.MentionSource, handles []string) (*domain.MentionResult, error)
+	Notify(ctx context.Context, src domain.MentionSource, mentioned []domain.User)
+	ListForUser(ctx context.Context, userID int64, filter domain.MentionFilter) (*domain.MentionListResponse, error)
 }
 
 type NotificationService interface {
 	NotifyTaskAssigned(ctx context.Context, task *domain.Task, assignee *domain.User) error
 	NotifyCommentAdded(ctx context.Context, comment *domain.TaskComment, task *domain.Task) error
+	NotifyThreadReply(ctx context.Context, reply *domain.TaskComment, task *domain.Task, recipients []domain.User) error
+	NotifyMentioned(ctx context.Context, task *domain.Task, authorID int64, recipient *domain.User) error
+	NotifyOwnershipNominated(ctx context.Context, team *domain.Team, nominee *domain.User) error
 }
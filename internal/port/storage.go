@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+	"io"
+)
+
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+var activityTypes = map[domain.ActivityType]bool{
+	domain.ActivityTaskCreated:        true,
+	domain.ActivityTaskUpdated:        true,
+	domain.ActivityCommentAdded:       true,
+	domain.ActivityMemberAdded:        true,
+	domain.ActivityMemberRemoved:      true,
+	domain.ActivityMemberLeft:         true,
+	domain.ActivityMemberRoleChanged:  true,
+	domain.ActivityOwnerTransferred:   true,
+	domain.ActivityTeamUpdated:        true,
+	domain.ActivityTeamArchived:       true,
+	domain.ActivityTeamUnarchived:     true,
+	domain.ActivityCustomRoleChanged:  true,
+	domain.ActivityCustomRoleCreated:  true,
+	domain.ActivityCustomRoleUpdated:  true,
+	domain.ActivityCustomRoleDeleted:  true,
+	domain.ActivityInvitationSent:     true,
+	domain.ActivityInvitationRevoked:  true,
+	domain.ActivityInvitationAccepted: true,
+	domain.ActivityInvitationDeclined: true,
+}
+
+var invitationActivity = map[domain.ActivityType]bool{
+	domain.ActivityInvitationSent:     true,
+	domain.ActivityInvitationRevoked:  true,
+	domain.ActivityInvitationAccepted: true,
+	domain.ActivityInvitationDeclined: true,
+}
+
+type ActivityServiceImpl struct {
+	activityRepo port.ActivityRepository
+	authz        port.Authorizer
+}
+
+func NewActivityService(activityRepo port.ActivityRepository, authz port.Authorizer) *ActivityServiceImpl {
+	return &ActivityServiceImpl{
+		activityRepo: activityRepo,
+		authz:        authz,
+	}
+}
+
+func (s *ActivityServiceImpl) GetTeamActivity(ctx context.Context, userID, teamID int64, query domain.ActivityQuery) (*domain.ActivityFeed, error) {
+	member, err := s.authz.Member(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	// The feed spans every task of the team, so guests limited to some of
+	// them cannot read it.
+	if member.TaskScoped {
+		return nil, apperror.ErrInsufficientRole
+	}
+
+	filter := domain.ActivityFilter{
+		TeamID:          teamID,
+		ActorID:         query.ActorID,
+		TaskID:          query.TaskID,
+		Limit:           query.Limit,
+		ExcludeInternal: member.IsGuest(),
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	if query.Types != "" {
+		for _, raw := range strings.Split(query.Types, ",") {
+			t := domain.ActivityType(strings.TrimSpace(raw))
+			if !activityTypes[t] {
+				return nil, apperror.BadRequest(fmt.Sprintf("unknown activity type %q", t))
+			}
+			filter.Types = append(filter.Types, t)
+		}
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeActivityCursor(query.Cursor)
+		if err != nil {
+			return nil, apperror.BadRequest("invalid cursor")
+		}
+		filter.Cursor = cursor
+	}
+
+	// Fetch one extra row to know whether another page exists.
+	pageLimit := filter.Limit
+	filter.Limit++
+	events, err := s.activityRepo.ListByTeam(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invitation events carry the invitee's address; only those who may
+	// see pending invitations get it.
+	if !s.authz.Can(member, domain.PermTeamInvite) {
+		for i := range events {
+			if invitationActivity[events[i].Type] {
+				events[i].Details = ""
+			}
+		}
+	}
+
+	feed := &domain.ActivityFeed{Events: events}
+	if len(events) > pageLimit {
+		feed.Events = events[:pageLimit]
+		last := feed.Events[pageLimit-1]
+		feed.NextCursor = encodeActivityCursor(domain.ActivityCursor{
+			OccurredAt: last.OccurredAt,
+			Type:       last.Type,
+			SubjectID:  last.SubjectID,
+		})
+	}
+	if feed.Events == nil {
+		feed.Events = []domain.ActivityEvent{}
+	}
+	return feed, nil
+}
+
+func encodeActivityCursor(c domain.ActivityCursor) string {
+	raw := fmt.Sprintf("%d:%s:%d", c.OccurredAt.UnixNano(), c.Type, c.SubjectID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeActivityCursor(s string) (*domain.ActivityCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ActivityCursor{
+		OccurredAt: time.Unix(0, nanos).UTC(),
+		Type:       domain.ActivityType(parts[1]),
+		SubjectID:  id,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestActivityService_GetTeamActivity_NextCursor(t *testing.T) {
+	activityRepo := new(mocks.ActivityRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	svc := NewActivityService(activityRepo, NewAuthorizer(teamRepo))
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
+	}, nil)
+	activityRepo.On("ListByTeam", mock.Anything, mock.MatchedBy(func(f domain.ActivityFilter) bool {
+		return f.TeamID == 1 && f.Limit == 3 && f.Cursor == nil &&
+			len(f.Types) == 2 && f.Types[0] == domain.ActivityCommentAdded
+	})).Return([]domain.ActivityEvent{
+		{Type: domain.ActivityCommentAdded, SubjectID: 3, OccurredAt: now},
+		{Type: domain.ActivityTaskCreated, SubjectID: 9, OccurredAt: now.Add(-time.Minute)},
+		{Type: domain.ActivityTaskCreated, SubjectID: 8, OccurredAt: now.Add(-2 * time.Minute)},
+	}, nil)
+
+	feed, err := svc.GetTeamActivity(context.Background(), 1, 1, domain.ActivityQuery{
+		Types: "comment_added, task_created",
+		Limit: 2,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, feed.Events, 2)
+	assert.NotEmpty(t, feed.NextCursor)
+
+	cursor, err := decodeActivityCursor(feed.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ActivityTaskCreated, cursor.Type)
+	assert.Equal(t, int64(9), cursor.SubjectID)
+	assert.True(t, cursor.OccurredAt.Equal(now.Add(-time.Minute)))
+}
+
+func TestActivityService_GetTeamActivity_LastPage(t *testing.T) {
+	activityRepo := new(mocks.ActivityRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	svc := NewActivityService(activityRepo, NewAuthorizer(teamRepo))
+
+	cursor := encodeActivityCursor(domain.ActivityCursor{
+		OccurredAt: time.Unix(1700000000, 0).UTC(), Type: domain.ActivityTaskUpdated, SubjectID: 4,
+	})
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
+	}, nil)
+	activityRepo.On("ListByTeam", mock.Anything, mock.MatchedBy(func(f domain.ActivityFilter) bool {
+		return f.Limit == 51 && f.Cursor != nil && f.Cursor.SubjectID == 4 && f.ActorID == 7
+	})).Return([]domain.ActivityEvent{}, nil)
+
+	feed, err := svc.GetTeamActivity(context.Background(), 1, 1, domain.ActivityQuery{
+		Cursor: cursor, ActorID: 7,
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, feed.Events)
+	assert.Empty(t, feed.NextCursor)
+}
+
+func TestActivityService_GetTeamActivity_InvalidFilters(t *testing.T) {
+	activityRepo := new(mocks.ActivityRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	svc := NewActivityService(activityRepo, NewAuthorizer(teamRepo))
+
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
+	}, nil)
+
+	_, err := svc.GetTeamActivity(context.Background(), 1, 1, domain.ActivityQuery{Types: "deleted_everything"})
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+
+	_, err = svc.GetTeamActivity(context.Background(), 1, 1, domain.ActivityQuery{Cursor: "%%%"})
+	appErr, ok = apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	activityRepo.AssertNotCalled(t, "ListByTeam", mock.Anything, mock.Anything)
+}
+
+func TestActivityService_GetTeamActivity_NotMember(t *testing.T) {
+	activityRepo := new(mocks.ActivityRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	svc := NewActivityService(activityRepo, NewAuthorizer(teamRepo))
+
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(99)).Return(nil, nil)
+
+	result, err := svc.GetTeamActivity(context.Background(), 99, 1, domain.ActivityQuery{})
+
+	assert.Nil(t, result)
+	assert.Equal(t, apperror.ErrNotTeamMember, err)
+}
+
+func TestActivityService_GetTeamActivity_RedactsInviteeEmail(t *testing.T) {
+	activityRepo := new(mocks.ActivityRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	svc := NewActivityService(activityRepo, NewAuthorizer(teamRepo))
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	stubTeamMember(teamRepo, 2, domain.TeamRoleMember)
+	events := func() []domain.ActivityEvent {
+		return []domain.ActivityEvent{
+			{Type: domain.ActivityInvitationSent, SubjectID: 2, Details: "new@example.com"},
+			{Type: domain.ActivityMemberAdded, SubjectID: 1, Details: "member"},
+		}
+	}
+	activityRepo.On("ListByTeam", mock.Anything, mock.Anything).Return(events(), nil).Once()
+	activityRepo.On("ListByTeam", mock.Anything, mock.Anything).Return(events(), nil).Once()
+
+	feed, err := svc.GetTeamActivity(context.Background(), 2, 1, domain.ActivityQuery{})
+	assert.NoError(t, err)
+	assert.Empty(t, feed.Events[0].Details)
+	assert.Equal(t, "member", feed.Events[1].Details)
+
+	feed, err = svc.GetTeamActivity(context.Background(), 1, 1, domain.ActivityQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, "new@example.com", feed.Events[0].Details)
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+var errNotSystemAdmin = apperror.Forbidden("system administrator role required")
+
+type AdminServiceImpl struct {
+	userRepo    port.UserRepository
+	teamRepo    port.TeamRepository
+	taskRepo    port.TaskRepository
+	historyRepo port.TaskHistoryRepository
+	auditRepo   port.AdminAuditRepository
+	authSvc     port.AuthService
+	taskCache   port.TaskCache
+	txManager   port.TransactionManager
+}
+
+func NewAdminService(
+	userRepo port.UserRepository,
+	teamRepo port.TeamRepository,
+	taskRepo port.TaskRepository,
+	historyRepo port.TaskHistoryRepository,
+	auditRepo port.AdminAuditRepository,
+	authSvc port.AuthService,
+	taskCache port.TaskCache,
+	txManager port.TransactionManager,
+) *AdminServiceImpl {
+	return &AdminServiceImpl{
+		userRepo:    userRepo,
+		teamRepo:    teamRepo,
+		taskRepo:    taskRepo,
+		historyRepo: historyRepo,
+		auditRepo:   auditRepo,
+		authSvc:     authSvc,
+		taskCache:   taskCache,
+		txManager:   txManager,
+	}
+}
+
+func (s *AdminServiceImpl) ListUsers(ctx context.Context, adminID int64, filter domain.UserSearchFilter) (*domain.UserListResponse, error) {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+	filter.Page, filter.PageSize = normalizePage(filter.Page, filter.PageSize)
+
+	users, total, err := s.userRepo.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if users == nil {
+		users = []domain.User{}
+	}
+	return &domain.UserListResponse{
+		Users:      users,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: (total + filter.PageSize - 1) / filter.PageSize,
+	}, nil
+}
+
+// SetUserDisabled locks an account out or lets it back in. A disabled user
+// cannot log in, and tokens issued earlier stop working on the next request,
+// including impersonation tokens a disabled admin handed out.
+func (s *AdminServiceImpl) SetUserDisabled(ctx context.Context, adminID, userID int64, disabled bool) (*domain.User, error) {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+	if userID == adminID && disabled {
+		return nil, apperror.New(http.StatusConflict, "you cannot disable your own account")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if (user.DisabledAt != nil) == disabled {
+		return user, nil
+	}
+
+	action := domain.AdminActionUserEnabled
+	if disabled {
+		action = domain.AdminActionUserDisabled
+	}
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.SetDisabled(ctx, userID, disabled); err != nil {
+			return err
+		}
+		return s.auditRepo.Create(ctx, &domain.AdminAuditEntry{
+			AdminID:      adminID,
+			Action:       action,
+			TargetUserID: &userID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if disabled {
+		if err := s.authSvc.RevokeSessions(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return s.userRepo.GetByID(ctx, userID)
+}
+
+func (s *AdminServiceImpl) ListTeams(ctx context.Context, adminID int64, filter domain.AdminListFilter) (*domain.TeamListResponse, error) {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+	filter.Page, filter.PageSize = normalizePage(filter.Page, filter.PageSize)
+
+	teams, total, err := s.teamRepo.ListAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if teams == nil {
+		teams = []domain.Team{}
+	}
+	return &domain.TeamListResponse{
+		Teams:      teams,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: (total + filter.PageSize - 1) / filter.PageSize,
+	}, nil
+}
+
+func (s *AdminServiceImpl) ListOrphanedAssignees(ctx context.Context, adminID int64) ([]domain.OrphanedAssignee, error) {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+	return s.taskRepo.GetOrphanedAssignees(ctx)
+}
+
+// RepairOrphanedAssignees unassigns every task whose assignee has left the
+// task's team. Each change lands in the task history under the admin's name.
+func (s *AdminServiceImpl) RepairOrphanedAssignees(ctx context.Context, adminID int64) (*domain.RepairResult, error) {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	result := &domain.RepairResult{}
+	teams := make(map[int64]bool)
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		orphans, err := s.taskRepo.GetOrphanedAssignees(ctx)
+		if err != nil {
+			return err
+		}
+		for _, o := range orphans {
+			cleared, err := s.taskRepo.ClearAssignee(ctx, o.TaskID, o.AssigneeID)
+			if err != nil {
+				return err
+			}
+			if !cleared {
+				continue
+			}
+			_, err = s.historyRepo.CreateChangeSet(ctx, &domain.TaskChangeSet{
+				TaskID:    o.TaskID,
+				UserID:    adminID,
+				RequestID: requestctx.RequestID(ctx),
+				Source:    requestctx.Source(ctx),
+			}, []domain.TaskHistory{
+				historyEntry("assignee_id", domain.HistoryValueUser, strconv.FormatInt(o.AssigneeID, 10), ""),
+			})
+			if err != nil {
+				return err
+			}
+			result.Unassigned++
+			teams[o.TeamID] = true
+		}
+		return s.auditRepo.Create(ctx, &domain.AdminAuditEntry{
+			AdminID: adminID,
+			Action:  domain.AdminActionOrphansRepaired,
+			Details: strconv.Itoa(result.Unassigned),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for teamID := range teams {
+		_ = s.taskCache.InvalidateTeam(ctx, teamID)
+	}
+	return result, nil
+}
+
+// Impersonate issues a short-lived token acting as another user. Other
+// admins and disabled accounts cannot be impersonated.
+func (s *AdminServiceImpl) Impersonate(ctx context.Context, adminID, userID int64) (*domain.ImpersonationResponse, error) {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSystemAdmin() {
+		return nil, apperror.Forbidden("administrators cannot be impersonated")
+	}
+	if user.DisabledAt != nil {
+		return nil, apperror.New(http.StatusConflict, "account is disabled")
+	}
+
+	err = s.auditRepo.Create(ctx, &domain.AdminAuditEntry{
+		AdminID:      adminID,
+		Action:       domain.AdminActionImpersonated,
+		TargetUserID: &userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := s.authSvc.IssueImpersonationToken(ctx, userID, adminID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ImpersonationResponse{Token: token, ExpiresAt: expiresAt, User: *user}, nil
+}
+
+// maxAuditDetails matches the width of admin_audit_log.details.
+const maxAuditDetails = 255
+
+// RecordImpersonatedRequest audits one request made with an impersonation
+// token, naming the method, path and request ID in the details.
+func (s *AdminServiceImpl) RecordImpersonatedRequest(ctx context.Context, adminID, userID int64, method, path string) error {
+	details := method + " " + path
+	if requestID := requestctx.RequestID(ctx); requestID != "" {
+		details += " request_id=" + requestID
+	}
+	if runes := []rune(details); len(runes) > maxAuditDetails {
+		details = string(runes[:maxAuditDetails])
+	}
+
+	return s.auditRepo.Create(ctx, &domain.AdminAuditEntry{
+		AdminID:      adminID,
+		Action:       domain.AdminActionImpersonatedReq,
+		TargetUserID: &userID,
+		Details:      details,
+	})
+}
+
+func (s *AdminServiceImpl) ListAudit(ctx context.Context, adminID int64, filter domain.AdminListFilter) (*domain.AdminAuditListResponse, error) {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+	filter.Page, filter.PageSize = normalizePage(filter.Page, filter.PageSize)
+
+	entries, total, err := s.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []domain.AdminAuditEntry{}
+	}
+	return &domain.AdminAuditListResponse{
+		Entries:    entries,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: (total + filter.PageSize - 1) / filter.PageSize,
+	}, nil
+}
+
+func (s *AdminServiceImpl) requireAdmin(ctx context.Context, userID int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsSystemAdmin() {
+		return errNotSystemAdmin
+	}
+	return nil
+}
+
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return page, pageSize
+}
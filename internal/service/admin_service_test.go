@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type adminServiceDeps struct {
+	userRepo    *mocks.UserRepositoryMock
+	taskRepo    *mocks.TaskRepositoryMock
+	historyRepo *mocks.TaskHistoryRepositoryMock
+	auditRepo   *mocks.AdminAuditRepositoryMock
+	authSvc     *mocks.AuthServiceMock
+	cache       *mocks.TaskCacheMock
+}
+
+func newAdminService() (*AdminServiceImpl, adminServiceDeps) {
+	d := adminServiceDeps{
+		userRepo:    new(mocks.UserRepositoryMock),
+		taskRepo:    new(mocks.TaskRepositoryMock),
+		historyRepo: new(mocks.TaskHistoryRepositoryMock),
+		auditRepo:   new(mocks.AdminAuditRepositoryMock),
+		authSvc:     new(mocks.AuthServiceMock),
+		cache:       new(mocks.TaskCacheMock),
+	}
+	txManager := new(mocks.TransactionManagerMock)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil).Maybe()
+	d.userRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1, SystemRole: domain.SystemRoleAdmin}, nil).Maybe()
+	d.userRepo.On("GetByID", mock.Anything, int64(2)).Return(&domain.User{ID: 2, SystemRole: domain.SystemRoleUser}, nil).Maybe()
+	svc := NewAdminService(d.userRepo, new(mocks.TeamRepositoryMock), d.taskRepo, d.historyRepo, d.auditRepo, d.authSvc, d.cache, txManager)
+	return svc, d
+}
+
+func TestAdminService_ListOrphanedAssignees_RequiresAdmin(t *testing.T) {
+	svc, d := newAdminService()
+
+	_, err := svc.ListOrphanedAssignees(context.Background(), 2)
+
+	assert.Equal(t, errNotSystemAdmin, err)
+	d.taskRepo.AssertNotCalled(t, "GetOrphanedAssignees", mock.Anything)
+}
+
+func TestAdminService_ListOrphanedAssignees(t *testing.T) {
+	svc, d := newAdminService()
+
+	expected := []domain.OrphanedAssignee{
+		{TaskID: 1, TaskTitle: "Task 1", AssigneeID: 5, AssigneeName: "Ghost User"},
+	}
+	d.taskRepo.On("GetOrphanedAssignees", mock.Anything).Return(expected, nil)
+
+	result, err := svc.ListOrphanedAssignees(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "Ghost User", result[0].AssigneeName)
+}
+
+func TestAdminService_RepairOrphanedAssignees(t *testing.T) {
+	svc, d := newAdminService()
+
+	d.taskRepo.On("GetOrphanedAssignees", mock.Anything).Return([]domain.OrphanedAssignee{
+		{TaskID: 7, TeamID: 3, AssigneeID: 5},
+		{TaskID: 8, TeamID: 4, AssigneeID: 6},
+	}, nil)
+	d.taskRepo.On("ClearAssignee", mock.Anything, int64(7), int64(5)).Return(true, nil)
+	d.taskRepo.On("ClearAssignee", mock.Anything, int64(8), int64(6)).Return(false, nil)
+	d.historyRepo.On("CreateChangeSet", mock.Anything, mock.MatchedBy(func(set *domain.TaskChangeSet) bool {
+		return set.TaskID == 7 && set.UserID == 1
+	}), []domain.TaskHistory{{Field: "assignee_id", ValueType: domain.HistoryValueUser, OldValue: "5"}}).Return(int64(1), nil)
+	d.auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AdminAuditEntry) bool {
+		return e.Action == domain.AdminActionOrphansRepaired && e.Details == "1"
+	})).Return(nil)
+	d.cache.On("InvalidateTeam", mock.Anything, int64(3)).Return(nil)
+
+	result, err := svc.RepairOrphanedAssignees(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Unassigned)
+	d.historyRepo.AssertNumberOfCalls(t, "CreateChangeSet", 1)
+	d.cache.AssertExpectations(t)
+	d.auditRepo.AssertExpectations(t)
+}
+
+func TestAdminService_Impersonate_RecordsAudit(t *testing.T) {
+	svc, d := newAdminService()
+
+	expiresAt := time.Now().Add(time.Hour)
+	d.auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AdminAuditEntry) bool {
+		return e.Action == domain.AdminActionImpersonated && e.AdminID == 1 && *e.TargetUserID == 2
+	})).Return(nil)
+	d.authSvc.On("IssueImpersonationToken", mock.Anything, int64(2), int64(1)).Return("token", expiresAt, nil)
+
+	resp, err := svc.Impersonate(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "token", resp.Token)
+	assert.Equal(t, int64(2), resp.User.ID)
+	d.auditRepo.AssertExpectations(t)
+}
+
+func TestAdminService_RecordImpersonatedRequest(t *testing.T) {
+	svc, d := newAdminService()
+
+	d.auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AdminAuditEntry) bool {
+		return e.Action == domain.AdminActionImpersonatedReq && e.AdminID == 1 && *e.TargetUserID == 2 &&
+			e.Details == "DELETE /api/v1/tasks/5 request_id=abc"
+	})).Return(nil).Once()
+	d.auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AdminAuditEntry) bool {
+		return len(e.Details) == maxAuditDetails && strings.HasPrefix(e.Details, "POST /")
+	})).Return(nil).Once()
+
+	ctx := requestctx.WithRequestID(context.Background(), "abc")
+	assert.NoError(t, svc.RecordImpersonatedRequest(ctx, 1, 2, "DELETE", "/api/v1/tasks/5"))
+	assert.NoError(t, svc.RecordImpersonatedRequest(context.Background(), 1, 2, "POST", "/"+strings.Repeat("x", 300)))
+	d.auditRepo.AssertExpectations(t)
+}
+
+func TestAdminService_Impersonate_AdminForbidden(t *testing.T) {
+	svc, d := newAdminService()
+	d.userRepo.On("GetByID", mock.Anything, int64(3)).Return(&domain.User{ID: 3, SystemRole: domain.SystemRoleAdmin}, nil)
+
+	_, err := svc.Impersonate(context.Background(), 1, 3)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.Code)
+	d.authSvc.AssertNotCalled(t, "IssueImpersonationToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminService_SetUserDisabled_Self(t *testing.T) {
+	svc, d := newAdminService()
+
+	_, err := svc.SetUserDisabled(context.Background(), 1, 1, true)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 409, appErr.Code)
+	d.userRepo.AssertNotCalled(t, "SetDisabled", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminService_SetUserDisabled_Success(t *testing.T) {
+	svc, d := newAdminService()
+
+	d.userRepo.On("SetDisabled", mock.Anything, int64(2), true).Return(nil)
+	d.auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AdminAuditEntry) bool {
+		return e.Action == domain.AdminActionUserDisabled && *e.TargetUserID == 2
+	})).Return(nil)
+	d.authSvc.On("RevokeSessions", mock.Anything, int64(2)).Return(nil)
+
+	_, err := svc.SetUserDisabled(context.Background(), 1, 2, true)
+
+	assert.NoError(t, err)
+	d.userRepo.AssertExpectations(t)
+	d.auditRepo.AssertExpectations(t)
+	d.authSvc.AssertExpectations(t)
+}
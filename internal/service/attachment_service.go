@@ -0,0 +1,315 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+const maxFileNameLength = 255
+
+type AttachmentServiceImpl struct {
+	attachmentRepo port.AttachmentRepository
+	taskRepo       port.TaskRepository
+	authz          port.Authorizer
+	commentRepo    port.CommentRepository
+	blobStore      port.BlobStore
+	maxSize        int64
+	allowedTypes   []string
+}
+
+func NewAttachmentService(
+	attachmentRepo port.AttachmentRepository,
+	taskRepo port.TaskRepository,
+	authz port.Authorizer,
+	commentRepo port.CommentRepository,
+	blobStore port.BlobStore,
+	maxSize int64,
+	allowedTypes []string,
+) *AttachmentServiceImpl {
+	return &AttachmentServiceImpl{
+		attachmentRepo: attachmentRepo,
+		taskRepo:       taskRepo,
+		authz:          authz,
+		commentRepo:    commentRepo,
+		blobStore:      blobStore,
+		maxSize:        maxSize,
+		allowedTypes:   allowedTypes,
+	}
+}
+
+func (s *AttachmentServiceImpl) Upload(ctx context.Context, userID, taskID int64, commentID *int64, upload domain.AttachmentUpload) (*domain.Attachment, error) {
+	if upload.Size <= 0 {
+		return nil, apperror.BadRequest("file is empty")
+	}
+	if upload.Size > s.maxSize {
+		return nil, apperror.New(http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("file exceeds the %d byte limit", s.maxSize))
+	}
+
+	member, err := s.checkTaskAccess(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(member); err != nil {
+		return nil, err
+	}
+	if !s.authz.Can(member, domain.PermAttachmentUpload) {
+		return nil, apperror.ErrInsufficientRole
+	}
+	if commentID != nil {
+		comment, err := s.commentRepo.GetByID(ctx, *commentID)
+		if err != nil {
+			return nil, err
+		}
+		if comment.TaskID != taskID || comment.IsDeleted() {
+			return nil, apperror.NotFound("comment not found")
+		}
+	}
+
+	// The declared content type is ignored; sniff the leading bytes instead.
+	content := bufio.NewReaderSize(upload.Content, 512)
+	head, err := content.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, apperror.Internal("read upload", err)
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !s.typeAllowed(contentType) {
+		return nil, apperror.New(http.StatusUnsupportedMediaType,
+			fmt.Sprintf("file type %s is not allowed", contentType))
+	}
+
+	key, err := newStorageKey(taskID)
+	if err != nil {
+		return nil, apperror.Internal("generate storage key", err)
+	}
+	if err := s.blobStore.Put(ctx, key, content, upload.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	attachment := &domain.Attachment{
+		TaskID:      taskID,
+		CommentID:   commentID,
+		UploaderID:  userID,
+		FileName:    sanitizeFileName(upload.FileName),
+		ContentType: contentType,
+		Size:        upload.Size,
+		StorageKey:  key,
+	}
+	id, err := s.attachmentRepo.Create(ctx, attachment)
+	if err != nil {
+		_ = s.blobStore.Delete(ctx, key)
+		return nil, err
+	}
+
+	return s.attachmentRepo.GetByID(ctx, id)
+}
+
+func (s *AttachmentServiceImpl) List(ctx context.Context, userID, taskID int64) ([]domain.Attachment, error) {
+	member, err := s.checkTaskAccess(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.ListByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if member.IsGuest() {
+		if attachments, err = s.withoutInternal(ctx, taskID, attachments); err != nil {
+			return nil, err
+		}
+	}
+	if attachments == nil {
+		attachments = []domain.Attachment{}
+	}
+	return attachments, nil
+}
+
+func (s *AttachmentServiceImpl) Open(ctx context.Context, userID, taskID, attachmentID int64) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, _, err := s.loadAttachment(ctx, userID, taskID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := s.blobStore.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, body, nil
+}
+
+func (s *AttachmentServiceImpl) Delete(ctx context.Context, userID, taskID, attachmentID int64) error {
+	attachment, member, err := s.loadAttachment(ctx, userID, taskID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := checkWritable(member); err != nil {
+		return err
+	}
+	isUploader := attachment.UploaderID == userID && !member.IsGuest()
+	if !isUploader && !s.authz.Can(member, domain.PermAttachmentDelete) {
+		return apperror.ErrInsufficientRole
+	}
+
+	return s.purge(ctx, []domain.Attachment{*attachment})
+}
+
+func (s *AttachmentServiceImpl) DeleteForTask(ctx context.Context, taskID int64) error {
+	attachments, err := s.attachmentRepo.ListByTaskID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	return s.purge(ctx, attachments)
+}
+
+func (s *AttachmentServiceImpl) DeleteForComment(ctx context.Context, commentID int64) error {
+	attachments, err := s.attachmentRepo.ListByCommentID(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	return s.purge(ctx, attachments)
+}
+
+func (s *AttachmentServiceImpl) DeleteForTeam(ctx context.Context, teamID int64) error {
+	attachments, err := s.attachmentRepo.ListByTeamID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	return s.purge(ctx, attachments)
+}
+
+// purge removes the rows first so a failed blob delete leaves an orphaned
+// file rather than a dangling attachment.
+func (s *AttachmentServiceImpl) purge(ctx context.Context, attachments []domain.Attachment) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(attachments))
+	for i, a := range attachments {
+		ids[i] = a.ID
+	}
+	if err := s.attachmentRepo.DeleteByIDs(ctx, ids); err != nil {
+		return err
+	}
+
+	for _, a := range attachments {
+		if err := s.blobStore.Delete(ctx, a.StorageKey); err != nil {
+			log.Printf("[ATTACHMENTS] failed to delete blob %s: %v", a.StorageKey, err)
+		}
+	}
+	return nil
+}
+
+func (s *AttachmentServiceImpl) loadAttachment(ctx context.Context, userID, taskID, attachmentID int64) (*domain.Attachment, *domain.TeamMember, error) {
+	member, err := s.checkTaskAccess(ctx, userID, taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.attachmentRepo.GetByID(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment.TaskID != taskID {
+		return nil, nil, apperror.NotFound("attachment not found")
+	}
+	if attachment.CommentID != nil && member.IsGuest() {
+		comment, err := s.commentRepo.GetByID(ctx, *attachment.CommentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if comment.Internal {
+			return nil, nil, apperror.NotFound("attachment not found")
+		}
+	}
+	return attachment, member, nil
+}
+
+// withoutInternal drops attachments of internal comments, which guests may
+// not see.
+func (s *AttachmentServiceImpl) withoutInternal(ctx context.Context, taskID int64, attachments []domain.Attachment) ([]domain.Attachment, error) {
+	comments, err := s.commentRepo.ListByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	internal := make(map[int64]bool)
+	for _, c := range comments {
+		if c.Internal {
+			internal[c.ID] = true
+		}
+	}
+
+	visible := attachments[:0]
+	for _, a := range attachments {
+		if a.CommentID == nil || !internal[*a.CommentID] {
+			visible = append(visible, a)
+		}
+	}
+	return visible, nil
+}
+
+func (s *AttachmentServiceImpl) checkTaskAccess(ctx context.Context, userID, taskID int64) (*domain.TeamMember, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authz.TaskMember(ctx, userID, task)
+}
+
+// typeAllowed matches exact types and wildcards such as "image/*".
+func (s *AttachmentServiceImpl) typeAllowed(contentType string) bool {
+	for _, allowed := range s.allowedTypes {
+		if allowed == contentType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(contentType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func newStorageKey(taskID int64) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(b)), nil
+}
+
+// sanitizeFileName keeps only the base name and drops control characters so
+// the name is safe to echo back in Content-Disposition.
+func sanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if runes := []rune(name); len(runes) > maxFileNameLength {
+		name = string(runes[:maxFileNameLength])
+	}
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	return name
+}
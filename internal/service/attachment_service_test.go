@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newAttachmentService(role domain.TeamRole, userID int64) (
+	*AttachmentServiceImpl, *mocks.AttachmentRepositoryMock, *mocks.CommentRepositoryMock, *mocks.BlobStoreMock,
+) {
+	attachmentRepo := new(mocks.AttachmentRepositoryMock)
+	taskRepo := new(mocks.TaskRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	commentRepo := new(mocks.CommentRepositoryMock)
+	blobStore := new(mocks.BlobStoreMock)
+	svc := NewAttachmentService(attachmentRepo, taskRepo, NewAuthorizer(teamRepo), commentRepo, blobStore, 1024, []string{"image/*", "text/plain"})
+
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{ID: 1, TeamID: 1}, nil).Maybe()
+	teamRepo.On("GetMember", mock.Anything, int64(1), userID).Return(&domain.TeamMember{
+		TeamID: 1, UserID: userID, Role: role,
+	}, nil).Maybe()
+	return svc, attachmentRepo, commentRepo, blobStore
+}
+
+func TestAttachmentService_Upload_Success(t *testing.T) {
+	svc, attachmentRepo, _, blobStore := newAttachmentService(domain.TeamRoleMember, 1)
+	body := "plain text notes"
+
+	blobStore.On("Put", mock.Anything, mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "tasks/1/")
+	}), mock.Anything, int64(len(body)), "text/plain").Return(nil)
+	attachmentRepo.On("Create", mock.Anything, mock.MatchedBy(func(a *domain.Attachment) bool {
+		return a.FileName == "notes.txt" && a.ContentType == "text/plain" && a.UploaderID == 1
+	})).Return(int64(7), nil)
+	attachmentRepo.On("GetByID", mock.Anything, int64(7)).Return(&domain.Attachment{ID: 7, TaskID: 1}, nil)
+
+	result, err := svc.Upload(context.Background(), 1, 1, nil, domain.AttachmentUpload{
+		FileName: "../../etc/notes.txt",
+		Size:     int64(len(body)),
+		Content:  strings.NewReader(body),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), result.ID)
+	blobStore.AssertExpectations(t)
+	attachmentRepo.AssertExpectations(t)
+}
+
+func TestAttachmentService_Upload_TooLarge(t *testing.T) {
+	svc, _, _, blobStore := newAttachmentService(domain.TeamRoleMember, 1)
+
+	result, err := svc.Upload(context.Background(), 1, 1, nil, domain.AttachmentUpload{
+		FileName: "big.txt",
+		Size:     2048,
+		Content:  strings.NewReader(strings.Repeat("a", 2048)),
+	})
+
+	assert.Nil(t, result)
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, appErr.Code)
+	blobStore.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAttachmentService_Upload_DisallowedType(t *testing.T) {
+	svc, _, _, blobStore := newAttachmentService(domain.TeamRoleMember, 1)
+	body := "%PDF-1.4 fake"
+
+	result, err := svc.Upload(context.Background(), 1, 1, nil, domain.AttachmentUpload{
+		FileName: "image.png",
+		Size:     int64(len(body)),
+		Content:  strings.NewReader(body),
+	})
+
+	assert.Nil(t, result)
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnsupportedMediaType, appErr.Code)
+	blobStore.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAttachmentService_Upload_CommentFromOtherTask(t *testing.T) {
+	svc, _, commentRepo, _ := newAttachmentService(domain.TeamRoleMember, 1)
+	commentID := int64(5)
+	commentRepo.On("GetByID", mock.Anything, commentID).Return(&domain.TaskComment{ID: 5, TaskID: 2}, nil)
+
+	result, err := svc.Upload(context.Background(), 1, 1, &commentID, domain.AttachmentUpload{
+		FileName: "a.txt",
+		Size:     1,
+		Content:  strings.NewReader("a"),
+	})
+
+	assert.Nil(t, result)
+	assert.Error(t, err)
+}
+
+func TestAttachmentService_Upload_RemovesBlobWhenInsertFails(t *testing.T) {
+	svc, attachmentRepo, _, blobStore := newAttachmentService(domain.TeamRoleMember, 1)
+	blobStore.On("Put", mock.Anything, mock.Anything, mock.Anything, int64(1), "text/plain").Return(nil)
+	blobStore.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	attachmentRepo.On("Create", mock.Anything, mock.Anything).Return(int64(0), errors.New("db down"))
+
+	_, err := svc.Upload(context.Background(), 1, 1, nil, domain.AttachmentUpload{
+		FileName: "a.txt",
+		Size:     1,
+		Content:  strings.NewReader("a"),
+	})
+
+	assert.Error(t, err)
+	blobStore.AssertCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestAttachmentService_Delete_ByOtherMember(t *testing.T) {
+	svc, attachmentRepo, _, _ := newAttachmentService(domain.TeamRoleMember, 2)
+	attachmentRepo.On("GetByID", mock.Anything, int64(7)).Return(&domain.Attachment{
+		ID: 7, TaskID: 1, UploaderID: 1, StorageKey: "tasks/1/abc",
+	}, nil)
+
+	err := svc.Delete(context.Background(), 2, 1, 7)
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	attachmentRepo.AssertNotCalled(t, "DeleteByIDs", mock.Anything, mock.Anything)
+}
+
+func TestAttachmentService_Delete_ByModerator(t *testing.T) {
+	svc, attachmentRepo, _, blobStore := newAttachmentService(domain.TeamRoleAdmin, 2)
+	attachmentRepo.On("GetByID", mock.Anything, int64(7)).Return(&domain.Attachment{
+		ID: 7, TaskID: 1, UploaderID: 1, StorageKey: "tasks/1/abc",
+	}, nil)
+	attachmentRepo.On("DeleteByIDs", mock.Anything, []int64{7}).Return(nil)
+	blobStore.On("Delete", mock.Anything, "tasks/1/abc").Return(nil)
+
+	err := svc.Delete(context.Background(), 2, 1, 7)
+
+	assert.NoError(t, err)
+	attachmentRepo.AssertExpectations(t)
+	blobStore.AssertExpectations(t)
+}
+
+func TestAttachmentService_DeleteForTask_IgnoresBlobErrors(t *testing.T) {
+	svc, attachmentRepo, _, blobStore := newAttachmentService(domain.TeamRoleMember, 1)
+	attachmentRepo.On("ListByTaskID", mock.Anything, int64(1)).Return([]domain.Attachment{
+		{ID: 1, StorageKey: "tasks/1/a"},
+		{ID: 2, StorageKey: "tasks/1/b"},
+	}, nil)
+	attachmentRepo.On("DeleteByIDs", mock.Anything, []int64{1, 2}).Return(nil)
+	blobStore.On("Delete", mock.Anything, "tasks/1/a").Return(errors.New("unreachable"))
+	blobStore.On("Delete", mock.Anything, "tasks/1/b").Return(nil)
+
+	err := svc.DeleteForTask(context.Background(), 1)
+
+	assert.NoError(t, err)
+	blobStore.AssertExpectations(t)
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	assert.Equal(t, "report.pdf", sanitizeFileName(`C:\Users\me\report.pdf`))
+	assert.Equal(t, "evil.txt", sanitizeFileName("evil\r\n.txt"))
+	assert.Equal(t, "file", sanitizeFileName(""))
+	assert.Len(t, []rune(sanitizeFileName(strings.Repeat("я", 300))), maxFileNameLength)
+}
@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -9,20 +12,71 @@ import (
 
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/jwtkeys"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
 	"github.com/shalfey088/team-task-nexus/internal/port"
 )
 
+const (
+	impersonationTTL    = time.Hour
+	refreshTokenPurpose = "refresh"
+
+	// A login challenge is a JWT with this typ; the access token middleware
+	// refuses any token that has one.
+	challengeTokenType = "2fa_challenge"
+	challengeTTL       = 5 * time.Minute
+)
+
+var (
+	errAccountDisabled     = apperror.Forbidden("account is disabled")
+	errEmailNotVerified    = apperror.Forbidden("email address is not verified")
+	errInvalidChallenge    = apperror.New(http.StatusUnauthorized, "invalid or expired login challenge; sign in again")
+	errInvalidRefreshToken = apperror.New(http.StatusUnauthorized, "invalid or expired refresh token")
+	errRefreshTokenReused  = apperror.New(http.StatusUnauthorized, "refresh token was already used; all sessions of this login were revoked")
+)
+
 type AuthServiceImpl struct {
-	userRepo  port.UserRepository
-	jwtSecret string
-	jwtExpiry time.Duration
+	userRepo      port.UserRepository
+	refreshRepo   port.RefreshTokenRepository
+	txManager     port.TransactionManager
+	invitationSvc port.InvitationService
+	verifier      port.EmailVerifier
+	secondFactor  port.SecondFactorVerifier
+	denylist      port.TokenDenylist
+	keys          *jwtkeys.KeyRing
+	accessTTL     time.Duration
+	refreshTTL    time.Duration
+	signer        *signedtoken.Signer
+	policy        domain.EmailVerificationPolicy
 }
 
-func NewAuthService(userRepo port.UserRepository, jwtSecret string, jwtExpiry time.Duration) *AuthServiceImpl {
+func NewAuthService(
+	userRepo port.UserRepository,
+	refreshRepo port.RefreshTokenRepository,
+	txManager port.TransactionManager,
+	invitationSvc port.InvitationService,
+	verifier port.EmailVerifier,
+	secondFactor port.SecondFactorVerifier,
+	denylist port.TokenDenylist,
+	keys *jwtkeys.KeyRing,
+	tokenSecret string,
+	accessTTL time.Duration,
+	refreshTTL time.Duration,
+	policy domain.EmailVerificationPolicy,
+) *AuthServiceImpl {
 	return &AuthServiceImpl{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
-		jwtExpiry: jwtExpiry,
+		userRepo:      userRepo,
+		refreshRepo:   refreshRepo,
+		txManager:     txManager,
+		invitationSvc: invitationSvc,
+		verifier:      verifier,
+		secondFactor:  secondFactor,
+		denylist:      denylist,
+		keys:          keys,
+		accessTTL:     accessTTL,
+		refreshTTL:    refreshTTL,
+		signer:        signedtoken.NewSigner(tokenSecret),
+		policy:        policy,
 	}
 }
 
@@ -30,6 +84,10 @@ func (s *AuthServiceImpl) Register(ctx context.Context, req domain.RegisterReque
 	if req.Email == "" || req.Password == "" || req.FullName == "" {
 		return nil, apperror.BadRequest("email, password, and full_name are required")
 	}
+	email, err := validateEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -37,23 +95,48 @@ func (s *AuthServiceImpl) Register(ctx context.Context, req domain.RegisterReque
 	}
 
 	user := &domain.User{
-		Email:        req.Email,
+		Email:        email,
 		PasswordHash: string(hash),
 		FullName:     req.FullName,
+		SystemRole:   domain.SystemRoleUser,
 	}
 
-	id, err := s.userRepo.Create(ctx, user)
+	// With an invite token the account is only created if the invitation
+	// can be accepted, so both happen in one transaction. The token is only
+	// ever mailed to the invited address, so holding it verifies the email;
+	// Accept still checks that the addresses match.
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		id, err := s.userRepo.Create(ctx, user)
+		if err != nil {
+			return err
+		}
+		user.ID = id
+
+		if req.InviteToken == "" {
+			return nil
+		}
+		if err := s.userRepo.MarkEmailVerified(ctx, id); err != nil {
+			return err
+		}
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		_, err = s.invitationSvc.Accept(ctx, id, req.InviteToken)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	user.ID = id
-	token, err := s.generateToken(user.ID)
-	if err != nil {
-		return nil, apperror.Internal("generate token", err)
+	if user.EmailVerified() {
+		return s.startSession(ctx, user, "")
 	}
 
-	return &domain.AuthResponse{Token: token, User: *user}, nil
+	// A failed mail does not undo the registration; the user can ask for
+	// another link.
+	_ = s.verifier.SendVerification(ctx, user)
+	if s.policy.BlockLogin {
+		return &domain.AuthResponse{User: *user, VerificationRequired: true}, nil
+	}
+	return s.startSession(ctx, user, "")
 }
 
 func (s *AuthServiceImpl) Login(ctx context.Context, req domain.LoginRequest) (*domain.AuthResponse, error) {
@@ -61,7 +144,7 @@ func (s *AuthServiceImpl) Login(ctx context.Context, req domain.LoginRequest) (*
 		return nil, apperror.BadRequest("email and password are required")
 	}
 
-	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	user, err := s.userRepo.GetByEmail(ctx, normalizeEmail(req.Email))
 	if err != nil {
 		return nil, apperror.ErrInvalidCredentials
 	}
@@ -69,21 +152,318 @@ func (s *AuthServiceImpl) Login(ctx context.Context, req domain.LoginRequest) (*
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
 		return nil, apperror.ErrInvalidCredentials
 	}
+	if user.DisabledAt != nil {
+		return nil, errAccountDisabled
+	}
+	if s.policy.BlockLogin && !user.EmailVerified() {
+		return nil, errEmailNotVerified
+	}
+
+	if user.TwoFactorEnabled() {
+		challenge, err := s.issueChallenge(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.AuthResponse{TwoFactorRequired: true, ChallengeToken: challenge, User: *user}, nil
+	}
+	return s.startSession(ctx, user, "")
+}
+
+// CompleteLogin finishes a login that returned a challenge. Each challenge
+// gets one attempt: after a wrong code the password has to be entered again,
+// which keeps codes from being guessed.
+func (s *AuthServiceImpl) CompleteLogin(ctx context.Context, req domain.LoginChallengeRequest) (*domain.AuthResponse, error) {
+	if req.Code == "" && req.RecoveryCode == "" {
+		return nil, apperror.BadRequest("code or recovery_code is required")
+	}
 
-	token, err := s.generateToken(user.ID)
+	claims, err := s.keys.Parse(req.ChallengeToken)
+	if err != nil {
+		return nil, errInvalidChallenge
+	}
+	typ, _ := claims["typ"].(string)
+	challengeID, _ := claims["cid"].(string)
+	userIDFloat, ok := claims["user_id"].(float64)
+	if typ != challengeTokenType || challengeID == "" || !ok {
+		return nil, errInvalidChallenge
+	}
+
+	// Claiming is atomic, so parallel requests with one challenge get a
+	// single attempt between them.
+	claimed, err := s.denylist.Claim(ctx, challengeID, challengeTTL)
+	if err != nil {
+		return nil, apperror.Internal("claim challenge", err)
+	}
+	if !claimed {
+		return nil, errInvalidChallenge
+	}
+
+	user, err := s.userRepo.GetByID(ctx, int64(userIDFloat))
+	if err != nil {
+		return nil, err
+	}
+	if user.DisabledAt != nil {
+		return nil, errAccountDisabled
+	}
+	if err := s.secondFactor.VerifySecondFactor(ctx, user, req.Code, req.RecoveryCode); err != nil {
+		if appErr, ok := apperror.IsAppError(err); ok && appErr.Code == http.StatusBadRequest {
+			return nil, errInvalidChallenge
+		}
+		return nil, err
+	}
+	return s.startSession(ctx, user, "")
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token pair.
+// Each refresh token works once: presenting one that was already exchanged
+// means it leaked, so every token of its login is revoked.
+func (s *AuthServiceImpl) Refresh(ctx context.Context, refreshToken string) (*domain.AuthResponse, error) {
+	if err := s.signer.Verify(refreshTokenPurpose, refreshToken, time.Now()); err != nil {
+		return nil, errInvalidRefreshToken
+	}
+
+	var resp *domain.AuthResponse
+	var reusedFamily string
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		token, err := s.refreshRepo.GetByHashForUpdate(ctx, signedtoken.Hash(refreshToken))
+		if err != nil {
+			if appErr, ok := apperror.IsAppError(err); ok && appErr.Code == http.StatusNotFound {
+				return errInvalidRefreshToken
+			}
+			return err
+		}
+		if token.RevokedAt != nil || !token.ExpiresAt.After(time.Now()) {
+			return errInvalidRefreshToken
+		}
+		if token.RotatedAt != nil {
+			reusedFamily = token.FamilyID
+			return s.refreshRepo.RevokeFamily(ctx, token.FamilyID)
+		}
+
+		user, err := s.userRepo.GetByID(ctx, token.UserID)
+		if err != nil {
+			return err
+		}
+		if user.DisabledAt != nil {
+			return errAccountDisabled
+		}
+		if s.policy.BlockLogin && !user.EmailVerified() {
+			return errEmailNotVerified
+		}
+		if err := s.refreshRepo.MarkRotated(ctx, token.ID); err != nil {
+			return err
+		}
+		resp, err = s.startSession(ctx, user, token.FamilyID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reusedFamily != "" {
+		_ = s.denylist.Revoke(ctx, reusedFamily, s.accessTTL)
+		return nil, errRefreshTokenReused
+	}
+	return resp, nil
+}
+
+// Logout ends the session the access token belongs to: its refresh token
+// family is revoked and the access token itself stops working at once.
+func (s *AuthServiceImpl) Logout(ctx context.Context, claims domain.AccessClaims) error {
+	if claims.FamilyID != "" {
+		if err := s.refreshRepo.RevokeFamily(ctx, claims.FamilyID); err != nil {
+			return err
+		}
+		if err := s.denylist.Revoke(ctx, claims.FamilyID, s.accessTTL); err != nil {
+			return apperror.Internal("revoke session", err)
+		}
+	}
+	if err := s.denylist.Revoke(ctx, claims.TokenID, time.Until(claims.ExpiresAt)); err != nil {
+		return apperror.Internal("revoke token", err)
+	}
+	return nil
+}
+
+// LogoutEverywhere ends every session of the user. It is not available to an
+// admin impersonating them.
+func (s *AuthServiceImpl) LogoutEverywhere(ctx context.Context, claims domain.AccessClaims) error {
+	if claims.ImpersonatorID != 0 {
+		return apperror.Forbidden("not available while impersonating")
+	}
+
+	if err := s.RevokeSessions(ctx, claims.UserID); err != nil {
+		return err
+	}
+	if err := s.denylist.Revoke(ctx, claims.TokenID, time.Until(claims.ExpiresAt)); err != nil {
+		return apperror.Internal("revoke token", err)
+	}
+	return nil
+}
+
+// RevokeSessions ends every session of the user: their refresh tokens stop
+// working and access tokens issued for them are refused at once.
+func (s *AuthServiceImpl) RevokeSessions(ctx context.Context, userID int64) error {
+	var families []string
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		families, err = s.refreshRepo.RevokeForUser(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// A family may belong to an impersonation token, which outlives a
+	// regular access token.
+	ttl := max(s.accessTTL, impersonationTTL)
+	for _, family := range families {
+		if err := s.denylist.Revoke(ctx, family, ttl); err != nil {
+			return apperror.Internal("revoke session", err)
+		}
+	}
+	return nil
+}
+
+// CheckActive fails for accounts that were disabled or removed.
+func (s *AuthServiceImpl) CheckActive(ctx context.Context, userID int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if appErr, ok := apperror.IsAppError(err); ok && appErr.Code == http.StatusNotFound {
+			return apperror.ErrUnauthorized
+		}
+		return err
+	}
+	if user.DisabledAt != nil {
+		return errAccountDisabled
+	}
+	return nil
+}
+
+// IssueImpersonationToken returns a short-lived token for userID that also
+// names the admin acting as them. It cannot be refreshed, but its family is
+// recorded for both accounts, so revoking the sessions of either one ends it.
+func (s *AuthServiceImpl) IssueImpersonationToken(ctx context.Context, userID, adminID int64) (string, time.Time, error) {
+	familyID, err := randomID()
+	if err != nil {
+		return "", time.Time{}, apperror.Internal("generate token", err)
+	}
+	token, expiresAt, err := s.generateToken(userID, familyID, adminID, impersonationTTL)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		for _, ownerID := range []int64{userID, adminID} {
+			// The row holds the hash of a value that is never handed out,
+			// so it cannot be exchanged for a session.
+			placeholder, err := randomID()
+			if err != nil {
+				return apperror.Internal("generate token", err)
+			}
+			if _, err := s.refreshRepo.Create(ctx, &domain.RefreshToken{
+				UserID:    ownerID,
+				FamilyID:  familyID,
+				TokenHash: signedtoken.Hash(placeholder),
+				ExpiresAt: expiresAt,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// startSession issues an access token and a refresh token in familyID,
+// starting a new family when it is empty.
+func (s *AuthServiceImpl) startSession(ctx context.Context, user *domain.User, familyID string) (*domain.AuthResponse, error) {
+	if familyID == "" {
+		var err error
+		if familyID, err = randomID(); err != nil {
+			return nil, apperror.Internal("generate token", err)
+		}
+	}
+
+	refreshExpiresAt := time.Now().Add(s.refreshTTL).Truncate(time.Second)
+	refreshToken, err := s.signer.Sign(refreshTokenPurpose, refreshExpiresAt)
 	if err != nil {
 		return nil, apperror.Internal("generate token", err)
 	}
+	_, err = s.refreshRepo.Create(ctx, &domain.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: signedtoken.Hash(refreshToken),
+		ExpiresAt: refreshExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return &domain.AuthResponse{Token: token, User: *user}, nil
+	token, expiresAt, err := s.generateToken(user.ID, familyID, 0, s.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AuthResponse{
+		Token:        token,
+		ExpiresAt:    expiresAt,
+		RefreshToken: refreshToken,
+		User:         *user,
+	}, nil
 }
 
-func (s *AuthServiceImpl) generateToken(userID int64) (string, error) {
+func (s *AuthServiceImpl) issueChallenge(userID int64) (string, error) {
+	challengeID, err := randomID()
+	if err != nil {
+		return "", apperror.Internal("generate token", err)
+	}
+	now := time.Now()
+	token, err := s.keys.Sign(jwt.MapClaims{
+		"user_id": userID,
+		"typ":     challengeTokenType,
+		"cid":     challengeID,
+		"exp":     now.Add(challengeTTL).Unix(),
+		"iat":     now.Unix(),
+	})
+	if err != nil {
+		return "", apperror.Internal("generate token", err)
+	}
+	return token, nil
+}
+
+func (s *AuthServiceImpl) generateToken(userID int64, familyID string, impersonatorID int64, ttl time.Duration) (string, time.Time, error) {
+	jti, err := randomID()
+	if err != nil {
+		return "", time.Time{}, apperror.Internal("generate token", err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl).Truncate(time.Second)
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(s.jwtExpiry).Unix(),
-		"iat":     time.Now().Unix(),
+		"jti":     jti,
+		"exp":     expiresAt.Unix(),
+		"iat":     now.Unix(),
+	}
+	if familyID != "" {
+		claims["fam"] = familyID
+	}
+	if impersonatorID != 0 {
+		claims["impersonator_id"] = impersonatorID
+	}
+
+	token, err := s.keys.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, apperror.Internal("generate token", err)
+	}
+	return token, expiresAt, nil
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
+	return hex.EncodeToString(b), nil
 }
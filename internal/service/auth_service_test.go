@@ -7,15 +7,43 @@ import (
 
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/jwtkeys"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
 	"github.com/shalfey088/team-task-nexus/test/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
 )
 
+func newAuthService(userRepo *mocks.UserRepositoryMock, invitationSvc *mocks.InvitationServiceMock) *AuthServiceImpl {
+	refreshRepo := new(mocks.RefreshTokenRepositoryMock)
+	refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(int64(1), nil).Maybe()
+	return newAuthServiceWithSessions(userRepo, invitationSvc, refreshRepo, new(mocks.TokenDenylistMock))
+}
+
+func newAuthServiceWithSessions(userRepo *mocks.UserRepositoryMock, invitationSvc *mocks.InvitationServiceMock, refreshRepo *mocks.RefreshTokenRepositoryMock, denylist *mocks.TokenDenylistMock) *AuthServiceImpl {
+	txManager := new(mocks.TransactionManagerMock)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil).Maybe()
+	verifier := new(mocks.EmailVerifierMock)
+	verifier.On("SendVerification", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Maybe()
+	return NewAuthService(userRepo, refreshRepo, txManager, invitationSvc, verifier, new(mocks.SecondFactorVerifierMock), denylist, newTestKeyRing(), "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+}
+
+func newTestKeyRing() *jwtkeys.KeyRing {
+	key, err := jwtkeys.Generate("test-key")
+	if err != nil {
+		panic(err)
+	}
+	ring, err := jwtkeys.NewKeyRing([]jwtkeys.Key{key}, jwtkeys.Options{Issuer: "test-issuer", Audience: "test-api", Grace: time.Hour})
+	if err != nil {
+		panic(err)
+	}
+	return ring
+}
+
 func TestAuthService_Register_Success(t *testing.T) {
 	userRepo := new(mocks.UserRepositoryMock)
-	svc := NewAuthService(userRepo, "test-secret", 24*time.Hour)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
 
 	userRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(int64(1), nil)
 
@@ -37,7 +65,7 @@ func TestAuthService_Register_Success(t *testing.T) {
 
 func TestAuthService_Register_EmptyFields(t *testing.T) {
 	userRepo := new(mocks.UserRepositoryMock)
-	svc := NewAuthService(userRepo, "test-secret", 24*time.Hour)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
 
 	req := domain.RegisterRequest{Email: "", Password: "", FullName: ""}
 	result, err := svc.Register(context.Background(), req)
@@ -51,7 +79,7 @@ func TestAuthService_Register_EmptyFields(t *testing.T) {
 
 func TestAuthService_Register_EmailTaken(t *testing.T) {
 	userRepo := new(mocks.UserRepositoryMock)
-	svc := NewAuthService(userRepo, "test-secret", 24*time.Hour)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
 
 	userRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(int64(0), apperror.ErrEmailTaken)
 
@@ -73,7 +101,7 @@ func TestAuthService_Register_EmailTaken(t *testing.T) {
 
 func TestAuthService_Login_Success(t *testing.T) {
 	userRepo := new(mocks.UserRepositoryMock)
-	svc := NewAuthService(userRepo, "test-secret", 24*time.Hour)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
 
 	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
 	user := &domain.User{
@@ -94,13 +122,33 @@ func TestAuthService_Login_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.NotEmpty(t, result.Token)
+	assert.NotEmpty(t, result.RefreshToken)
 	assert.Equal(t, int64(1), result.User.ID)
 	userRepo.AssertExpectations(t)
 }
 
+func TestAuthService_Login_TokenCarriesKidIssuerAndAudience(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	userRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(&domain.User{
+		ID: 1, Email: "test@example.com", PasswordHash: string(hash),
+	}, nil)
+
+	result, err := svc.Login(context.Background(), domain.LoginRequest{Email: "test@example.com", Password: "password123"})
+	assert.NoError(t, err)
+
+	claims, err := svc.keys.Parse(result.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-issuer", claims["iss"])
+	assert.Equal(t, "test-api", claims["aud"])
+	assert.Equal(t, float64(1), claims["user_id"])
+}
+
 func TestAuthService_Login_WrongPassword(t *testing.T) {
 	userRepo := new(mocks.UserRepositoryMock)
-	svc := NewAuthService(userRepo, "test-secret", 24*time.Hour)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
 
 	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
 	user := &domain.User{
@@ -125,7 +173,7 @@ func TestAuthService_Login_WrongPassword(t *testing.T) {
 
 func TestAuthService_Login_UserNotFound(t *testing.T) {
 	userRepo := new(mocks.UserRepositoryMock)
-	svc := NewAuthService(userRepo, "test-secret", 24*time.Hour)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
 
 	userRepo.On("GetByEmail", mock.Anything, "notfound@example.com").Return(nil, apperror.NotFound("user not found"))
 
@@ -143,7 +191,7 @@ func TestAuthService_Login_UserNotFound(t *testing.T) {
 
 func TestAuthService_Login_EmptyFields(t *testing.T) {
 	userRepo := new(mocks.UserRepositoryMock)
-	svc := NewAuthService(userRepo, "test-secret", 24*time.Hour)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
 
 	req := domain.LoginRequest{Email: "", Password: ""}
 	result, err := svc.Login(context.Background(), req)
@@ -154,3 +202,218 @@ func TestAuthService_Login_EmptyFields(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, 400, appErr.Code)
 }
+
+func TestAuthService_Register_WithInvitation(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	invitationSvc := new(mocks.InvitationServiceMock)
+	svc := newAuthService(userRepo, invitationSvc)
+
+	userRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(int64(7), nil)
+	userRepo.On("MarkEmailVerified", mock.Anything, int64(7)).Return(nil)
+	invitationSvc.On("Accept", mock.Anything, int64(7), "invite-token").Return(&domain.Team{ID: 1}, nil)
+
+	result, err := svc.Register(context.Background(), domain.RegisterRequest{
+		Email:       "invited@example.com",
+		Password:    "password123",
+		FullName:    "Invited User",
+		InviteToken: "invite-token",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), result.User.ID)
+	assert.True(t, result.User.EmailVerified())
+	userRepo.AssertExpectations(t)
+	invitationSvc.AssertExpectations(t)
+}
+
+func TestAuthService_Register_InvalidInvitation(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	invitationSvc := new(mocks.InvitationServiceMock)
+	svc := newAuthService(userRepo, invitationSvc)
+
+	userRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(int64(7), nil)
+	userRepo.On("MarkEmailVerified", mock.Anything, int64(7)).Return(nil)
+	invitationSvc.On("Accept", mock.Anything, int64(7), "bad-token").Return(nil, apperror.BadRequest("invalid or expired invitation"))
+
+	result, err := svc.Register(context.Background(), domain.RegisterRequest{
+		Email:       "invited@example.com",
+		Password:    "password123",
+		FullName:    "Invited User",
+		InviteToken: "bad-token",
+	})
+
+	assert.Nil(t, result)
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+}
+
+func TestAuthService_Login_DisabledAccount(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	disabledAt := time.Now()
+	userRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(&domain.User{
+		ID: 1, Email: "test@example.com", PasswordHash: string(hash), DisabledAt: &disabledAt,
+	}, nil)
+
+	result, err := svc.Login(context.Background(), domain.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+	assert.Nil(t, result)
+	assert.Equal(t, errAccountDisabled, err)
+}
+
+func TestAuthService_CheckActive(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
+
+	disabledAt := time.Now()
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1}, nil)
+	userRepo.On("GetByID", mock.Anything, int64(2)).Return(&domain.User{ID: 2, DisabledAt: &disabledAt}, nil)
+	userRepo.On("GetByID", mock.Anything, int64(3)).Return(nil, apperror.NotFound("user not found"))
+
+	assert.NoError(t, svc.CheckActive(context.Background(), 1))
+	assert.Equal(t, errAccountDisabled, svc.CheckActive(context.Background(), 2))
+	assert.Equal(t, apperror.ErrUnauthorized, svc.CheckActive(context.Background(), 3))
+}
+
+func TestAuthService_Refresh_RotatesToken(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	refreshRepo := new(mocks.RefreshTokenRepositoryMock)
+	svc := newAuthServiceWithSessions(userRepo, new(mocks.InvitationServiceMock), refreshRepo, new(mocks.TokenDenylistMock))
+
+	token, _ := svc.signer.Sign(refreshTokenPurpose, time.Now().Add(time.Hour))
+	refreshRepo.On("GetByHashForUpdate", mock.Anything, signedtoken.Hash(token)).Return(&domain.RefreshToken{
+		ID: 5, UserID: 1, FamilyID: "fam", ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1}, nil)
+	refreshRepo.On("MarkRotated", mock.Anything, int64(5)).Return(nil)
+	refreshRepo.On("Create", mock.Anything, mock.MatchedBy(func(rt *domain.RefreshToken) bool {
+		return rt.UserID == 1 && rt.FamilyID == "fam" && rt.TokenHash != signedtoken.Hash(token)
+	})).Return(int64(6), nil)
+
+	result, err := svc.Refresh(context.Background(), token)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Token)
+	assert.NotEqual(t, token, result.RefreshToken)
+	refreshRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_BlockedUntilVerified(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	refreshRepo := new(mocks.RefreshTokenRepositoryMock)
+	svc := newAuthServiceWithSessions(userRepo, new(mocks.InvitationServiceMock), refreshRepo, new(mocks.TokenDenylistMock))
+	svc.policy = domain.EmailVerificationPolicy{BlockLogin: true}
+
+	token, _ := svc.signer.Sign(refreshTokenPurpose, time.Now().Add(time.Hour))
+	refreshRepo.On("GetByHashForUpdate", mock.Anything, signedtoken.Hash(token)).Return(&domain.RefreshToken{
+		ID: 5, UserID: 1, FamilyID: "fam", ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1}, nil)
+
+	result, err := svc.Refresh(context.Background(), token)
+
+	assert.Nil(t, result)
+	assert.Equal(t, errEmailNotVerified, err)
+	refreshRepo.AssertNotCalled(t, "MarkRotated", mock.Anything, mock.Anything)
+	refreshRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	refreshRepo := new(mocks.RefreshTokenRepositoryMock)
+	denylist := new(mocks.TokenDenylistMock)
+	svc := newAuthServiceWithSessions(new(mocks.UserRepositoryMock), new(mocks.InvitationServiceMock), refreshRepo, denylist)
+
+	rotatedAt := time.Now()
+	token, _ := svc.signer.Sign(refreshTokenPurpose, time.Now().Add(time.Hour))
+	refreshRepo.On("GetByHashForUpdate", mock.Anything, signedtoken.Hash(token)).Return(&domain.RefreshToken{
+		ID: 5, UserID: 1, FamilyID: "fam", ExpiresAt: time.Now().Add(time.Hour), RotatedAt: &rotatedAt,
+	}, nil)
+	refreshRepo.On("RevokeFamily", mock.Anything, "fam").Return(nil)
+	denylist.On("Revoke", mock.Anything, "fam", 15*time.Minute).Return(nil)
+
+	result, err := svc.Refresh(context.Background(), token)
+
+	assert.Nil(t, result)
+	assert.Equal(t, errRefreshTokenReused, err)
+	refreshRepo.AssertExpectations(t)
+	denylist.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_InvalidToken(t *testing.T) {
+	svc := newAuthService(new(mocks.UserRepositoryMock), new(mocks.InvitationServiceMock))
+
+	result, err := svc.Refresh(context.Background(), "not-a-token")
+
+	assert.Nil(t, result)
+	assert.Equal(t, errInvalidRefreshToken, err)
+}
+
+func TestAuthService_Logout_RevokesSession(t *testing.T) {
+	refreshRepo := new(mocks.RefreshTokenRepositoryMock)
+	denylist := new(mocks.TokenDenylistMock)
+	svc := newAuthServiceWithSessions(new(mocks.UserRepositoryMock), new(mocks.InvitationServiceMock), refreshRepo, denylist)
+
+	refreshRepo.On("RevokeFamily", mock.Anything, "fam").Return(nil)
+	denylist.On("Revoke", mock.Anything, "fam", 15*time.Minute).Return(nil)
+	denylist.On("Revoke", mock.Anything, "jti", mock.AnythingOfType("time.Duration")).Return(nil)
+
+	err := svc.Logout(context.Background(), domain.AccessClaims{
+		UserID: 1, TokenID: "jti", FamilyID: "fam", ExpiresAt: time.Now().Add(10 * time.Minute),
+	})
+
+	assert.NoError(t, err)
+	refreshRepo.AssertExpectations(t)
+	denylist.AssertExpectations(t)
+}
+
+func TestAuthService_LogoutEverywhere(t *testing.T) {
+	refreshRepo := new(mocks.RefreshTokenRepositoryMock)
+	denylist := new(mocks.TokenDenylistMock)
+	svc := newAuthServiceWithSessions(new(mocks.UserRepositoryMock), new(mocks.InvitationServiceMock), refreshRepo, denylist)
+
+	refreshRepo.On("RevokeForUser", mock.Anything, int64(1)).Return([]string{"fam-a", "fam-b"}, nil)
+	denylist.On("Revoke", mock.Anything, "fam-a", impersonationTTL).Return(nil)
+	denylist.On("Revoke", mock.Anything, "fam-b", impersonationTTL).Return(nil)
+	denylist.On("Revoke", mock.Anything, "jti", mock.AnythingOfType("time.Duration")).Return(nil)
+
+	claims := domain.AccessClaims{UserID: 1, TokenID: "jti", FamilyID: "fam-a", ExpiresAt: time.Now().Add(10 * time.Minute)}
+	assert.NoError(t, svc.LogoutEverywhere(context.Background(), claims))
+	denylist.AssertExpectations(t)
+
+	claims.ImpersonatorID = 9
+	err := svc.LogoutEverywhere(context.Background(), claims)
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.Code)
+}
+
+func TestAuthService_IssueImpersonationToken_RecordsFamilyForBothAccounts(t *testing.T) {
+	refreshRepo := new(mocks.RefreshTokenRepositoryMock)
+	svc := newAuthServiceWithSessions(new(mocks.UserRepositoryMock), new(mocks.InvitationServiceMock), refreshRepo, new(mocks.TokenDenylistMock))
+
+	var rows []*domain.RefreshToken
+	refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).
+		Run(func(args mock.Arguments) { rows = append(rows, args.Get(1).(*domain.RefreshToken)) }).
+		Return(int64(1), nil)
+
+	token, expiresAt, err := svc.IssueImpersonationToken(context.Background(), 2, 1)
+	assert.NoError(t, err)
+
+	claims, err := svc.keys.Parse(token)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), claims["impersonator_id"])
+	family, _ := claims["fam"].(string)
+	assert.NotEmpty(t, family)
+
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, int64(2), rows[0].UserID)
+		assert.Equal(t, int64(1), rows[1].UserID)
+		for _, row := range rows {
+			assert.Equal(t, family, row.FamilyID)
+			assert.Equal(t, expiresAt, row.ExpiresAt)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+// allPermissions lists every permission in the order they are reported.
+var allPermissions = []domain.Permission{
+	domain.PermTeamUpdate,
+	domain.PermTeamArchive,
+	domain.PermTeamDelete,
+	domain.PermTeamInvite,
+	domain.PermMemberManage,
+	domain.PermRoleManage,
+	domain.PermTaskCreate,
+	domain.PermTaskUpdate,
+	domain.PermTaskDelete,
+	domain.PermCommentCreate,
+	domain.PermCommentModerate,
+	domain.PermAttachmentUpload,
+	domain.PermAttachmentDelete,
+}
+
+// grantablePermissions may be put into custom roles. Archiving, deleting
+// the team and managing members or roles stay with owners and admins.
+var grantablePermissions = map[domain.Permission]bool{
+	domain.PermTeamUpdate:       true,
+	domain.PermTeamInvite:       true,
+	domain.PermTaskCreate:       true,
+	domain.PermTaskUpdate:       true,
+	domain.PermTaskDelete:       true,
+	domain.PermCommentCreate:    true,
+	domain.PermCommentModerate:  true,
+	domain.PermAttachmentUpload: true,
+	domain.PermAttachmentDelete: true,
+}
+
+var rolePermissions = map[domain.TeamRole]map[domain.Permission]bool{
+	domain.TeamRoleOwner: permissionSet(allPermissions...),
+	domain.TeamRoleAdmin: permissionSet(
+		domain.PermTeamUpdate,
+		domain.PermTeamInvite,
+		domain.PermMemberManage,
+		domain.PermRoleManage,
+		domain.PermTaskCreate,
+		domain.PermTaskUpdate,
+		domain.PermTaskDelete,
+		domain.PermCommentCreate,
+		domain.PermCommentModerate,
+		domain.PermAttachmentUpload,
+		domain.PermAttachmentDelete,
+	),
+	domain.TeamRoleMember: permissionSet(
+		domain.PermTaskCreate,
+		domain.PermTaskUpdate,
+		domain.PermCommentCreate,
+		domain.PermAttachmentUpload,
+	),
+	domain.TeamRoleGuest: permissionSet(
+		domain.PermCommentCreate,
+	),
+}
+
+type AuthorizerImpl struct {
+	teamRepo port.TeamRepository
+}
+
+func NewAuthorizer(teamRepo port.TeamRepository) *AuthorizerImpl {
+	return &AuthorizerImpl{teamRepo: teamRepo}
+}
+
+func (a *AuthorizerImpl) Member(ctx context.Context, userID, teamID int64) (*domain.TeamMember, error) {
+	if restricted, ok := requestctx.TeamRestriction(ctx); ok && restricted != teamID {
+		return nil, apperror.ErrNotTeamMember
+	}
+	member, err := a.teamRepo.GetMember(ctx, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, apperror.ErrNotTeamMember
+	}
+	if err := checkTwoFactor(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// TaskMember resolves the caller's membership of the task's team. A guest
+// limited to specific tasks gets a not-found for any other task.
+func (a *AuthorizerImpl) TaskMember(ctx context.Context, userID int64, task *domain.Task) (*domain.TeamMember, error) {
+	member, err := a.Member(ctx, userID, task.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.TaskScoped {
+		return member, nil
+	}
+	granted, err := a.teamRepo.IsTaskGranted(ctx, userID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !granted {
+		return nil, apperror.NotFound("task not found")
+	}
+	return member, nil
+}
+
+// Access resolves which of teamIDs the user may read tasks in, checking each
+// team exactly as Member does. With nil teamIDs every team the user reaches
+// is considered. Teams the user is refused are left out rather than failing
+// the whole call.
+func (a *AuthorizerImpl) Access(ctx context.Context, userID int64, teamIDs []int64) (*domain.TaskAccess, error) {
+	if teamIDs == nil {
+		teams, err := a.teamRepo.ListByUserID(ctx, userID, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range teams {
+			teamIDs = append(teamIDs, t.ID)
+		}
+	}
+
+	access := &domain.TaskAccess{UserID: userID}
+	for _, teamID := range teamIDs {
+		member, err := a.Member(ctx, userID, teamID)
+		if err != nil {
+			var appErr *apperror.AppError
+			if errors.As(err, &appErr) && appErr.Code == http.StatusForbidden {
+				continue
+			}
+			return nil, err
+		}
+		if member.TaskScoped {
+			access.ScopedTeams = append(access.ScopedTeams, teamID)
+		} else {
+			access.Teams = append(access.Teams, teamID)
+		}
+	}
+	return access, nil
+}
+
+func (a *AuthorizerImpl) Authorize(ctx context.Context, userID, teamID int64, perm domain.Permission) (*domain.TeamMember, error) {
+	member, err := a.Member(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if !a.Can(member, perm) {
+		return nil, apperror.ErrInsufficientRole
+	}
+	return member, nil
+}
+
+// Can reports whether the member holds perm. A custom role replaces the
+// defaults of the member role; owners and admins always keep theirs.
+func (a *AuthorizerImpl) Can(member *domain.TeamMember, perm domain.Permission) bool {
+	if member.Role == domain.TeamRoleMember && member.CustomPermissions != nil {
+		for _, p := range domain.ParsePermissions(*member.CustomPermissions) {
+			if p == perm && grantablePermissions[p] {
+				return true
+			}
+		}
+		return false
+	}
+	return rolePermissions[member.Role][perm]
+}
+
+func (a *AuthorizerImpl) Permissions(member *domain.TeamMember) []domain.Permission {
+	perms := []domain.Permission{}
+	for _, p := range allPermissions {
+		if a.Can(member, p) {
+			perms = append(perms, p)
+		}
+	}
+	return perms
+}
+
+func permissionSet(perms ...domain.Permission) map[domain.Permission]bool {
+	set := make(map[domain.Permission]bool, len(perms))
+	for _, p := range perms {
+		set[p] = true
+	}
+	return set
+}
@@ -2,6 +2,11 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
@@ -9,23 +14,38 @@ import (
 )
 
 type CommentServiceImpl struct {
-	commentRepo port.CommentRepository
-	taskRepo    port.TaskRepository
-	teamRepo    port.TeamRepository
-	notifSvc    port.NotificationService
+	commentRepo  port.CommentRepository
+	taskRepo     port.TaskRepository
+	authz        port.Authorizer
+	userRepo     port.UserRepository
+	reactionRepo port.ReactionRepository
+	txManager    port.TransactionManager
+	notifSvc     port.NotificationService
+	mentionSvc   port.MentionService
+	attachSvc    port.AttachmentService
 }
 
 func NewCommentService(
 	commentRepo port.CommentRepository,
 	taskRepo port.TaskRepository,
-	teamRepo port.TeamRepository,
+	authz port.Authorizer,
+	userRepo port.UserRepository,
+	reactionRepo port.ReactionRepository,
+	txManager port.TransactionManager,
 	notifSvc port.NotificationService,
+	mentionSvc port.MentionService,
+	attachSvc port.AttachmentService,
 ) *CommentServiceImpl {
 	return &CommentServiceImpl{
-		commentRepo: commentRepo,
-		taskRepo:    taskRepo,
-		teamRepo:    teamRepo,
-		notifSvc:    notifSvc,
+		commentRepo:  commentRepo,
+		taskRepo:     taskRepo,
+		authz:        authz,
+		userRepo:     userRepo,
+		reactionRepo: reactionRepo,
+		txManager:    txManager,
+		notifSvc:     notifSvc,
+		mentionSvc:   mentionSvc,
+		attachSvc:    attachSvc,
 	}
 }
 
@@ -39,44 +59,493 @@ func (s *CommentServiceImpl) Create(ctx context.Context, userID, taskID int64, r
 		return nil, err
 	}
 
-	member, err := s.teamRepo.GetMember(ctx, task.TeamID, userID)
+	member, err := s.authz.TaskMember(ctx, userID, task)
 	if err != nil {
 		return nil, err
 	}
-	if member == nil {
-		return nil, apperror.ErrNotTeamMember
+	if !s.authz.Can(member, domain.PermCommentCreate) {
+		return nil, apperror.ErrInsufficientRole
+	}
+	if err := checkWritable(member); err != nil {
+		return nil, err
+	}
+	if req.Internal && member.IsGuest() {
+		return nil, apperror.Forbidden("guests cannot post internal comments")
 	}
 
 	comment := &domain.TaskComment{
-		TaskID:  taskID,
-		UserID:  userID,
-		Content: req.Content,
+		TaskID:   taskID,
+		UserID:   userID,
+		Content:  req.Content,
+		Internal: req.Internal,
 	}
 
-	id, err := s.commentRepo.Create(ctx, comment)
+	if req.ParentID != nil {
+		parent, err := s.commentRepo.GetByID(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.TaskID != taskID {
+			return nil, apperror.BadRequest("parent comment belongs to another task")
+		}
+		if parent.Internal && member.IsGuest() {
+			return nil, apperror.NotFound("comment not found")
+		}
+		if parent.Depth+1 > domain.MaxCommentDepth {
+			return nil, apperror.BadRequest("maximum reply depth reached")
+		}
+		rootID := parent.ThreadID()
+		comment.ParentID = &parent.ID
+		comment.RootID = &rootID
+		comment.Depth = parent.Depth + 1
+		// Replies to internal comments stay internal.
+		comment.Internal = comment.Internal || parent.Internal
+	}
+
+	handles := parseMentions(comment.Content)
+	var mentions *domain.MentionResult
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		id, err := s.commentRepo.Create(ctx, comment)
+		if err != nil {
+			return err
+		}
+		comment.ID = id
+		if len(handles) == 0 {
+			return nil
+		}
+		mentions, err = s.mentionSvc.Record(ctx, mentionSource(comment, task), handles)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	comment.ID = id
 
 	_ = s.notifSvc.NotifyCommentAdded(ctx, comment, task)
+	if comment.RootID != nil {
+		s.notifyThreadParticipants(ctx, comment, task)
+	}
+	if mentions != nil {
+		comment.UnresolvedMentions = mentions.Unresolved
+		s.mentionSvc.Notify(ctx, mentionSource(comment, task), mentions.Mentioned)
+	}
 
 	return comment, nil
 }
 
+func mentionSource(comment *domain.TaskComment, task *domain.Task) domain.MentionSource {
+	return domain.MentionSource{AuthorID: comment.UserID, Task: task, CommentID: &comment.ID, Internal: comment.Internal}
+}
+
+func (s *CommentServiceImpl) notifyThreadParticipants(ctx context.Context, reply *domain.TaskComment, task *domain.Task) {
+	participantIDs, err := s.commentRepo.ListThreadParticipants(ctx, *reply.RootID)
+	if err != nil {
+		return
+	}
+
+	var recipientIDs []int64
+	for _, id := range participantIDs {
+		if id == reply.UserID {
+			continue
+		}
+		if reply.Internal {
+			member, err := s.authz.Member(ctx, id, task.TeamID)
+			if err != nil || member.IsGuest() {
+				continue
+			}
+		}
+		recipientIDs = append(recipientIDs, id)
+	}
+	if len(recipientIDs) == 0 {
+		return
+	}
+
+	recipients, err := s.userRepo.GetByIDs(ctx, recipientIDs)
+	if err != nil {
+		return
+	}
+	_ = s.notifSvc.NotifyThreadReply(ctx, reply, task, recipients)
+}
+
 func (s *CommentServiceImpl) ListByTaskID(ctx context.Context, userID, taskID int64) ([]domain.TaskComment, error) {
 	task, err := s.taskRepo.GetByID(ctx, taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	member, err := s.teamRepo.GetMember(ctx, task.TeamID, userID)
+	member, err := s.authz.TaskMember(ctx, userID, task)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.ListByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if member.IsGuest() {
+		comments = withoutInternal(comments)
+	}
+	if err := s.prepareForListing(ctx, userID, comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (s *CommentServiceImpl) ListPage(ctx context.Context, userID, taskID int64, query domain.CommentQuery) (*domain.CommentPage, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.authz.TaskMember(ctx, userID, task)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := domain.CommentFilter{
+		TaskID:          taskID,
+		Limit:           query.Limit,
+		ExcludeInternal: member.IsGuest(),
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	if query.Since != "" {
+		since, err := time.Parse(time.RFC3339, query.Since)
+		if err != nil {
+			return nil, apperror.BadRequest("since must be an RFC 3339 timestamp")
+		}
+		filter.Since = &since
+	}
+	if query.Cursor != "" {
+		cursor, err := decodeCommentCursor(query.Cursor)
+		if err != nil {
+			return nil, apperror.BadRequest("invalid cursor")
+		}
+		filter.Cursor = cursor
+	}
+
+	// Fetch one extra row to know whether another page exists in the
+	// direction of travel.
+	pageLimit := filter.Limit
+	filter.Limit++
+	comments, total, err := s.commentRepo.ListPage(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	backward := filter.Cursor != nil && filter.Cursor.Before
+	hasMore := len(comments) > pageLimit
+	if hasMore {
+		if backward {
+			comments = comments[len(comments)-pageLimit:]
+		} else {
+			comments = comments[:pageLimit]
+		}
+	}
+	if comments == nil {
+		comments = []domain.TaskComment{}
+	}
+
+	page := &domain.CommentPage{Comments: comments, Total: total}
+	if len(comments) > 0 {
+		first, last := comments[0], comments[len(comments)-1]
+		next := encodeCommentCursor(domain.CommentCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		prev := encodeCommentCursor(domain.CommentCursor{CreatedAt: first.CreatedAt, ID: first.ID, Before: true})
+		// Arriving via a cursor means the side we came from is not empty.
+		if backward {
+			page.NextCursor = next
+			if hasMore {
+				page.PrevCursor = prev
+			}
+		} else {
+			if hasMore {
+				page.NextCursor = next
+			}
+			if filter.Cursor != nil {
+				page.PrevCursor = prev
+			}
+		}
+	}
+
+	if err := s.prepareForListing(ctx, userID, page.Comments); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func withoutInternal(comments []domain.TaskComment) []domain.TaskComment {
+	visible := comments[:0]
+	for _, c := range comments {
+		if !c.Internal {
+			visible = append(visible, c)
+		}
+	}
+	return visible
+}
+
+// prepareForListing blanks deleted comments and attaches reaction summaries.
+func (s *CommentServiceImpl) prepareForListing(ctx context.Context, userID int64, comments []domain.TaskComment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(comments))
+	for i := range comments {
+		ids[i] = comments[i].ID
+	}
+	reactions, err := s.reactionRepo.Summaries(ctx, domain.ReactionTargetComment, ids, userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		tombstone(&comments[i])
+		if !comments[i].IsDeleted() {
+			comments[i].Reactions = reactions[comments[i].ID]
+		}
+	}
+	return nil
+}
+
+func encodeCommentCursor(c domain.CommentCursor) string {
+	dir := "a"
+	if c.Before {
+		dir = "b"
+	}
+	raw := fmt.Sprintf("%s:%d:%d", dir, c.CreatedAt.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCommentCursor(s string) (*domain.CommentCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
 	if err != nil {
 		return nil, err
 	}
-	if member == nil {
-		return nil, apperror.ErrNotTeamMember
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 || (parts[0] != "a" && parts[0] != "b") {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.CommentCursor{
+		CreatedAt: time.Unix(0, nanos).UTC(),
+		ID:        id,
+		Before:    parts[0] == "b",
+	}, nil
+}
+
+func (s *CommentServiceImpl) Update(ctx context.Context, userID, taskID, commentID int64, req domain.UpdateCommentRequest) (*domain.TaskComment, error) {
+	if req.Content == "" {
+		return nil, apperror.BadRequest("comment content is required")
 	}
 
-	return s.commentRepo.ListByTaskID(ctx, taskID)
+	comment, task, member, err := s.loadComment(ctx, userID, taskID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.authz.Can(member, domain.PermCommentCreate) {
+		return nil, apperror.ErrInsufficientRole
+	}
+	if err := checkWritable(member); err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() {
+		return nil, apperror.NotFound("comment not found")
+	}
+	if comment.UserID != userID {
+		return nil, apperror.Forbidden("only the author can edit this comment")
+	}
+	if comment.Content == req.Content {
+		return comment, nil
+	}
+
+	handles := newMentions(req.Content, comment.Content)
+	var mentions *domain.MentionResult
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		err := s.commentRepo.CreateRevision(ctx, &domain.CommentRevision{
+			CommentID: commentID,
+			Content:   comment.Content,
+			EditedBy:  userID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.commentRepo.UpdateContent(ctx, commentID, req.Content); err != nil {
+			return err
+		}
+		if len(handles) == 0 {
+			return nil
+		}
+		mentions, err = s.mentionSvc.Record(ctx, mentionSource(comment, task), handles)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err = s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if mentions != nil {
+		comment.UnresolvedMentions = mentions.Unresolved
+		s.mentionSvc.Notify(ctx, mentionSource(comment, task), mentions.Mentioned)
+	}
+	return comment, nil
+}
+
+func (s *CommentServiceImpl) Delete(ctx context.Context, userID, taskID, commentID int64) error {
+	comment, _, member, err := s.loadComment(ctx, userID, taskID, commentID)
+	if err != nil {
+		return err
+	}
+	if err := checkWritable(member); err != nil {
+		return err
+	}
+	if comment.IsDeleted() {
+		return nil
+	}
+	if comment.UserID != userID && !s.authz.Can(member, domain.PermCommentModerate) {
+		return apperror.ErrInsufficientRole
+	}
+
+	if err := s.commentRepo.SoftDelete(ctx, commentID, userID); err != nil {
+		return err
+	}
+	return s.attachSvc.DeleteForComment(ctx, commentID)
+}
+
+func (s *CommentServiceImpl) ListRevisions(ctx context.Context, userID, taskID, commentID int64) ([]domain.CommentRevision, error) {
+	comment, _, member, err := s.loadComment(ctx, userID, taskID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	// Deleted content stays available to its author and moderators only.
+	if comment.IsDeleted() && comment.UserID != userID && !s.authz.Can(member, domain.PermCommentModerate) {
+		return nil, apperror.NotFound("comment not found")
+	}
+
+	revisions, err := s.commentRepo.ListRevisions(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if revisions == nil {
+		revisions = []domain.CommentRevision{}
+	}
+	return revisions, nil
+}
+
+func (s *CommentServiceImpl) ListThreads(ctx context.Context, userID, taskID int64) ([]domain.CommentNode, error) {
+	comments, err := s.ListByTaskID(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return buildCommentTree(comments), nil
+}
+
+// SetThreadResolved is open to anyone who may comment, to the author of the
+// thread and to moderators.
+func (s *CommentServiceImpl) SetThreadResolved(ctx context.Context, userID, taskID, commentID int64, resolved bool) (*domain.TaskComment, error) {
+	comment, _, member, err := s.loadComment(ctx, userID, taskID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID && !s.authz.Can(member, domain.PermCommentCreate) && !s.authz.Can(member, domain.PermCommentModerate) {
+		return nil, apperror.ErrInsufficientRole
+	}
+	if err := checkWritable(member); err != nil {
+		return nil, err
+	}
+	if comment.ParentID != nil {
+		return nil, apperror.BadRequest("only top-level comments can be resolved")
+	}
+	if (comment.ResolvedAt != nil) == resolved {
+		tombstone(comment)
+		return comment, nil
+	}
+
+	var resolvedBy *int64
+	if resolved {
+		resolvedBy = &userID
+	}
+	if err := s.commentRepo.SetResolved(ctx, commentID, resolvedBy); err != nil {
+		return nil, err
+	}
+
+	comment, err = s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	tombstone(comment)
+	return comment, nil
+}
+
+// buildCommentTree nests comments under their parents. Input must be ordered
+// by creation time so parents precede their replies.
+func buildCommentTree(comments []domain.TaskComment) []domain.CommentNode {
+	children := make(map[int64][]domain.TaskComment)
+	var roots []domain.TaskComment
+	for _, c := range comments {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	var build func(c domain.TaskComment) domain.CommentNode
+	build = func(c domain.TaskComment) domain.CommentNode {
+		node := domain.CommentNode{TaskComment: c, Replies: []domain.CommentNode{}}
+		for _, child := range children[c.ID] {
+			childNode := build(child)
+			node.ReplyCount += 1 + childNode.ReplyCount
+			node.Replies = append(node.Replies, childNode)
+		}
+		return node
+	}
+
+	tree := make([]domain.CommentNode, 0, len(roots))
+	for _, root := range roots {
+		tree = append(tree, build(root))
+	}
+	return tree
+}
+
+func (s *CommentServiceImpl) loadComment(ctx context.Context, userID, taskID, commentID int64) (*domain.TaskComment, *domain.Task, *domain.TeamMember, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	member, err := s.authz.TaskMember(ctx, userID, task)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if comment.TaskID != taskID || (comment.Internal && member.IsGuest()) {
+		return nil, nil, nil, apperror.NotFound("comment not found")
+	}
+	return comment, task, member, nil
+}
+
+// tombstone strips the content of a soft-deleted comment while keeping its
+// place in the thread.
+func tombstone(c *domain.TaskComment) {
+	if c.IsDeleted() {
+		c.Content = ""
+	}
 }
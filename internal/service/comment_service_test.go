@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
@@ -15,8 +16,13 @@ func TestCommentService_Create_Success(t *testing.T) {
 	commentRepo := new(mocks.CommentRepositoryMock)
 	taskRepo := new(mocks.TaskRepositoryMock)
 	teamRepo := new(mocks.TeamRepositoryMock)
+	userRepo := new(mocks.UserRepositoryMock)
+	reactionRepo := new(mocks.ReactionRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
 	notifSvc := new(mocks.NotificationServiceMock)
-	svc := NewCommentService(commentRepo, taskRepo, teamRepo, notifSvc)
+	mentionSvc := new(mocks.MentionServiceMock)
+	attachSvc := new(mocks.AttachmentServiceMock)
+	svc := NewCommentService(commentRepo, taskRepo, NewAuthorizer(teamRepo), userRepo, reactionRepo, txManager, notifSvc, mentionSvc, attachSvc)
 
 	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{
 		ID: 1, TeamID: 1, Title: "Test Task",
@@ -24,6 +30,7 @@ func TestCommentService_Create_Success(t *testing.T) {
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
 		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
 	}, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 	commentRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.TaskComment")).Return(int64(1), nil)
 	notifSvc.On("NotifyCommentAdded", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
@@ -42,8 +49,13 @@ func TestCommentService_Create_EmptyContent(t *testing.T) {
 	commentRepo := new(mocks.CommentRepositoryMock)
 	taskRepo := new(mocks.TaskRepositoryMock)
 	teamRepo := new(mocks.TeamRepositoryMock)
+	userRepo := new(mocks.UserRepositoryMock)
+	reactionRepo := new(mocks.ReactionRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
 	notifSvc := new(mocks.NotificationServiceMock)
-	svc := NewCommentService(commentRepo, taskRepo, teamRepo, notifSvc)
+	mentionSvc := new(mocks.MentionServiceMock)
+	attachSvc := new(mocks.AttachmentServiceMock)
+	svc := NewCommentService(commentRepo, taskRepo, NewAuthorizer(teamRepo), userRepo, reactionRepo, txManager, notifSvc, mentionSvc, attachSvc)
 
 	result, err := svc.Create(context.Background(), 1, 1, domain.CreateCommentRequest{
 		Content: "",
@@ -60,8 +72,13 @@ func TestCommentService_Create_NotTeamMember(t *testing.T) {
 	commentRepo := new(mocks.CommentRepositoryMock)
 	taskRepo := new(mocks.TaskRepositoryMock)
 	teamRepo := new(mocks.TeamRepositoryMock)
+	userRepo := new(mocks.UserRepositoryMock)
+	reactionRepo := new(mocks.ReactionRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
 	notifSvc := new(mocks.NotificationServiceMock)
-	svc := NewCommentService(commentRepo, taskRepo, teamRepo, notifSvc)
+	mentionSvc := new(mocks.MentionServiceMock)
+	attachSvc := new(mocks.AttachmentServiceMock)
+	svc := NewCommentService(commentRepo, taskRepo, NewAuthorizer(teamRepo), userRepo, reactionRepo, txManager, notifSvc, mentionSvc, attachSvc)
 
 	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{
 		ID: 1, TeamID: 1,
@@ -81,8 +98,13 @@ func TestCommentService_ListByTaskID_Success(t *testing.T) {
 	commentRepo := new(mocks.CommentRepositoryMock)
 	taskRepo := new(mocks.TaskRepositoryMock)
 	teamRepo := new(mocks.TeamRepositoryMock)
+	userRepo := new(mocks.UserRepositoryMock)
+	reactionRepo := new(mocks.ReactionRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
 	notifSvc := new(mocks.NotificationServiceMock)
-	svc := NewCommentService(commentRepo, taskRepo, teamRepo, notifSvc)
+	mentionSvc := new(mocks.MentionServiceMock)
+	attachSvc := new(mocks.AttachmentServiceMock)
+	svc := NewCommentService(commentRepo, taskRepo, NewAuthorizer(teamRepo), userRepo, reactionRepo, txManager, notifSvc, mentionSvc, attachSvc)
 
 	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{
 		ID: 1, TeamID: 1,
@@ -94,19 +116,30 @@ func TestCommentService_ListByTaskID_Success(t *testing.T) {
 		{ID: 1, TaskID: 1, Content: "Comment 1"},
 		{ID: 2, TaskID: 1, Content: "Comment 2"},
 	}, nil)
+	reactionRepo.On("Summaries", mock.Anything, domain.ReactionTargetComment, []int64{1, 2}, int64(1)).
+		Return(map[int64][]domain.ReactionSummary{
+			2: {{Emoji: "+1", Count: 3, ReactedByMe: true}},
+		}, nil)
 
 	result, err := svc.ListByTaskID(context.Background(), 1, 1)
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
+	assert.Empty(t, result[0].Reactions)
+	assert.Equal(t, []domain.ReactionSummary{{Emoji: "+1", Count: 3, ReactedByMe: true}}, result[1].Reactions)
 }
 
 func TestCommentService_ListByTaskID_TaskNotFound(t *testing.T) {
 	commentRepo := new(mocks.CommentRepositoryMock)
 	taskRepo := new(mocks.TaskRepositoryMock)
 	teamRepo := new(mocks.TeamRepositoryMock)
+	userRepo := new(mocks.UserRepositoryMock)
+	reactionRepo := new(mocks.ReactionRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
 	notifSvc := new(mocks.NotificationServiceMock)
-	svc := NewCommentService(commentRepo, taskRepo, teamRepo, notifSvc)
+	mentionSvc := new(mocks.MentionServiceMock)
+	attachSvc := new(mocks.AttachmentServiceMock)
+	svc := NewCommentService(commentRepo, taskRepo, NewAuthorizer(teamRepo), userRepo, reactionRepo, txManager, notifSvc, mentionSvc, attachSvc)
 
 	taskRepo.On("GetByID", mock.Anything, int64(999)).Return(nil, apperror.NotFound("task not found"))
 
@@ -115,3 +148,419 @@ func TestCommentService_ListByTaskID_TaskNotFound(t *testing.T) {
 	assert.Nil(t, result)
 	assert.Error(t, err)
 }
+
+func newCommentServiceWithComment(role domain.TeamRole, userID int64, comment *domain.TaskComment) (
+	*CommentServiceImpl, *mocks.CommentRepositoryMock, *mocks.TransactionManagerMock,
+) {
+	return newCommentServiceForMember(&domain.TeamMember{TeamID: 1, UserID: userID, Role: role}, comment)
+}
+
+func newCommentServiceForMember(member *domain.TeamMember, comment *domain.TaskComment) (
+	*CommentServiceImpl, *mocks.CommentRepositoryMock, *mocks.TransactionManagerMock,
+) {
+	userID := member.UserID
+	commentRepo := new(mocks.CommentRepositoryMock)
+	taskRepo := new(mocks.TaskRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	userRepo := new(mocks.UserRepositoryMock)
+	reactionRepo := new(mocks.ReactionRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
+	notifSvc := new(mocks.NotificationServiceMock)
+	mentionSvc := new(mocks.MentionServiceMock)
+	attachSvc := new(mocks.AttachmentServiceMock)
+	svc := NewCommentService(commentRepo, taskRepo, NewAuthorizer(teamRepo), userRepo, reactionRepo, txManager, notifSvc, mentionSvc, attachSvc)
+
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{ID: 1, TeamID: 1}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), userID).Return(member, nil)
+	commentRepo.On("GetByID", mock.Anything, comment.ID).Return(comment, nil)
+	reactionRepo.On("Summaries", mock.Anything, domain.ReactionTargetComment, mock.Anything, userID).
+		Return(map[int64][]domain.ReactionSummary{}, nil).Maybe()
+	attachSvc.On("DeleteForComment", mock.Anything, comment.ID).Return(nil).Maybe()
+	return svc, commentRepo, txManager
+}
+
+func TestCommentService_ListByTaskID_Tombstones(t *testing.T) {
+	deletedAt := time.Now()
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleMember, 1, &domain.TaskComment{ID: 1, TaskID: 1})
+	commentRepo.On("ListByTaskID", mock.Anything, int64(1)).Return([]domain.TaskComment{
+		{ID: 1, TaskID: 1, Content: "kept"},
+		{ID: 2, TaskID: 1, Content: "secret", DeletedAt: &deletedAt},
+	}, nil)
+
+	result, err := svc.ListByTaskID(context.Background(), 1, 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "kept", result[0].Content)
+	assert.Empty(t, result[1].Content)
+	assert.NotNil(t, result[1].DeletedAt)
+}
+
+func TestCommentService_ListByTaskID_GuestSkipsInternal(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleGuest, 1, &domain.TaskComment{ID: 1, TaskID: 1})
+	commentRepo.On("ListByTaskID", mock.Anything, int64(1)).Return([]domain.TaskComment{
+		{ID: 1, TaskID: 1, Content: "public"},
+		{ID: 2, TaskID: 1, Content: "team only", Internal: true},
+	}, nil)
+
+	result, err := svc.ListByTaskID(context.Background(), 1, 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, int64(1), result[0].ID)
+}
+
+func TestCommentService_Create_GuestInternalForbidden(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleGuest, 1, &domain.TaskComment{ID: 1, TaskID: 1})
+
+	_, err := svc.Create(context.Background(), 1, 1, domain.CreateCommentRequest{Content: "psst", Internal: true})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.Code)
+	commentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCommentService_Update_InternalHiddenFromGuest(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleGuest, 1, &domain.TaskComment{
+		ID: 5, TaskID: 1, UserID: 1, Content: "old", Internal: true,
+	})
+
+	_, err := svc.Update(context.Background(), 1, 1, 5, domain.UpdateCommentRequest{Content: "new"})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 404, appErr.Code)
+	commentRepo.AssertNotCalled(t, "UpdateContent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommentService_Update_StoresRevision(t *testing.T) {
+	svc, commentRepo, txManager := newCommentServiceWithComment(domain.TeamRoleMember, 1, &domain.TaskComment{
+		ID: 5, TaskID: 1, UserID: 1, Content: "old",
+	})
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	commentRepo.On("CreateRevision", mock.Anything, mock.MatchedBy(func(rev *domain.CommentRevision) bool {
+		return rev.CommentID == 5 && rev.Content == "old" && rev.EditedBy == 1
+	})).Return(nil)
+	commentRepo.On("UpdateContent", mock.Anything, int64(5), "new").Return(nil)
+
+	_, err := svc.Update(context.Background(), 1, 1, 5, domain.UpdateCommentRequest{Content: "new"})
+
+	assert.NoError(t, err)
+	commentRepo.AssertExpectations(t)
+}
+
+func TestCommentService_Update_NotAuthor(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleOwner, 2, &domain.TaskComment{
+		ID: 5, TaskID: 1, UserID: 1, Content: "old",
+	})
+
+	result, err := svc.Update(context.Background(), 2, 1, 5, domain.UpdateCommentRequest{Content: "new"})
+
+	assert.Nil(t, result)
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.Code)
+	commentRepo.AssertNotCalled(t, "UpdateContent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommentService_Update_NoCommentPermission(t *testing.T) {
+	perms := string(domain.PermTaskCreate)
+	svc, commentRepo, _ := newCommentServiceForMember(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember, CustomPermissions: &perms,
+	}, &domain.TaskComment{ID: 5, TaskID: 1, UserID: 1, Content: "old"})
+
+	result, err := svc.Update(context.Background(), 1, 1, 5, domain.UpdateCommentRequest{Content: "new"})
+
+	assert.Nil(t, result)
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	commentRepo.AssertNotCalled(t, "UpdateContent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommentService_Update_WrongTask(t *testing.T) {
+	svc, _, _ := newCommentServiceWithComment(domain.TeamRoleMember, 1, &domain.TaskComment{
+		ID: 5, TaskID: 42, UserID: 1, Content: "old",
+	})
+
+	_, err := svc.Update(context.Background(), 1, 1, 5, domain.UpdateCommentRequest{Content: "new"})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 404, appErr.Code)
+}
+
+func TestCommentService_Delete_ByModerator(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleAdmin, 2, &domain.TaskComment{
+		ID: 5, TaskID: 1, UserID: 1, Content: "spam",
+	})
+	commentRepo.On("SoftDelete", mock.Anything, int64(5), int64(2)).Return(nil)
+
+	err := svc.Delete(context.Background(), 2, 1, 5)
+
+	assert.NoError(t, err)
+	commentRepo.AssertExpectations(t)
+}
+
+func TestCommentService_Delete_ByOtherMember(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleMember, 2, &domain.TaskComment{
+		ID: 5, TaskID: 1, UserID: 1, Content: "mine",
+	})
+
+	err := svc.Delete(context.Background(), 2, 1, 5)
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	commentRepo.AssertNotCalled(t, "SoftDelete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommentService_ListRevisions_DeletedHiddenFromMembers(t *testing.T) {
+	deletedAt := time.Now()
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleMember, 2, &domain.TaskComment{
+		ID: 5, TaskID: 1, UserID: 1, DeletedAt: &deletedAt,
+	})
+
+	result, err := svc.ListRevisions(context.Background(), 2, 1, 5)
+
+	assert.Nil(t, result)
+	assert.Error(t, err)
+	commentRepo.AssertNotCalled(t, "ListRevisions", mock.Anything, mock.Anything)
+}
+
+func TestCommentService_Create_ReplyNotifiesParticipants(t *testing.T) {
+	commentRepo := new(mocks.CommentRepositoryMock)
+	taskRepo := new(mocks.TaskRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	userRepo := new(mocks.UserRepositoryMock)
+	reactionRepo := new(mocks.ReactionRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
+	notifSvc := new(mocks.NotificationServiceMock)
+	mentionSvc := new(mocks.MentionServiceMock)
+	attachSvc := new(mocks.AttachmentServiceMock)
+	svc := NewCommentService(commentRepo, taskRepo, NewAuthorizer(teamRepo), userRepo, reactionRepo, txManager, notifSvc, mentionSvc, attachSvc)
+
+	rootID := int64(10)
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{ID: 1, TeamID: 1}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(3)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 3, Role: domain.TeamRoleMember,
+	}, nil)
+	commentRepo.On("GetByID", mock.Anything, int64(11)).Return(&domain.TaskComment{
+		ID: 11, TaskID: 1, UserID: 2, ParentID: &rootID, RootID: &rootID, Depth: 1,
+	}, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	commentRepo.On("Create", mock.Anything, mock.MatchedBy(func(c *domain.TaskComment) bool {
+		return *c.ParentID == 11 && *c.RootID == 10 && c.Depth == 2
+	})).Return(int64(12), nil)
+	notifSvc.On("NotifyCommentAdded", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	commentRepo.On("ListThreadParticipants", mock.Anything, int64(10)).Return([]int64{1, 2, 3}, nil)
+	userRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return([]domain.User{{ID: 1}, {ID: 2}}, nil)
+	notifSvc.On("NotifyThreadReply", mock.Anything, mock.Anything, mock.Anything,
+		[]domain.User{{ID: 1}, {ID: 2}}).Return(nil)
+
+	parentID := int64(11)
+	result, err := svc.Create(context.Background(), 3, 1, domain.CreateCommentRequest{
+		Content: "reply", ParentID: &parentID,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), result.ID)
+	notifSvc.AssertExpectations(t)
+}
+
+func TestCommentService_Create_ReplyTooDeep(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleMember, 1, &domain.TaskComment{
+		ID: 5, TaskID: 1, UserID: 2, Depth: domain.MaxCommentDepth,
+	})
+
+	parentID := int64(5)
+	result, err := svc.Create(context.Background(), 1, 1, domain.CreateCommentRequest{
+		Content: "too deep", ParentID: &parentID,
+	})
+
+	assert.Nil(t, result)
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	commentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCommentService_ListThreads_BuildsTree(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleMember, 1, &domain.TaskComment{ID: 1, TaskID: 1})
+	one, two := int64(1), int64(2)
+	commentRepo.On("ListByTaskID", mock.Anything, int64(1)).Return([]domain.TaskComment{
+		{ID: 1, TaskID: 1},
+		{ID: 2, TaskID: 1, ParentID: &one, RootID: &one, Depth: 1},
+		{ID: 3, TaskID: 1},
+		{ID: 4, TaskID: 1, ParentID: &two, RootID: &one, Depth: 2},
+		{ID: 5, TaskID: 1, ParentID: &one, RootID: &one, Depth: 1},
+	}, nil)
+
+	threads, err := svc.ListThreads(context.Background(), 1, 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, threads, 2)
+	assert.Equal(t, 3, threads[0].ReplyCount)
+	assert.Len(t, threads[0].Replies, 2)
+	assert.Equal(t, 1, threads[0].Replies[0].ReplyCount)
+	assert.Equal(t, int64(4), threads[0].Replies[0].Replies[0].ID)
+	assert.Equal(t, 0, threads[1].ReplyCount)
+	assert.NotNil(t, threads[1].Replies)
+}
+
+func TestCommentService_SetThreadResolved(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleMember, 1, &domain.TaskComment{
+		ID: 5, TaskID: 1, UserID: 2,
+	})
+	commentRepo.On("SetResolved", mock.Anything, int64(5), mock.MatchedBy(func(by *int64) bool {
+		return by != nil && *by == 1
+	})).Return(nil)
+
+	_, err := svc.SetThreadResolved(context.Background(), 1, 1, 5, true)
+
+	assert.NoError(t, err)
+	commentRepo.AssertExpectations(t)
+}
+
+func TestCommentService_SetThreadResolved_NoCommentPermission(t *testing.T) {
+	perms := string(domain.PermTaskCreate)
+	svc, commentRepo, _ := newCommentServiceForMember(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember, CustomPermissions: &perms,
+	}, &domain.TaskComment{ID: 5, TaskID: 1, UserID: 2})
+
+	result, err := svc.SetThreadResolved(context.Background(), 1, 1, 5, true)
+
+	assert.Nil(t, result)
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	commentRepo.AssertNotCalled(t, "SetResolved", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommentService_SetThreadResolved_ByThreadAuthor(t *testing.T) {
+	perms := string(domain.PermTaskCreate)
+	svc, commentRepo, _ := newCommentServiceForMember(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember, CustomPermissions: &perms,
+	}, &domain.TaskComment{ID: 5, TaskID: 1, UserID: 1})
+	commentRepo.On("SetResolved", mock.Anything, int64(5), mock.Anything).Return(nil)
+
+	_, err := svc.SetThreadResolved(context.Background(), 1, 1, 5, true)
+
+	assert.NoError(t, err)
+	commentRepo.AssertExpectations(t)
+}
+
+func TestCommentService_SetThreadResolved_ReplyRejected(t *testing.T) {
+	parentID := int64(4)
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleMember, 1, &domain.TaskComment{
+		ID: 5, TaskID: 1, UserID: 2, ParentID: &parentID, RootID: &parentID, Depth: 1,
+	})
+
+	_, err := svc.SetThreadResolved(context.Background(), 1, 1, 5, true)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	commentRepo.AssertNotCalled(t, "SetResolved", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommentService_Create_RecordsMentions(t *testing.T) {
+	commentRepo := new(mocks.CommentRepositoryMock)
+	taskRepo := new(mocks.TaskRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	userRepo := new(mocks.UserRepositoryMock)
+	reactionRepo := new(mocks.ReactionRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
+	notifSvc := new(mocks.NotificationServiceMock)
+	mentionSvc := new(mocks.MentionServiceMock)
+	attachSvc := new(mocks.AttachmentServiceMock)
+	svc := NewCommentService(commentRepo, taskRepo, NewAuthorizer(teamRepo), userRepo, reactionRepo, txManager, notifSvc, mentionSvc, attachSvc)
+
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{ID: 1, TeamID: 1}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
+	}, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	commentRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.TaskComment")).Return(int64(7), nil)
+	mentioned := []domain.User{{ID: 2}}
+	mentionSvc.On("Record", mock.Anything, mock.MatchedBy(func(src domain.MentionSource) bool {
+		return src.AuthorID == 1 && *src.CommentID == 7
+	}), []string{"alice", "stranger"}).Return(&domain.MentionResult{
+		Mentioned: mentioned, Unresolved: []string{"stranger"},
+	}, nil)
+	notifSvc.On("NotifyCommentAdded", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mentionSvc.On("Notify", mock.Anything, mock.Anything, mentioned).Return()
+
+	result, err := svc.Create(context.Background(), 1, 1, domain.CreateCommentRequest{
+		Content: "@alice @stranger have a look",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"stranger"}, result.UnresolvedMentions)
+	mentionSvc.AssertExpectations(t)
+}
+
+func pageComments(ids ...int64) []domain.TaskComment {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	comments := make([]domain.TaskComment, len(ids))
+	for i, id := range ids {
+		comments[i] = domain.TaskComment{ID: id, TaskID: 1, Content: "c", CreatedAt: base.Add(time.Duration(id) * time.Minute)}
+	}
+	return comments
+}
+
+func TestCommentService_ListPage_FirstPage(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleMember, 1, &domain.TaskComment{ID: 1, TaskID: 1})
+	commentRepo.On("ListPage", mock.Anything, mock.MatchedBy(func(f domain.CommentFilter) bool {
+		return f.TaskID == 1 && f.Limit == 3 && f.Cursor == nil
+	})).Return(pageComments(1, 2, 3), 5, nil)
+
+	page, err := svc.ListPage(context.Background(), 1, 1, domain.CommentQuery{Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, page.Total)
+	assert.Len(t, page.Comments, 2)
+	assert.Equal(t, int64(2), page.Comments[1].ID)
+	assert.NotEmpty(t, page.NextCursor)
+	assert.Empty(t, page.PrevCursor)
+
+	cursor, err := decodeCommentCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), cursor.ID)
+	assert.False(t, cursor.Before)
+}
+
+func TestCommentService_ListPage_Backward(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleMember, 1, &domain.TaskComment{ID: 1, TaskID: 1})
+	before := encodeCommentCursor(domain.CommentCursor{CreatedAt: time.Now(), ID: 5, Before: true})
+	commentRepo.On("ListPage", mock.Anything, mock.MatchedBy(func(f domain.CommentFilter) bool {
+		return f.Cursor != nil && f.Cursor.Before && f.Cursor.ID == 5
+	})).Return(pageComments(2, 3, 4), 5, nil)
+
+	page, err := svc.ListPage(context.Background(), 1, 1, domain.CommentQuery{Limit: 2, Cursor: before})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, []int64{page.Comments[0].ID, page.Comments[1].ID})
+	assert.NotEmpty(t, page.PrevCursor)
+	assert.NotEmpty(t, page.NextCursor)
+}
+
+func TestCommentService_ListPage_Since(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleMember, 1, &domain.TaskComment{ID: 1, TaskID: 1})
+	commentRepo.On("ListPage", mock.Anything, mock.MatchedBy(func(f domain.CommentFilter) bool {
+		return f.Since != nil && f.Since.Equal(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	})).Return([]domain.TaskComment{}, 0, nil)
+
+	page, err := svc.ListPage(context.Background(), 1, 1, domain.CommentQuery{Since: "2024-01-01T12:00:00Z"})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, page.Comments)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestCommentService_ListPage_InvalidParams(t *testing.T) {
+	svc, commentRepo, _ := newCommentServiceWithComment(domain.TeamRoleMember, 1, &domain.TaskComment{ID: 1, TaskID: 1})
+
+	_, err := svc.ListPage(context.Background(), 1, 1, domain.CommentQuery{Since: "yesterday"})
+	assert.Error(t, err)
+
+	_, err = svc.ListPage(context.Background(), 1, 1, domain.CommentQuery{Cursor: "garbage"})
+	assert.Error(t, err)
+
+	commentRepo.AssertNotCalled(t, "ListPage", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+const (
+	emailVerificationPurpose = "email_verification"
+	maxEmailLength           = 254
+)
+
+var (
+	errInvalidEmail             = apperror.BadRequest("email is not a valid address")
+	errInvalidVerificationToken = apperror.BadRequest("invalid or expired email verification token")
+)
+
+type EmailVerificationServiceImpl struct {
+	userRepo   port.UserRepository
+	verifyRepo port.EmailVerificationRepository
+	txManager  port.TransactionManager
+	mailer     port.Mailer
+	signer     *signedtoken.Signer
+	ttl        time.Duration
+	verifyURL  string
+}
+
+func NewEmailVerificationService(
+	userRepo port.UserRepository,
+	verifyRepo port.EmailVerificationRepository,
+	txManager port.TransactionManager,
+	mailer port.Mailer,
+	tokenSecret string,
+	ttl time.Duration,
+	verifyURL string,
+) *EmailVerificationServiceImpl {
+	return &EmailVerificationServiceImpl{
+		userRepo:   userRepo,
+		verifyRepo: verifyRepo,
+		txManager:  txManager,
+		mailer:     mailer,
+		signer:     signedtoken.NewSigner(tokenSecret),
+		ttl:        ttl,
+		verifyURL:  verifyURL,
+	}
+}
+
+// SendVerification mails a new verification link; earlier links stop
+// working.
+func (s *EmailVerificationServiceImpl) SendVerification(ctx context.Context, user *domain.User) error {
+	if user.EmailVerified() {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(s.ttl).Truncate(time.Second)
+	token, err := s.signer.Sign(emailVerificationPurpose, expiresAt)
+	if err != nil {
+		return apperror.Internal("generate token", err)
+	}
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.verifyRepo.InvalidateForUser(ctx, user.ID); err != nil {
+			return err
+		}
+		_, err := s.verifyRepo.Create(ctx, &domain.EmailVerificationToken{
+			UserID:    user.ID,
+			TokenHash: signedtoken.Hash(token),
+			ExpiresAt: expiresAt,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf(s.verifyURL, url.QueryEscape(token))
+	return s.mailer.Send(ctx, domain.MailMessage{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hi %s,\r\n\r\nFollow this link to confirm your email address:\r\n%s\r\n\r\n"+
+			"The link expires in %s. If you did not create an account, ignore this email.\r\n",
+			user.FullName, link, s.ttl),
+	})
+}
+
+func (s *EmailVerificationServiceImpl) Verify(ctx context.Context, req domain.VerifyEmailRequest) error {
+	if req.Token == "" {
+		return apperror.BadRequest("token is required")
+	}
+	if err := s.signer.Verify(emailVerificationPurpose, req.Token, time.Now()); err != nil {
+		return errInvalidVerificationToken
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		token, err := s.verifyRepo.GetByHashForUpdate(ctx, signedtoken.Hash(req.Token))
+		if err != nil {
+			if appErr, ok := apperror.IsAppError(err); ok && appErr.Code == http.StatusNotFound {
+				return errInvalidVerificationToken
+			}
+			return err
+		}
+		if token.UsedAt != nil || !token.ExpiresAt.After(time.Now()) {
+			return errInvalidVerificationToken
+		}
+
+		if err := s.userRepo.MarkEmailVerified(ctx, token.UserID); err != nil {
+			return err
+		}
+		return s.verifyRepo.InvalidateForUser(ctx, token.UserID)
+	})
+}
+
+// Resend mails a fresh link. Like the forgot-password flow it succeeds for
+// unknown and already verified addresses, so it reveals nothing.
+func (s *EmailVerificationServiceImpl) Resend(ctx context.Context, req domain.ResendVerificationRequest) error {
+	email, err := validateEmail(req.Email)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if appErr, ok := apperror.IsAppError(err); ok && appErr.Code == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+	if user.DisabledAt != nil {
+		return nil
+	}
+	return s.SendVerification(ctx, user)
+}
+
+// validateEmail normalizes email and checks that it is a bare address with
+// a dotted domain, e.g. no display name or angle brackets.
+func validateEmail(email string) (string, error) {
+	email = normalizeEmail(email)
+	if email == "" || len(email) > maxEmailLength {
+		return "", errInvalidEmail
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return "", errInvalidEmail
+	}
+	_, host, _ := strings.Cut(email, "@")
+	if !strings.Contains(host, ".") || strings.HasPrefix(host, ".") || strings.HasSuffix(host, ".") {
+		return "", errInvalidEmail
+	}
+	return email, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newEmailVerificationService(userRepo *mocks.UserRepositoryMock, verifyRepo *mocks.EmailVerificationRepositoryMock, mailer *mocks.MailerMock) *EmailVerificationServiceImpl {
+	txManager := new(mocks.TransactionManagerMock)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil).Maybe()
+	return NewEmailVerificationService(userRepo, verifyRepo, txManager, mailer, "test-secret", time.Hour, "https://app.test/verify?token=%s")
+}
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"User@Example.COM", "user@example.com", true},
+		{"  first.last+tag@mail.example.org ", "first.last+tag@mail.example.org", true},
+		{"", "", false},
+		{"not-an-email", "", false},
+		{"user@localhost", "", false},
+		{"user@example.", "", false},
+		{"Someone <user@example.com>", "", false},
+		{"user@@example.com", "", false},
+	}
+	for _, tt := range tests {
+		got, err := validateEmail(tt.in)
+		if tt.ok {
+			assert.NoError(t, err, tt.in)
+			assert.Equal(t, tt.want, got)
+		} else {
+			assert.Equal(t, errInvalidEmail, err, tt.in)
+		}
+	}
+}
+
+func TestEmailVerificationService_SendVerification_SkipsVerified(t *testing.T) {
+	mailer := new(mocks.MailerMock)
+	svc := newEmailVerificationService(new(mocks.UserRepositoryMock), new(mocks.EmailVerificationRepositoryMock), mailer)
+
+	now := time.Now()
+	err := svc.SendVerification(context.Background(), &domain.User{ID: 1, Email: "user@example.com", EmailVerifiedAt: &now})
+
+	assert.NoError(t, err)
+	mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestEmailVerificationService_Verify_Success(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	verifyRepo := new(mocks.EmailVerificationRepositoryMock)
+	svc := newEmailVerificationService(userRepo, verifyRepo, new(mocks.MailerMock))
+
+	token, err := svc.signer.Sign(emailVerificationPurpose, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	verifyRepo.On("GetByHashForUpdate", mock.Anything, signedtoken.Hash(token)).
+		Return(&domain.EmailVerificationToken{ID: 2, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	userRepo.On("MarkEmailVerified", mock.Anything, int64(1)).Return(nil)
+	verifyRepo.On("InvalidateForUser", mock.Anything, int64(1)).Return(nil)
+
+	err = svc.Verify(context.Background(), domain.VerifyEmailRequest{Token: token})
+
+	assert.NoError(t, err)
+	userRepo.AssertExpectations(t)
+	verifyRepo.AssertExpectations(t)
+}
+
+func TestEmailVerificationService_Verify_ExpiredToken(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	verifyRepo := new(mocks.EmailVerificationRepositoryMock)
+	svc := newEmailVerificationService(userRepo, verifyRepo, new(mocks.MailerMock))
+
+	token, err := svc.signer.Sign(emailVerificationPurpose, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	verifyRepo.On("GetByHashForUpdate", mock.Anything, signedtoken.Hash(token)).
+		Return(&domain.EmailVerificationToken{ID: 2, UserID: 1, ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+
+	err = svc.Verify(context.Background(), domain.VerifyEmailRequest{Token: token})
+
+	assert.Equal(t, errInvalidVerificationToken, err)
+	userRepo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Register_InvalidEmail(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
+
+	_, err := svc.Register(context.Background(), domain.RegisterRequest{Email: "nope", Password: "password123", FullName: "Test"})
+
+	assert.Equal(t, errInvalidEmail, err)
+	userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Register_VerificationRequired(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
+	verifier := new(mocks.EmailVerifierMock)
+	svc.verifier = verifier
+	svc.policy = domain.EmailVerificationPolicy{BlockLogin: true}
+
+	userRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return u.Email == "new@example.com"
+	})).Return(int64(1), nil)
+	verifier.On("SendVerification", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return u.ID == 1
+	})).Return(nil)
+
+	result, err := svc.Register(context.Background(), domain.RegisterRequest{Email: " New@Example.com", Password: "password123", FullName: "New"})
+
+	require.NoError(t, err)
+	assert.True(t, result.VerificationRequired)
+	assert.Empty(t, result.Token)
+	assert.Empty(t, result.RefreshToken)
+	verifier.AssertExpectations(t)
+}
+
+func TestAuthService_Login_UnverifiedBlocked(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newAuthService(userRepo, new(mocks.InvitationServiceMock))
+	svc.policy = domain.EmailVerificationPolicy{BlockLogin: true}
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	userRepo.On("GetByEmail", mock.Anything, "user@example.com").Return(&domain.User{
+		ID: 1, Email: "user@example.com", PasswordHash: string(hash),
+	}, nil)
+
+	_, err := svc.Login(context.Background(), domain.LoginRequest{Email: "User@Example.com", Password: "password123"})
+
+	assert.Equal(t, errEmailNotVerified, err)
+}
+
+func TestInvitationService_Invite_UnverifiedInviterBlocked(t *testing.T) {
+	svc, teamRepo, userRepo, invitationRepo, _, _ := newInvitationService()
+	svc.policy = domain.EmailVerificationPolicy{BlockInvitations: true}
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1, Email: "owner@example.com"}, nil)
+
+	_, err := svc.Invite(context.Background(), 1, 1, domain.InviteRequest{Email: "new@example.com"})
+
+	assert.Error(t, err)
+	invitationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+const invitationTokenPurpose = "team-invitation"
+
+var errInvalidInvitation = apperror.BadRequest("invalid or expired invitation")
+
+type InvitationServiceImpl struct {
+	teamRepo       port.TeamRepository
+	authz          port.Authorizer
+	userRepo       port.UserRepository
+	invitationRepo port.InvitationRepository
+	activityRepo   port.ActivityRepository
+	txManager      port.TransactionManager
+	mailer         port.Mailer
+	signer         *signedtoken.Signer
+	ttl            time.Duration
+	acceptURL      string
+	policy         domain.EmailVerificationPolicy
+}
+
+func NewInvitationService(
+	teamRepo port.TeamRepository,
+	authz port.Authorizer,
+	userRepo port.UserRepository,
+	invitationRepo port.InvitationRepository,
+	activityRepo port.ActivityRepository,
+	txManager port.TransactionManager,
+	mailer port.Mailer,
+	secret string,
+	ttl time.Duration,
+	acceptURL string,
+	policy domain.EmailVerificationPolicy,
+) *InvitationServiceImpl {
+	return &InvitationServiceImpl{
+		teamRepo:       teamRepo,
+		authz:          authz,
+		userRepo:       userRepo,
+		invitationRepo: invitationRepo,
+		activityRepo:   activityRepo,
+		txManager:      txManager,
+		mailer:         mailer,
+		signer:         signedtoken.NewSigner(secret),
+		ttl:            ttl,
+		acceptURL:      acceptURL,
+		policy:         policy,
+	}
+}
+
+// Invite creates a pending invitation for an email address, whether or not
+// it belongs to a registered user. The invitee joins only after accepting.
+func (s *InvitationServiceImpl) Invite(ctx context.Context, inviterID, teamID int64, req domain.InviteRequest) (*domain.Invitation, error) {
+	email := normalizeEmail(req.Email)
+	if email == "" {
+		return nil, apperror.BadRequest("email is required")
+	}
+
+	inviter, err := s.checkManager(ctx, inviterID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkVerified(ctx, inviterID); err != nil {
+		return nil, err
+	}
+
+	role := domain.TeamRoleMember
+	switch req.Role {
+	case "", string(domain.TeamRoleMember):
+	case string(domain.TeamRoleAdmin):
+		role = domain.TeamRoleAdmin
+	case string(domain.TeamRoleGuest):
+		role = domain.TeamRoleGuest
+	default:
+		return nil, apperror.BadRequest("role must be admin, member or guest")
+	}
+	if role == domain.TeamRoleAdmin && !s.authz.Can(inviter, domain.PermMemberManage) {
+		return nil, apperror.ErrInsufficientRole
+	}
+
+	if user, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		member, err := s.teamRepo.GetMember(ctx, teamID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if member != nil && member.IsDirect() {
+			return nil, apperror.New(http.StatusConflict, "user is already a member of this team")
+		}
+	}
+
+	existing, err := s.invitationRepo.GetPendingByEmail(ctx, teamID, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, apperror.New(http.StatusConflict, "an invitation is already pending for this email")
+	}
+
+	token, expiresAt, err := s.issueToken()
+	if err != nil {
+		return nil, err
+	}
+	var invitation *domain.Invitation
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		id, err := s.invitationRepo.Create(ctx, &domain.Invitation{
+			TeamID:    teamID,
+			Email:     email,
+			Role:      role,
+			InviterID: inviterID,
+			TokenHash: signedtoken.Hash(token),
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:  teamID,
+			ActorID: inviterID,
+			Type:    domain.ActivityInvitationSent,
+			Details: email,
+		}); err != nil {
+			return err
+		}
+
+		invitation, err = s.invitationRepo.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notify(ctx, invitation, token)
+	return invitation, nil
+}
+
+func (s *InvitationServiceImpl) ListPending(ctx context.Context, userID, teamID int64) ([]domain.Invitation, error) {
+	if _, err := s.checkManager(ctx, userID, teamID); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.ListPending(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if invitations == nil {
+		invitations = []domain.Invitation{}
+	}
+	return invitations, nil
+}
+
+func (s *InvitationServiceImpl) Revoke(ctx context.Context, userID, teamID, invitationID int64) error {
+	invitation, err := s.loadTeamInvitation(ctx, userID, teamID, invitationID)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.invitationRepo.Resolve(ctx, invitation.ID, domain.InvitationRevoked, nil)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return apperror.New(http.StatusConflict, "invitation is no longer pending")
+		}
+		return s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:  teamID,
+			ActorID: userID,
+			Type:    domain.ActivityInvitationRevoked,
+			Details: invitation.Email,
+		})
+	})
+}
+
+// Resend issues a fresh token with a new expiry; the previous token stops
+// working because only the latest hash is stored.
+func (s *InvitationServiceImpl) Resend(ctx context.Context, userID, teamID, invitationID int64) (*domain.Invitation, error) {
+	invitation, err := s.loadTeamInvitation(ctx, userID, teamID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.Status != domain.InvitationPending {
+		return nil, apperror.New(http.StatusConflict, "invitation is no longer pending")
+	}
+	if err := s.checkVerified(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := s.issueToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.invitationRepo.Rotate(ctx, invitation.ID, signedtoken.Hash(token), expiresAt); err != nil {
+		return nil, err
+	}
+
+	invitation.ExpiresAt = expiresAt
+	s.notify(ctx, invitation, token)
+	return invitation, nil
+}
+
+// Accept adds the caller to the team. The caller's email must be verified
+// and match the invited address. It joins an already open transaction, which lets
+// registration create the user and accept the invitation atomically.
+func (s *InvitationServiceImpl) Accept(ctx context.Context, userID int64, token string) (*domain.Team, error) {
+	invitation, err := s.lookup(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var team *domain.Team
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if normalizeEmail(user.Email) != invitation.Email {
+			return apperror.Forbidden("this invitation was sent to a different email address")
+		}
+		if !user.EmailVerified() {
+			return apperror.Forbidden("verify your email address before accepting the invitation")
+		}
+
+		team, err = s.teamRepo.GetByID(ctx, invitation.TeamID)
+		if err != nil {
+			return err
+		}
+		if team.ArchivedAt != nil {
+			return errTeamArchived
+		}
+
+		ok, err := s.invitationRepo.Resolve(ctx, invitation.ID, domain.InvitationAccepted, &userID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errInvalidInvitation
+		}
+
+		if err := s.teamRepo.AddMember(ctx, &domain.TeamMember{
+			TeamID: invitation.TeamID,
+			UserID: userID,
+			Role:   invitation.Role,
+		}); err != nil {
+			return err
+		}
+		if err := s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:  invitation.TeamID,
+			ActorID: userID,
+			Type:    domain.ActivityInvitationAccepted,
+			Details: invitation.Email,
+		}); err != nil {
+			return err
+		}
+		if err := s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:       invitation.TeamID,
+			ActorID:      invitation.InviterID,
+			TargetUserID: &userID,
+			Type:         domain.ActivityMemberAdded,
+			Details:      string(invitation.Role),
+		}); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// Decline needs only the token, so the invitee may have no account; the
+// event is attributed to the inviter.
+func (s *InvitationServiceImpl) Decline(ctx context.Context, token string) error {
+	invitation, err := s.lookup(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.invitationRepo.Resolve(ctx, invitation.ID, domain.InvitationDeclined, nil)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errInvalidInvitation
+		}
+		return s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:  invitation.TeamID,
+			ActorID: invitation.InviterID,
+			Type:    domain.ActivityInvitationDeclined,
+			Details: invitation.Email,
+		})
+	})
+}
+
+// lookup verifies the token signature before touching the database and
+// then finds the pending invitation it was issued for.
+func (s *InvitationServiceImpl) lookup(ctx context.Context, token string) (*domain.Invitation, error) {
+	if err := s.signer.Verify(invitationTokenPurpose, token, time.Now()); err != nil {
+		return nil, errInvalidInvitation
+	}
+
+	invitation, err := s.invitationRepo.GetByTokenHash(ctx, signedtoken.Hash(token))
+	if err != nil {
+		var appErr *apperror.AppError
+		if errors.As(err, &appErr) && appErr.Code == http.StatusNotFound {
+			return nil, errInvalidInvitation
+		}
+		return nil, err
+	}
+	if invitation.Status != domain.InvitationPending || !time.Now().Before(invitation.ExpiresAt) {
+		return nil, errInvalidInvitation
+	}
+	return invitation, nil
+}
+
+func (s *InvitationServiceImpl) loadTeamInvitation(ctx context.Context, userID, teamID, invitationID int64) (*domain.Invitation, error) {
+	if _, err := s.checkManager(ctx, userID, teamID); err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.invitationRepo.GetByID(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.TeamID != teamID {
+		return nil, apperror.NotFound("invitation not found")
+	}
+	return invitation, nil
+}
+
+func (s *InvitationServiceImpl) checkManager(ctx context.Context, userID, teamID int64) (*domain.TeamMember, error) {
+	return s.authz.Authorize(ctx, userID, teamID, domain.PermTeamInvite)
+}
+
+// checkVerified keeps accounts with an unconfirmed email from sending
+// invitations when the policy asks for it.
+func (s *InvitationServiceImpl) checkVerified(ctx context.Context, userID int64) error {
+	if !s.policy.BlockInvitations {
+		return nil
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.EmailVerified() {
+		return apperror.Forbidden("verify your email address before inviting others")
+	}
+	return nil
+}
+
+func (s *InvitationServiceImpl) issueToken() (string, time.Time, error) {
+	expiresAt := time.Now().Add(s.ttl).Truncate(time.Second)
+	token, err := s.signer.Sign(invitationTokenPurpose, expiresAt)
+	if err != nil {
+		return "", time.Time{}, apperror.Internal("generate invitation token", err)
+	}
+	return token, expiresAt, nil
+}
+
+// notify mails the invitation link, the only place the token is sent. A
+// failed delivery is not an error: the inviter can resend the invitation.
+func (s *InvitationServiceImpl) notify(ctx context.Context, invitation *domain.Invitation, token string) {
+	team, err := s.teamRepo.GetByID(ctx, invitation.TeamID)
+	if err != nil {
+		return
+	}
+	link := fmt.Sprintf(s.acceptURL, url.QueryEscape(token))
+	_ = s.mailer.Send(ctx, domain.MailMessage{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("You are invited to join %s", team.Name),
+		Body: fmt.Sprintf("Hi,\r\n\r\nYou have been invited to join the team %s as %s. Follow this link to accept:\r\n%s\r\n\r\n"+
+			"The link expires on %s. If you do not want to join, ignore this email.\r\n",
+			team.Name, invitation.Role, link, invitation.ExpiresAt.UTC().Format("2006-01-02 15:04 MST")),
+	})
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newInvitationService() (
+	*InvitationServiceImpl, *mocks.TeamRepositoryMock, *mocks.UserRepositoryMock,
+	*mocks.InvitationRepositoryMock, *mocks.ActivityRepositoryMock, *mocks.MailerMock,
+) {
+	teamRepo := new(mocks.TeamRepositoryMock)
+	userRepo := new(mocks.UserRepositoryMock)
+	invitationRepo := new(mocks.InvitationRepositoryMock)
+	activityRepo := new(mocks.ActivityRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
+	mailer := new(mocks.MailerMock)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil).Maybe()
+	svc := NewInvitationService(teamRepo, NewAuthorizer(teamRepo), userRepo, invitationRepo, activityRepo, txManager, mailer, "test-secret", 24*time.Hour, "https://app.test/accept?token=%s", domain.EmailVerificationPolicy{})
+	return svc, teamRepo, userRepo, invitationRepo, activityRepo, mailer
+}
+
+func signInvitation(t *testing.T, svc *InvitationServiceImpl, expiresAt time.Time) string {
+	t.Helper()
+	token, err := svc.signer.Sign(invitationTokenPurpose, expiresAt)
+	assert.NoError(t, err)
+	return token
+}
+
+// mailedToken extracts the invitation token from the accept link in a mail.
+func mailedToken(t *testing.T, msg domain.MailMessage) string {
+	t.Helper()
+	_, after, ok := strings.Cut(msg.Body, "https://app.test/accept?token=")
+	assert.True(t, ok)
+	token, err := url.QueryUnescape(strings.Fields(after)[0])
+	assert.NoError(t, err)
+	return token
+}
+
+func TestInvitationService_Invite_Success(t *testing.T) {
+	svc, teamRepo, userRepo, invitationRepo, activityRepo, mailer := newInvitationService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	userRepo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, apperror.NotFound("user not found"))
+	invitationRepo.On("GetPendingByEmail", mock.Anything, int64(1), "new@example.com").Return(nil, nil)
+	invitationRepo.On("Create", mock.Anything, mock.MatchedBy(func(inv *domain.Invitation) bool {
+		return inv.Email == "new@example.com" && inv.Role == domain.TeamRoleAdmin && inv.InviterID == 1 &&
+			len(inv.TokenHash) == 64 && inv.ExpiresAt.After(time.Now())
+	})).Return(int64(5), nil)
+	invitationRepo.On("GetByID", mock.Anything, int64(5)).Return(&domain.Invitation{
+		ID: 5, TeamID: 1, Email: "new@example.com", Status: domain.InvitationPending,
+	}, nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.TeamID == 1 && e.ActorID == 1 && e.Type == domain.ActivityInvitationSent && e.Details == "new@example.com"
+	})).Return(nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, Name: "Core"}, nil)
+	var sent domain.MailMessage
+	mailer.On("Send", mock.Anything, mock.AnythingOfType("domain.MailMessage")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(domain.MailMessage) }).
+		Return(nil)
+
+	invitation, err := svc.Invite(context.Background(), 1, 1, domain.InviteRequest{
+		Email: "  New@Example.com ",
+		Role:  "admin",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), invitation.ID)
+	invitationRepo.AssertExpectations(t)
+	activityRepo.AssertExpectations(t)
+	mailer.AssertExpectations(t)
+	assert.Equal(t, "new@example.com", sent.To)
+	assert.NoError(t, svc.signer.Verify(invitationTokenPurpose, mailedToken(t, sent), time.Now()))
+
+	// The token only travels by mail; the inviter's response must not carry it.
+	body, err := json.Marshal(invitation)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(body), "token")
+}
+
+func TestInvitationService_Invite_EmptyEmail(t *testing.T) {
+	svc, _, _, _, _, _ := newInvitationService()
+
+	_, err := svc.Invite(context.Background(), 1, 1, domain.InviteRequest{Email: " "})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+}
+
+func TestInvitationService_Invite_NotMember(t *testing.T) {
+	svc, teamRepo, _, _, _, _ := newInvitationService()
+
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(99)).Return(nil, nil)
+
+	_, err := svc.Invite(context.Background(), 99, 1, domain.InviteRequest{Email: "user@example.com"})
+
+	assert.Equal(t, apperror.ErrNotTeamMember, err)
+}
+
+func TestInvitationService_Invite_InsufficientRole(t *testing.T) {
+	svc, teamRepo, _, _, _, _ := newInvitationService()
+
+	stubTeamMember(teamRepo, 2, domain.TeamRoleMember)
+
+	_, err := svc.Invite(context.Background(), 2, 1, domain.InviteRequest{Email: "user@example.com"})
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+}
+
+func TestInvitationService_Invite_AlreadyMember(t *testing.T) {
+	svc, teamRepo, userRepo, _, _, _ := newInvitationService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	stubTeamMember(teamRepo, 3, domain.TeamRoleMember)
+	userRepo.On("GetByEmail", mock.Anything, "member@example.com").Return(&domain.User{ID: 3}, nil)
+
+	_, err := svc.Invite(context.Background(), 1, 1, domain.InviteRequest{Email: "member@example.com"})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 409, appErr.Code)
+}
+
+func TestInvitationService_Invite_AlreadyPending(t *testing.T) {
+	svc, teamRepo, userRepo, invitationRepo, _, _ := newInvitationService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	userRepo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, apperror.NotFound("user not found"))
+	invitationRepo.On("GetPendingByEmail", mock.Anything, int64(1), "new@example.com").Return(&domain.Invitation{ID: 4}, nil)
+
+	_, err := svc.Invite(context.Background(), 1, 1, domain.InviteRequest{Email: "new@example.com"})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 409, appErr.Code)
+	invitationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestInvitationService_Accept_Success(t *testing.T) {
+	svc, teamRepo, userRepo, invitationRepo, activityRepo, _ := newInvitationService()
+
+	token := signInvitation(t, svc, time.Now().Add(time.Hour))
+	invitationRepo.On("GetByTokenHash", mock.Anything, signedtoken.Hash(token)).Return(&domain.Invitation{
+		ID: 5, TeamID: 1, Email: "new@example.com", Role: domain.TeamRoleMember, InviterID: 1,
+		Status: domain.InvitationPending, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	verifiedAt := time.Now()
+	userRepo.On("GetByID", mock.Anything, int64(7)).Return(&domain.User{ID: 7, Email: "New@example.com", EmailVerifiedAt: &verifiedAt}, nil)
+	invitationRepo.On("Resolve", mock.Anything, int64(5), domain.InvitationAccepted, mock.Anything).Return(true, nil)
+	teamRepo.On("AddMember", mock.Anything, mock.MatchedBy(func(m *domain.TeamMember) bool {
+		return m.TeamID == 1 && m.UserID == 7 && m.Role == domain.TeamRoleMember
+	})).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.ActorID == 7 && e.Type == domain.ActivityInvitationAccepted
+	})).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.ActorID == 1 && e.TargetUserID != nil && *e.TargetUserID == 7 && e.Type == domain.ActivityMemberAdded
+	})).Return(nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1}, nil)
+
+	team, err := svc.Accept(context.Background(), 7, token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), team.ID)
+	teamRepo.AssertExpectations(t)
+	activityRepo.AssertExpectations(t)
+}
+
+func TestInvitationService_Accept_DifferentEmail(t *testing.T) {
+	svc, teamRepo, userRepo, invitationRepo, _, _ := newInvitationService()
+
+	token := signInvitation(t, svc, time.Now().Add(time.Hour))
+	invitationRepo.On("GetByTokenHash", mock.Anything, signedtoken.Hash(token)).Return(&domain.Invitation{
+		ID: 5, TeamID: 1, Email: "new@example.com", Status: domain.InvitationPending, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	userRepo.On("GetByID", mock.Anything, int64(8)).Return(&domain.User{ID: 8, Email: "other@example.com"}, nil)
+
+	_, err := svc.Accept(context.Background(), 8, token)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.Code)
+	teamRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestInvitationService_Accept_UnverifiedEmail(t *testing.T) {
+	svc, teamRepo, userRepo, invitationRepo, _, _ := newInvitationService()
+
+	token := signInvitation(t, svc, time.Now().Add(time.Hour))
+	invitationRepo.On("GetByTokenHash", mock.Anything, signedtoken.Hash(token)).Return(&domain.Invitation{
+		ID: 5, TeamID: 1, Email: "new@example.com", Status: domain.InvitationPending, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	userRepo.On("GetByID", mock.Anything, int64(7)).Return(&domain.User{ID: 7, Email: "new@example.com"}, nil)
+
+	_, err := svc.Accept(context.Background(), 7, token)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.Code)
+	invitationRepo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	teamRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestInvitationService_Accept_ArchivedTeam(t *testing.T) {
+	svc, teamRepo, userRepo, invitationRepo, _, _ := newInvitationService()
+
+	token := signInvitation(t, svc, time.Now().Add(time.Hour))
+	invitationRepo.On("GetByTokenHash", mock.Anything, signedtoken.Hash(token)).Return(&domain.Invitation{
+		ID: 5, TeamID: 1, Email: "new@example.com", Status: domain.InvitationPending, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	verifiedAt := time.Now()
+	userRepo.On("GetByID", mock.Anything, int64(7)).Return(&domain.User{ID: 7, Email: "new@example.com", EmailVerifiedAt: &verifiedAt}, nil)
+	archivedAt := time.Now()
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, ArchivedAt: &archivedAt}, nil)
+
+	_, err := svc.Accept(context.Background(), 7, token)
+
+	assert.Equal(t, errTeamArchived, err)
+	invitationRepo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	teamRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestInvitationService_Accept_ExpiredToken(t *testing.T) {
+	svc, _, _, invitationRepo, _, _ := newInvitationService()
+
+	token := signInvitation(t, svc, time.Now().Add(-time.Minute))
+
+	_, err := svc.Accept(context.Background(), 7, token)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	invitationRepo.AssertNotCalled(t, "GetByTokenHash", mock.Anything, mock.Anything)
+}
+
+func TestInvitationService_Accept_TamperedToken(t *testing.T) {
+	svc, _, _, _, _, _ := newInvitationService()
+
+	token := signInvitation(t, svc, time.Now().Add(time.Hour))
+
+	_, err := svc.Accept(context.Background(), 7, token+"x")
+
+	assert.Equal(t, errInvalidInvitation, err)
+}
+
+func TestInvitationService_Decline_AlreadyResolved(t *testing.T) {
+	svc, _, _, invitationRepo, _, _ := newInvitationService()
+
+	token := signInvitation(t, svc, time.Now().Add(time.Hour))
+	invitationRepo.On("GetByTokenHash", mock.Anything, signedtoken.Hash(token)).Return(&domain.Invitation{
+		ID: 5, Status: domain.InvitationRevoked, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+
+	err := svc.Decline(context.Background(), token)
+
+	assert.Equal(t, errInvalidInvitation, err)
+}
+
+func TestInvitationService_Decline_RecordsEvent(t *testing.T) {
+	svc, _, _, invitationRepo, activityRepo, _ := newInvitationService()
+
+	token := signInvitation(t, svc, time.Now().Add(time.Hour))
+	invitationRepo.On("GetByTokenHash", mock.Anything, signedtoken.Hash(token)).Return(&domain.Invitation{
+		ID: 5, TeamID: 1, Email: "new@example.com", InviterID: 1,
+		Status: domain.InvitationPending, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	invitationRepo.On("Resolve", mock.Anything, int64(5), domain.InvitationDeclined, (*int64)(nil)).Return(true, nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.TeamID == 1 && e.ActorID == 1 && e.Type == domain.ActivityInvitationDeclined && e.Details == "new@example.com"
+	})).Return(nil)
+
+	err := svc.Decline(context.Background(), token)
+
+	assert.NoError(t, err)
+	activityRepo.AssertExpectations(t)
+}
+
+func TestInvitationService_Revoke_RecordsEvent(t *testing.T) {
+	svc, teamRepo, _, invitationRepo, activityRepo, _ := newInvitationService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	invitationRepo.On("GetByID", mock.Anything, int64(5)).Return(&domain.Invitation{
+		ID: 5, TeamID: 1, Email: "new@example.com", Status: domain.InvitationPending,
+	}, nil)
+	invitationRepo.On("Resolve", mock.Anything, int64(5), domain.InvitationRevoked, (*int64)(nil)).Return(true, nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.TeamID == 1 && e.ActorID == 1 && e.Type == domain.ActivityInvitationRevoked
+	})).Return(nil)
+
+	err := svc.Revoke(context.Background(), 1, 1, 5)
+
+	assert.NoError(t, err)
+	activityRepo.AssertExpectations(t)
+}
+
+func TestInvitationService_Revoke_OtherTeam(t *testing.T) {
+	svc, teamRepo, _, invitationRepo, _, _ := newInvitationService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	invitationRepo.On("GetByID", mock.Anything, int64(5)).Return(&domain.Invitation{ID: 5, TeamID: 2}, nil)
+
+	err := svc.Revoke(context.Background(), 1, 1, 5)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 404, appErr.Code)
+}
+
+func TestInvitationService_Invite_UnknownRole(t *testing.T) {
+	svc, teamRepo, _, invitationRepo, _, mailer := newInvitationService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+
+	_, err := svc.Invite(context.Background(), 1, 1, domain.InviteRequest{Email: "new@example.com", Role: "admni"})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	invitationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestInvitationService_Resend_RotatesToken(t *testing.T) {
+	svc, teamRepo, _, invitationRepo, _, mailer := newInvitationService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	invitationRepo.On("GetByID", mock.Anything, int64(5)).Return(&domain.Invitation{
+		ID: 5, TeamID: 1, Status: domain.InvitationPending, TokenHash: "old",
+	}, nil)
+	var newHash string
+	invitationRepo.On("Rotate", mock.Anything, int64(5), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) { newHash = args.String(2) }).Return(nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1}, nil)
+	var sent domain.MailMessage
+	mailer.On("Send", mock.Anything, mock.AnythingOfType("domain.MailMessage")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(domain.MailMessage) }).
+		Return(nil)
+
+	invitation, err := svc.Resend(context.Background(), 1, 1, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, signedtoken.Hash(mailedToken(t, sent)), newHash)
+	assert.True(t, invitation.ExpiresAt.After(time.Now()))
+}
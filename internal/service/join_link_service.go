@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+var errJoinLinkUnavailable = apperror.New(http.StatusGone, "join link is no longer valid")
+
+type JoinLinkServiceImpl struct {
+	teamRepo     port.TeamRepository
+	authz        port.Authorizer
+	userRepo     port.UserRepository
+	linkRepo     port.JoinLinkRepository
+	activityRepo port.ActivityRepository
+	txManager    port.TransactionManager
+}
+
+func NewJoinLinkService(
+	teamRepo port.TeamRepository,
+	authz port.Authorizer,
+	userRepo port.UserRepository,
+	linkRepo port.JoinLinkRepository,
+	activityRepo port.ActivityRepository,
+	txManager port.TransactionManager,
+) *JoinLinkServiceImpl {
+	return &JoinLinkServiceImpl{
+		teamRepo:     teamRepo,
+		authz:        authz,
+		userRepo:     userRepo,
+		linkRepo:     linkRepo,
+		activityRepo: activityRepo,
+		txManager:    txManager,
+	}
+}
+
+func (s *JoinLinkServiceImpl) Create(ctx context.Context, userID, teamID int64, req domain.CreateJoinLinkRequest) (*domain.JoinLink, error) {
+	member, err := s.checkManager(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	role := domain.TeamRoleMember
+	switch req.Role {
+	case "", string(domain.TeamRoleMember):
+	case string(domain.TeamRoleAdmin):
+		role = domain.TeamRoleAdmin
+	case string(domain.TeamRoleGuest):
+		role = domain.TeamRoleGuest
+	default:
+		return nil, apperror.BadRequest("role must be admin, member or guest")
+	}
+	if role == domain.TeamRoleAdmin && !s.authz.Can(member, domain.PermMemberManage) {
+		return nil, apperror.ErrInsufficientRole
+	}
+	if req.MaxUses != nil && *req.MaxUses < 1 {
+		return nil, apperror.BadRequest("max_uses must be at least 1")
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, apperror.BadRequest("expires_at must be in the future")
+	}
+
+	var allowedDomain *string
+	if d := strings.TrimPrefix(normalizeEmail(req.AllowedDomain), "@"); d != "" {
+		if strings.Contains(d, "@") || !strings.Contains(d, ".") {
+			return nil, apperror.BadRequest("allowed_domain must be a domain such as example.com")
+		}
+		allowedDomain = &d
+	}
+
+	code, err := newJoinCode()
+	if err != nil {
+		return nil, apperror.Internal("generate join code", err)
+	}
+	id, err := s.linkRepo.Create(ctx, &domain.JoinLink{
+		TeamID:        teamID,
+		Code:          code,
+		Role:          role,
+		CreatorID:     userID,
+		MaxUses:       req.MaxUses,
+		AllowedDomain: allowedDomain,
+		ExpiresAt:     req.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.linkRepo.GetByID(ctx, id)
+}
+
+func (s *JoinLinkServiceImpl) List(ctx context.Context, userID, teamID int64) ([]domain.JoinLink, error) {
+	if _, err := s.checkManager(ctx, userID, teamID); err != nil {
+		return nil, err
+	}
+
+	links, err := s.linkRepo.ListByTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if links == nil {
+		links = []domain.JoinLink{}
+	}
+	return links, nil
+}
+
+func (s *JoinLinkServiceImpl) Revoke(ctx context.Context, userID, teamID, linkID int64) error {
+	link, err := s.loadTeamLink(ctx, userID, teamID, linkID)
+	if err != nil {
+		return err
+	}
+	return s.linkRepo.Revoke(ctx, link.ID)
+}
+
+func (s *JoinLinkServiceImpl) ListUses(ctx context.Context, userID, teamID, linkID int64) ([]domain.JoinLinkUse, error) {
+	link, err := s.loadTeamLink(ctx, userID, teamID, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	uses, err := s.linkRepo.ListUses(ctx, link.ID)
+	if err != nil {
+		return nil, err
+	}
+	if uses == nil {
+		uses = []domain.JoinLinkUse{}
+	}
+	return uses, nil
+}
+
+// Join adds the caller to the link's team. The use is claimed with a
+// conditional update inside the transaction, so max_uses holds under
+// concurrent joins.
+func (s *JoinLinkServiceImpl) Join(ctx context.Context, userID int64, code string) (*domain.Team, error) {
+	link, err := s.linkRepo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if link.RevokedAt != nil || link.Expired(time.Now()) || link.Exhausted() {
+		return nil, errJoinLinkUnavailable
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, link.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if team.ArchivedAt != nil {
+		return nil, errTeamArchived
+	}
+
+	// Anyone can register an address they do not own, so the domain only
+	// counts once the address is verified.
+	if link.AllowedDomain != nil {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !user.EmailVerified() {
+			return nil, apperror.Forbidden("verify your email address before using this join link")
+		}
+		_, domainPart, _ := strings.Cut(normalizeEmail(user.Email), "@")
+		if domainPart != *link.AllowedDomain {
+			return nil, apperror.Forbidden("this join link is restricted to @" + *link.AllowedDomain + " addresses")
+		}
+	}
+
+	member, err := s.teamRepo.GetMember(ctx, link.TeamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member != nil && member.IsDirect() {
+		return nil, apperror.New(http.StatusConflict, "user is already a member of this team")
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.linkRepo.IncrementUses(ctx, link.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errJoinLinkUnavailable
+		}
+
+		if err := s.teamRepo.AddMember(ctx, &domain.TeamMember{
+			TeamID: link.TeamID,
+			UserID: userID,
+			Role:   link.Role,
+		}); err != nil {
+			return err
+		}
+		if err := s.linkRepo.RecordUse(ctx, link.ID, userID); err != nil {
+			return err
+		}
+		if err := s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:       link.TeamID,
+			ActorID:      link.CreatorID,
+			TargetUserID: &userID,
+			Type:         domain.ActivityMemberAdded,
+			Details:      string(link.Role),
+		}); err != nil {
+			return err
+		}
+
+		team, err = s.teamRepo.GetByID(ctx, link.TeamID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *JoinLinkServiceImpl) loadTeamLink(ctx context.Context, userID, teamID, linkID int64) (*domain.JoinLink, error) {
+	if _, err := s.checkManager(ctx, userID, teamID); err != nil {
+		return nil, err
+	}
+
+	link, err := s.linkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.TeamID != teamID {
+		return nil, apperror.NotFound("join link not found")
+	}
+	return link, nil
+}
+
+func (s *JoinLinkServiceImpl) checkManager(ctx context.Context, userID, teamID int64) (*domain.TeamMember, error) {
+	return s.authz.Authorize(ctx, userID, teamID, domain.PermTeamInvite)
+}
+
+func newJoinCode() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newJoinLinkService() (
+	*JoinLinkServiceImpl, *mocks.TeamRepositoryMock, *mocks.UserRepositoryMock,
+	*mocks.JoinLinkRepositoryMock, *mocks.ActivityRepositoryMock,
+) {
+	teamRepo := new(mocks.TeamRepositoryMock)
+	userRepo := new(mocks.UserRepositoryMock)
+	linkRepo := new(mocks.JoinLinkRepositoryMock)
+	activityRepo := new(mocks.ActivityRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil).Maybe()
+	svc := NewJoinLinkService(teamRepo, NewAuthorizer(teamRepo), userRepo, linkRepo, activityRepo, txManager)
+	return svc, teamRepo, userRepo, linkRepo, activityRepo
+}
+
+func TestJoinLinkService_Create_Success(t *testing.T) {
+	svc, teamRepo, _, linkRepo, _ := newJoinLinkService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	maxUses := 5
+	linkRepo.On("Create", mock.Anything, mock.MatchedBy(func(l *domain.JoinLink) bool {
+		return l.TeamID == 1 && l.CreatorID == 1 && l.Role == domain.TeamRoleMember && len(l.Code) == 22 &&
+			*l.MaxUses == 5 && *l.AllowedDomain == "example.com"
+	})).Return(int64(3), nil)
+	linkRepo.On("GetByID", mock.Anything, int64(3)).Return(&domain.JoinLink{ID: 3, TeamID: 1}, nil)
+
+	link, err := svc.Create(context.Background(), 1, 1, domain.CreateJoinLinkRequest{
+		MaxUses:       &maxUses,
+		AllowedDomain: " @Example.COM",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), link.ID)
+	linkRepo.AssertExpectations(t)
+}
+
+func TestJoinLinkService_Create_InsufficientRole(t *testing.T) {
+	svc, teamRepo, _, _, _ := newJoinLinkService()
+
+	stubTeamMember(teamRepo, 2, domain.TeamRoleMember)
+
+	_, err := svc.Create(context.Background(), 2, 1, domain.CreateJoinLinkRequest{})
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+}
+
+func TestJoinLinkService_Create_InvalidLimits(t *testing.T) {
+	svc, teamRepo, _, _, _ := newJoinLinkService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	zero := 0
+	past := time.Now().Add(-time.Hour)
+
+	for _, req := range []domain.CreateJoinLinkRequest{
+		{Role: "owner"},
+		{MaxUses: &zero},
+		{ExpiresAt: &past},
+		{AllowedDomain: "user@example.com"},
+	} {
+		_, err := svc.Create(context.Background(), 1, 1, req)
+
+		appErr, ok := apperror.IsAppError(err)
+		assert.True(t, ok)
+		assert.Equal(t, 400, appErr.Code)
+	}
+}
+
+func TestJoinLinkService_Join_Success(t *testing.T) {
+	svc, teamRepo, _, linkRepo, activityRepo := newJoinLinkService()
+
+	linkRepo.On("GetByCode", mock.Anything, "code").Return(&domain.JoinLink{
+		ID: 3, TeamID: 1, Role: domain.TeamRoleMember, CreatorID: 1,
+	}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(7)).Return(nil, nil)
+	linkRepo.On("IncrementUses", mock.Anything, int64(3)).Return(true, nil)
+	teamRepo.On("AddMember", mock.Anything, mock.MatchedBy(func(m *domain.TeamMember) bool {
+		return m.TeamID == 1 && m.UserID == 7 && m.Role == domain.TeamRoleMember
+	})).Return(nil)
+	linkRepo.On("RecordUse", mock.Anything, int64(3), int64(7)).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.ActorID == 1 && *e.TargetUserID == 7 && e.Type == domain.ActivityMemberAdded
+	})).Return(nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1}, nil)
+
+	team, err := svc.Join(context.Background(), 7, "code")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), team.ID)
+	linkRepo.AssertExpectations(t)
+	activityRepo.AssertExpectations(t)
+}
+
+func TestJoinLinkService_Join_Unavailable(t *testing.T) {
+	revoked := time.Now()
+	expired := time.Now().Add(-time.Minute)
+	one := 1
+
+	for _, link := range []*domain.JoinLink{
+		{ID: 3, TeamID: 1, RevokedAt: &revoked},
+		{ID: 3, TeamID: 1, ExpiresAt: &expired},
+		{ID: 3, TeamID: 1, MaxUses: &one, UseCount: 1},
+	} {
+		svc, _, _, linkRepo, _ := newJoinLinkService()
+		linkRepo.On("GetByCode", mock.Anything, "code").Return(link, nil)
+
+		_, err := svc.Join(context.Background(), 7, "code")
+
+		assert.Equal(t, errJoinLinkUnavailable, err)
+	}
+}
+
+func TestJoinLinkService_Join_LostRace(t *testing.T) {
+	svc, teamRepo, _, linkRepo, _ := newJoinLinkService()
+
+	linkRepo.On("GetByCode", mock.Anything, "code").Return(&domain.JoinLink{ID: 3, TeamID: 1}, nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(7)).Return(nil, nil)
+	linkRepo.On("IncrementUses", mock.Anything, int64(3)).Return(false, nil)
+
+	_, err := svc.Join(context.Background(), 7, "code")
+
+	assert.Equal(t, errJoinLinkUnavailable, err)
+	teamRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestJoinLinkService_Join_DomainRestricted(t *testing.T) {
+	svc, teamRepo, userRepo, linkRepo, _ := newJoinLinkService()
+
+	domainName := "example.com"
+	verifiedAt := time.Now()
+	linkRepo.On("GetByCode", mock.Anything, "code").Return(&domain.JoinLink{ID: 3, TeamID: 1, AllowedDomain: &domainName}, nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1}, nil)
+	userRepo.On("GetByID", mock.Anything, int64(7)).Return(&domain.User{
+		ID: 7, Email: "user@example.com.evil.io", EmailVerifiedAt: &verifiedAt,
+	}, nil)
+
+	_, err := svc.Join(context.Background(), 7, "code")
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.Code)
+	teamRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestJoinLinkService_Join_DomainRestrictedUnverified(t *testing.T) {
+	svc, teamRepo, userRepo, linkRepo, _ := newJoinLinkService()
+
+	domainName := "example.com"
+	linkRepo.On("GetByCode", mock.Anything, "code").Return(&domain.JoinLink{ID: 3, TeamID: 1, AllowedDomain: &domainName}, nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1}, nil)
+	userRepo.On("GetByID", mock.Anything, int64(7)).Return(&domain.User{ID: 7, Email: "user@example.com"}, nil)
+
+	_, err := svc.Join(context.Background(), 7, "code")
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.Code)
+	assert.Contains(t, appErr.Message, "verify")
+	linkRepo.AssertNotCalled(t, "IncrementUses", mock.Anything, mock.Anything)
+	teamRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestJoinLinkService_Join_ArchivedTeam(t *testing.T) {
+	svc, teamRepo, _, linkRepo, _ := newJoinLinkService()
+
+	archivedAt := time.Now()
+	linkRepo.On("GetByCode", mock.Anything, "code").Return(&domain.JoinLink{ID: 3, TeamID: 1, Role: domain.TeamRoleMember}, nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, ArchivedAt: &archivedAt}, nil)
+
+	_, err := svc.Join(context.Background(), 7, "code")
+
+	assert.Equal(t, errTeamArchived, err)
+	linkRepo.AssertNotCalled(t, "IncrementUses", mock.Anything, mock.Anything)
+}
+
+func TestJoinLinkService_Join_AlreadyMember(t *testing.T) {
+	svc, teamRepo, _, linkRepo, _ := newJoinLinkService()
+
+	linkRepo.On("GetByCode", mock.Anything, "code").Return(&domain.JoinLink{ID: 3, TeamID: 1}, nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1}, nil)
+	stubTeamMember(teamRepo, 7, domain.TeamRoleMember)
+
+	_, err := svc.Join(context.Background(), 7, "code")
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 409, appErr.Code)
+	linkRepo.AssertNotCalled(t, "IncrementUses", mock.Anything, mock.Anything)
+}
+
+func TestJoinLinkService_Revoke_OtherTeam(t *testing.T) {
+	svc, teamRepo, _, linkRepo, _ := newJoinLinkService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	linkRepo.On("GetByID", mock.Anything, int64(3)).Return(&domain.JoinLink{ID: 3, TeamID: 2}, nil)
+
+	err := svc.Revoke(context.Background(), 1, 1, 3)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 404, appErr.Code)
+	linkRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+}
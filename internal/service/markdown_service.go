@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/markdown"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+type MarkdownServiceImpl struct {
+	taskRepo      port.TaskRepository
+	authz         port.Authorizer
+	renderer      *markdown.Renderer
+	excerptLength int
+}
+
+func NewMarkdownService(taskRepo port.TaskRepository, authz port.Authorizer, taskURLFormat string, excerptLength int) *MarkdownServiceImpl {
+	return &MarkdownServiceImpl{
+		taskRepo:      taskRepo,
+		authz:         authz,
+		renderer:      markdown.NewRenderer(taskURLFormat),
+		excerptLength: excerptLength,
+	}
+}
+
+func (s *MarkdownServiceImpl) RenderTasks(ctx context.Context, userID int64, tasks []*domain.Task, withHTML bool) error {
+	sources := make([]string, len(tasks))
+	for i, t := range tasks {
+		sources[i] = t.Description
+	}
+
+	rendered, err := s.render(ctx, userID, sources, withHTML)
+	if err != nil {
+		return err
+	}
+	for i, t := range tasks {
+		t.Excerpt = rendered[i].excerpt
+		t.DescriptionHTML = rendered[i].html
+	}
+	return nil
+}
+
+func (s *MarkdownServiceImpl) RenderComments(ctx context.Context, userID int64, comments []*domain.TaskComment, withHTML bool) error {
+	sources := make([]string, len(comments))
+	for i, c := range comments {
+		sources[i] = c.Content
+	}
+
+	rendered, err := s.render(ctx, userID, sources, withHTML)
+	if err != nil {
+		return err
+	}
+	for i, c := range comments {
+		c.Excerpt = rendered[i].excerpt
+		c.ContentHTML = rendered[i].html
+	}
+	return nil
+}
+
+type renderedText struct {
+	html    string
+	excerpt string
+}
+
+func (s *MarkdownServiceImpl) render(ctx context.Context, userID int64, sources []string, withHTML bool) ([]renderedText, error) {
+	var visible map[int64]bool
+	if withHTML {
+		var err error
+		if visible, err = s.visibleTaskRefs(ctx, userID, sources); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]renderedText, len(sources))
+	for i, src := range sources {
+		if src == "" {
+			continue
+		}
+		result[i].excerpt = s.renderer.Excerpt(src, s.excerptLength)
+		if withHTML {
+			result[i].html = s.renderer.HTML(src, visible)
+		}
+	}
+	return result, nil
+}
+
+// visibleTaskRefs resolves every #123 reference in sources at once, so links
+// never reveal tasks the caller could not open. Access is checked only for
+// the teams the referenced tasks belong to.
+func (s *MarkdownServiceImpl) visibleTaskRefs(ctx context.Context, userID int64, sources []string) (map[int64]bool, error) {
+	seen := make(map[int64]bool)
+	var ids []int64
+	for _, src := range sources {
+		for _, id := range markdown.TaskRefs(src) {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	teamIDs, err := s.taskRepo.ListTeamIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(teamIDs) == 0 {
+		return nil, nil
+	}
+	access, err := s.authz.Access(ctx, userID, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	visibleIDs, err := s.taskRepo.ListVisibleIDs(ctx, *access, ids)
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[int64]bool, len(visibleIDs))
+	for _, id := range visibleIDs {
+		visible[id] = true
+	}
+	return visible, nil
+}
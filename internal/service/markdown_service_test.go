@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMarkdownService_RenderTasks_LinksVisibleRefsOnly(t *testing.T) {
+	taskRepo := new(mocks.TaskRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	svc := NewMarkdownService(taskRepo, NewAuthorizer(teamRepo), "/tasks/%d", 160)
+
+	taskRepo.On("ListTeamIDs", mock.Anything, []int64{2, 3}).Return([]int64{1, 2}, nil)
+	stubTeamMember(teamRepo, 1, domain.TeamRoleMember)
+	teamRepo.On("GetMember", mock.Anything, int64(2), int64(1)).Return(nil, nil)
+	taskRepo.On("ListVisibleIDs", mock.Anything, domain.TaskAccess{UserID: 1, Teams: []int64{1}}, []int64{2, 3}).
+		Return([]int64{2}, nil)
+
+	tasks := []*domain.Task{{Description: "Blocked by #2, see #3"}}
+	err := svc.RenderTasks(context.Background(), 1, tasks, true)
+
+	assert.NoError(t, err)
+	assert.Contains(t, tasks[0].DescriptionHTML, `<a href="/tasks/2" rel="nofollow">#2</a>`)
+	assert.NotContains(t, tasks[0].DescriptionHTML, `/tasks/3`)
+	assert.Equal(t, "Blocked by #2, see #3", tasks[0].Excerpt)
+}
+
+func TestMarkdownService_RenderTasks_RespectsTokenRestriction(t *testing.T) {
+	taskRepo := new(mocks.TaskRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	svc := NewMarkdownService(taskRepo, NewAuthorizer(teamRepo), "/tasks/%d", 160)
+
+	taskRepo.On("ListTeamIDs", mock.Anything, []int64{2}).Return([]int64{1}, nil)
+	taskRepo.On("ListVisibleIDs", mock.Anything, domain.TaskAccess{UserID: 1}, []int64{2}).Return([]int64{}, nil)
+
+	ctx := requestctx.WithTeamRestriction(context.Background(), 9)
+	tasks := []*domain.Task{{Description: "see #2"}}
+	err := svc.RenderTasks(ctx, 1, tasks, true)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, tasks[0].DescriptionHTML, "/tasks/2")
+	teamRepo.AssertNotCalled(t, "GetMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMarkdownService_RenderComments_Sanitizes(t *testing.T) {
+	svc := NewMarkdownService(new(mocks.TaskRepositoryMock), NewAuthorizer(new(mocks.TeamRepositoryMock)), "/tasks/%d", 160)
+
+	comments := []*domain.TaskComment{{
+		Content: "**hi** <script>alert(1)</script> [x](javascript:alert(1)) <img src=x onerror=alert(1)>",
+	}}
+	err := svc.RenderComments(context.Background(), 1, comments, true)
+
+	assert.NoError(t, err)
+	html := comments[0].ContentHTML
+	assert.Contains(t, html, "<strong>hi</strong>")
+	assert.NotContains(t, html, "<script")
+	assert.NotContains(t, html, "javascript:")
+	assert.NotContains(t, html, "onerror")
+}
+
+func TestMarkdownService_RenderTasks_ExcerptOnly(t *testing.T) {
+	taskRepo := new(mocks.TaskRepositoryMock)
+	svc := NewMarkdownService(taskRepo, NewAuthorizer(new(mocks.TeamRepositoryMock)), "/tasks/%d", 10)
+
+	tasks := []*domain.Task{{Description: "# Title\n\nSome *long* description #5"}, {}}
+	err := svc.RenderTasks(context.Background(), 1, tasks, false)
+
+	assert.NoError(t, err)
+	assert.Empty(t, tasks[0].DescriptionHTML)
+	assert.True(t, strings.HasPrefix(tasks[0].Excerpt, "Title Some"))
+	assert.True(t, strings.HasSuffix(tasks[0].Excerpt, "…"))
+	assert.Empty(t, tasks[1].Excerpt)
+	taskRepo.AssertNotCalled(t, "ListVisibleIDs", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+// mentionPattern matches @email and @username, where a username is the local
+// part of a member's email. The leading group keeps plain email addresses in
+// text from being read as mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([\w.+-]+(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+
+type MentionServiceImpl struct {
+	mentionRepo port.MentionRepository
+	teamRepo    port.TeamRepository
+	authz       port.Authorizer
+	notifSvc    port.NotificationService
+}
+
+func NewMentionService(
+	mentionRepo port.MentionRepository,
+	teamRepo port.TeamRepository,
+	authz port.Authorizer,
+	notifSvc port.NotificationService,
+) *MentionServiceImpl {
+	return &MentionServiceImpl{
+		mentionRepo: mentionRepo,
+		teamRepo:    teamRepo,
+		authz:       authz,
+		notifSvc:    notifSvc,
+	}
+}
+
+func (s *MentionServiceImpl) Record(ctx context.Context, src domain.MentionSource, handles []string) (*domain.MentionResult, error) {
+	result := &domain.MentionResult{}
+	if len(handles) == 0 {
+		return result, nil
+	}
+
+	members, err := s.teamRepo.ListMentionCandidates(ctx, src.Task.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceType := domain.MentionSourceTask
+	if src.CommentID != nil {
+		sourceType = domain.MentionSourceComment
+	}
+
+	seen := make(map[int64]bool)
+	var mentions []domain.Mention
+	for _, handle := range handles {
+		member := resolveMention(members, handle)
+		if member != nil {
+			visible, err := s.canSee(ctx, member, src)
+			if err != nil {
+				return nil, err
+			}
+			if !visible {
+				member = nil
+			}
+		}
+		if member == nil {
+			result.Unresolved = append(result.Unresolved, handle)
+			continue
+		}
+		if member.UserID == src.AuthorID || seen[member.UserID] {
+			continue
+		}
+		seen[member.UserID] = true
+
+		mentions = append(mentions, domain.Mention{
+			UserID:    member.UserID,
+			AuthorID:  src.AuthorID,
+			TeamID:    src.Task.TeamID,
+			TaskID:    src.Task.ID,
+			CommentID: src.CommentID,
+			Source:    sourceType,
+		})
+		result.Mentioned = append(result.Mentioned, domain.User{
+			ID:       member.UserID,
+			Email:    member.Email,
+			FullName: member.FullName,
+		})
+	}
+
+	if err := s.mentionRepo.CreateBatch(ctx, mentions); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// canSee reports whether member can read the mentioning text, resolving
+// their access to the task the same way a request of theirs would be. Guests
+// cannot read internal comments.
+func (s *MentionServiceImpl) canSee(ctx context.Context, member *domain.TeamMemberDetails, src domain.MentionSource) (bool, error) {
+	resolved, err := s.authz.TaskMember(ctx, member.UserID, src.Task)
+	if err != nil {
+		var appErr *apperror.AppError
+		if errors.As(err, &appErr) && appErr.Code < http.StatusInternalServerError {
+			return false, nil
+		}
+		return false, err
+	}
+	if src.Internal && resolved.Role == domain.TeamRoleGuest {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (s *MentionServiceImpl) Notify(ctx context.Context, src domain.MentionSource, mentioned []domain.User) {
+	for i := range mentioned {
+		_ = s.notifSvc.NotifyMentioned(ctx, src.Task, src.AuthorID, &mentioned[i])
+	}
+}
+
+func (s *MentionServiceImpl) ListForUser(ctx context.Context, userID int64, filter domain.MentionFilter) (*domain.MentionListResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = 20
+	}
+	if filter.PageSize > 100 {
+		filter.PageSize = 100
+	}
+
+	access, err := s.authz.Access(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	mentions, total, err := s.mentionRepo.ListByUser(ctx, *access, filter)
+	if err != nil {
+		return nil, err
+	}
+	if mentions == nil {
+		mentions = []domain.Mention{}
+	}
+
+	return &domain.MentionListResponse{
+		Mentions:   mentions,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: (total + filter.PageSize - 1) / filter.PageSize,
+	}, nil
+}
+
+// resolveMention matches a full email exactly and a bare username only when
+// it identifies a single member.
+func resolveMention(members []domain.TeamMemberDetails, handle string) *domain.TeamMemberDetails {
+	var match *domain.TeamMemberDetails
+	for i := range members {
+		email := strings.ToLower(members[i].Email)
+		if strings.Contains(handle, "@") {
+			if email == handle {
+				return &members[i]
+			}
+			continue
+		}
+		local, _, _ := strings.Cut(email, "@")
+		if local == handle {
+			if match != nil {
+				return nil
+			}
+			match = &members[i]
+		}
+	}
+	return match
+}
+
+// parseMentions returns the distinct lowercased handles in text, in order of
+// first appearance.
+func parseMentions(text string) []string {
+	seen := make(map[string]bool)
+	var handles []string
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		handle := strings.ToLower(strings.TrimRight(m[1], ".-"))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+	}
+	return handles
+}
+
+// newMentions returns the handles in text that were not already present in
+// previous, so edits only notify people who were newly mentioned.
+func newMentions(text, previous string) []string {
+	old := make(map[string]bool)
+	for _, h := range parseMentions(previous) {
+		old[h] = true
+	}
+	var handles []string
+	for _, h := range parseMentions(text) {
+		if !old[h] {
+			handles = append(handles, h)
+		}
+	}
+	return handles
+}
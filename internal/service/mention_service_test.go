@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseMentions(t *testing.T) {
+	handles := parseMentions("@alice and @Bob@Example.com, ping @alice. mail carol@example.com (@dave)")
+
+	assert.Equal(t, []string{"alice", "bob@example.com", "dave"}, handles)
+}
+
+func TestNewMentions_SkipsExisting(t *testing.T) {
+	handles := newMentions("@alice @bob", "hi @alice")
+
+	assert.Equal(t, []string{"bob"}, handles)
+}
+
+func TestMentionService_Record_ResolvesMembersOnly(t *testing.T) {
+	mentionRepo := new(mocks.MentionRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	notifSvc := new(mocks.NotificationServiceMock)
+	svc := NewMentionService(mentionRepo, teamRepo, NewAuthorizer(teamRepo), notifSvc)
+
+	teamRepo.On("ListMentionCandidates", mock.Anything, int64(1)).Return([]domain.TeamMemberDetails{
+		{TeamID: 1, UserID: 1, Email: "author@a.com"},
+		{TeamID: 1, UserID: 2, Email: "alice@a.com", FullName: "Alice"},
+		{TeamID: 1, UserID: 3, Email: "sam@a.com"},
+		{TeamID: 1, UserID: 4, Email: "sam@b.com"},
+	}, nil)
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	stubTeamMember(teamRepo, 2, domain.TeamRoleMember)
+	commentID := int64(9)
+	mentionRepo.On("CreateBatch", mock.Anything, []domain.Mention{{
+		UserID: 2, AuthorID: 1, TeamID: 1, TaskID: 5, CommentID: &commentID, Source: domain.MentionSourceComment,
+	}}).Return(nil)
+
+	result, err := svc.Record(context.Background(), domain.MentionSource{
+		AuthorID: 1, Task: &domain.Task{ID: 5, TeamID: 1}, CommentID: &commentID,
+	}, []string{"alice", "alice@a.com", "author", "sam", "ghost@x.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.User{{ID: 2, Email: "alice@a.com", FullName: "Alice"}}, result.Mentioned)
+	assert.Equal(t, []string{"sam", "ghost@x.com"}, result.Unresolved)
+	mentionRepo.AssertExpectations(t)
+}
+
+func TestMentionService_Record_InheritedMembers(t *testing.T) {
+	mentionRepo := new(mocks.MentionRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	svc := NewMentionService(mentionRepo, teamRepo, NewAuthorizer(teamRepo), new(mocks.NotificationServiceMock))
+
+	parentID := int64(7)
+	teamRepo.On("ListMentionCandidates", mock.Anything, int64(1)).Return([]domain.TeamMemberDetails{
+		{TeamID: 1, UserID: 2, Email: "lead@a.com"},
+		{TeamID: 1, UserID: 3, Email: "capped@a.com"},
+	}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(2)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 2, Role: domain.TeamRoleAdmin, InheritedFrom: &parentID,
+	}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(3)).Return(nil, nil)
+	mentionRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(m []domain.Mention) bool {
+		return len(m) == 1 && m[0].UserID == 2
+	})).Return(nil)
+
+	result, err := svc.Record(context.Background(), domain.MentionSource{
+		AuthorID: 1, Task: &domain.Task{ID: 5, TeamID: 1},
+	}, []string{"lead", "capped"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"capped"}, result.Unresolved)
+	mentionRepo.AssertExpectations(t)
+}
+
+func TestMentionService_Record_InternalSkipsGuests(t *testing.T) {
+	mentionRepo := new(mocks.MentionRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	svc := NewMentionService(mentionRepo, teamRepo, NewAuthorizer(teamRepo), new(mocks.NotificationServiceMock))
+
+	teamRepo.On("ListMentionCandidates", mock.Anything, int64(1)).Return([]domain.TeamMemberDetails{
+		{TeamID: 1, UserID: 2, Email: "alice@a.com"},
+		{TeamID: 1, UserID: 3, Email: "client@b.com"},
+	}, nil)
+	stubTeamMember(teamRepo, 2, domain.TeamRoleMember)
+	stubTeamMember(teamRepo, 3, domain.TeamRoleGuest)
+	commentID := int64(9)
+	mentionRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(m []domain.Mention) bool {
+		return len(m) == 1 && m[0].UserID == 2
+	})).Return(nil)
+
+	result, err := svc.Record(context.Background(), domain.MentionSource{
+		AuthorID: 1, Task: &domain.Task{ID: 5, TeamID: 1}, CommentID: &commentID, Internal: true,
+	}, []string{"alice", "client"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"client"}, result.Unresolved)
+	mentionRepo.AssertExpectations(t)
+}
+
+func TestMentionService_Record_ScopedGuestNeedsGrant(t *testing.T) {
+	mentionRepo := new(mocks.MentionRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	svc := NewMentionService(mentionRepo, teamRepo, NewAuthorizer(teamRepo), new(mocks.NotificationServiceMock))
+
+	teamRepo.On("ListMentionCandidates", mock.Anything, int64(1)).Return([]domain.TeamMemberDetails{
+		{TeamID: 1, UserID: 3, Email: "client@b.com"},
+		{TeamID: 1, UserID: 4, Email: "auditor@c.com"},
+	}, nil)
+	for _, userID := range []int64{3, 4} {
+		teamRepo.On("GetMember", mock.Anything, int64(1), userID).Return(&domain.TeamMember{
+			TeamID: 1, UserID: userID, Role: domain.TeamRoleGuest, TaskScoped: true,
+		}, nil)
+	}
+	teamRepo.On("IsTaskGranted", mock.Anything, int64(3), int64(5)).Return(false, nil)
+	teamRepo.On("IsTaskGranted", mock.Anything, int64(4), int64(5)).Return(true, nil)
+	mentionRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(m []domain.Mention) bool {
+		return len(m) == 1 && m[0].UserID == 4
+	})).Return(nil)
+
+	result, err := svc.Record(context.Background(), domain.MentionSource{
+		AuthorID: 1, Task: &domain.Task{ID: 5, TeamID: 1},
+	}, []string{"client", "auditor"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"client"}, result.Unresolved)
+	mentionRepo.AssertExpectations(t)
+}
+
+func TestMentionService_ListForUser_NormalizesPaging(t *testing.T) {
+	mentionRepo := new(mocks.MentionRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	svc := NewMentionService(mentionRepo, teamRepo, NewAuthorizer(teamRepo), new(mocks.NotificationServiceMock))
+
+	teamRepo.On("ListByUserID", mock.Anything, int64(1), true).Return([]domain.Team{{ID: 1}, {ID: 2}}, nil)
+	stubTeamMember(teamRepo, 1, domain.TeamRoleMember)
+	teamRepo.On("GetMember", mock.Anything, int64(2), int64(1)).Return(&domain.TeamMember{
+		TeamID: 2, UserID: 1, Role: domain.TeamRoleGuest, TaskScoped: true,
+	}, nil)
+	access := domain.TaskAccess{UserID: 1, Teams: []int64{1}, ScopedTeams: []int64{2}}
+	mentionRepo.On("ListByUser", mock.Anything, access, domain.MentionFilter{Page: 1, PageSize: 100}).
+		Return([]domain.Mention{{ID: 1}}, 101, nil)
+
+	result, err := svc.ListForUser(context.Background(), 1, domain.MentionFilter{PageSize: 500})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.TotalPages)
+	assert.Len(t, result.Mentions, 1)
+}
@@ -59,3 +59,38 @@ func (s *NotificationServiceImpl) NotifyCommentAdded(ctx context.Context, commen
 		task.Title, task.ID, comment.UserID)
 	return nil
 }
+
+func (s *NotificationServiceImpl) NotifyThreadReply(ctx context.Context, reply *domain.TaskComment, task *domain.Task, recipients []domain.User) error {
+	if s.isCircuitOpen() {
+		log.Printf("[NOTIFICATION] Circuit breaker open, skipping reply notification for task %d", task.ID)
+		return nil
+	}
+
+	for _, r := range recipients {
+		log.Printf("[NOTIFICATION] Mock email: New reply in a thread on task '%s' (ID: %d) by user %d to %s (%s)",
+			task.Title, task.ID, reply.UserID, r.FullName, r.Email)
+	}
+	return nil
+}
+
+func (s *NotificationServiceImpl) NotifyMentioned(ctx context.Context, task *domain.Task, authorID int64, recipient *domain.User) error {
+	if s.isCircuitOpen() {
+		log.Printf("[NOTIFICATION] Circuit breaker open, skipping mention notification for task %d", task.ID)
+		return nil
+	}
+
+	log.Printf("[NOTIFICATION] Mock email: %s (%s) was mentioned on task '%s' (ID: %d) by user %d",
+		recipient.FullName, recipient.Email, task.Title, task.ID, authorID)
+	return nil
+}
+
+func (s *NotificationServiceImpl) NotifyOwnershipNominated(ctx context.Context, team *domain.Team, nominee *domain.User) error {
+	if s.isCircuitOpen() {
+		log.Printf("[NOTIFICATION] Circuit breaker open, skipping ownership notification for team %d", team.ID)
+		return nil
+	}
+
+	log.Printf("[NOTIFICATION] Mock email: %s (%s) was nominated as the new owner of team '%s' (ID: %d)",
+		nominee.FullName, nominee.Email, team.Name, team.ID)
+	return nil
+}
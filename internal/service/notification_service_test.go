@@ -39,3 +39,24 @@ func TestNotificationService_CircuitBreaker(t *testing.T) {
 	err := svc.NotifyTaskAssigned(context.Background(), task, user)
 	assert.NoError(t, err)
 }
+
+func TestNotificationService_NotifyThreadReply(t *testing.T) {
+	svc := NewNotificationService()
+
+	reply := &domain.TaskComment{ID: 2, TaskID: 1, UserID: 3}
+	task := &domain.Task{ID: 1, Title: "Test Task"}
+	recipients := []domain.User{{ID: 1, Email: "a@example.com"}, {ID: 2, Email: "b@example.com"}}
+
+	err := svc.NotifyThreadReply(context.Background(), reply, task, recipients)
+	assert.NoError(t, err)
+}
+
+func TestNotificationService_NotifyMentioned(t *testing.T) {
+	svc := NewNotificationService()
+
+	task := &domain.Task{ID: 1, Title: "Test Task"}
+	recipient := &domain.User{ID: 2, Email: "b@example.com", FullName: "B"}
+
+	err := svc.NotifyMentioned(context.Background(), task, 1, recipient)
+	assert.NoError(t, err)
+}
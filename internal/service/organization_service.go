@@ -0,0 +1,498 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+const (
+	orgUserSearchMinLength = 2
+	orgUserSearchLimit     = 20
+
+	orgInvitationTokenPurpose = "org-invitation"
+)
+
+var errNotOrgMember = apperror.Forbidden("you are not a member of this organization")
+
+type OrganizationServiceImpl struct {
+	orgRepo        port.OrganizationRepository
+	userRepo       port.UserRepository
+	invitationRepo port.OrgInvitationRepository
+	txManager      port.TransactionManager
+	mailer         port.Mailer
+	signer         *signedtoken.Signer
+	ttl            time.Duration
+	acceptURL      string
+	policy         domain.EmailVerificationPolicy
+}
+
+func NewOrganizationService(
+	orgRepo port.OrganizationRepository,
+	userRepo port.UserRepository,
+	invitationRepo port.OrgInvitationRepository,
+	txManager port.TransactionManager,
+	mailer port.Mailer,
+	secret string,
+	ttl time.Duration,
+	acceptURL string,
+	policy domain.EmailVerificationPolicy,
+) *OrganizationServiceImpl {
+	return &OrganizationServiceImpl{
+		orgRepo:        orgRepo,
+		userRepo:       userRepo,
+		invitationRepo: invitationRepo,
+		txManager:      txManager,
+		mailer:         mailer,
+		signer:         signedtoken.NewSigner(secret),
+		ttl:            ttl,
+		acceptURL:      acceptURL,
+		policy:         policy,
+	}
+}
+
+func (s *OrganizationServiceImpl) Create(ctx context.Context, userID int64, req domain.CreateOrganizationRequest) (*domain.Organization, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, apperror.BadRequest("organization name is required")
+	}
+
+	var org *domain.Organization
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		id, err := s.orgRepo.Create(ctx, &domain.Organization{Name: name, CreatedBy: userID})
+		if err != nil {
+			return err
+		}
+		err = s.orgRepo.AddMember(ctx, &domain.OrgMember{
+			OrgID:  id,
+			UserID: userID,
+			Role:   domain.OrgRoleAdmin,
+		})
+		if err != nil {
+			return err
+		}
+		org, err = s.orgRepo.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (s *OrganizationServiceImpl) List(ctx context.Context, userID int64) ([]domain.Organization, error) {
+	orgs, err := s.orgRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if orgs == nil {
+		orgs = []domain.Organization{}
+	}
+	return orgs, nil
+}
+
+func (s *OrganizationServiceImpl) GetByID(ctx context.Context, userID, orgID int64) (*domain.Organization, error) {
+	if _, err := s.checkMember(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.GetByID(ctx, orgID)
+}
+
+func (s *OrganizationServiceImpl) Update(ctx context.Context, userID, orgID int64, req domain.UpdateOrganizationRequest) (*domain.Organization, error) {
+	if err := s.checkAdmin(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, apperror.BadRequest("organization name is required")
+		}
+		if name != org.Name {
+			org.Name = name
+			if err := s.orgRepo.Update(ctx, org); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s.orgRepo.GetByID(ctx, orgID)
+}
+
+func (s *OrganizationServiceImpl) ListMembers(ctx context.Context, userID, orgID int64) ([]domain.OrgMemberDetails, error) {
+	if _, err := s.checkMember(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.orgRepo.ListMembers(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if members == nil {
+		members = []domain.OrgMemberDetails{}
+	}
+	return members, nil
+}
+
+// InviteMember mails an invitation to join the organization. The response
+// is the same whether or not the address belongs to a registered user, and
+// inviting an address that already has a pending invitation sends it again
+// with a fresh token. Only existing members are refused, which admins can
+// see in the member list anyway.
+func (s *OrganizationServiceImpl) InviteMember(ctx context.Context, userID, orgID int64, req domain.InviteOrgMemberRequest) (*domain.OrgInvitation, error) {
+	role, err := parseOrgRole(req.Role, domain.OrgRoleMember)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAdmin(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+
+	email := normalizeEmail(req.Email)
+	if email == "" {
+		return nil, apperror.BadRequest("email is required")
+	}
+	if err := s.checkVerified(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if user, err := s.userRepo.GetByEmail(ctx, email); err == nil {
+		member, err := s.orgRepo.GetMember(ctx, orgID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if member != nil {
+			return nil, apperror.New(http.StatusConflict, "user is already a member of this organization")
+		}
+	}
+
+	token, expiresAt, err := s.issueInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.invitationRepo.GetPendingByEmail(ctx, orgID, email)
+	if err != nil {
+		return nil, err
+	}
+	if invitation != nil {
+		if err := s.invitationRepo.Rotate(ctx, invitation.ID, signedtoken.Hash(token), expiresAt); err != nil {
+			return nil, err
+		}
+		invitation.ExpiresAt = expiresAt
+	} else {
+		id, err := s.invitationRepo.Create(ctx, &domain.OrgInvitation{
+			OrgID:     orgID,
+			Email:     email,
+			Role:      role,
+			InviterID: userID,
+			TokenHash: signedtoken.Hash(token),
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		invitation, err = s.invitationRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.notifyInvitation(ctx, invitation, token)
+	return invitation, nil
+}
+
+// AcceptInvitation adds the caller to the organization. The caller's email
+// must be verified and match the invited address.
+func (s *OrganizationServiceImpl) AcceptInvitation(ctx context.Context, userID int64, token string) (*domain.Organization, error) {
+	invitation, err := s.lookupInvitation(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var org *domain.Organization
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if normalizeEmail(user.Email) != invitation.Email {
+			return apperror.Forbidden("this invitation was sent to a different email address")
+		}
+		if !user.EmailVerified() {
+			return apperror.Forbidden("verify your email address before accepting the invitation")
+		}
+
+		ok, err := s.invitationRepo.Resolve(ctx, invitation.ID, domain.InvitationAccepted, &userID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errInvalidInvitation
+		}
+
+		if err := s.orgRepo.AddMember(ctx, &domain.OrgMember{
+			OrgID:  invitation.OrgID,
+			UserID: userID,
+			Role:   invitation.Role,
+		}); err != nil {
+			return err
+		}
+		org, err = s.orgRepo.GetByID(ctx, invitation.OrgID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// DeclineInvitation needs only the token, so the invitee may have no account.
+func (s *OrganizationServiceImpl) DeclineInvitation(ctx context.Context, token string) error {
+	invitation, err := s.lookupInvitation(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.invitationRepo.Resolve(ctx, invitation.ID, domain.InvitationDeclined, nil)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errInvalidInvitation
+	}
+	return nil
+}
+
+func (s *OrganizationServiceImpl) ChangeMemberRole(ctx context.Context, actorID, orgID, targetID int64, req domain.UpdateOrgMemberRoleRequest) (*domain.OrgMember, error) {
+	role, err := parseOrgRole(req.Role, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAdmin(ctx, actorID, orgID); err != nil {
+		return nil, err
+	}
+
+	target, err := s.orgRepo.GetMember(ctx, orgID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, apperror.NotFound("member not found")
+	}
+	if target.Role == role {
+		return target, nil
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if target.Role == domain.OrgRoleAdmin {
+			if err := s.checkNotLastAdmin(ctx, orgID); err != nil {
+				return err
+			}
+		}
+		return s.orgRepo.UpdateMemberRole(ctx, orgID, targetID, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	target.Role = role
+	return target, nil
+}
+
+// RemoveMember lets admins remove anyone and members remove themselves. The
+// user is also removed from every team of the organization, so owners must
+// hand their teams over first.
+func (s *OrganizationServiceImpl) RemoveMember(ctx context.Context, actorID, orgID, targetID int64) error {
+	if actorID != targetID {
+		if err := s.checkAdmin(ctx, actorID, orgID); err != nil {
+			return err
+		}
+	}
+
+	target, err := s.orgRepo.GetMember(ctx, orgID, targetID)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		if actorID == targetID {
+			return errNotOrgMember
+		}
+		return apperror.NotFound("member not found")
+	}
+
+	owned, err := s.orgRepo.CountOwnedTeams(ctx, orgID, targetID)
+	if err != nil {
+		return err
+	}
+	if owned > 0 {
+		return apperror.New(http.StatusConflict, "the user owns teams in this organization; transfer ownership first")
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if target.Role == domain.OrgRoleAdmin {
+			if err := s.checkNotLastAdmin(ctx, orgID); err != nil {
+				return err
+			}
+		}
+		return s.orgRepo.RemoveMember(ctx, orgID, targetID)
+	})
+}
+
+func (s *OrganizationServiceImpl) ListTeams(ctx context.Context, userID, orgID int64) ([]domain.Team, error) {
+	if err := s.checkAdmin(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+
+	teams, err := s.orgRepo.ListTeams(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if teams == nil {
+		teams = []domain.Team{}
+	}
+	return teams, nil
+}
+
+// SearchUsers looks up organization members by email or name prefix, so team
+// managers can find people to invite without exposing users outside the
+// organization.
+func (s *OrganizationServiceImpl) SearchUsers(ctx context.Context, userID, orgID int64, query string) ([]domain.OrgMemberDetails, error) {
+	query = strings.TrimSpace(query)
+	if len([]rune(query)) < orgUserSearchMinLength {
+		return nil, apperror.BadRequest("search query must be at least 2 characters")
+	}
+	if _, err := s.checkMember(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+
+	users, err := s.orgRepo.SearchMembers(ctx, orgID, query, orgUserSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+	if users == nil {
+		users = []domain.OrgMemberDetails{}
+	}
+	return users, nil
+}
+
+func (s *OrganizationServiceImpl) checkMember(ctx context.Context, userID, orgID int64) (*domain.OrgMember, error) {
+	member, err := s.orgRepo.GetMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errNotOrgMember
+	}
+	return member, nil
+}
+
+func (s *OrganizationServiceImpl) checkAdmin(ctx context.Context, userID, orgID int64) error {
+	member, err := s.checkMember(ctx, userID, orgID)
+	if err != nil {
+		return err
+	}
+	if member.Role != domain.OrgRoleAdmin {
+		return apperror.ErrInsufficientRole
+	}
+	return nil
+}
+
+func (s *OrganizationServiceImpl) checkNotLastAdmin(ctx context.Context, orgID int64) error {
+	admins, err := s.orgRepo.CountMembersByRole(ctx, orgID, domain.OrgRoleAdmin)
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return apperror.New(http.StatusConflict, "an organization must keep at least one admin")
+	}
+	return nil
+}
+
+// lookupInvitation verifies the token signature before touching the database
+// and then finds the pending invitation it was issued for.
+func (s *OrganizationServiceImpl) lookupInvitation(ctx context.Context, token string) (*domain.OrgInvitation, error) {
+	if err := s.signer.Verify(orgInvitationTokenPurpose, token, time.Now()); err != nil {
+		return nil, errInvalidInvitation
+	}
+
+	invitation, err := s.invitationRepo.GetByTokenHash(ctx, signedtoken.Hash(token))
+	if err != nil {
+		var appErr *apperror.AppError
+		if errors.As(err, &appErr) && appErr.Code == http.StatusNotFound {
+			return nil, errInvalidInvitation
+		}
+		return nil, err
+	}
+	if invitation.Status != domain.InvitationPending || !time.Now().Before(invitation.ExpiresAt) {
+		return nil, errInvalidInvitation
+	}
+	return invitation, nil
+}
+
+// checkVerified keeps accounts with an unconfirmed email from sending
+// invitations when the policy asks for it.
+func (s *OrganizationServiceImpl) checkVerified(ctx context.Context, userID int64) error {
+	if !s.policy.BlockInvitations {
+		return nil
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.EmailVerified() {
+		return apperror.Forbidden("verify your email address before inviting others")
+	}
+	return nil
+}
+
+func (s *OrganizationServiceImpl) issueInvitationToken() (string, time.Time, error) {
+	expiresAt := time.Now().Add(s.ttl).Truncate(time.Second)
+	token, err := s.signer.Sign(orgInvitationTokenPurpose, expiresAt)
+	if err != nil {
+		return "", time.Time{}, apperror.Internal("generate invitation token", err)
+	}
+	return token, expiresAt, nil
+}
+
+// notifyInvitation mails the invitation link, the only place the token is
+// sent. A failed delivery is not an error: inviting the address again
+// resends it.
+func (s *OrganizationServiceImpl) notifyInvitation(ctx context.Context, invitation *domain.OrgInvitation, token string) {
+	org, err := s.orgRepo.GetByID(ctx, invitation.OrgID)
+	if err != nil {
+		return
+	}
+	link := fmt.Sprintf(s.acceptURL, url.QueryEscape(token))
+	_ = s.mailer.Send(ctx, domain.MailMessage{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("You are invited to join %s", org.Name),
+		Body: fmt.Sprintf("Hi,\r\n\r\nYou have been invited to join the organization %s as %s. Follow this link to accept:\r\n%s\r\n\r\n"+
+			"If you do not have an account yet, sign up with this email address first. "+
+			"The link expires on %s. If you do not want to join, ignore this email.\r\n",
+			org.Name, invitation.Role, link, invitation.ExpiresAt.UTC().Format("2006-01-02 15:04 MST")),
+	})
+}
+
+func parseOrgRole(raw string, fallback domain.OrgRole) (domain.OrgRole, error) {
+	if raw == "" && fallback != "" {
+		return fallback, nil
+	}
+	switch role := domain.OrgRole(raw); role {
+	case domain.OrgRoleAdmin, domain.OrgRoleMember, domain.OrgRoleBilling:
+		return role, nil
+	}
+	return "", apperror.BadRequest("role must be admin, member or billing")
+}
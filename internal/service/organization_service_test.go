@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newOrganizationService() (*OrganizationServiceImpl, *mocks.OrganizationRepositoryMock, *mocks.UserRepositoryMock) {
+	svc, orgRepo, userRepo, _, _ := newOrgInvitationService()
+	return svc, orgRepo, userRepo
+}
+
+func newOrgInvitationService() (
+	*OrganizationServiceImpl, *mocks.OrganizationRepositoryMock, *mocks.UserRepositoryMock,
+	*mocks.OrgInvitationRepositoryMock, *mocks.MailerMock,
+) {
+	orgRepo := new(mocks.OrganizationRepositoryMock)
+	userRepo := new(mocks.UserRepositoryMock)
+	invitationRepo := new(mocks.OrgInvitationRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
+	mailer := new(mocks.MailerMock)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil).Maybe()
+	svc := NewOrganizationService(orgRepo, userRepo, invitationRepo, txManager, mailer, "test-secret", 24*time.Hour, "https://app.test/org-accept?token=%s", domain.EmailVerificationPolicy{})
+	return svc, orgRepo, userRepo, invitationRepo, mailer
+}
+
+func stubOrgMember(orgRepo *mocks.OrganizationRepositoryMock, userID int64, role domain.OrgRole) {
+	orgRepo.On("GetMember", mock.Anything, int64(1), userID).Return(&domain.OrgMember{
+		OrgID: 1, UserID: userID, Role: role,
+	}, nil)
+}
+
+func TestOrganizationService_Create_CreatorBecomesAdmin(t *testing.T) {
+	svc, orgRepo, _ := newOrganizationService()
+
+	orgRepo.On("Create", mock.Anything, mock.MatchedBy(func(o *domain.Organization) bool {
+		return o.Name == "Acme" && !o.Personal && o.CreatedBy == 1
+	})).Return(int64(4), nil)
+	orgRepo.On("AddMember", mock.Anything, &domain.OrgMember{OrgID: 4, UserID: 1, Role: domain.OrgRoleAdmin}).Return(nil)
+	orgRepo.On("GetByID", mock.Anything, int64(4)).Return(&domain.Organization{ID: 4, Name: "Acme"}, nil)
+
+	org, err := svc.Create(context.Background(), 1, domain.CreateOrganizationRequest{Name: " Acme "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), org.ID)
+	orgRepo.AssertExpectations(t)
+}
+
+func TestOrganizationService_InviteMember_RequiresAdmin(t *testing.T) {
+	svc, orgRepo, userRepo := newOrganizationService()
+
+	stubOrgMember(orgRepo, 2, domain.OrgRoleMember)
+
+	_, err := svc.InviteMember(context.Background(), 2, 1, domain.InviteOrgMemberRequest{Email: "x@example.com"})
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	userRepo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+}
+
+func TestOrganizationService_InviteMember_SameResponseForUnknownAndRegistered(t *testing.T) {
+	for _, registered := range []bool{false, true} {
+		svc, orgRepo, userRepo, invitationRepo, mailer := newOrgInvitationService()
+
+		stubOrgMember(orgRepo, 1, domain.OrgRoleAdmin)
+		if registered {
+			userRepo.On("GetByEmail", mock.Anything, "x@example.com").Return(&domain.User{ID: 7, Email: "x@example.com"}, nil)
+			orgRepo.On("GetMember", mock.Anything, int64(1), int64(7)).Return(nil, nil)
+		} else {
+			userRepo.On("GetByEmail", mock.Anything, "x@example.com").Return(nil, apperror.NotFound("user not found"))
+		}
+		invitationRepo.On("GetPendingByEmail", mock.Anything, int64(1), "x@example.com").Return(nil, nil)
+		invitationRepo.On("Create", mock.Anything, mock.MatchedBy(func(inv *domain.OrgInvitation) bool {
+			return inv.OrgID == 1 && inv.Email == "x@example.com" && inv.Role == domain.OrgRoleMember && len(inv.TokenHash) == 64
+		})).Return(int64(3), nil)
+		invitationRepo.On("GetByID", mock.Anything, int64(3)).Return(&domain.OrgInvitation{
+			ID: 3, OrgID: 1, Email: "x@example.com", Role: domain.OrgRoleMember, Status: domain.InvitationPending,
+		}, nil)
+		orgRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Organization{ID: 1, Name: "Acme"}, nil)
+		mailer.On("Send", mock.Anything, mock.MatchedBy(func(msg domain.MailMessage) bool {
+			return msg.To == "x@example.com"
+		})).Return(nil)
+
+		invitation, err := svc.InviteMember(context.Background(), 1, 1, domain.InviteOrgMemberRequest{Email: " X@example.com "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.OrgInvitation{
+			ID: 3, OrgID: 1, Email: "x@example.com", Role: domain.OrgRoleMember, Status: domain.InvitationPending,
+		}, invitation)
+		orgRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+		mailer.AssertExpectations(t)
+	}
+}
+
+func TestOrganizationService_InviteMember_PendingIsResent(t *testing.T) {
+	svc, orgRepo, userRepo, invitationRepo, mailer := newOrgInvitationService()
+
+	stubOrgMember(orgRepo, 1, domain.OrgRoleAdmin)
+	userRepo.On("GetByEmail", mock.Anything, "x@example.com").Return(nil, apperror.NotFound("user not found"))
+	invitationRepo.On("GetPendingByEmail", mock.Anything, int64(1), "x@example.com").Return(&domain.OrgInvitation{
+		ID: 3, OrgID: 1, Email: "x@example.com", Role: domain.OrgRoleMember, Status: domain.InvitationPending,
+	}, nil)
+	invitationRepo.On("Rotate", mock.Anything, int64(3), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+	orgRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Organization{ID: 1, Name: "Acme"}, nil)
+	mailer.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	invitation, err := svc.InviteMember(context.Background(), 1, 1, domain.InviteOrgMemberRequest{Email: "x@example.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), invitation.ID)
+	invitationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	invitationRepo.AssertExpectations(t)
+	mailer.AssertExpectations(t)
+}
+
+func TestOrganizationService_AcceptInvitation_DifferentEmail(t *testing.T) {
+	svc, orgRepo, userRepo, invitationRepo, _ := newOrgInvitationService()
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	token, err := svc.signer.Sign(orgInvitationTokenPurpose, expiresAt)
+	assert.NoError(t, err)
+	invitationRepo.On("GetByTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(&domain.OrgInvitation{
+		ID: 3, OrgID: 1, Email: "x@example.com", Role: domain.OrgRoleMember, Status: domain.InvitationPending, ExpiresAt: expiresAt,
+	}, nil)
+	userRepo.On("GetByID", mock.Anything, int64(7)).Return(&domain.User{ID: 7, Email: "other@example.com"}, nil)
+
+	_, err = svc.AcceptInvitation(context.Background(), 7, token)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.Code)
+	orgRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestOrganizationService_AcceptInvitation_TeamTokenRejected(t *testing.T) {
+	svc, orgRepo, _, invitationRepo, _ := newOrgInvitationService()
+
+	token, err := svc.signer.Sign(invitationTokenPurpose, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	_, err = svc.AcceptInvitation(context.Background(), 7, token)
+
+	assert.Equal(t, errInvalidInvitation, err)
+	invitationRepo.AssertNotCalled(t, "GetByTokenHash", mock.Anything, mock.Anything)
+	orgRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestOrganizationService_ChangeMemberRole_LastAdmin(t *testing.T) {
+	svc, orgRepo, _ := newOrganizationService()
+
+	stubOrgMember(orgRepo, 1, domain.OrgRoleAdmin)
+	orgRepo.On("CountMembersByRole", mock.Anything, int64(1), domain.OrgRoleAdmin).Return(1, nil)
+
+	_, err := svc.ChangeMemberRole(context.Background(), 1, 1, 1, domain.UpdateOrgMemberRoleRequest{Role: "member"})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 409, appErr.Code)
+	orgRepo.AssertNotCalled(t, "UpdateMemberRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOrganizationService_ChangeMemberRole_InvalidRole(t *testing.T) {
+	svc, _, _ := newOrganizationService()
+
+	_, err := svc.ChangeMemberRole(context.Background(), 1, 1, 2, domain.UpdateOrgMemberRoleRequest{Role: "owner"})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+}
+
+func TestOrganizationService_RemoveMember_OwnsTeams(t *testing.T) {
+	svc, orgRepo, _ := newOrganizationService()
+
+	stubOrgMember(orgRepo, 1, domain.OrgRoleAdmin)
+	stubOrgMember(orgRepo, 2, domain.OrgRoleMember)
+	orgRepo.On("CountOwnedTeams", mock.Anything, int64(1), int64(2)).Return(1, nil)
+
+	err := svc.RemoveMember(context.Background(), 1, 1, 2)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 409, appErr.Code)
+	orgRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOrganizationService_RemoveMember_Self(t *testing.T) {
+	svc, orgRepo, _ := newOrganizationService()
+
+	stubOrgMember(orgRepo, 2, domain.OrgRoleMember)
+	orgRepo.On("CountOwnedTeams", mock.Anything, int64(1), int64(2)).Return(0, nil)
+	orgRepo.On("RemoveMember", mock.Anything, int64(1), int64(2)).Return(nil)
+
+	err := svc.RemoveMember(context.Background(), 2, 1, 2)
+
+	assert.NoError(t, err)
+	orgRepo.AssertExpectations(t)
+}
+
+func TestOrganizationService_SearchUsers(t *testing.T) {
+	svc, orgRepo, _ := newOrganizationService()
+
+	stubOrgMember(orgRepo, 2, domain.OrgRoleMember)
+	orgRepo.On("SearchMembers", mock.Anything, int64(1), "an", orgUserSearchLimit).Return([]domain.OrgMemberDetails{
+		{OrgID: 1, UserID: 3, FullName: "Ann"},
+	}, nil)
+
+	users, err := svc.SearchUsers(context.Background(), 2, 1, " an ")
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+}
+
+func TestOrganizationService_SearchUsers_NotMember(t *testing.T) {
+	svc, orgRepo, _ := newOrganizationService()
+
+	orgRepo.On("GetMember", mock.Anything, int64(1), int64(5)).Return(nil, nil)
+
+	_, err := svc.SearchUsers(context.Background(), 5, 1, "ann")
+
+	assert.Equal(t, errNotOrgMember, err)
+	orgRepo.AssertNotCalled(t, "SearchMembers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+const ownershipTransferTTL = 7 * 24 * time.Hour
+
+var errNoPendingTransfer = apperror.NotFound("no pending ownership transfer")
+
+type OwnershipServiceImpl struct {
+	teamRepo     port.TeamRepository
+	authz        port.Authorizer
+	userRepo     port.UserRepository
+	transferRepo port.OwnershipTransferRepository
+	activityRepo port.ActivityRepository
+	txManager    port.TransactionManager
+	notifSvc     port.NotificationService
+}
+
+func NewOwnershipService(
+	teamRepo port.TeamRepository,
+	authz port.Authorizer,
+	userRepo port.UserRepository,
+	transferRepo port.OwnershipTransferRepository,
+	activityRepo port.ActivityRepository,
+	txManager port.TransactionManager,
+	notifSvc port.NotificationService,
+) *OwnershipServiceImpl {
+	return &OwnershipServiceImpl{
+		teamRepo:     teamRepo,
+		authz:        authz,
+		userRepo:     userRepo,
+		transferRepo: transferRepo,
+		activityRepo: activityRepo,
+		txManager:    txManager,
+		notifSvc:     notifSvc,
+	}
+}
+
+// Nominate replaces any open nomination for the team with a new one.
+func (s *OwnershipServiceImpl) Nominate(ctx context.Context, ownerID, teamID int64, req domain.NominateOwnerRequest) (*domain.OwnershipTransfer, error) {
+	if req.UserID == 0 {
+		return nil, apperror.BadRequest("user_id is required")
+	}
+	if req.UserID == ownerID {
+		return nil, apperror.BadRequest("cannot transfer ownership to yourself")
+	}
+
+	team, err := s.loadOwnedTeam(ctx, ownerID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	nominee, err := s.teamRepo.GetMember(ctx, teamID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if nominee == nil || !nominee.IsDirect() {
+		return nil, apperror.BadRequest("the new owner must be a member of the team")
+	}
+
+	var transfer *domain.OwnershipTransfer
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		pending, err := s.pending(ctx, teamID)
+		if err != nil {
+			return err
+		}
+		if pending != nil {
+			if err := s.transferRepo.Resolve(ctx, pending.ID, domain.TransferCancelled); err != nil {
+				return err
+			}
+		}
+
+		id, err := s.transferRepo.Create(ctx, &domain.OwnershipTransfer{
+			TeamID:     teamID,
+			FromUserID: ownerID,
+			ToUserID:   req.UserID,
+			ExpiresAt:  time.Now().Add(ownershipTransferTTL),
+		})
+		if err != nil {
+			return err
+		}
+		transfer, err = s.transferRepo.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if user, err := s.userRepo.GetByID(ctx, req.UserID); err == nil {
+		_ = s.notifSvc.NotifyOwnershipNominated(ctx, team, user)
+	}
+	return transfer, nil
+}
+
+func (s *OwnershipServiceImpl) GetPending(ctx context.Context, userID, teamID int64) (*domain.OwnershipTransfer, error) {
+	if _, err := s.authz.Member(ctx, userID, teamID); err != nil {
+		return nil, err
+	}
+
+	transfer, err := s.transferRepo.GetPending(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer == nil || transfer.Expired(time.Now()) {
+		return nil, errNoPendingTransfer
+	}
+	return transfer, nil
+}
+
+// Accept hands the team to the nominee: teams.owner_id, both member roles
+// and the transfer status change in one transaction. The previous owner
+// stays on as an admin.
+func (s *OwnershipServiceImpl) Accept(ctx context.Context, userID, teamID int64) (*domain.Team, error) {
+	var team *domain.Team
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		transfer, err := s.pending(ctx, teamID)
+		if err != nil {
+			return err
+		}
+		if transfer == nil {
+			return errNoPendingTransfer
+		}
+		if transfer.ToUserID != userID {
+			return apperror.Forbidden("only the nominated member can accept the transfer")
+		}
+
+		current, err := s.teamRepo.GetByID(ctx, teamID)
+		if err != nil {
+			return err
+		}
+		if current.OwnerID != transfer.FromUserID {
+			return apperror.New(http.StatusConflict, "the team owner has changed since the nomination")
+		}
+		nominee, err := s.teamRepo.GetMember(ctx, teamID, userID)
+		if err != nil {
+			return err
+		}
+		if nominee == nil || !nominee.IsDirect() {
+			return apperror.ErrNotTeamMember
+		}
+
+		if err := s.teamRepo.UpdateOwner(ctx, teamID, userID); err != nil {
+			return err
+		}
+		if err := s.teamRepo.UpdateMemberRole(ctx, teamID, userID, domain.TeamRoleOwner); err != nil {
+			return err
+		}
+		if err := s.teamRepo.UpdateMemberRole(ctx, teamID, transfer.FromUserID, domain.TeamRoleAdmin); err != nil {
+			return err
+		}
+		if err := s.transferRepo.Resolve(ctx, transfer.ID, domain.TransferAccepted); err != nil {
+			return err
+		}
+		if err := s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:       teamID,
+			ActorID:      transfer.FromUserID,
+			TargetUserID: &userID,
+			Type:         domain.ActivityOwnerTransferred,
+			Details:      fmt.Sprintf("transfer #%d", transfer.ID),
+		}); err != nil {
+			return err
+		}
+
+		team, err = s.teamRepo.GetByID(ctx, teamID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *OwnershipServiceImpl) Decline(ctx context.Context, userID, teamID int64) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		transfer, err := s.pending(ctx, teamID)
+		if err != nil {
+			return err
+		}
+		if transfer == nil {
+			return errNoPendingTransfer
+		}
+		if transfer.ToUserID != userID {
+			return apperror.Forbidden("only the nominated member can decline the transfer")
+		}
+		return s.transferRepo.Resolve(ctx, transfer.ID, domain.TransferDeclined)
+	})
+}
+
+func (s *OwnershipServiceImpl) Cancel(ctx context.Context, ownerID, teamID int64) error {
+	if _, err := s.loadOwnedTeam(ctx, ownerID, teamID); err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		transfer, err := s.pending(ctx, teamID)
+		if err != nil {
+			return err
+		}
+		if transfer == nil {
+			return errNoPendingTransfer
+		}
+		return s.transferRepo.Resolve(ctx, transfer.ID, domain.TransferCancelled)
+	})
+}
+
+// pending returns the open nomination for the team, marking it expired and
+// returning nil once its deadline has passed.
+func (s *OwnershipServiceImpl) pending(ctx context.Context, teamID int64) (*domain.OwnershipTransfer, error) {
+	transfer, err := s.transferRepo.GetPending(ctx, teamID)
+	if err != nil || transfer == nil {
+		return nil, err
+	}
+	if transfer.Expired(time.Now()) {
+		if err := s.transferRepo.Resolve(ctx, transfer.ID, domain.TransferExpired); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return transfer, nil
+}
+
+func (s *OwnershipServiceImpl) loadOwnedTeam(ctx context.Context, userID, teamID int64) (*domain.Team, error) {
+	if _, err := s.authz.Member(ctx, userID, teamID); err != nil {
+		return nil, err
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team.OwnerID != userID {
+		return nil, apperror.ErrInsufficientRole
+	}
+	return team, nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newOwnershipService() (
+	*OwnershipServiceImpl, *mocks.TeamRepositoryMock, *mocks.UserRepositoryMock,
+	*mocks.OwnershipTransferRepositoryMock, *mocks.ActivityRepositoryMock, *mocks.NotificationServiceMock,
+) {
+	teamRepo := new(mocks.TeamRepositoryMock)
+	userRepo := new(mocks.UserRepositoryMock)
+	transferRepo := new(mocks.OwnershipTransferRepositoryMock)
+	activityRepo := new(mocks.ActivityRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
+	notifSvc := new(mocks.NotificationServiceMock)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil).Maybe()
+	svc := NewOwnershipService(teamRepo, NewAuthorizer(teamRepo), userRepo, transferRepo, activityRepo, txManager, notifSvc)
+	return svc, teamRepo, userRepo, transferRepo, activityRepo, notifSvc
+}
+
+func TestOwnershipService_Nominate_ReplacesPending(t *testing.T) {
+	svc, teamRepo, userRepo, transferRepo, _, notifSvc := newOwnershipService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	stubTeamMember(teamRepo, 2, domain.TeamRoleAdmin)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, OwnerID: 1}, nil)
+	transferRepo.On("GetPending", mock.Anything, int64(1)).Return(&domain.OwnershipTransfer{
+		ID: 3, TeamID: 1, FromUserID: 1, ToUserID: 4, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	transferRepo.On("Resolve", mock.Anything, int64(3), domain.TransferCancelled).Return(nil)
+	transferRepo.On("Create", mock.Anything, mock.MatchedBy(func(tr *domain.OwnershipTransfer) bool {
+		return tr.FromUserID == 1 && tr.ToUserID == 2 && tr.ExpiresAt.After(time.Now())
+	})).Return(int64(5), nil)
+	transferRepo.On("GetByID", mock.Anything, int64(5)).Return(&domain.OwnershipTransfer{ID: 5, Status: domain.TransferPending}, nil)
+	userRepo.On("GetByID", mock.Anything, int64(2)).Return(&domain.User{ID: 2}, nil)
+	notifSvc.On("NotifyOwnershipNominated", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	transfer, err := svc.Nominate(context.Background(), 1, 1, domain.NominateOwnerRequest{UserID: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), transfer.ID)
+	transferRepo.AssertExpectations(t)
+	notifSvc.AssertExpectations(t)
+}
+
+func TestOwnershipService_Nominate_NotOwner(t *testing.T) {
+	svc, teamRepo, _, transferRepo, _, _ := newOwnershipService()
+
+	stubTeamMember(teamRepo, 2, domain.TeamRoleAdmin)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, OwnerID: 1}, nil)
+
+	_, err := svc.Nominate(context.Background(), 2, 1, domain.NominateOwnerRequest{UserID: 3})
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	transferRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestOwnershipService_Accept_SwapsRoles(t *testing.T) {
+	svc, teamRepo, _, transferRepo, activityRepo, _ := newOwnershipService()
+
+	transferRepo.On("GetPending", mock.Anything, int64(1)).Return(&domain.OwnershipTransfer{
+		ID: 5, TeamID: 1, FromUserID: 1, ToUserID: 2, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, OwnerID: 1}, nil)
+	stubTeamMember(teamRepo, 2, domain.TeamRoleAdmin)
+	teamRepo.On("UpdateOwner", mock.Anything, int64(1), int64(2)).Return(nil)
+	teamRepo.On("UpdateMemberRole", mock.Anything, int64(1), int64(2), domain.TeamRoleOwner).Return(nil)
+	teamRepo.On("UpdateMemberRole", mock.Anything, int64(1), int64(1), domain.TeamRoleAdmin).Return(nil)
+	transferRepo.On("Resolve", mock.Anything, int64(5), domain.TransferAccepted).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.Type == domain.ActivityOwnerTransferred && e.ActorID == 1 && *e.TargetUserID == 2
+	})).Return(nil)
+
+	_, err := svc.Accept(context.Background(), 2, 1)
+
+	assert.NoError(t, err)
+	teamRepo.AssertExpectations(t)
+	transferRepo.AssertExpectations(t)
+	activityRepo.AssertExpectations(t)
+}
+
+func TestOwnershipService_Accept_WrongUser(t *testing.T) {
+	svc, teamRepo, _, transferRepo, _, _ := newOwnershipService()
+
+	transferRepo.On("GetPending", mock.Anything, int64(1)).Return(&domain.OwnershipTransfer{
+		ID: 5, TeamID: 1, FromUserID: 1, ToUserID: 2, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+
+	_, err := svc.Accept(context.Background(), 3, 1)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 403, appErr.Code)
+	teamRepo.AssertNotCalled(t, "UpdateOwner", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOwnershipService_Accept_Expired(t *testing.T) {
+	svc, teamRepo, _, transferRepo, _, _ := newOwnershipService()
+
+	transferRepo.On("GetPending", mock.Anything, int64(1)).Return(&domain.OwnershipTransfer{
+		ID: 5, TeamID: 1, FromUserID: 1, ToUserID: 2, ExpiresAt: time.Now().Add(-time.Minute),
+	}, nil)
+	transferRepo.On("Resolve", mock.Anything, int64(5), domain.TransferExpired).Return(nil)
+
+	_, err := svc.Accept(context.Background(), 2, 1)
+
+	assert.Equal(t, errNoPendingTransfer, err)
+	teamRepo.AssertNotCalled(t, "UpdateOwner", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOwnershipService_Decline(t *testing.T) {
+	svc, _, _, transferRepo, _, _ := newOwnershipService()
+
+	transferRepo.On("GetPending", mock.Anything, int64(1)).Return(&domain.OwnershipTransfer{
+		ID: 5, TeamID: 1, FromUserID: 1, ToUserID: 2, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	transferRepo.On("Resolve", mock.Anything, int64(5), domain.TransferDeclined).Return(nil)
+
+	err := svc.Decline(context.Background(), 2, 1)
+
+	assert.NoError(t, err)
+	transferRepo.AssertExpectations(t)
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+const (
+	passwordResetPurpose = "password_reset"
+	minPasswordLength    = 8
+)
+
+var (
+	errInvalidResetToken = apperror.BadRequest("invalid or expired password reset token")
+	errWeakPassword      = apperror.BadRequest(fmt.Sprintf("new_password must be at least %d characters", minPasswordLength))
+)
+
+type PasswordServiceImpl struct {
+	userRepo  port.UserRepository
+	resetRepo port.PasswordResetRepository
+	txManager port.TransactionManager
+	sessions  port.SessionRevoker
+	mailer    port.Mailer
+	signer    *signedtoken.Signer
+	resetTTL  time.Duration
+	resetURL  string
+}
+
+func NewPasswordService(
+	userRepo port.UserRepository,
+	resetRepo port.PasswordResetRepository,
+	txManager port.TransactionManager,
+	sessions port.SessionRevoker,
+	mailer port.Mailer,
+	tokenSecret string,
+	resetTTL time.Duration,
+	resetURL string,
+) *PasswordServiceImpl {
+	return &PasswordServiceImpl{
+		userRepo:  userRepo,
+		resetRepo: resetRepo,
+		txManager: txManager,
+		sessions:  sessions,
+		mailer:    mailer,
+		signer:    signedtoken.NewSigner(tokenSecret),
+		resetTTL:  resetTTL,
+		resetURL:  resetURL,
+	}
+}
+
+// Forgot mails a reset link to the account with this email. It reports
+// success for unknown or disabled accounts too, so the endpoint cannot be
+// used to find out who is registered. A new link replaces earlier ones.
+func (s *PasswordServiceImpl) Forgot(ctx context.Context, req domain.ForgotPasswordRequest) error {
+	if req.Email == "" {
+		return apperror.BadRequest("email is required")
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		if appErr, ok := apperror.IsAppError(err); ok && appErr.Code == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+	if user.DisabledAt != nil {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(s.resetTTL).Truncate(time.Second)
+	token, err := s.signer.Sign(passwordResetPurpose, expiresAt)
+	if err != nil {
+		return apperror.Internal("generate token", err)
+	}
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.resetRepo.InvalidateForUser(ctx, user.ID); err != nil {
+			return err
+		}
+		_, err := s.resetRepo.Create(ctx, &domain.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: signedtoken.Hash(token),
+			ExpiresAt: expiresAt,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf(s.resetURL, url.QueryEscape(token))
+	return s.mailer.Send(ctx, domain.MailMessage{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\r\n\r\nFollow this link to choose a new password:\r\n%s\r\n\r\n"+
+			"The link works once and expires in %s. If you did not ask for it, ignore this email.\r\n",
+			user.FullName, link, s.resetTTL),
+	})
+}
+
+// Reset sets a new password with a token from Forgot and signs the account
+// out everywhere.
+func (s *PasswordServiceImpl) Reset(ctx context.Context, req domain.ResetPasswordRequest) error {
+	if req.Token == "" {
+		return apperror.BadRequest("token is required")
+	}
+	if len(req.NewPassword) < minPasswordLength {
+		return errWeakPassword
+	}
+	if err := s.signer.Verify(passwordResetPurpose, req.Token, time.Now()); err != nil {
+		return errInvalidResetToken
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return apperror.Internal("hash password", err)
+	}
+
+	var userID int64
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		token, err := s.resetRepo.GetByHashForUpdate(ctx, signedtoken.Hash(req.Token))
+		if err != nil {
+			if appErr, ok := apperror.IsAppError(err); ok && appErr.Code == http.StatusNotFound {
+				return errInvalidResetToken
+			}
+			return err
+		}
+		if token.UsedAt != nil || !token.ExpiresAt.After(time.Now()) {
+			return errInvalidResetToken
+		}
+		userID = token.UserID
+
+		if err := s.userRepo.UpdatePassword(ctx, token.UserID, string(hash)); err != nil {
+			return err
+		}
+		return s.resetRepo.InvalidateForUser(ctx, token.UserID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.sessions.RevokeSessions(ctx, userID)
+}
+
+// Change replaces the password of the signed-in user after checking the old
+// one. All sessions end, the current one included.
+func (s *PasswordServiceImpl) Change(ctx context.Context, claims domain.AccessClaims, req domain.ChangePasswordRequest) error {
+	if claims.ImpersonatorID != 0 {
+		return apperror.Forbidden("not available while impersonating")
+	}
+	if req.OldPassword == "" {
+		return apperror.BadRequest("old_password is required")
+	}
+	if len(req.NewPassword) < minPasswordLength {
+		return errWeakPassword
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.OldPassword)); err != nil {
+		return apperror.BadRequest("old password is incorrect")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return apperror.Internal("hash password", err)
+	}
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdatePassword(ctx, user.ID, string(hash)); err != nil {
+			return err
+		}
+		return s.resetRepo.InvalidateForUser(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.sessions.RevokeSessions(ctx, user.ID)
+}
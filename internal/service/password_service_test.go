@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newPasswordService(userRepo *mocks.UserRepositoryMock, resetRepo *mocks.PasswordResetRepositoryMock, sessions *mocks.SessionRevokerMock, mailer *mocks.MailerMock) *PasswordServiceImpl {
+	txManager := new(mocks.TransactionManagerMock)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil).Maybe()
+	return NewPasswordService(userRepo, resetRepo, txManager, sessions, mailer, "test-secret", time.Hour, "https://app.test/reset?token=%s")
+}
+
+func TestPasswordService_Forgot_UnknownEmailSendsNothing(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	mailer := new(mocks.MailerMock)
+	svc := newPasswordService(userRepo, new(mocks.PasswordResetRepositoryMock), new(mocks.SessionRevokerMock), mailer)
+
+	userRepo.On("GetByEmail", mock.Anything, "nobody@example.com").Return(nil, apperror.NotFound("user not found"))
+
+	err := svc.Forgot(context.Background(), domain.ForgotPasswordRequest{Email: "nobody@example.com"})
+
+	assert.NoError(t, err)
+	mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestPasswordService_Forgot_MailsSingleUseLink(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	resetRepo := new(mocks.PasswordResetRepositoryMock)
+	mailer := new(mocks.MailerMock)
+	svc := newPasswordService(userRepo, resetRepo, new(mocks.SessionRevokerMock), mailer)
+
+	userRepo.On("GetByEmail", mock.Anything, "user@example.com").Return(&domain.User{ID: 1, Email: "user@example.com", FullName: "User"}, nil)
+	resetRepo.On("InvalidateForUser", mock.Anything, int64(1)).Return(nil)
+	var stored *domain.PasswordResetToken
+	resetRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.PasswordResetToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*domain.PasswordResetToken) }).
+		Return(int64(1), nil)
+	var sent domain.MailMessage
+	mailer.On("Send", mock.Anything, mock.AnythingOfType("domain.MailMessage")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(domain.MailMessage) }).
+		Return(nil)
+
+	err := svc.Forgot(context.Background(), domain.ForgotPasswordRequest{Email: "user@example.com"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", sent.To)
+
+	_, after, ok := strings.Cut(sent.Body, "https://app.test/reset?token=")
+	require.True(t, ok)
+	token, err := url.QueryUnescape(strings.Fields(after)[0])
+	require.NoError(t, err)
+	assert.Equal(t, signedtoken.Hash(token), stored.TokenHash, "only the hash is stored")
+	assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+}
+
+func TestPasswordService_Reset_Success(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	resetRepo := new(mocks.PasswordResetRepositoryMock)
+	sessions := new(mocks.SessionRevokerMock)
+	svc := newPasswordService(userRepo, resetRepo, sessions, new(mocks.MailerMock))
+
+	token, err := svc.signer.Sign(passwordResetPurpose, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	resetRepo.On("GetByHashForUpdate", mock.Anything, signedtoken.Hash(token)).
+		Return(&domain.PasswordResetToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	var newHash string
+	userRepo.On("UpdatePassword", mock.Anything, int64(1), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { newHash = args.String(2) }).
+		Return(nil)
+	resetRepo.On("InvalidateForUser", mock.Anything, int64(1)).Return(nil)
+	sessions.On("RevokeSessions", mock.Anything, int64(1)).Return(nil)
+
+	err = svc.Reset(context.Background(), domain.ResetPasswordRequest{Token: token, NewPassword: "new-password"})
+
+	require.NoError(t, err)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(newHash), []byte("new-password")))
+	resetRepo.AssertExpectations(t)
+	sessions.AssertExpectations(t)
+}
+
+func TestPasswordService_Reset_UsedToken(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	resetRepo := new(mocks.PasswordResetRepositoryMock)
+	sessions := new(mocks.SessionRevokerMock)
+	svc := newPasswordService(userRepo, resetRepo, sessions, new(mocks.MailerMock))
+
+	token, err := svc.signer.Sign(passwordResetPurpose, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	usedAt := time.Now().Add(-time.Minute)
+	resetRepo.On("GetByHashForUpdate", mock.Anything, signedtoken.Hash(token)).
+		Return(&domain.PasswordResetToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}, nil)
+
+	err = svc.Reset(context.Background(), domain.ResetPasswordRequest{Token: token, NewPassword: "new-password"})
+
+	assert.Equal(t, errInvalidResetToken, err)
+	userRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+	sessions.AssertNotCalled(t, "RevokeSessions", mock.Anything, mock.Anything)
+}
+
+func TestPasswordService_Change_WrongOldPassword(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newPasswordService(userRepo, new(mocks.PasswordResetRepositoryMock), new(mocks.SessionRevokerMock), new(mocks.MailerMock))
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.MinCost)
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1, PasswordHash: string(hash)}, nil)
+
+	err := svc.Change(context.Background(), domain.AccessClaims{UserID: 1}, domain.ChangePasswordRequest{
+		OldPassword: "wrong-password", NewPassword: "new-password",
+	})
+
+	appErr, ok := apperror.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	userRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPasswordService_Change_RevokesSessions(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	resetRepo := new(mocks.PasswordResetRepositoryMock)
+	sessions := new(mocks.SessionRevokerMock)
+	svc := newPasswordService(userRepo, resetRepo, sessions, new(mocks.MailerMock))
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.MinCost)
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1, PasswordHash: string(hash)}, nil)
+	userRepo.On("UpdatePassword", mock.Anything, int64(1), mock.AnythingOfType("string")).Return(nil)
+	resetRepo.On("InvalidateForUser", mock.Anything, int64(1)).Return(nil)
+	sessions.On("RevokeSessions", mock.Anything, int64(1)).Return(nil)
+
+	err := svc.Change(context.Background(), domain.AccessClaims{UserID: 1}, domain.ChangePasswordRequest{
+		OldPassword: "old-password", NewPassword: "new-password",
+	})
+
+	require.NoError(t, err)
+	sessions.AssertExpectations(t)
+
+	err = svc.Change(context.Background(), domain.AccessClaims{UserID: 1, ImpersonatorID: 9}, domain.ChangePasswordRequest{
+		OldPassword: "old-password", NewPassword: "new-password",
+	})
+	appErr, ok := apperror.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, 403, appErr.Code)
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+const (
+	maxRoleNameLength = 64
+	maxGuestTasks     = 100
+)
+
+type PermissionServiceImpl struct {
+	authz        port.Authorizer
+	teamRepo     port.TeamRepository
+	roleRepo     port.TeamRoleRepository
+	taskRepo     port.TaskRepository
+	commentRepo  port.CommentRepository
+	activityRepo port.ActivityRepository
+	txManager    port.TransactionManager
+}
+
+func NewPermissionService(
+	authz port.Authorizer,
+	teamRepo port.TeamRepository,
+	roleRepo port.TeamRoleRepository,
+	taskRepo port.TaskRepository,
+	commentRepo port.CommentRepository,
+	activityRepo port.ActivityRepository,
+	txManager port.TransactionManager,
+) *PermissionServiceImpl {
+	return &PermissionServiceImpl{
+		authz:        authz,
+		teamRepo:     teamRepo,
+		roleRepo:     roleRepo,
+		taskRepo:     taskRepo,
+		commentRepo:  commentRepo,
+		activityRepo: activityRepo,
+		txManager:    txManager,
+	}
+}
+
+func (s *PermissionServiceImpl) ListRoles(ctx context.Context, userID, teamID int64) ([]domain.CustomRole, error) {
+	if _, err := s.authz.Member(ctx, userID, teamID); err != nil {
+		return nil, err
+	}
+	return s.roleRepo.ListByTeam(ctx, teamID)
+}
+
+func (s *PermissionServiceImpl) CreateRole(ctx context.Context, userID, teamID int64, req domain.CustomRoleRequest) (*domain.CustomRole, error) {
+	if _, err := s.authz.Authorize(ctx, userID, teamID, domain.PermRoleManage); err != nil {
+		return nil, err
+	}
+	role, err := buildCustomRole(req)
+	if err != nil {
+		return nil, err
+	}
+
+	role.TeamID = teamID
+	var created *domain.CustomRole
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		id, err := s.roleRepo.Create(ctx, role)
+		if err != nil {
+			return err
+		}
+		if err := s.recordRoleEvent(ctx, teamID, userID, domain.ActivityCustomRoleCreated, role.Name); err != nil {
+			return err
+		}
+		created, err = s.roleRepo.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *PermissionServiceImpl) UpdateRole(ctx context.Context, userID, teamID, roleID int64, req domain.CustomRoleRequest) (*domain.CustomRole, error) {
+	if _, err := s.authz.Authorize(ctx, userID, teamID, domain.PermRoleManage); err != nil {
+		return nil, err
+	}
+	if _, err := s.loadRole(ctx, teamID, roleID); err != nil {
+		return nil, err
+	}
+	role, err := buildCustomRole(req)
+	if err != nil {
+		return nil, err
+	}
+
+	role.ID = roleID
+	var updated *domain.CustomRole
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.roleRepo.Update(ctx, role); err != nil {
+			return err
+		}
+		if err := s.recordRoleEvent(ctx, teamID, userID, domain.ActivityCustomRoleUpdated, role.Name); err != nil {
+			return err
+		}
+		updated, err = s.roleRepo.GetByID(ctx, roleID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *PermissionServiceImpl) DeleteRole(ctx context.Context, userID, teamID, roleID int64) error {
+	if _, err := s.authz.Authorize(ctx, userID, teamID, domain.PermRoleManage); err != nil {
+		return err
+	}
+	role, err := s.loadRole(ctx, teamID, roleID)
+	if err != nil {
+		return err
+	}
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.roleRepo.Delete(ctx, roleID); err != nil {
+			return err
+		}
+		return s.recordRoleEvent(ctx, teamID, userID, domain.ActivityCustomRoleDeleted, role.Name)
+	})
+}
+
+// recordRoleEvent adds a change to a team's custom roles to its activity
+// feed; details carry the role name.
+func (s *PermissionServiceImpl) recordRoleEvent(ctx context.Context, teamID, actorID int64, eventType domain.ActivityType, roleName string) error {
+	return s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+		TeamID:  teamID,
+		ActorID: actorID,
+		Type:    eventType,
+		Details: roleName,
+	})
+}
+
+// AssignRole gives a member a custom role, or takes it away when RoleID is
+// nil. Owners and admins keep their built-in permissions and cannot hold
+// one.
+func (s *PermissionServiceImpl) AssignRole(ctx context.Context, actorID, teamID, targetID int64, req domain.AssignCustomRoleRequest) error {
+	if _, err := s.authz.Authorize(ctx, actorID, teamID, domain.PermRoleManage); err != nil {
+		return err
+	}
+
+	target, err := s.teamRepo.GetMember(ctx, teamID, targetID)
+	if err != nil {
+		return err
+	}
+	if target == nil || !target.IsDirect() {
+		return apperror.NotFound("member not found")
+	}
+	if target.Role != domain.TeamRoleMember {
+		return apperror.New(http.StatusConflict, "custom roles can only be assigned to members")
+	}
+
+	details := ""
+	if req.RoleID != nil {
+		role, err := s.loadRole(ctx, teamID, *req.RoleID)
+		if err != nil {
+			return err
+		}
+		details = role.Name
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.roleRepo.Assign(ctx, teamID, targetID, req.RoleID); err != nil {
+			return err
+		}
+		return s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:       teamID,
+			ActorID:      actorID,
+			TargetUserID: &targetID,
+			Type:         domain.ActivityCustomRoleChanged,
+			Details:      details,
+		})
+	})
+}
+
+// SetGuestScope limits a guest to the listed tasks of the team, or lifts the
+// limit when TaskIDs is nil.
+func (s *PermissionServiceImpl) SetGuestScope(ctx context.Context, actorID, teamID, targetID int64, req domain.GuestScopeRequest) error {
+	if _, err := s.authz.Authorize(ctx, actorID, teamID, domain.PermMemberManage); err != nil {
+		return err
+	}
+
+	target, err := s.teamRepo.GetMember(ctx, teamID, targetID)
+	if err != nil {
+		return err
+	}
+	if target == nil || !target.IsDirect() {
+		return apperror.NotFound("member not found")
+	}
+	if !target.IsGuest() {
+		return apperror.New(http.StatusConflict, "only guests can be limited to tasks")
+	}
+	if len(req.TaskIDs) > maxGuestTasks {
+		return apperror.BadRequest(fmt.Sprintf("a guest can be limited to at most %d tasks", maxGuestTasks))
+	}
+	for _, taskID := range req.TaskIDs {
+		task, err := s.taskRepo.GetByID(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		if task.TeamID != teamID {
+			return apperror.BadRequest(fmt.Sprintf("task %d belongs to another team", taskID))
+		}
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.teamRepo.SetGuestTasks(ctx, teamID, targetID, req.TaskIDs)
+	})
+}
+
+// Describe reports the caller's permissions on a team, task or comment.
+// Authors may always delete their own tasks and comments, so those rights
+// are added on top of the role's.
+func (s *PermissionServiceImpl) Describe(ctx context.Context, userID int64, resource string, resourceID int64) (*domain.PermissionSummary, error) {
+	var task *domain.Task
+	var comment *domain.TaskComment
+	var err error
+	switch resource {
+	case "team":
+	case "task":
+		task, err = s.taskRepo.GetByID(ctx, resourceID)
+		if err != nil {
+			return nil, err
+		}
+	case "comment":
+		comment, err = s.commentRepo.GetByID(ctx, resourceID)
+		if err != nil {
+			return nil, err
+		}
+		task, err = s.taskRepo.GetByID(ctx, comment.TaskID)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, apperror.BadRequest("resource must be team, task or comment")
+	}
+
+	var member *domain.TeamMember
+	if task != nil {
+		member, err = s.authz.TaskMember(ctx, userID, task)
+	} else {
+		member, err = s.authz.Member(ctx, userID, resourceID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if comment != nil && comment.Internal && member.IsGuest() {
+		return nil, apperror.NotFound("comment not found")
+	}
+
+	var authored domain.Permission
+	switch {
+	case comment != nil:
+		if comment.UserID == userID {
+			authored = domain.PermCommentModerate
+		}
+	case task != nil:
+		if task.CreatorID == userID && !member.IsGuest() {
+			authored = domain.PermTaskDelete
+		}
+	}
+
+	perms := s.authz.Permissions(member)
+	if authored != "" && !s.authz.Can(member, authored) {
+		perms = append(perms, authored)
+	}
+	return &domain.PermissionSummary{
+		Resource:     resource,
+		ResourceID:   resourceID,
+		TeamID:       member.TeamID,
+		Role:         member.Role,
+		CustomRoleID: member.CustomRoleID,
+		Permissions:  perms,
+	}, nil
+}
+
+func (s *PermissionServiceImpl) loadRole(ctx context.Context, teamID, roleID int64) (*domain.CustomRole, error) {
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if role.TeamID != teamID {
+		return nil, apperror.NotFound("role not found")
+	}
+	return role, nil
+}
+
+// buildCustomRole validates the request and orders its permissions the way
+// they are reported.
+func buildCustomRole(req domain.CustomRoleRequest) (*domain.CustomRole, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxRoleNameLength {
+		return nil, apperror.BadRequest("role name must be 1 to 64 characters")
+	}
+
+	requested := make(map[domain.Permission]bool, len(req.Permissions))
+	for _, p := range req.Permissions {
+		if !grantablePermissions[p] {
+			return nil, apperror.BadRequest("permission " + string(p) + " cannot be granted by a custom role")
+		}
+		requested[p] = true
+	}
+	perms := []domain.Permission{}
+	for _, p := range allPermissions {
+		if requested[p] {
+			perms = append(perms, p)
+		}
+	}
+	return &domain.CustomRole{Name: name, Permissions: perms}, nil
+}
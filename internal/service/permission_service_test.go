@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newPermissionService() (
+	*PermissionServiceImpl, *mocks.TeamRepositoryMock, *mocks.TeamRoleRepositoryMock,
+	*mocks.TaskRepositoryMock, *mocks.ActivityRepositoryMock,
+) {
+	teamRepo := new(mocks.TeamRepositoryMock)
+	roleRepo := new(mocks.TeamRoleRepositoryMock)
+	taskRepo := new(mocks.TaskRepositoryMock)
+	activityRepo := new(mocks.ActivityRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil).Maybe()
+	svc := NewPermissionService(NewAuthorizer(teamRepo), teamRepo, roleRepo, taskRepo, new(mocks.CommentRepositoryMock), activityRepo, txManager)
+	return svc, teamRepo, roleRepo, taskRepo, activityRepo
+}
+
+func TestAuthorizer_CustomRoleReplacesDefaults(t *testing.T) {
+	authz := NewAuthorizer(new(mocks.TeamRepositoryMock))
+	perms := "comment.create,team.delete"
+	member := &domain.TeamMember{Role: domain.TeamRoleMember, CustomPermissions: &perms}
+
+	assert.True(t, authz.Can(member, domain.PermCommentCreate))
+	assert.False(t, authz.Can(member, domain.PermTaskCreate))
+	// Non-grantable permissions are ignored even if stored
+	assert.False(t, authz.Can(member, domain.PermTeamDelete))
+	assert.Equal(t, []domain.Permission{domain.PermCommentCreate}, authz.Permissions(member))
+}
+
+func TestAuthorizer_RoleDefaults(t *testing.T) {
+	authz := NewAuthorizer(new(mocks.TeamRepositoryMock))
+
+	assert.True(t, authz.Can(&domain.TeamMember{Role: domain.TeamRoleOwner}, domain.PermTeamDelete))
+	assert.False(t, authz.Can(&domain.TeamMember{Role: domain.TeamRoleAdmin}, domain.PermTeamDelete))
+	assert.True(t, authz.Can(&domain.TeamMember{Role: domain.TeamRoleAdmin}, domain.PermCommentModerate))
+	assert.False(t, authz.Can(&domain.TeamMember{Role: domain.TeamRoleMember}, domain.PermTaskDelete))
+	assert.Equal(t, []domain.Permission{domain.PermCommentCreate}, authz.Permissions(&domain.TeamMember{Role: domain.TeamRoleGuest}))
+}
+
+func TestAuthorizer_TaskMember_ScopedGuest(t *testing.T) {
+	teamRepo := new(mocks.TeamRepositoryMock)
+	authz := NewAuthorizer(teamRepo)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(3)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 3, Role: domain.TeamRoleGuest, TaskScoped: true,
+	}, nil)
+	teamRepo.On("IsTaskGranted", mock.Anything, int64(3), int64(7)).Return(true, nil)
+	teamRepo.On("IsTaskGranted", mock.Anything, int64(3), int64(8)).Return(false, nil)
+
+	_, err := authz.TaskMember(context.Background(), 3, &domain.Task{ID: 7, TeamID: 1})
+	assert.NoError(t, err)
+
+	_, err = authz.TaskMember(context.Background(), 3, &domain.Task{ID: 8, TeamID: 1})
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 404, appErr.Code)
+}
+
+func TestPermissionService_CreateRole(t *testing.T) {
+	svc, teamRepo, roleRepo, _, activityRepo := newPermissionService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	roleRepo.On("Create", mock.Anything, mock.MatchedBy(func(r *domain.CustomRole) bool {
+		return r.TeamID == 1 && r.Name == "Reviewer" &&
+			assert.ObjectsAreEqual([]domain.Permission{domain.PermTaskUpdate, domain.PermCommentCreate}, r.Permissions)
+	})).Return(int64(3), nil)
+	roleRepo.On("GetByID", mock.Anything, int64(3)).Return(&domain.CustomRole{ID: 3, TeamID: 1, Name: "Reviewer"}, nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.TeamID == 1 && e.ActorID == 1 && e.Type == domain.ActivityCustomRoleCreated && e.Details == "Reviewer"
+	})).Return(nil)
+
+	role, err := svc.CreateRole(context.Background(), 1, 1, domain.CustomRoleRequest{
+		Name:        " Reviewer ",
+		Permissions: []domain.Permission{domain.PermCommentCreate, domain.PermTaskUpdate, domain.PermTaskUpdate},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), role.ID)
+	roleRepo.AssertExpectations(t)
+	activityRepo.AssertExpectations(t)
+}
+
+func TestPermissionService_UpdateRole(t *testing.T) {
+	svc, teamRepo, roleRepo, _, activityRepo := newPermissionService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	roleRepo.On("GetByID", mock.Anything, int64(3)).Return(&domain.CustomRole{ID: 3, TeamID: 1, Name: "Reviewer"}, nil)
+	roleRepo.On("Update", mock.Anything, mock.MatchedBy(func(r *domain.CustomRole) bool {
+		return r.ID == 3 && r.Name == "Editor"
+	})).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.TeamID == 1 && e.ActorID == 1 && e.Type == domain.ActivityCustomRoleUpdated && e.Details == "Editor"
+	})).Return(nil)
+
+	_, err := svc.UpdateRole(context.Background(), 1, 1, 3, domain.CustomRoleRequest{
+		Name:        "Editor",
+		Permissions: []domain.Permission{domain.PermTaskUpdate},
+	})
+
+	assert.NoError(t, err)
+	roleRepo.AssertExpectations(t)
+	activityRepo.AssertExpectations(t)
+}
+
+func TestPermissionService_DeleteRole(t *testing.T) {
+	svc, teamRepo, roleRepo, _, activityRepo := newPermissionService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	roleRepo.On("GetByID", mock.Anything, int64(3)).Return(&domain.CustomRole{ID: 3, TeamID: 1, Name: "Reviewer"}, nil)
+	roleRepo.On("Delete", mock.Anything, int64(3)).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.TeamID == 1 && e.ActorID == 1 && e.Type == domain.ActivityCustomRoleDeleted && e.Details == "Reviewer"
+	})).Return(nil)
+
+	err := svc.DeleteRole(context.Background(), 1, 1, 3)
+
+	assert.NoError(t, err)
+	roleRepo.AssertExpectations(t)
+	activityRepo.AssertExpectations(t)
+}
+
+func TestPermissionService_CreateRole_NotGrantable(t *testing.T) {
+	svc, teamRepo, roleRepo, _, _ := newPermissionService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+
+	_, err := svc.CreateRole(context.Background(), 1, 1, domain.CustomRoleRequest{
+		Name:        "Boss",
+		Permissions: []domain.Permission{domain.PermMemberManage},
+	})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	roleRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestPermissionService_CreateRole_MemberForbidden(t *testing.T) {
+	svc, teamRepo, _, _, _ := newPermissionService()
+
+	stubTeamMember(teamRepo, 2, domain.TeamRoleMember)
+
+	_, err := svc.CreateRole(context.Background(), 2, 1, domain.CustomRoleRequest{Name: "Reviewer"})
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+}
+
+func TestPermissionService_AssignRole(t *testing.T) {
+	svc, teamRepo, roleRepo, _, activityRepo := newPermissionService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	stubTeamMember(teamRepo, 2, domain.TeamRoleMember)
+	roleID := int64(3)
+	roleRepo.On("GetByID", mock.Anything, roleID).Return(&domain.CustomRole{ID: 3, TeamID: 1, Name: "Reviewer"}, nil)
+	roleRepo.On("Assign", mock.Anything, int64(1), int64(2), &roleID).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.Type == domain.ActivityCustomRoleChanged && *e.TargetUserID == 2 && e.Details == "Reviewer"
+	})).Return(nil)
+
+	err := svc.AssignRole(context.Background(), 1, 1, 2, domain.AssignCustomRoleRequest{RoleID: &roleID})
+
+	assert.NoError(t, err)
+	roleRepo.AssertExpectations(t)
+	activityRepo.AssertExpectations(t)
+}
+
+func TestPermissionService_AssignRole_AdminTarget(t *testing.T) {
+	svc, teamRepo, roleRepo, _, _ := newPermissionService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	stubTeamMember(teamRepo, 2, domain.TeamRoleAdmin)
+	roleID := int64(3)
+
+	err := svc.AssignRole(context.Background(), 1, 1, 2, domain.AssignCustomRoleRequest{RoleID: &roleID})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 409, appErr.Code)
+	roleRepo.AssertNotCalled(t, "Assign", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPermissionService_SetGuestScope(t *testing.T) {
+	svc, teamRepo, _, taskRepo, _ := newPermissionService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	stubTeamMember(teamRepo, 3, domain.TeamRoleGuest)
+	taskRepo.On("GetByID", mock.Anything, int64(7)).Return(&domain.Task{ID: 7, TeamID: 1}, nil)
+	teamRepo.On("SetGuestTasks", mock.Anything, int64(1), int64(3), []int64{7}).Return(nil)
+
+	err := svc.SetGuestScope(context.Background(), 1, 1, 3, domain.GuestScopeRequest{TaskIDs: []int64{7}})
+
+	assert.NoError(t, err)
+	teamRepo.AssertExpectations(t)
+}
+
+func TestPermissionService_SetGuestScope_ForeignTask(t *testing.T) {
+	svc, teamRepo, _, taskRepo, _ := newPermissionService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	stubTeamMember(teamRepo, 3, domain.TeamRoleGuest)
+	taskRepo.On("GetByID", mock.Anything, int64(9)).Return(&domain.Task{ID: 9, TeamID: 2}, nil)
+
+	err := svc.SetGuestScope(context.Background(), 1, 1, 3, domain.GuestScopeRequest{TaskIDs: []int64{9}})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	teamRepo.AssertNotCalled(t, "SetGuestTasks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPermissionService_SetGuestScope_NotGuest(t *testing.T) {
+	svc, teamRepo, _, _, _ := newPermissionService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	stubTeamMember(teamRepo, 2, domain.TeamRoleMember)
+
+	err := svc.SetGuestScope(context.Background(), 1, 1, 2, domain.GuestScopeRequest{TaskIDs: []int64{}})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 409, appErr.Code)
+}
+
+func TestPermissionService_Describe_OwnTask(t *testing.T) {
+	svc, teamRepo, _, taskRepo, _ := newPermissionService()
+
+	stubTeamMember(teamRepo, 2, domain.TeamRoleMember)
+	taskRepo.On("GetByID", mock.Anything, int64(5)).Return(&domain.Task{ID: 5, TeamID: 1, CreatorID: 2}, nil)
+
+	summary, err := svc.Describe(context.Background(), 2, "task", 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), summary.TeamID)
+	assert.Equal(t, domain.TeamRoleMember, summary.Role)
+	assert.Contains(t, summary.Permissions, domain.PermTaskDelete)
+	assert.NotContains(t, summary.Permissions, domain.PermTeamInvite)
+}
+
+func TestPermissionService_Describe_UnknownResource(t *testing.T) {
+	svc, _, _, _, _ := newPermissionService()
+
+	_, err := svc.Describe(context.Background(), 1, "project", 1)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+// lastUsedResolution keeps last_used_at from being written on every request.
+const lastUsedResolution = time.Minute
+
+type PersonalTokenServiceImpl struct {
+	tokenRepo port.PersonalTokenRepository
+	authz     port.Authorizer
+}
+
+func NewPersonalTokenService(tokenRepo port.PersonalTokenRepository, authz port.Authorizer) *PersonalTokenServiceImpl {
+	return &PersonalTokenServiceImpl{tokenRepo: tokenRepo, authz: authz}
+}
+
+// Create issues a token and returns it in plain text; only its hash is kept.
+func (s *PersonalTokenServiceImpl) Create(ctx context.Context, claims domain.AccessClaims, req domain.CreatePersonalAccessTokenRequest) (*domain.CreatedPersonalAccessToken, error) {
+	if claims.ImpersonatorID != 0 {
+		return nil, apperror.Forbidden("not available while impersonating")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, apperror.BadRequest("name is required and must be at most 100 characters")
+	}
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, apperror.BadRequest("expires_at must be in the future")
+	}
+	if req.TeamID != nil {
+		if _, err := s.authz.Member(ctx, claims.UserID, *req.TeamID); err != nil {
+			return nil, err
+		}
+	}
+
+	plain, err := generatePersonalToken()
+	if err != nil {
+		return nil, apperror.Internal("generate token", err)
+	}
+	token := &domain.PersonalAccessToken{
+		UserID:    claims.UserID,
+		TeamID:    req.TeamID,
+		Name:      name,
+		TokenHash: signedtoken.Hash(plain),
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	id, err := s.tokenRepo.Create(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.tokenRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.CreatedPersonalAccessToken{Token: plain, PersonalAccessToken: *created}, nil
+}
+
+func (s *PersonalTokenServiceImpl) List(ctx context.Context, userID int64) ([]domain.PersonalAccessToken, error) {
+	return s.tokenRepo.ListByUser(ctx, userID)
+}
+
+// Revoke disables a token at once; it is looked up on every request.
+func (s *PersonalTokenServiceImpl) Revoke(ctx context.Context, userID, tokenID int64) error {
+	token, err := s.tokenRepo.GetByID(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	if token.UserID != userID {
+		return apperror.NotFound("personal access token not found")
+	}
+	if token.RevokedAt != nil {
+		return nil
+	}
+	return s.tokenRepo.Revoke(ctx, tokenID)
+}
+
+func (s *PersonalTokenServiceImpl) AuthenticatePersonalToken(ctx context.Context, plain string) (*domain.AccessClaims, error) {
+	token, err := s.tokenRepo.GetByHash(ctx, signedtoken.Hash(plain))
+	if err != nil {
+		if appErr, ok := apperror.IsAppError(err); ok && appErr.Code == http.StatusNotFound {
+			return nil, apperror.ErrUnauthorized
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if token.RevokedAt != nil || token.Expired(now) {
+		return nil, apperror.ErrUnauthorized
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		_ = s.tokenRepo.TouchLastUsed(ctx, token.ID)
+	}
+
+	claims := &domain.AccessClaims{
+		UserID:          token.UserID,
+		PersonalTokenID: token.ID,
+		Scopes:          token.Scopes,
+		TeamID:          token.TeamID,
+	}
+	if token.ExpiresAt != nil {
+		claims.ExpiresAt = *token.ExpiresAt
+	}
+	return claims, nil
+}
+
+func normalizeScopes(requested []domain.TokenScope) ([]domain.TokenScope, error) {
+	if len(requested) == 0 {
+		return nil, apperror.BadRequest("at least one scope is required")
+	}
+	seen := make(map[domain.TokenScope]bool, len(requested))
+	scopes := make([]domain.TokenScope, 0, len(requested))
+	for _, sc := range requested {
+		if !sc.IsValid() {
+			return nil, apperror.BadRequest("unknown scope: " + string(sc))
+		}
+		if !seen[sc] {
+			seen[sc] = true
+			scopes = append(scopes, sc)
+		}
+	}
+	return scopes, nil
+}
+
+func generatePersonalToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return domain.PersonalTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
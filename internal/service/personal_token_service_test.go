@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newPersonalTokenService() (*PersonalTokenServiceImpl, *mocks.PersonalTokenRepositoryMock, *mocks.TeamRepositoryMock) {
+	tokenRepo := new(mocks.PersonalTokenRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	return NewPersonalTokenService(tokenRepo, NewAuthorizer(teamRepo)), tokenRepo, teamRepo
+}
+
+func TestPersonalTokenService_Create_StoresHashOnly(t *testing.T) {
+	svc, tokenRepo, teamRepo := newPersonalTokenService()
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleMember)
+	var stored *domain.PersonalAccessToken
+	tokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.PersonalAccessToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*domain.PersonalAccessToken) }).
+		Return(int64(5), nil)
+	tokenRepo.On("GetByID", mock.Anything, int64(5)).Return(&domain.PersonalAccessToken{
+		ID: 5, UserID: 1, Name: "ci", Scopes: []domain.TokenScope{domain.ScopeTasksWrite},
+	}, nil)
+
+	teamID := int64(1)
+	result, err := svc.Create(context.Background(), domain.AccessClaims{UserID: 1}, domain.CreatePersonalAccessTokenRequest{
+		Name:   " ci ",
+		Scopes: []domain.TokenScope{domain.ScopeTasksWrite, domain.ScopeTasksWrite},
+		TeamID: &teamID,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(result.Token, domain.PersonalTokenPrefix))
+	assert.Equal(t, signedtoken.Hash(result.Token), stored.TokenHash)
+	assert.Equal(t, "ci", stored.Name)
+	assert.Equal(t, []domain.TokenScope{domain.ScopeTasksWrite}, stored.Scopes)
+	assert.Equal(t, int64(5), result.ID)
+}
+
+func TestPersonalTokenService_Create_Validation(t *testing.T) {
+	svc, _, teamRepo := newPersonalTokenService()
+	teamRepo.On("GetMember", mock.Anything, int64(2), int64(1)).Return(nil, nil)
+
+	past := time.Now().Add(-time.Hour)
+	otherTeam := int64(2)
+	cases := map[string]struct {
+		claims domain.AccessClaims
+		req    domain.CreatePersonalAccessTokenRequest
+		code   int
+	}{
+		"no scopes":     {domain.AccessClaims{UserID: 1}, domain.CreatePersonalAccessTokenRequest{Name: "ci"}, 400},
+		"unknown scope": {domain.AccessClaims{UserID: 1}, domain.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []domain.TokenScope{"root"}}, 400},
+		"no name":       {domain.AccessClaims{UserID: 1}, domain.CreatePersonalAccessTokenRequest{Scopes: []domain.TokenScope{domain.ScopeReadOnly}}, 400},
+		"expired":       {domain.AccessClaims{UserID: 1}, domain.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []domain.TokenScope{domain.ScopeReadOnly}, ExpiresAt: &past}, 400},
+		"not a member":  {domain.AccessClaims{UserID: 1}, domain.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []domain.TokenScope{domain.ScopeReadOnly}, TeamID: &otherTeam}, 403},
+		"impersonating": {domain.AccessClaims{UserID: 1, ImpersonatorID: 9}, domain.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []domain.TokenScope{domain.ScopeReadOnly}}, 403},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			result, err := svc.Create(context.Background(), tc.claims, tc.req)
+			assert.Nil(t, result)
+			appErr, ok := apperror.IsAppError(err)
+			assert.True(t, ok)
+			assert.Equal(t, tc.code, appErr.Code)
+		})
+	}
+}
+
+func TestPersonalTokenService_Authenticate(t *testing.T) {
+	svc, tokenRepo, _ := newPersonalTokenService()
+
+	teamID := int64(3)
+	revokedAt := time.Now()
+	expiredAt := time.Now().Add(-time.Minute)
+	tokenRepo.On("GetByHash", mock.Anything, signedtoken.Hash("ttn_pat_live")).Return(&domain.PersonalAccessToken{
+		ID: 1, UserID: 7, TeamID: &teamID, Scopes: []domain.TokenScope{domain.ScopeReadOnly},
+	}, nil)
+	tokenRepo.On("GetByHash", mock.Anything, signedtoken.Hash("ttn_pat_revoked")).Return(&domain.PersonalAccessToken{ID: 2, RevokedAt: &revokedAt}, nil)
+	tokenRepo.On("GetByHash", mock.Anything, signedtoken.Hash("ttn_pat_expired")).Return(&domain.PersonalAccessToken{ID: 3, ExpiresAt: &expiredAt}, nil)
+	tokenRepo.On("GetByHash", mock.Anything, signedtoken.Hash("ttn_pat_unknown")).Return(nil, apperror.NotFound("personal access token not found"))
+	tokenRepo.On("TouchLastUsed", mock.Anything, int64(1)).Return(nil).Once()
+
+	claims, err := svc.AuthenticatePersonalToken(context.Background(), "ttn_pat_live")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), claims.UserID)
+	assert.True(t, claims.IsPersonalToken())
+	assert.Equal(t, &teamID, claims.TeamID)
+	assert.False(t, claims.HasScope(domain.ScopeTasksWrite))
+
+	for _, token := range []string{"ttn_pat_revoked", "ttn_pat_expired", "ttn_pat_unknown"} {
+		_, err := svc.AuthenticatePersonalToken(context.Background(), token)
+		assert.Equal(t, apperror.ErrUnauthorized, err, token)
+	}
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestPersonalTokenService_Revoke_OtherUsersToken(t *testing.T) {
+	svc, tokenRepo, _ := newPersonalTokenService()
+	tokenRepo.On("GetByID", mock.Anything, int64(5)).Return(&domain.PersonalAccessToken{ID: 5, UserID: 2}, nil)
+
+	err := svc.Revoke(context.Background(), 1, 5)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 404, appErr.Code)
+	tokenRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+}
+
+func TestAuthorizer_Member_TeamRestriction(t *testing.T) {
+	teamRepo := new(mocks.TeamRepositoryMock)
+	authz := NewAuthorizer(teamRepo)
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+
+	ctx := requestctx.WithTeamRestriction(context.Background(), 2)
+	_, err := authz.Member(ctx, 1, 1)
+
+	assert.Equal(t, apperror.ErrNotTeamMember, err)
+	teamRepo.AssertNotCalled(t, "GetMember", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+// emojiShortcode accepts shortcodes such as "+1", "tada" or "thumbs_up".
+var emojiShortcode = regexp.MustCompile(`^[a-z0-9_+-]{1,32}$`)
+
+type ReactionServiceImpl struct {
+	reactionRepo port.ReactionRepository
+	taskRepo     port.TaskRepository
+	authz        port.Authorizer
+	commentRepo  port.CommentRepository
+	txManager    port.TransactionManager
+}
+
+func NewReactionService(
+	reactionRepo port.ReactionRepository,
+	taskRepo port.TaskRepository,
+	authz port.Authorizer,
+	commentRepo port.CommentRepository,
+	txManager port.TransactionManager,
+) *ReactionServiceImpl {
+	return &ReactionServiceImpl{
+		reactionRepo: reactionRepo,
+		taskRepo:     taskRepo,
+		authz:        authz,
+		commentRepo:  commentRepo,
+		txManager:    txManager,
+	}
+}
+
+func (s *ReactionServiceImpl) ToggleTaskReaction(ctx context.Context, userID, taskID int64, req domain.ToggleReactionRequest) (*domain.ReactionToggleResult, error) {
+	if !emojiShortcode.MatchString(req.Emoji) {
+		return nil, apperror.BadRequest("invalid emoji shortcode")
+	}
+	member, err := s.checkTaskAccess(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(member); err != nil {
+		return nil, err
+	}
+	return s.toggle(ctx, domain.ReactionTargetTask, taskID, userID, req.Emoji)
+}
+
+func (s *ReactionServiceImpl) ToggleCommentReaction(ctx context.Context, userID, taskID, commentID int64, req domain.ToggleReactionRequest) (*domain.ReactionToggleResult, error) {
+	if !emojiShortcode.MatchString(req.Emoji) {
+		return nil, apperror.BadRequest("invalid emoji shortcode")
+	}
+	member, err := s.checkTaskAccess(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(member); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.TaskID != taskID || comment.IsDeleted() || (comment.Internal && member.IsGuest()) {
+		return nil, apperror.NotFound("comment not found")
+	}
+	return s.toggle(ctx, domain.ReactionTargetComment, commentID, userID, req.Emoji)
+}
+
+func (s *ReactionServiceImpl) ListTaskReactions(ctx context.Context, userID, taskID int64) ([]domain.ReactionSummary, error) {
+	if _, err := s.checkTaskAccess(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+
+	summaries, err := s.reactionRepo.Summaries(ctx, domain.ReactionTargetTask, []int64{taskID}, userID)
+	if err != nil {
+		return nil, err
+	}
+	if summaries[taskID] == nil {
+		return []domain.ReactionSummary{}, nil
+	}
+	return summaries[taskID], nil
+}
+
+func (s *ReactionServiceImpl) toggle(ctx context.Context, target domain.ReactionTarget, targetID, userID int64, emoji string) (*domain.ReactionToggleResult, error) {
+	result := &domain.ReactionToggleResult{Emoji: emoji}
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		removed, err := s.reactionRepo.Remove(ctx, target, targetID, userID, emoji)
+		if err != nil || removed {
+			return err
+		}
+		result.Reacted = true
+		return s.reactionRepo.Add(ctx, target, targetID, userID, emoji)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := s.reactionRepo.Summaries(ctx, target, []int64{targetID}, userID)
+	if err != nil {
+		return nil, err
+	}
+	result.Reactions = summaries[targetID]
+	if result.Reactions == nil {
+		result.Reactions = []domain.ReactionSummary{}
+	}
+	return result, nil
+}
+
+func (s *ReactionServiceImpl) checkTaskAccess(ctx context.Context, userID, taskID int64) (*domain.TeamMember, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authz.TaskMember(ctx, userID, task)
+}
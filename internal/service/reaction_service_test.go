@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newReactionServiceDeps() (
+	*ReactionServiceImpl,
+	*mocks.ReactionRepositoryMock,
+	*mocks.CommentRepositoryMock,
+	*mocks.TransactionManagerMock,
+) {
+	reactionRepo := new(mocks.ReactionRepositoryMock)
+	taskRepo := new(mocks.TaskRepositoryMock)
+	teamRepo := new(mocks.TeamRepositoryMock)
+	commentRepo := new(mocks.CommentRepositoryMock)
+	txManager := new(mocks.TransactionManagerMock)
+	svc := NewReactionService(reactionRepo, taskRepo, NewAuthorizer(teamRepo), commentRepo, txManager)
+
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{ID: 1, TeamID: 1}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
+	}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(2)).Return(nil, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	return svc, reactionRepo, commentRepo, txManager
+}
+
+func TestReactionService_ToggleTaskReaction_Adds(t *testing.T) {
+	svc, reactionRepo, _, _ := newReactionServiceDeps()
+	reactionRepo.On("Remove", mock.Anything, domain.ReactionTargetTask, int64(1), int64(1), "+1").Return(false, nil)
+	reactionRepo.On("Add", mock.Anything, domain.ReactionTargetTask, int64(1), int64(1), "+1").Return(nil)
+	reactionRepo.On("Summaries", mock.Anything, domain.ReactionTargetTask, []int64{1}, int64(1)).
+		Return(map[int64][]domain.ReactionSummary{1: {{Emoji: "+1", Count: 1, ReactedByMe: true}}}, nil)
+
+	result, err := svc.ToggleTaskReaction(context.Background(), 1, 1, domain.ToggleReactionRequest{Emoji: "+1"})
+
+	assert.NoError(t, err)
+	assert.True(t, result.Reacted)
+	assert.Len(t, result.Reactions, 1)
+	reactionRepo.AssertExpectations(t)
+}
+
+func TestReactionService_ToggleCommentReaction_Removes(t *testing.T) {
+	svc, reactionRepo, commentRepo, _ := newReactionServiceDeps()
+	commentRepo.On("GetByID", mock.Anything, int64(5)).Return(&domain.TaskComment{ID: 5, TaskID: 1}, nil)
+	reactionRepo.On("Remove", mock.Anything, domain.ReactionTargetComment, int64(5), int64(1), "tada").Return(true, nil)
+	reactionRepo.On("Summaries", mock.Anything, domain.ReactionTargetComment, []int64{5}, int64(1)).
+		Return(map[int64][]domain.ReactionSummary{}, nil)
+
+	result, err := svc.ToggleCommentReaction(context.Background(), 1, 1, 5, domain.ToggleReactionRequest{Emoji: "tada"})
+
+	assert.NoError(t, err)
+	assert.False(t, result.Reacted)
+	assert.Empty(t, result.Reactions)
+	reactionRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReactionService_ToggleCommentReaction_DeletedComment(t *testing.T) {
+	svc, _, commentRepo, _ := newReactionServiceDeps()
+	deletedAt := time.Now()
+	commentRepo.On("GetByID", mock.Anything, int64(5)).Return(&domain.TaskComment{ID: 5, TaskID: 1, DeletedAt: &deletedAt}, nil)
+
+	result, err := svc.ToggleCommentReaction(context.Background(), 1, 1, 5, domain.ToggleReactionRequest{Emoji: "tada"})
+
+	assert.Nil(t, result)
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 404, appErr.Code)
+}
+
+func TestReactionService_ToggleTaskReaction_InvalidEmoji(t *testing.T) {
+	svc, _, _, _ := newReactionServiceDeps()
+
+	_, err := svc.ToggleTaskReaction(context.Background(), 1, 1, domain.ToggleReactionRequest{Emoji: "<script>"})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+}
+
+func TestReactionService_ListTaskReactions_NotMember(t *testing.T) {
+	svc, _, _, _ := newReactionServiceDeps()
+
+	_, err := svc.ListTaskReactions(context.Background(), 2, 1)
+
+	assert.Equal(t, apperror.ErrNotTeamMember, err)
+}
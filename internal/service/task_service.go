@@ -4,40 +4,52 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
 	"github.com/shalfey088/team-task-nexus/internal/port"
 )
 
+// maxImportTasks caps one import request; larger imports are split by the
+// client.
+const maxImportTasks = 100
+
 type TaskServiceImpl struct {
 	taskRepo    port.TaskRepository
-	teamRepo    port.TeamRepository
+	authz       port.Authorizer
 	userRepo    port.UserRepository
 	historyRepo port.TaskHistoryRepository
 	taskCache   port.TaskCache
 	txManager   port.TransactionManager
 	notifSvc    port.NotificationService
+	mentionSvc  port.MentionService
+	attachSvc   port.AttachmentService
 }
 
 func NewTaskService(
 	taskRepo port.TaskRepository,
-	teamRepo port.TeamRepository,
+	authz port.Authorizer,
 	userRepo port.UserRepository,
 	historyRepo port.TaskHistoryRepository,
 	taskCache port.TaskCache,
 	txManager port.TransactionManager,
 	notifSvc port.NotificationService,
+	mentionSvc port.MentionService,
+	attachSvc port.AttachmentService,
 ) *TaskServiceImpl {
 	return &TaskServiceImpl{
 		taskRepo:    taskRepo,
-		teamRepo:    teamRepo,
+		authz:       authz,
 		userRepo:    userRepo,
 		historyRepo: historyRepo,
 		taskCache:   taskCache,
 		txManager:   txManager,
 		notifSvc:    notifSvc,
+		mentionSvc:  mentionSvc,
+		attachSvc:   attachSvc,
 	}
 }
 
@@ -49,12 +61,12 @@ func (s *TaskServiceImpl) Create(ctx context.Context, userID int64, req domain.C
 		return nil, apperror.BadRequest("team_id is required")
 	}
 
-	member, err := s.teamRepo.GetMember(ctx, req.TeamID, userID)
+	member, err := s.authz.Authorize(ctx, userID, req.TeamID, domain.PermTaskCreate)
 	if err != nil {
 		return nil, err
 	}
-	if member == nil {
-		return nil, apperror.ErrNotTeamMember
+	if err := checkWritable(member); err != nil {
+		return nil, err
 	}
 
 	task := &domain.Task{
@@ -80,18 +92,36 @@ func (s *TaskServiceImpl) Create(ctx context.Context, userID int64, req domain.C
 		task.DueDate = sql.NullTime{Time: t, Valid: true}
 	}
 
-	id, err := s.taskRepo.Create(ctx, task)
+	handles := parseMentions(task.Description)
+	var mentions *domain.MentionResult
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		id, err := s.taskRepo.Create(ctx, task)
+		if err != nil {
+			return err
+		}
+		task.ID = id
+		if len(handles) == 0 {
+			return nil
+		}
+		mentions, err = s.mentionSvc.Record(ctx, domain.MentionSource{AuthorID: userID, Task: task}, handles)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	_ = s.taskCache.InvalidateTeam(ctx, req.TeamID)
 
-	task, err = s.taskRepo.GetByID(ctx, id)
+	task, err = s.taskRepo.GetByID(ctx, task.ID)
 	if err != nil {
 		return nil, err
 	}
 
+	if mentions != nil {
+		task.UnresolvedMentions = mentions.Unresolved
+		s.mentionSvc.Notify(ctx, domain.MentionSource{AuthorID: userID, Task: task}, mentions.Mentioned)
+	}
+
 	if task.AssigneeID.Valid {
 		assignee, aErr := s.userRepo.GetByID(ctx, task.AssigneeID.Int64)
 		if aErr == nil {
@@ -102,60 +132,124 @@ func (s *TaskServiceImpl) Create(ctx context.Context, userID int64, req domain.C
 	return task, nil
 }
 
+// Import creates all tasks or none. The change source is left to the caller,
+// so history written here carries whatever the request context says.
+func (s *TaskServiceImpl) Import(ctx context.Context, userID int64, req domain.ImportTasksRequest) (*domain.ImportTasksResponse, error) {
+	if len(req.Tasks) == 0 {
+		return nil, apperror.BadRequest("tasks are required")
+	}
+	if len(req.Tasks) > maxImportTasks {
+		return nil, apperror.BadRequest(fmt.Sprintf("at most %d tasks can be imported at once", maxImportTasks))
+	}
+
+	result := &domain.ImportTasksResponse{Tasks: make([]domain.Task, 0, len(req.Tasks))}
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		for _, item := range req.Tasks {
+			task, err := s.Create(ctx, userID, domain.CreateTaskRequest{
+				Title:       item.Title,
+				Description: item.Description,
+				Priority:    item.Priority,
+				TeamID:      req.TeamID,
+				AssigneeID:  item.AssigneeID,
+				DueDate:     item.DueDate,
+			})
+			if err != nil {
+				return err
+			}
+			if item.Status != "" && item.Status != string(task.Status) {
+				status := item.Status
+				if task, err = s.Update(ctx, userID, task.ID, domain.UpdateTaskRequest{Status: &status}); err != nil {
+					return err
+				}
+			}
+			result.Tasks = append(result.Tasks, *task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (s *TaskServiceImpl) Update(ctx context.Context, userID, taskID int64, req domain.UpdateTaskRequest) (*domain.Task, error) {
 	task, err := s.taskRepo.GetByID(ctx, taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	member, err := s.teamRepo.GetMember(ctx, task.TeamID, userID)
+	member, err := s.authz.Authorize(ctx, userID, task.TeamID, domain.PermTaskUpdate)
 	if err != nil {
 		return nil, err
 	}
-	if member == nil {
-		return nil, apperror.ErrNotTeamMember
+	if err := checkWritable(member); err != nil {
+		return nil, err
 	}
 
-	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
-		if req.Title != nil && *req.Title != task.Title {
-			s.recordHistory(ctx, taskID, userID, "title", task.Title, *req.Title)
-			task.Title = *req.Title
-		}
-		if req.Description != nil && *req.Description != task.Description {
-			s.recordHistory(ctx, taskID, userID, "description", task.Description, *req.Description)
-			task.Description = *req.Description
-		}
-		if req.Status != nil && *req.Status != string(task.Status) {
-			s.recordHistory(ctx, taskID, userID, "status", string(task.Status), *req.Status)
-			task.Status = domain.TaskStatus(*req.Status)
-		}
-		if req.Priority != nil && domain.TaskPriority(*req.Priority) != task.Priority {
-			s.recordHistory(ctx, taskID, userID, "priority", fmt.Sprintf("%d", task.Priority), fmt.Sprintf("%d", *req.Priority))
-			task.Priority = domain.TaskPriority(*req.Priority)
-		}
-		if req.AssigneeID != nil {
-			oldVal := "unassigned"
-			if task.AssigneeID.Valid {
-				oldVal = fmt.Sprintf("%d", task.AssigneeID.Int64)
-			}
-			newVal := fmt.Sprintf("%d", *req.AssigneeID)
-			s.recordHistory(ctx, taskID, userID, "assignee_id", oldVal, newVal)
-			task.AssigneeID = sql.NullInt64{Int64: *req.AssigneeID, Valid: true}
-		}
-		if req.DueDate != nil {
-			oldVal := "none"
-			if task.DueDate.Valid {
-				oldVal = task.DueDate.Time.Format("2006-01-02")
-			}
-			t, err := time.Parse("2006-01-02", *req.DueDate)
-			if err != nil {
-				return apperror.BadRequest("invalid due_date format, use YYYY-MM-DD")
-			}
-			s.recordHistory(ctx, taskID, userID, "due_date", oldVal, *req.DueDate)
+	var changes []domain.TaskHistory
+	var handles []string
+	if req.Title != nil && *req.Title != task.Title {
+		changes = append(changes, historyEntry("title", domain.HistoryValueString, task.Title, *req.Title))
+		task.Title = *req.Title
+	}
+	if req.Description != nil && *req.Description != task.Description {
+		changes = append(changes, historyEntry("description", domain.HistoryValueText, task.Description, *req.Description))
+		handles = newMentions(*req.Description, task.Description)
+		task.Description = *req.Description
+	}
+	if req.Status != nil && *req.Status != string(task.Status) {
+		changes = append(changes, historyEntry("status", domain.HistoryValueStatus, string(task.Status), *req.Status))
+		task.Status = domain.TaskStatus(*req.Status)
+	}
+	if req.Priority != nil && domain.TaskPriority(*req.Priority) != task.Priority {
+		changes = append(changes, historyEntry("priority", domain.HistoryValuePriority,
+			strconv.Itoa(int(task.Priority)), strconv.Itoa(*req.Priority)))
+		task.Priority = domain.TaskPriority(*req.Priority)
+	}
+	if req.AssigneeID != nil && (!task.AssigneeID.Valid || task.AssigneeID.Int64 != *req.AssigneeID) {
+		oldVal := ""
+		if task.AssigneeID.Valid {
+			oldVal = strconv.FormatInt(task.AssigneeID.Int64, 10)
+		}
+		changes = append(changes, historyEntry("assignee_id", domain.HistoryValueUser,
+			oldVal, strconv.FormatInt(*req.AssigneeID, 10)))
+		task.AssigneeID = sql.NullInt64{Int64: *req.AssigneeID, Valid: true}
+	}
+	if req.DueDate != nil {
+		t, err := time.Parse("2006-01-02", *req.DueDate)
+		if err != nil {
+			return nil, apperror.BadRequest("invalid due_date format, use YYYY-MM-DD")
+		}
+		oldVal := ""
+		if task.DueDate.Valid {
+			oldVal = task.DueDate.Time.Format("2006-01-02")
+		}
+		if oldVal != *req.DueDate {
+			changes = append(changes, historyEntry("due_date", domain.HistoryValueDate, oldVal, *req.DueDate))
 			task.DueDate = sql.NullTime{Time: t, Valid: true}
 		}
+	}
 
-		return s.taskRepo.Update(ctx, task)
+	if len(changes) == 0 {
+		return task, nil
+	}
+
+	var mentions *domain.MentionResult
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.taskRepo.Update(ctx, task); err != nil {
+			return err
+		}
+		_, err := s.historyRepo.CreateChangeSet(ctx, &domain.TaskChangeSet{
+			TaskID:    taskID,
+			UserID:    userID,
+			RequestID: requestctx.RequestID(ctx),
+			Source:    requestctx.Source(ctx),
+		}, changes)
+		if err != nil || len(handles) == 0 {
+			return err
+		}
+		mentions, err = s.mentionSvc.Record(ctx, domain.MentionSource{AuthorID: userID, Task: task}, handles)
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -163,33 +257,85 @@ func (s *TaskServiceImpl) Update(ctx context.Context, userID, taskID int64, req
 
 	_ = s.taskCache.InvalidateTeam(ctx, task.TeamID)
 
-	return s.taskRepo.GetByID(ctx, taskID)
+	task, err = s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mentions != nil {
+		task.UnresolvedMentions = mentions.Unresolved
+		s.mentionSvc.Notify(ctx, domain.MentionSource{AuthorID: userID, Task: task}, mentions.Mentioned)
+	}
+	return task, nil
 }
 
-func (s *TaskServiceImpl) recordHistory(ctx context.Context, taskID, userID int64, field, oldVal, newVal string) {
-	_ = s.historyRepo.Create(ctx, &domain.TaskHistory{
-		TaskID:   taskID,
-		UserID:   userID,
-		Field:    field,
-		OldValue: oldVal,
-		NewValue: newVal,
-	})
+func (s *TaskServiceImpl) Delete(ctx context.Context, userID, taskID int64) error {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.authz.Member(ctx, userID, task.TeamID)
+	if err != nil {
+		return err
+	}
+	if err := checkWritable(member); err != nil {
+		return err
+	}
+	// Guests may not delete tasks, even ones they created before losing
+	// their member role.
+	isCreator := task.CreatorID == userID && !member.IsGuest()
+	if !isCreator && !s.authz.Can(member, domain.PermTaskDelete) {
+		return apperror.ErrInsufficientRole
+	}
+
+	if err := s.attachSvc.DeleteForTask(ctx, taskID); err != nil {
+		return err
+	}
+	if err := s.taskRepo.Delete(ctx, taskID); err != nil {
+		return err
+	}
+
+	_ = s.taskCache.InvalidateTeam(ctx, task.TeamID)
+	return nil
 }
 
+func historyEntry(field string, valueType domain.HistoryValueType, oldVal, newVal string) domain.TaskHistory {
+	return domain.TaskHistory{
+		Field:     field,
+		ValueType: valueType,
+		OldValue:  oldVal,
+		NewValue:  newVal,
+	}
+}
+
+// List without a team covers every team the caller can read, honouring
+// guest scopes and token restrictions the same way a team list does.
 func (s *TaskServiceImpl) List(ctx context.Context, userID int64, filter domain.TaskFilter) (*domain.TaskListResponse, error) {
 	if filter.TeamID > 0 {
-		member, err := s.teamRepo.GetMember(ctx, filter.TeamID, userID)
+		member, err := s.authz.Member(ctx, userID, filter.TeamID)
 		if err != nil {
 			return nil, err
 		}
-		if member == nil {
-			return nil, apperror.ErrNotTeamMember
+		if member.TaskScoped {
+			filter.GuestID = userID
 		}
+	} else {
+		access, err := s.authz.Access(ctx, userID, nil)
+		if err != nil {
+			return nil, err
+		}
+		filter.Access = access
 	}
 
-	cached, err := s.taskCache.GetTaskList(ctx, filter)
-	if err == nil && cached != nil {
-		return cached, nil
+	// Lists of scoped guests and cross-team lists differ per user and
+	// bypass the team cache.
+	cacheable := filter.GuestID == 0 && filter.Access == nil
+	if cacheable {
+		cached, err := s.taskCache.GetTaskList(ctx, filter)
+		if err == nil && cached != nil {
+			return cached, nil
+		}
 	}
 
 	tasks, total, err := s.taskRepo.List(ctx, filter)
@@ -215,28 +361,131 @@ func (s *TaskServiceImpl) List(ctx context.Context, userID int64, filter domain.
 		TotalPages: totalPages,
 	}
 
-	_ = s.taskCache.SetTaskList(ctx, filter, response)
+	if cacheable {
+		_ = s.taskCache.SetTaskList(ctx, filter, response)
+	}
 
 	return response, nil
 }
 
-func (s *TaskServiceImpl) GetHistory(ctx context.Context, userID, taskID int64) ([]domain.TaskHistory, error) {
+func (s *TaskServiceImpl) GetHistory(ctx context.Context, userID, taskID int64, filter domain.HistoryFilter) (*domain.TaskHistoryResponse, error) {
 	task, err := s.taskRepo.GetByID(ctx, taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	member, err := s.teamRepo.GetMember(ctx, task.TeamID, userID)
+	if _, err := s.authz.TaskMember(ctx, userID, task); err != nil {
+		return nil, err
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = 20
+	}
+	if filter.PageSize > 100 {
+		filter.PageSize = 100
+	}
+
+	sets, total, err := s.historyRepo.ListChangeSets(ctx, taskID, filter)
 	if err != nil {
 		return nil, err
 	}
-	if member == nil {
-		return nil, apperror.ErrNotTeamMember
+
+	setIDs := make([]int64, 0, len(sets))
+	for _, set := range sets {
+		setIDs = append(setIDs, set.ID)
+	}
+	entries, err := s.historyRepo.ListByChangeSetIDs(ctx, setIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.historyRepo.ListByTaskID(ctx, taskID)
+	names, err := s.resolveHistoryUsers(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	bySet := make(map[int64][]domain.TaskFieldChange, len(sets))
+	for _, e := range entries {
+		bySet[e.ChangeSetID] = append(bySet[e.ChangeSetID], domain.TaskFieldChange{
+			Field:    e.Field,
+			Type:     e.ValueType,
+			OldValue: decodeHistoryValue(e.ValueType, e.OldValue, names),
+			NewValue: decodeHistoryValue(e.ValueType, e.NewValue, names),
+		})
+	}
+
+	if sets == nil {
+		sets = []domain.TaskChangeSet{}
+	}
+	for i := range sets {
+		sets[i].Changes = bySet[sets[i].ID]
+		if sets[i].Changes == nil {
+			sets[i].Changes = []domain.TaskFieldChange{}
+		}
+	}
+
+	return &domain.TaskHistoryResponse{
+		ChangeSets: sets,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: (total + filter.PageSize - 1) / filter.PageSize,
+	}, nil
+}
+
+func (s *TaskServiceImpl) resolveHistoryUsers(ctx context.Context, entries []domain.TaskHistory) (map[int64]string, error) {
+	seen := make(map[int64]bool)
+	var ids []int64
+	for _, e := range entries {
+		if e.ValueType != domain.HistoryValueUser {
+			continue
+		}
+		for _, raw := range []string{e.OldValue, e.NewValue} {
+			id, err := strconv.ParseInt(raw, 10, 64)
+			if err == nil && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	names := make(map[int64]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
+	}
+	users, err := s.userRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		names[u.ID] = u.FullName
+	}
+	return names, nil
 }
 
-func (s *TaskServiceImpl) GetOrphanedAssignees(ctx context.Context) ([]domain.OrphanedAssignee, error) {
-	return s.taskRepo.GetOrphanedAssignees(ctx)
+func decodeHistoryValue(valueType domain.HistoryValueType, raw string, names map[int64]string) interface{} {
+	switch valueType {
+	case domain.HistoryValueUser:
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil
+		}
+		return domain.HistoryUserRef{ID: id, FullName: names[id]}
+	case domain.HistoryValuePriority:
+		p, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil
+		}
+		return p
+	case domain.HistoryValueDate:
+		if _, err := time.Parse("2006-01-02", raw); err != nil {
+			return nil
+		}
+		return raw
+	default:
+		return raw
+	}
 }
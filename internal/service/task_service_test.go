@@ -8,6 +8,7 @@ import (
 
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
 	"github.com/shalfey088/team-task-nexus/test/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -21,6 +22,8 @@ func newTaskServiceDeps() (
 	*mocks.TaskCacheMock,
 	*mocks.TransactionManagerMock,
 	*mocks.NotificationServiceMock,
+	*mocks.MentionServiceMock,
+	*mocks.AttachmentServiceMock,
 ) {
 	return new(mocks.TaskRepositoryMock),
 		new(mocks.TeamRepositoryMock),
@@ -28,16 +31,19 @@ func newTaskServiceDeps() (
 		new(mocks.TaskHistoryRepositoryMock),
 		new(mocks.TaskCacheMock),
 		new(mocks.TransactionManagerMock),
-		new(mocks.NotificationServiceMock)
+		new(mocks.NotificationServiceMock),
+		new(mocks.MentionServiceMock),
+		new(mocks.AttachmentServiceMock)
 }
 
 func TestTaskService_Create_Success(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
 		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner,
 	}, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(int64(1), nil)
 	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
 	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{
@@ -56,8 +62,8 @@ func TestTaskService_Create_Success(t *testing.T) {
 }
 
 func TestTaskService_Create_EmptyTitle(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	result, err := svc.Create(context.Background(), 1, domain.CreateTaskRequest{
 		Title:  "",
@@ -69,8 +75,8 @@ func TestTaskService_Create_EmptyTitle(t *testing.T) {
 }
 
 func TestTaskService_Create_NotTeamMember(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(99)).Return(nil, nil)
 
@@ -84,14 +90,44 @@ func TestTaskService_Create_NotTeamMember(t *testing.T) {
 	assert.Equal(t, apperror.ErrNotTeamMember, err)
 }
 
+func TestTaskService_Create_CustomRoleWithoutPermission(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	perms := "comment.create"
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(2)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 2, Role: domain.TeamRoleMember, CustomPermissions: &perms,
+	}, nil)
+
+	_, err := svc.Create(context.Background(), 2, domain.CreateTaskRequest{Title: "Test Task", TeamID: 1})
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_Create_GuestForbidden(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(3)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 3, Role: domain.TeamRoleGuest,
+	}, nil)
+
+	_, err := svc.Create(context.Background(), 3, domain.CreateTaskRequest{Title: "Test Task", TeamID: 1})
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestTaskService_Create_WithAssignee(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	assigneeID := int64(2)
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
 		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner,
 	}, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(int64(1), nil)
 	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
 	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{
@@ -114,9 +150,51 @@ func TestTaskService_Create_WithAssignee(t *testing.T) {
 	notifSvc.AssertExpectations(t)
 }
 
+func TestTaskService_Import_RecordsSourceFromContext(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleMember)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(int64(7), nil).Once()
+	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(int64(8), nil).Once()
+	taskRepo.On("GetByID", mock.Anything, int64(7)).Return(&domain.Task{ID: 7, TeamID: 1, Status: domain.TaskStatusTodo}, nil)
+	taskRepo.On("GetByID", mock.Anything, int64(8)).Return(&domain.Task{ID: 8, TeamID: 1, Status: domain.TaskStatusTodo}, nil)
+	taskRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
+	historyRepo.On("CreateChangeSet", mock.Anything, mock.MatchedBy(func(set *domain.TaskChangeSet) bool {
+		return set.TaskID == 7 && set.Source == domain.ChangeSourceImport
+	}), mock.Anything).Return(int64(1), nil).Once()
+
+	ctx := requestctx.WithSource(context.Background(), domain.ChangeSourceImport)
+	result, err := svc.Import(ctx, 1, domain.ImportTasksRequest{TeamID: 1, Tasks: []domain.ImportedTask{
+		{Title: "Legacy bug", Status: "done"},
+		{Title: "Legacy idea"},
+	}})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Tasks, 2)
+	historyRepo.AssertExpectations(t)
+}
+
+func TestTaskService_Import_TooManyTasks(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	result, err := svc.Import(context.Background(), 1, domain.ImportTasksRequest{
+		TeamID: 1, Tasks: make([]domain.ImportedTask, maxImportTasks+1),
+	})
+
+	assert.Nil(t, result)
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestTaskService_Update_Success(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	existingTask := &domain.Task{
 		ID: 1, Title: "Old Title", Status: domain.TaskStatusTodo, TeamID: 1,
@@ -126,7 +204,7 @@ func TestTaskService_Update_Success(t *testing.T) {
 		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner,
 	}, nil)
 	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
-	historyRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.TaskHistory")).Return(nil)
+	historyRepo.On("CreateChangeSet", mock.Anything, mock.AnythingOfType("*domain.TaskChangeSet"), mock.Anything).Return(int64(1), nil)
 	taskRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
 	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
 
@@ -146,8 +224,8 @@ func TestTaskService_Update_Success(t *testing.T) {
 }
 
 func TestTaskService_List_WithCache(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	filter := domain.TaskFilter{TeamID: 1, Page: 1, PageSize: 20}
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
@@ -172,9 +250,27 @@ func TestTaskService_List_WithCache(t *testing.T) {
 	taskRepo.AssertNotCalled(t, "List")
 }
 
+func TestTaskService_List_ScopedGuestBypassesCache(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(3)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 3, Role: domain.TeamRoleGuest, TaskScoped: true,
+	}, nil)
+	taskRepo.On("List", mock.Anything, domain.TaskFilter{TeamID: 1, Page: 1, PageSize: 20, GuestID: 3}).
+		Return([]domain.Task{{ID: 7, Title: "Shared"}}, 1, nil)
+
+	result, err := svc.List(context.Background(), 3, domain.TaskFilter{TeamID: 1, Page: 1, PageSize: 20})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Tasks, 1)
+	cache.AssertNotCalled(t, "GetTaskList", mock.Anything, mock.Anything)
+	cache.AssertNotCalled(t, "SetTaskList", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestTaskService_List_CacheMiss(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	filter := domain.TaskFilter{TeamID: 1, Page: 1, PageSize: 20}
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
@@ -196,8 +292,8 @@ func TestTaskService_List_CacheMiss(t *testing.T) {
 }
 
 func TestTaskService_GetHistory_Success(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{
 		ID: 1, TeamID: 1,
@@ -205,26 +301,42 @@ func TestTaskService_GetHistory_Success(t *testing.T) {
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
 		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
 	}, nil)
-	historyRepo.On("ListByTaskID", mock.Anything, int64(1)).Return([]domain.TaskHistory{
-		{TaskID: 1, Field: "status", OldValue: "todo", NewValue: "in_progress"},
+	filter := domain.HistoryFilter{Page: 1, PageSize: 20}
+	historyRepo.On("ListChangeSets", mock.Anything, int64(1), filter).Return([]domain.TaskChangeSet{
+		{ID: 10, TaskID: 1, UserID: 1, UserName: "Owner", Source: domain.ChangeSourceAPI},
+		{ID: 11, TaskID: 1, UserID: 1, UserName: "Owner", Source: domain.ChangeSourceAPI},
+	}, 2, nil)
+	historyRepo.On("ListByChangeSetIDs", mock.Anything, []int64{10, 11}).Return([]domain.TaskHistory{
+		{ChangeSetID: 10, TaskID: 1, Field: "status", ValueType: domain.HistoryValueStatus, OldValue: "todo", NewValue: "in_progress"},
+		{ChangeSetID: 10, TaskID: 1, Field: "assignee_id", ValueType: domain.HistoryValueUser, OldValue: "", NewValue: "2"},
+		{ChangeSetID: 11, TaskID: 1, Field: "priority", ValueType: domain.HistoryValuePriority, OldValue: "1", NewValue: "3"},
+	}, nil)
+	userRepo.On("GetByIDs", mock.Anything, []int64{2}).Return([]domain.User{
+		{ID: 2, FullName: "Assignee"},
 	}, nil)
 
-	result, err := svc.GetHistory(context.Background(), 1, 1)
+	result, err := svc.GetHistory(context.Background(), 1, 1, domain.HistoryFilter{})
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 1)
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, 1, result.TotalPages)
+	assert.Len(t, result.ChangeSets, 2)
+	assert.Len(t, result.ChangeSets[0].Changes, 2)
+	assert.Nil(t, result.ChangeSets[0].Changes[1].OldValue)
+	assert.Equal(t, domain.HistoryUserRef{ID: 2, FullName: "Assignee"}, result.ChangeSets[0].Changes[1].NewValue)
+	assert.Equal(t, 3, result.ChangeSets[1].Changes[0].NewValue)
 }
 
 func TestTaskService_GetHistory_NotMember(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{
 		ID: 1, TeamID: 1,
 	}, nil)
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(99)).Return(nil, nil)
 
-	result, err := svc.GetHistory(context.Background(), 99, 1)
+	result, err := svc.GetHistory(context.Background(), 99, 1, domain.HistoryFilter{})
 
 	assert.Nil(t, result)
 	assert.Error(t, err)
@@ -232,8 +344,8 @@ func TestTaskService_GetHistory_NotMember(t *testing.T) {
 }
 
 func TestTaskService_Update_AllFields(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	existingTask := &domain.Task{
 		ID: 1, Title: "Old Title", Description: "Old Desc",
@@ -246,7 +358,7 @@ func TestTaskService_Update_AllFields(t *testing.T) {
 		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner,
 	}, nil)
 	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
-	historyRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.TaskHistory")).Return(nil)
+	historyRepo.On("CreateChangeSet", mock.Anything, mock.AnythingOfType("*domain.TaskChangeSet"), mock.Anything).Return(int64(1), nil)
 	taskRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
 	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
 
@@ -278,9 +390,97 @@ func TestTaskService_Update_AllFields(t *testing.T) {
 	assert.Equal(t, "New Title", result.Title)
 }
 
+func TestTaskService_Update_GroupsChangesIntoOneChangeSet(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	existingTask := &domain.Task{
+		ID: 1, Title: "Old Title", Status: domain.TaskStatusTodo, TeamID: 1,
+		Priority: domain.TaskPriorityLow,
+	}
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(existingTask, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
+	}, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	taskRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+	historyRepo.On("CreateChangeSet", mock.Anything,
+		mock.MatchedBy(func(set *domain.TaskChangeSet) bool {
+			return set.TaskID == 1 && set.UserID == 1 && set.Source == domain.ChangeSourceAPI
+		}),
+		mock.MatchedBy(func(entries []domain.TaskHistory) bool {
+			return len(entries) == 3 &&
+				entries[0].Field == "title" &&
+				entries[1].Field == "priority" && entries[1].ValueType == domain.HistoryValuePriority &&
+				entries[2].Field == "assignee_id" && entries[2].OldValue == "" && entries[2].NewValue == "4"
+		}),
+	).Return(int64(1), nil).Once()
+	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
+
+	newTitle := "New Title"
+	newPriority := 3
+	newAssignee := int64(4)
+	_, err := svc.Update(context.Background(), 1, 1, domain.UpdateTaskRequest{
+		Title:      &newTitle,
+		Priority:   &newPriority,
+		AssigneeID: &newAssignee,
+	})
+
+	assert.NoError(t, err)
+	historyRepo.AssertExpectations(t)
+}
+
+func TestTaskService_Update_HistoryFailureAbortsTransaction(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	existingTask := &domain.Task{
+		ID: 1, Title: "Old Title", Status: domain.TaskStatusTodo, TeamID: 1,
+	}
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(existingTask, nil).Once()
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
+	}, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	taskRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+	historyRepo.On("CreateChangeSet", mock.Anything, mock.Anything, mock.Anything).
+		Return(int64(0), apperror.Internal("create task history", assert.AnError))
+
+	newTitle := "New Title"
+	result, err := svc.Update(context.Background(), 1, 1, domain.UpdateTaskRequest{
+		Title: &newTitle,
+	})
+
+	assert.Nil(t, result)
+	assert.Error(t, err)
+	cache.AssertNotCalled(t, "InvalidateTeam", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_Update_NoChanges(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	existingTask := &domain.Task{
+		ID: 1, Title: "Same", Status: domain.TaskStatusTodo, TeamID: 1,
+	}
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(existingTask, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
+	}, nil)
+
+	title := "Same"
+	result, err := svc.Update(context.Background(), 1, 1, domain.UpdateTaskRequest{
+		Title: &title,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Same", result.Title)
+	txManager.AssertNotCalled(t, "WithTransaction", mock.Anything, mock.Anything)
+}
+
 func TestTaskService_Update_NotMember(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{
 		ID: 1, TeamID: 1,
@@ -298,8 +498,8 @@ func TestTaskService_Update_NotMember(t *testing.T) {
 }
 
 func TestTaskService_Update_TaskNotFound(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	taskRepo.On("GetByID", mock.Anything, int64(999)).Return(nil, apperror.NotFound("task not found"))
 
@@ -313,12 +513,13 @@ func TestTaskService_Update_TaskNotFound(t *testing.T) {
 }
 
 func TestTaskService_Create_WithDueDate(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
 		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner,
 	}, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(int64(1), nil)
 	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
 	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{
@@ -336,8 +537,8 @@ func TestTaskService_Create_WithDueDate(t *testing.T) {
 }
 
 func TestTaskService_Create_InvalidDueDate(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
 		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner,
@@ -354,8 +555,8 @@ func TestTaskService_Create_InvalidDueDate(t *testing.T) {
 }
 
 func TestTaskService_Create_NoTeamID(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	result, err := svc.Create(context.Background(), 1, domain.CreateTaskRequest{
 		Title:  "Test Task",
@@ -366,25 +567,51 @@ func TestTaskService_Create_NoTeamID(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestTaskService_List_NoTeamFilter(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+func TestTaskService_List_AllTeamsScopedToCaller(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
-	filter := domain.TaskFilter{Page: 1, PageSize: 20}
-	cache.On("GetTaskList", mock.Anything, filter).Return(nil, nil)
-	taskRepo.On("List", mock.Anything, filter).Return([]domain.Task{}, 0, nil)
-	cache.On("SetTaskList", mock.Anything, filter, mock.AnythingOfType("*domain.TaskListResponse")).Return(nil)
+	teamRepo.On("ListByUserID", mock.Anything, int64(3), true).Return([]domain.Team{{ID: 1}, {ID: 2}, {ID: 4}}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(3)).Return(&domain.TeamMember{TeamID: 1, UserID: 3, Role: domain.TeamRoleMember}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(2), int64(3)).Return(&domain.TeamMember{
+		TeamID: 2, UserID: 3, Role: domain.TeamRoleGuest, TaskScoped: true,
+	}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(4), int64(3)).Return(&domain.TeamMember{
+		TeamID: 4, UserID: 3, Role: domain.TeamRoleMember, TeamRequire2FA: true,
+	}, nil)
+	access := &domain.TaskAccess{UserID: 3, Teams: []int64{1}, ScopedTeams: []int64{2}}
+	taskRepo.On("List", mock.Anything, domain.TaskFilter{Page: 1, PageSize: 20, Access: access}).
+		Return([]domain.Task{{ID: 1, TeamID: 1}, {ID: 5, TeamID: 2}}, 2, nil)
 
-	result, err := svc.List(context.Background(), 1, filter)
+	result, err := svc.List(context.Background(), 3, domain.TaskFilter{Page: 1, PageSize: 20})
 
 	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Empty(t, result.Tasks)
+	assert.Equal(t, 2, result.Total)
+	cache.AssertNotCalled(t, "GetTaskList", mock.Anything, mock.Anything)
+	cache.AssertNotCalled(t, "SetTaskList", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTaskService_List_AllTeamsRespectsTokenRestriction(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	teamRepo.On("ListByUserID", mock.Anything, int64(1), true).Return([]domain.Team{{ID: 1}, {ID: 2}}, nil)
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	access := &domain.TaskAccess{UserID: 1, Teams: []int64{1}}
+	taskRepo.On("List", mock.Anything, domain.TaskFilter{Page: 1, PageSize: 20, Access: access}).
+		Return([]domain.Task{{ID: 1, TeamID: 1}}, 1, nil)
+
+	ctx := requestctx.WithTeamRestriction(context.Background(), 1)
+	result, err := svc.List(ctx, 1, domain.TaskFilter{Page: 1, PageSize: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+	teamRepo.AssertNotCalled(t, "GetMember", mock.Anything, int64(2), mock.Anything)
 }
 
 func TestTaskService_Update_DueDateWithExistingDueDate(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	existingTask := &domain.Task{
 		ID: 1, Title: "Task", Status: domain.TaskStatusTodo, TeamID: 1,
@@ -397,7 +624,7 @@ func TestTaskService_Update_DueDateWithExistingDueDate(t *testing.T) {
 		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner,
 	}, nil)
 	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
-	historyRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.TaskHistory")).Return(nil)
+	historyRepo.On("CreateChangeSet", mock.Anything, mock.AnythingOfType("*domain.TaskChangeSet"), mock.Anything).Return(int64(1), nil)
 	taskRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
 	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
 	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(existingTask, nil).Once()
@@ -412,8 +639,8 @@ func TestTaskService_Update_DueDateWithExistingDueDate(t *testing.T) {
 }
 
 func TestTaskService_Update_UnassignedToAssigned(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	existingTask := &domain.Task{
 		ID: 1, Title: "Task", Status: domain.TaskStatusTodo, TeamID: 1,
@@ -424,7 +651,7 @@ func TestTaskService_Update_UnassignedToAssigned(t *testing.T) {
 		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner,
 	}, nil)
 	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
-	historyRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.TaskHistory")).Return(nil)
+	historyRepo.On("CreateChangeSet", mock.Anything, mock.AnythingOfType("*domain.TaskChangeSet"), mock.Anything).Return(int64(1), nil)
 	taskRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
 	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
 	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(existingTask, nil).Once()
@@ -439,8 +666,8 @@ func TestTaskService_Update_UnassignedToAssigned(t *testing.T) {
 }
 
 func TestTaskService_Update_InvalidDueDate(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	existingTask := &domain.Task{
 		ID: 1, Title: "Task", Status: domain.TaskStatusTodo, TeamID: 1,
@@ -461,8 +688,8 @@ func TestTaskService_Update_InvalidDueDate(t *testing.T) {
 }
 
 func TestTaskService_Update_StatusChange(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	existingTask := &domain.Task{
 		ID: 1, Title: "Task", Status: domain.TaskStatusTodo, TeamID: 1,
@@ -473,7 +700,7 @@ func TestTaskService_Update_StatusChange(t *testing.T) {
 		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner,
 	}, nil)
 	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
-	historyRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.TaskHistory")).Return(nil)
+	historyRepo.On("CreateChangeSet", mock.Anything, mock.AnythingOfType("*domain.TaskChangeSet"), mock.Anything).Return(int64(1), nil)
 	taskRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
 	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
 
@@ -491,18 +718,80 @@ func TestTaskService_Update_StatusChange(t *testing.T) {
 	assert.NotNil(t, result)
 }
 
-func TestTaskService_GetOrphanedAssignees(t *testing.T) {
-	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc := newTaskServiceDeps()
-	svc := NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc)
+func TestTaskService_Update_RecordsOnlyNewMentions(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
 
-	expected := []domain.OrphanedAssignee{
-		{TaskID: 1, TaskTitle: "Task 1", AssigneeID: 5, AssigneeName: "Ghost User"},
-	}
-	taskRepo.On("GetOrphanedAssignees", mock.Anything).Return(expected, nil)
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{
+		ID: 1, TeamID: 1, Description: "ask @alice",
+	}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
+	}, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	taskRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+	historyRepo.On("CreateChangeSet", mock.Anything, mock.Anything, mock.Anything).Return(int64(1), nil)
+	mentionSvc.On("Record", mock.Anything, mock.Anything, []string{"bob"}).Return(&domain.MentionResult{
+		Unresolved: []string{"bob"},
+	}, nil)
+	mentionSvc.On("Notify", mock.Anything, mock.Anything, []domain.User(nil)).Return()
+	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
+
+	desc := "ask @alice and @bob"
+	result, err := svc.Update(context.Background(), 1, 1, domain.UpdateTaskRequest{Description: &desc})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, result.UnresolvedMentions)
+	mentionSvc.AssertExpectations(t)
+}
+
+func TestTaskService_Delete_ByCreator(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{ID: 1, TeamID: 1, CreatorID: 1}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
+	}, nil)
+	attachSvc.On("DeleteForTask", mock.Anything, int64(1)).Return(nil)
+	taskRepo.On("Delete", mock.Anything, int64(1)).Return(nil)
+	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
 
-	result, err := svc.GetOrphanedAssignees(context.Background())
+	err := svc.Delete(context.Background(), 1, 1)
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 1)
-	assert.Equal(t, "Ghost User", result[0].AssigneeName)
+	attachSvc.AssertExpectations(t)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestTaskService_Delete_ByOtherMember(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{ID: 1, TeamID: 1, CreatorID: 1}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(2)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 2, Role: domain.TeamRoleMember,
+	}, nil)
+
+	err := svc.Delete(context.Background(), 2, 1)
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	attachSvc.AssertNotCalled(t, "DeleteForTask", mock.Anything, mock.Anything)
+	taskRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_Update_ArchivedTeam(t *testing.T) {
+	taskRepo, teamRepo, userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc := newTaskServiceDeps()
+	svc := NewTaskService(taskRepo, NewAuthorizer(teamRepo), userRepo, historyRepo, cache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	taskRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Task{ID: 1, TeamID: 1, CreatorID: 1}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner, TeamArchived: true,
+	}, nil)
+
+	title := "Renamed"
+	_, err := svc.Update(context.Background(), 1, 1, domain.UpdateTaskRequest{Title: &title})
+
+	assert.Equal(t, errTeamArchived, err)
+	txManager.AssertNotCalled(t, "WithTransaction", mock.Anything, mock.Anything)
 }
@@ -2,41 +2,108 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
 	"github.com/shalfey088/team-task-nexus/internal/port"
 )
 
+const teamDeletionTokenTTL = 10 * time.Minute
+
+var (
+	errTeamArchived      = apperror.New(http.StatusConflict, "team is archived and read-only")
+	errTwoFactorRequired = apperror.Forbidden("this team requires two-factor authentication; enable it to continue")
+)
+
 type TeamServiceImpl struct {
-	teamRepo    port.TeamRepository
-	userRepo    port.UserRepository
-	txManager   port.TransactionManager
-	notifSvc    port.NotificationService
+	teamRepo     port.TeamRepository
+	orgRepo      port.OrganizationRepository
+	authz        port.Authorizer
+	userRepo     port.UserRepository
+	activityRepo port.ActivityRepository
+	txManager    port.TransactionManager
+	notifSvc     port.NotificationService
+	taskCache    port.TaskCache
+	attachSvc    port.AttachmentService
+	signer       *signedtoken.Signer
 }
 
 func NewTeamService(
 	teamRepo port.TeamRepository,
+	orgRepo port.OrganizationRepository,
+	authz port.Authorizer,
 	userRepo port.UserRepository,
+	activityRepo port.ActivityRepository,
 	txManager port.TransactionManager,
 	notifSvc port.NotificationService,
+	taskCache port.TaskCache,
+	attachSvc port.AttachmentService,
+	tokenSecret string,
 ) *TeamServiceImpl {
 	return &TeamServiceImpl{
-		teamRepo:  teamRepo,
-		userRepo:  userRepo,
-		txManager: txManager,
-		notifSvc:  notifSvc,
+		teamRepo:     teamRepo,
+		orgRepo:      orgRepo,
+		authz:        authz,
+		userRepo:     userRepo,
+		activityRepo: activityRepo,
+		txManager:    txManager,
+		notifSvc:     notifSvc,
+		taskCache:    taskCache,
+		attachSvc:    attachSvc,
+		signer:       signedtoken.NewSigner(tokenSecret),
 	}
 }
 
+// Create places the team in req.OrgID when given, otherwise in the caller's
+// personal organization, which is created on first use. A sub-team always
+// lives in its parent's organization.
 func (s *TeamServiceImpl) Create(ctx context.Context, userID int64, req domain.CreateTeamRequest) (*domain.Team, error) {
 	if req.Name == "" {
 		return nil, apperror.BadRequest("team name is required")
 	}
 
+	if req.ParentID != nil {
+		parent, err := s.loadParent(ctx, userID, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if req.OrgID != 0 && req.OrgID != parent.OrgID {
+			return nil, apperror.BadRequest("a sub-team must belong to its parent's organization")
+		}
+		req.OrgID = parent.OrgID
+	} else if req.OrgID != 0 {
+		orgMember, err := s.orgRepo.GetMember(ctx, req.OrgID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if orgMember == nil {
+			return nil, errNotOrgMember
+		}
+		if orgMember.Role == domain.OrgRoleBilling {
+			return nil, apperror.ErrInsufficientRole
+		}
+	}
+
 	var team *domain.Team
 	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		orgID := req.OrgID
+		if orgID == 0 {
+			org, err := s.personalOrg(ctx, userID)
+			if err != nil {
+				return err
+			}
+			orgID = org.ID
+		}
+
 		t := &domain.Team{
+			OrgID:       orgID,
+			ParentID:    req.ParentID,
 			Name:        req.Name,
 			Description: req.Description,
 			OwnerID:     userID,
@@ -65,65 +132,493 @@ func (s *TeamServiceImpl) Create(ctx context.Context, userID int64, req domain.C
 	return team, nil
 }
 
+// loadParent checks that the user may attach sub-teams to the parent. Its
+// members may gain a role in the sub-team, so this takes an owner or admin
+// of the parent rather than a grantable permission.
+func (s *TeamServiceImpl) loadParent(ctx context.Context, userID, parentID int64) (*domain.Team, error) {
+	member, err := s.authz.Authorize(ctx, userID, parentID, domain.PermMemberManage)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(member); err != nil {
+		return nil, err
+	}
+	return s.teamRepo.GetByID(ctx, parentID)
+}
+
+func (s *TeamServiceImpl) personalOrg(ctx context.Context, userID int64) (*domain.Organization, error) {
+	org, err := s.orgRepo.GetPersonal(ctx, userID)
+	if err != nil || org != nil {
+		return org, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	org = &domain.Organization{
+		Name:      user.FullName + "'s workspace",
+		Personal:  true,
+		CreatedBy: userID,
+	}
+	if org.ID, err = s.orgRepo.Create(ctx, org); err != nil {
+		return nil, err
+	}
+	err = s.orgRepo.AddMember(ctx, &domain.OrgMember{
+		OrgID:  org.ID,
+		UserID: userID,
+		Role:   domain.OrgRoleAdmin,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
 func (s *TeamServiceImpl) GetByID(ctx context.Context, userID, teamID int64) (*domain.Team, error) {
-	member, err := s.teamRepo.GetMember(ctx, teamID, userID)
+	if _, err := s.authz.Member(ctx, userID, teamID); err != nil {
+		return nil, err
+	}
+	return s.teamRepo.GetByID(ctx, teamID)
+}
+
+func (s *TeamServiceImpl) ListByUserID(ctx context.Context, userID int64, includeArchived bool) ([]domain.Team, error) {
+	teams, err := s.teamRepo.ListByUserID(ctx, userID, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	restricted, ok := requestctx.TeamRestriction(ctx)
+	if !ok {
+		return teams, nil
+	}
+	visible := []domain.Team{}
+	for _, t := range teams {
+		if t.ID == restricted {
+			visible = append(visible, t)
+		}
+	}
+	return visible, nil
+}
+
+func (s *TeamServiceImpl) Update(ctx context.Context, userID, teamID int64, req domain.UpdateTeamRequest) (*domain.Team, error) {
+	member, err := s.authz.Authorize(ctx, userID, teamID, domain.PermTeamUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(member); err != nil {
+		return nil, err
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, teamID)
 	if err != nil {
 		return nil, err
 	}
-	if member == nil {
-		return nil, apperror.ErrNotTeamMember
+
+	var changed []string
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, apperror.BadRequest("team name is required")
+		}
+		if name != team.Name {
+			team.Name = name
+			changed = append(changed, "name")
+		}
+	}
+	if req.Description != nil && *req.Description != team.Description {
+		team.Description = *req.Description
+		changed = append(changed, "description")
+	}
+	if req.Require2FA != nil && *req.Require2FA != team.Require2FA {
+		// Custom roles can be granted team.update; the 2FA requirement
+		// decides who keeps access, so it stays with owners and admins.
+		if !s.authz.Can(member, domain.PermMemberManage) {
+			return nil, apperror.ErrInsufficientRole
+		}
+		// Otherwise the member would lock themselves out on the next request.
+		if *req.Require2FA && !member.TwoFactorEnabled {
+			return nil, apperror.Forbidden("enable two-factor authentication before requiring it for the team")
+		}
+		team.Require2FA = *req.Require2FA
+		changed = append(changed, "require_2fa")
+	}
+	if len(changed) == 0 {
+		return team, nil
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.teamRepo.Update(ctx, team); err != nil {
+			return err
+		}
+		return s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:  teamID,
+			ActorID: userID,
+			Type:    domain.ActivityTeamUpdated,
+			Details: strings.Join(changed, ","),
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 	return s.teamRepo.GetByID(ctx, teamID)
 }
 
-func (s *TeamServiceImpl) ListByUserID(ctx context.Context, userID int64) ([]domain.Team, error) {
-	return s.teamRepo.ListByUserID(ctx, userID)
+// SetParent moves a team within its organization's hierarchy and sets how
+// much of a parent member's role carries over to it. Both teams must be
+// managed by the caller, who cannot hand parent members a role above their
+// own. Moving a team under one of its own sub-teams is rejected.
+func (s *TeamServiceImpl) SetParent(ctx context.Context, userID, teamID int64, req domain.SetParentRequest) (*domain.Team, error) {
+	member, err := s.authz.Authorize(ctx, userID, teamID, domain.PermMemberManage)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(member); err != nil {
+		return nil, err
+	}
+
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	inherited := team.InheritedRole
+	switch req.InheritedRole {
+	case "":
+	case "none":
+		inherited = nil
+	case string(domain.TeamRoleAdmin), string(domain.TeamRoleMember), string(domain.TeamRoleGuest):
+		role := domain.TeamRole(req.InheritedRole)
+		inherited = &role
+	default:
+		return nil, apperror.BadRequest("inherited_role must be admin, member, guest or none")
+	}
+	if inherited != nil && inherited.Rank() > member.Role.Rank() {
+		return nil, apperror.Forbidden("inherited_role cannot exceed your own role in the team")
+	}
+
+	if req.ParentID != nil {
+		if *req.ParentID == teamID {
+			return nil, apperror.New(http.StatusConflict, "a team cannot be its own parent")
+		}
+		parent, err := s.loadParent(ctx, userID, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.OrgID != team.OrgID {
+			return nil, apperror.BadRequest("a sub-team must belong to its parent's organization")
+		}
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.teamRepo.LockHierarchy(ctx, team.OrgID); err != nil {
+			return err
+		}
+		if req.ParentID != nil {
+			descendants, err := s.teamRepo.ListDescendants(ctx, teamID)
+			if err != nil {
+				return err
+			}
+			for _, d := range descendants {
+				if d.ID == *req.ParentID {
+					return apperror.New(http.StatusConflict, "a team cannot be moved under its own sub-team")
+				}
+			}
+		}
+
+		if err := s.teamRepo.SetParent(ctx, teamID, req.ParentID, inherited); err != nil {
+			return err
+		}
+		return s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:  teamID,
+			ActorID: userID,
+			Type:    domain.ActivityTeamUpdated,
+			Details: "parent",
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.teamRepo.GetByID(ctx, teamID)
 }
 
-func (s *TeamServiceImpl) InviteUser(ctx context.Context, inviterID, teamID int64, req domain.InviteRequest) error {
-	if req.Email == "" {
-		return apperror.BadRequest("email is required")
+// GetTree returns the team with all of its sub-teams nested below it.
+func (s *TeamServiceImpl) GetTree(ctx context.Context, userID, teamID int64) (*domain.TeamTree, error) {
+	if _, err := s.authz.Member(ctx, userID, teamID); err != nil {
+		return nil, err
 	}
 
-	member, err := s.teamRepo.GetMember(ctx, teamID, inviterID)
+	root, err := s.teamRepo.GetByID(ctx, teamID)
 	if err != nil {
+		return nil, err
+	}
+	descendants, err := s.teamRepo.ListDescendants(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[int64][]domain.Team)
+	for _, d := range descendants {
+		children[*d.ParentID] = append(children[*d.ParentID], d)
+	}
+	tree := buildTeamTree(*root, children)
+	return &tree, nil
+}
+
+func buildTeamTree(team domain.Team, children map[int64][]domain.Team) domain.TeamTree {
+	node := domain.TeamTree{Team: team, Children: []domain.TeamTree{}}
+	for _, child := range children[team.ID] {
+		node.Children = append(node.Children, buildTeamTree(child, children))
+	}
+	return node
+}
+
+// SetArchived archives or restores a team. Archived teams keep their data
+// but all task writes are rejected and they are hidden from team lists.
+func (s *TeamServiceImpl) SetArchived(ctx context.Context, userID, teamID int64, archived bool) (*domain.Team, error) {
+	member, err := s.authz.Authorize(ctx, userID, teamID, domain.PermTeamArchive)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.TeamArchived != archived {
+		eventType := domain.ActivityTeamUnarchived
+		if archived {
+			eventType = domain.ActivityTeamArchived
+		}
+		err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := s.teamRepo.SetArchived(ctx, teamID, archived); err != nil {
+				return err
+			}
+			return s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+				TeamID:  teamID,
+				ActorID: userID,
+				Type:    eventType,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		_ = s.taskCache.InvalidateTeam(ctx, teamID)
+	}
+	return s.teamRepo.GetByID(ctx, teamID)
+}
+
+// IssueDeletionToken returns a short-lived token the owner must send back to
+// Delete, so a single stray request cannot wipe a team.
+func (s *TeamServiceImpl) IssueDeletionToken(ctx context.Context, userID, teamID int64) (*domain.TeamDeletionToken, error) {
+	if _, err := s.authz.Authorize(ctx, userID, teamID, domain.PermTeamDelete); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(teamDeletionTokenTTL).Truncate(time.Second)
+	token, err := s.signer.Sign(teamDeletionPurpose(teamID, userID), expiresAt)
+	if err != nil {
+		return nil, apperror.Internal("generate confirmation token", err)
+	}
+	return &domain.TeamDeletionToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+func (s *TeamServiceImpl) Delete(ctx context.Context, userID, teamID int64, req domain.DeleteTeamRequest) error {
+	if _, err := s.authz.Authorize(ctx, userID, teamID, domain.PermTeamDelete); err != nil {
 		return err
 	}
-	if member == nil {
-		return apperror.ErrNotTeamMember
+	if err := s.signer.Verify(teamDeletionPurpose(teamID, userID), req.ConfirmationToken, time.Now()); err != nil {
+		return apperror.BadRequest("invalid or expired confirmation token")
 	}
-	if member.Role != domain.TeamRoleOwner && member.Role != domain.TeamRoleAdmin {
-		return apperror.ErrInsufficientRole
+
+	descendants, err := s.teamRepo.ListDescendants(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if len(descendants) > 0 {
+		return apperror.New(http.StatusConflict, "team has sub-teams; move or delete them first")
+	}
+
+	// Attachment rows would cascade with the team, but their blobs would not.
+	if err := s.attachSvc.DeleteForTeam(ctx, teamID); err != nil {
+		return err
 	}
+	if err := s.teamRepo.Delete(ctx, teamID); err != nil {
+		return err
+	}
+
+	_ = s.taskCache.InvalidateTeam(ctx, teamID)
+	return nil
+}
 
-	invitee, err := s.userRepo.GetByEmail(ctx, req.Email)
+func teamDeletionPurpose(teamID, userID int64) string {
+	return fmt.Sprintf("team-delete:%d:%d", teamID, userID)
+}
+
+// checkWritable rejects changes to tasks and their content once the team is
+// archived.
+func checkWritable(member *domain.TeamMember) error {
+	if member.TeamArchived {
+		return errTeamArchived
+	}
+	return nil
+}
+
+// checkTwoFactor keeps members without 2FA out of teams that require it.
+func checkTwoFactor(member *domain.TeamMember) error {
+	if member.TeamRequire2FA && !member.TwoFactorEnabled {
+		return errTwoFactorRequired
+	}
+	return nil
+}
+
+func (s *TeamServiceImpl) ListMembers(ctx context.Context, userID, teamID int64) ([]domain.TeamMemberDetails, error) {
+	if _, err := s.authz.Member(ctx, userID, teamID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.teamRepo.ListMembers(ctx, teamID)
 	if err != nil {
-		return apperror.NotFound("user with this email not found")
+		return nil, err
+	}
+	if members == nil {
+		members = []domain.TeamMemberDetails{}
 	}
+	return members, nil
+}
 
-	role := domain.TeamRoleMember
-	if req.Role == string(domain.TeamRoleAdmin) {
-		role = domain.TeamRoleAdmin
+// ChangeMemberRole lets owners and admins change the role of members they
+// outrank. The owner role can only be handed over, never granted here.
+func (s *TeamServiceImpl) ChangeMemberRole(ctx context.Context, actorID, teamID, targetID int64, req domain.UpdateMemberRoleRequest) (*domain.TeamMember, error) {
+	role := domain.TeamRole(req.Role)
+	if role != domain.TeamRoleAdmin && role != domain.TeamRoleMember && role != domain.TeamRoleGuest {
+		return nil, apperror.BadRequest("role must be admin, member or guest")
 	}
 
-	return s.teamRepo.AddMember(ctx, &domain.TeamMember{
-		TeamID: teamID,
-		UserID: invitee.ID,
-		Role:   role,
+	actor, target, err := s.loadMemberPair(ctx, actorID, teamID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.Role.Rank() <= target.Role.Rank() || actor.Role.Rank() < role.Rank() {
+		return nil, apperror.ErrInsufficientRole
+	}
+	if target.Role == role {
+		return target, nil
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.teamRepo.UpdateMemberRole(ctx, teamID, targetID, role); err != nil {
+			return err
+		}
+		return s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:       teamID,
+			ActorID:      actorID,
+			TargetUserID: &targetID,
+			Type:         domain.ActivityMemberRoleChanged,
+			Details:      fmt.Sprintf("%s->%s", target.Role, role),
+		})
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	target.Role = role
+	target.CustomRoleID = nil
+	target.TaskScoped = target.TaskScoped && role == domain.TeamRoleGuest
+	return target, nil
 }
 
-func (s *TeamServiceImpl) GetStats(ctx context.Context, userID int64) ([]domain.TeamStats, error) {
-	return s.teamRepo.GetStats(ctx, userID)
+func (s *TeamServiceImpl) RemoveMember(ctx context.Context, actorID, teamID, targetID int64) error {
+	if actorID == targetID {
+		return s.Leave(ctx, actorID, teamID)
+	}
+
+	actor, target, err := s.loadMemberPair(ctx, actorID, teamID, targetID)
+	if err != nil {
+		return err
+	}
+	if actor.Role.Rank() <= target.Role.Rank() {
+		return apperror.ErrInsufficientRole
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.teamRepo.RemoveMember(ctx, teamID, targetID); err != nil {
+			return err
+		}
+		return s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:       teamID,
+			ActorID:      actorID,
+			TargetUserID: &targetID,
+			Type:         domain.ActivityMemberRemoved,
+			Details:      string(target.Role),
+		})
+	})
 }
 
-func (s *TeamServiceImpl) GetTopContributors(ctx context.Context, userID, teamID int64) ([]domain.TopContributor, error) {
-	member, err := s.teamRepo.GetMember(ctx, teamID, userID)
+func (s *TeamServiceImpl) Leave(ctx context.Context, userID, teamID int64) error {
+	member, err := s.authz.Member(ctx, userID, teamID)
+	if err != nil {
+		return err
+	}
+	if !member.IsDirect() {
+		return apperror.ErrNotTeamMember
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if member.Role == domain.TeamRoleOwner {
+			owners, err := s.teamRepo.CountMembersByRole(ctx, teamID, domain.TeamRoleOwner)
+			if err != nil {
+				return err
+			}
+			if owners <= 1 {
+				return apperror.New(http.StatusConflict, "the last owner cannot leave the team; transfer ownership first")
+			}
+		}
+
+		if err := s.teamRepo.RemoveMember(ctx, teamID, userID); err != nil {
+			return err
+		}
+		return s.activityRepo.CreateTeamEvent(ctx, &domain.TeamEvent{
+			TeamID:  teamID,
+			ActorID: userID,
+			Type:    domain.ActivityMemberLeft,
+			Details: string(member.Role),
+		})
+	})
+}
+
+func (s *TeamServiceImpl) loadMemberPair(ctx context.Context, actorID, teamID, targetID int64) (*domain.TeamMember, *domain.TeamMember, error) {
+	actor, err := s.authz.Authorize(ctx, actorID, teamID, domain.PermMemberManage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target, err := s.teamRepo.GetMember(ctx, teamID, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target == nil || !target.IsDirect() {
+		return nil, nil, apperror.NotFound("member not found")
+	}
+	return actor, target, nil
+}
+
+func (s *TeamServiceImpl) GetStats(ctx context.Context, userID int64, rollup bool) ([]domain.TeamStats, error) {
+	stats, err := s.teamRepo.GetStats(ctx, userID, rollup)
 	if err != nil {
 		return nil, err
 	}
-	if member == nil {
-		return nil, apperror.ErrNotTeamMember
+	restricted, ok := requestctx.TeamRestriction(ctx)
+	if !ok {
+		return stats, nil
+	}
+	visible := []domain.TeamStats{}
+	for _, st := range stats {
+		if st.ID == restricted {
+			visible = append(visible, st)
+		}
+	}
+	return visible, nil
+}
+
+func (s *TeamServiceImpl) GetTopContributors(ctx context.Context, userID, teamID int64) ([]domain.TopContributor, error) {
+	if _, err := s.authz.Member(ctx, userID, teamID); err != nil {
+		return nil, err
 	}
 	return s.teamRepo.GetTopContributors(ctx, teamID)
 }
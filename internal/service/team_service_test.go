@@ -11,19 +11,30 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-func newTeamServiceDeps() (*mocks.TeamRepositoryMock, *mocks.UserRepositoryMock, *mocks.TransactionManagerMock, *mocks.NotificationServiceMock) {
-	return new(mocks.TeamRepositoryMock), new(mocks.UserRepositoryMock), new(mocks.TransactionManagerMock), new(mocks.NotificationServiceMock)
+func newTeamServiceDeps() (*mocks.TeamRepositoryMock, *mocks.UserRepositoryMock, *mocks.ActivityRepositoryMock, *mocks.TransactionManagerMock, *mocks.NotificationServiceMock) {
+	return new(mocks.TeamRepositoryMock), new(mocks.UserRepositoryMock), new(mocks.ActivityRepositoryMock), new(mocks.TransactionManagerMock), new(mocks.NotificationServiceMock)
+}
+
+func newTeamService(
+	teamRepo *mocks.TeamRepositoryMock, userRepo *mocks.UserRepositoryMock, activityRepo *mocks.ActivityRepositoryMock,
+	txManager *mocks.TransactionManagerMock, notifSvc *mocks.NotificationServiceMock,
+) *TeamServiceImpl {
+	return NewTeamService(teamRepo, new(mocks.OrganizationRepositoryMock), NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, new(mocks.TaskCacheMock), new(mocks.AttachmentServiceMock), "test-secret")
 }
 
 func TestTeamService_Create_Success(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	orgRepo := new(mocks.OrganizationRepositoryMock)
+	svc := NewTeamService(teamRepo, orgRepo, NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, new(mocks.TaskCacheMock), new(mocks.AttachmentServiceMock), "test-secret")
 
 	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
-	teamRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Team")).Return(int64(1), nil)
+	orgRepo.On("GetPersonal", mock.Anything, int64(1)).Return(&domain.Organization{ID: 7, Personal: true}, nil)
+	teamRepo.On("Create", mock.Anything, mock.MatchedBy(func(t *domain.Team) bool {
+		return t.OrgID == 7
+	})).Return(int64(1), nil)
 	teamRepo.On("AddMember", mock.Anything, mock.AnythingOfType("*domain.TeamMember")).Return(nil)
 	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{
-		ID: 1, Name: "Test Team", OwnerID: 1,
+		ID: 1, OrgID: 7, Name: "Test Team", OwnerID: 1,
 	}, nil)
 
 	result, err := svc.Create(context.Background(), 1, domain.CreateTeamRequest{
@@ -35,11 +46,88 @@ func TestTeamService_Create_Success(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Equal(t, "Test Team", result.Name)
 	txManager.AssertExpectations(t)
+	teamRepo.AssertExpectations(t)
+}
+
+func TestTeamService_Create_UnderParentUsesParentOrg(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, OrgID: 7}, nil)
+	teamRepo.On("Create", mock.Anything, mock.MatchedBy(func(t *domain.Team) bool {
+		return t.OrgID == 7 && t.ParentID != nil && *t.ParentID == 1
+	})).Return(int64(2), nil)
+	teamRepo.On("AddMember", mock.Anything, mock.AnythingOfType("*domain.TeamMember")).Return(nil)
+	teamRepo.On("GetByID", mock.Anything, int64(2)).Return(&domain.Team{ID: 2, OrgID: 7}, nil)
+
+	parentID := int64(1)
+	_, err := svc.Create(context.Background(), 1, domain.CreateTeamRequest{Name: "Squad", ParentID: &parentID})
+
+	assert.NoError(t, err)
+	teamRepo.AssertExpectations(t)
+}
+
+func TestTeamService_Create_UnderParentOtherOrg(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, OrgID: 7}, nil)
+
+	parentID := int64(1)
+	_, err := svc.Create(context.Background(), 1, domain.CreateTeamRequest{Name: "Squad", OrgID: 8, ParentID: &parentID})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	teamRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestTeamService_Create_CreatesPersonalOrg(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	orgRepo := new(mocks.OrganizationRepositoryMock)
+	svc := NewTeamService(teamRepo, orgRepo, NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, new(mocks.TaskCacheMock), new(mocks.AttachmentServiceMock), "test-secret")
+
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	orgRepo.On("GetPersonal", mock.Anything, int64(1)).Return(nil, nil)
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1, FullName: "Ann"}, nil)
+	orgRepo.On("Create", mock.Anything, mock.MatchedBy(func(o *domain.Organization) bool {
+		return o.Personal && o.CreatedBy == 1 && o.Name == "Ann's workspace"
+	})).Return(int64(9), nil)
+	orgRepo.On("AddMember", mock.Anything, &domain.OrgMember{OrgID: 9, UserID: 1, Role: domain.OrgRoleAdmin}).Return(nil)
+	teamRepo.On("Create", mock.Anything, mock.MatchedBy(func(t *domain.Team) bool {
+		return t.OrgID == 9
+	})).Return(int64(1), nil)
+	teamRepo.On("AddMember", mock.Anything, mock.AnythingOfType("*domain.TeamMember")).Return(nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, OrgID: 9}, nil)
+
+	_, err := svc.Create(context.Background(), 1, domain.CreateTeamRequest{Name: "Test Team"})
+
+	assert.NoError(t, err)
+	orgRepo.AssertExpectations(t)
+	teamRepo.AssertExpectations(t)
+}
+
+func TestTeamService_Create_InOrg_BillingForbidden(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	orgRepo := new(mocks.OrganizationRepositoryMock)
+	svc := NewTeamService(teamRepo, orgRepo, NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, new(mocks.TaskCacheMock), new(mocks.AttachmentServiceMock), "test-secret")
+
+	orgRepo.On("GetMember", mock.Anything, int64(3), int64(1)).Return(&domain.OrgMember{
+		OrgID: 3, UserID: 1, Role: domain.OrgRoleBilling,
+	}, nil)
+
+	_, err := svc.Create(context.Background(), 1, domain.CreateTeamRequest{Name: "Test Team", OrgID: 3})
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	teamRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 }
 
 func TestTeamService_Create_EmptyName(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
 
 	result, err := svc.Create(context.Background(), 1, domain.CreateTeamRequest{Name: ""})
 
@@ -51,8 +139,8 @@ func TestTeamService_Create_EmptyName(t *testing.T) {
 }
 
 func TestTeamService_GetByID_Success(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
 
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
 		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner,
@@ -69,8 +157,8 @@ func TestTeamService_GetByID_Success(t *testing.T) {
 }
 
 func TestTeamService_GetByID_NotMember(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
 
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(2)).Return(nil, nil)
 
@@ -81,164 +169,458 @@ func TestTeamService_GetByID_NotMember(t *testing.T) {
 	assert.Equal(t, apperror.ErrNotTeamMember, err)
 }
 
-func TestTeamService_InviteUser_Success(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+func TestTeamService_ListByUserID(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+
+	expected := []domain.Team{
+		{ID: 1, Name: "Team 1"},
+		{ID: 2, Name: "Team 2"},
+	}
+	teamRepo.On("ListByUserID", mock.Anything, int64(1), false).Return(expected, nil)
+
+	result, err := svc.ListByUserID(context.Background(), 1, false)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+}
+
+func TestTeamService_GetStats_Success(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+
+	expected := []domain.TeamStats{
+		{ID: 1, Name: "Team 1", MemberCount: 5, DoneLast7D: 3},
+	}
+	teamRepo.On("GetStats", mock.Anything, int64(1), false).Return(expected, nil)
+
+	result, err := svc.GetStats(context.Background(), 1, false)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 5, result[0].MemberCount)
+}
+
+func TestTeamService_GetTopContributors_Success(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
 
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
-		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner,
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
 	}, nil)
-	userRepo.On("GetByEmail", mock.Anything, "newuser@example.com").Return(&domain.User{
-		ID: 2, Email: "newuser@example.com",
+	expected := []domain.TopContributor{
+		{UserID: 1, FullName: "User 1", TasksCreated: 10, Rank: 1},
+	}
+	teamRepo.On("GetTopContributors", mock.Anything, int64(1)).Return(expected, nil)
+
+	result, err := svc.GetTopContributors(context.Background(), 1, 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 10, result[0].TasksCreated)
+}
+
+func TestTeamService_GetTopContributors_NotMember(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(99)).Return(nil, nil)
+
+	result, err := svc.GetTopContributors(context.Background(), 99, 1)
+
+	assert.Nil(t, result)
+	assert.Error(t, err)
+	assert.Equal(t, apperror.ErrNotTeamMember, err)
+}
+
+func stubTeamMember(teamRepo *mocks.TeamRepositoryMock, userID int64, role domain.TeamRole) {
+	teamRepo.On("GetMember", mock.Anything, int64(1), userID).Return(&domain.TeamMember{
+		TeamID: 1, UserID: userID, Role: role,
 	}, nil)
-	teamRepo.On("AddMember", mock.Anything, mock.AnythingOfType("*domain.TeamMember")).Return(nil)
+}
 
-	err := svc.InviteUser(context.Background(), 1, 1, domain.InviteRequest{
-		Email: "newuser@example.com",
-		Role:  "member",
-	})
+func TestTeamService_ChangeMemberRole_OwnerPromotes(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	stubTeamMember(teamRepo, 2, domain.TeamRoleMember)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	teamRepo.On("UpdateMemberRole", mock.Anything, int64(1), int64(2), domain.TeamRoleAdmin).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.Type == domain.ActivityMemberRoleChanged && e.Details == "member->admin"
+	})).Return(nil)
+
+	member, err := svc.ChangeMemberRole(context.Background(), 1, 1, 2, domain.UpdateMemberRoleRequest{Role: "admin"})
 
 	assert.NoError(t, err)
+	assert.Equal(t, domain.TeamRoleAdmin, member.Role)
 	teamRepo.AssertExpectations(t)
-	userRepo.AssertExpectations(t)
+	activityRepo.AssertExpectations(t)
 }
 
-func TestTeamService_InviteUser_InsufficientRole(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+func TestTeamService_ChangeMemberRole_AdminCannotDemoteAdmin(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
 
-	teamRepo.On("GetMember", mock.Anything, int64(1), int64(2)).Return(&domain.TeamMember{
-		TeamID: 1, UserID: 2, Role: domain.TeamRoleMember,
-	}, nil)
+	stubTeamMember(teamRepo, 2, domain.TeamRoleAdmin)
+	stubTeamMember(teamRepo, 3, domain.TeamRoleAdmin)
 
-	err := svc.InviteUser(context.Background(), 2, 1, domain.InviteRequest{
-		Email: "newuser@example.com",
-	})
+	_, err := svc.ChangeMemberRole(context.Background(), 2, 1, 3, domain.UpdateMemberRoleRequest{Role: "member"})
 
-	assert.Error(t, err)
 	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	teamRepo.AssertNotCalled(t, "UpdateMemberRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestTeamService_ListByUserID(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+func TestTeamService_ChangeMemberRole_OwnerRoleRejected(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
 
-	expected := []domain.Team{
-		{ID: 1, Name: "Team 1"},
-		{ID: 2, Name: "Team 2"},
-	}
-	teamRepo.On("ListByUserID", mock.Anything, int64(1)).Return(expected, nil)
+	_, err := svc.ChangeMemberRole(context.Background(), 1, 1, 2, domain.UpdateMemberRoleRequest{Role: "owner"})
+
+	assert.Error(t, err)
+	teamRepo.AssertNotCalled(t, "GetMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTeamService_RemoveMember_AdminRemovesMember(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
 
-	result, err := svc.ListByUserID(context.Background(), 1)
+	stubTeamMember(teamRepo, 2, domain.TeamRoleAdmin)
+	stubTeamMember(teamRepo, 3, domain.TeamRoleMember)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	teamRepo.On("RemoveMember", mock.Anything, int64(1), int64(3)).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.Type == domain.ActivityMemberRemoved && *e.TargetUserID == 3
+	})).Return(nil)
+
+	err := svc.RemoveMember(context.Background(), 2, 1, 3)
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 2)
+	teamRepo.AssertExpectations(t)
 }
 
-func TestTeamService_GetStats_Success(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+func TestTeamService_RemoveMember_AdminCannotRemoveOwner(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
 
-	expected := []domain.TeamStats{
-		{ID: 1, Name: "Team 1", MemberCount: 5, DoneLast7D: 3},
-	}
-	teamRepo.On("GetStats", mock.Anything, int64(1)).Return(expected, nil)
+	stubTeamMember(teamRepo, 2, domain.TeamRoleAdmin)
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+
+	err := svc.RemoveMember(context.Background(), 2, 1, 1)
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	teamRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTeamService_Leave_LastOwner(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	teamRepo.On("CountMembersByRole", mock.Anything, int64(1), domain.TeamRoleOwner).Return(1, nil)
 
-	result, err := svc.GetStats(context.Background(), 1)
+	err := svc.Leave(context.Background(), 1, 1)
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 409, appErr.Code)
+	teamRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTeamService_Leave_Member(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+
+	stubTeamMember(teamRepo, 3, domain.TeamRoleMember)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	teamRepo.On("RemoveMember", mock.Anything, int64(1), int64(3)).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.Type == domain.ActivityMemberLeft && e.ActorID == 3
+	})).Return(nil)
+
+	err := svc.Leave(context.Background(), 3, 1)
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 1)
-	assert.Equal(t, 5, result[0].MemberCount)
+	teamRepo.AssertExpectations(t)
+	teamRepo.AssertNotCalled(t, "CountMembersByRole", mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestTeamService_InviteUser_EmptyEmail(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+func TestTeamService_RemoveMember_OrgAdminTarget(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
 
-	err := svc.InviteUser(context.Background(), 1, 1, domain.InviteRequest{Email: ""})
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(4)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 4, Role: domain.TeamRoleAdmin, ViaOrg: true,
+	}, nil)
+
+	err := svc.RemoveMember(context.Background(), 1, 1, 4)
 
-	assert.Error(t, err)
 	appErr, ok := apperror.IsAppError(err)
 	assert.True(t, ok)
-	assert.Equal(t, 400, appErr.Code)
+	assert.Equal(t, 404, appErr.Code)
+	teamRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestTeamService_InviteUser_NotMember(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+func TestTeamService_ListMembers_NotMember(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
 
-	teamRepo.On("GetMember", mock.Anything, int64(1), int64(99)).Return(nil, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(9)).Return(nil, nil)
 
-	err := svc.InviteUser(context.Background(), 99, 1, domain.InviteRequest{Email: "user@example.com"})
+	members, err := svc.ListMembers(context.Background(), 9, 1)
 
-	assert.Error(t, err)
+	assert.Nil(t, members)
 	assert.Equal(t, apperror.ErrNotTeamMember, err)
 }
 
-func TestTeamService_InviteUser_AsAdmin(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+func TestTeamService_Update_RenamesAndRecordsEvent(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
 
-	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
-		TeamID: 1, UserID: 1, Role: domain.TeamRoleAdmin,
-	}, nil)
-	userRepo.On("GetByEmail", mock.Anything, "newuser@example.com").Return(&domain.User{
-		ID: 3, Email: "newuser@example.com",
-	}, nil)
-	teamRepo.On("AddMember", mock.Anything, mock.AnythingOfType("*domain.TeamMember")).Return(nil)
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, Name: "Old", Description: "same"}, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	teamRepo.On("Update", mock.Anything, mock.MatchedBy(func(team *domain.Team) bool {
+		return team.Name == "New" && team.Description == "same"
+	})).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.Type == domain.ActivityTeamUpdated && e.Details == "name"
+	})).Return(nil)
 
-	err := svc.InviteUser(context.Background(), 1, 1, domain.InviteRequest{
-		Email: "newuser@example.com",
-		Role:  "admin",
-	})
+	name, description := " New ", "same"
+	_, err := svc.Update(context.Background(), 1, 1, domain.UpdateTeamRequest{Name: &name, Description: &description})
 
 	assert.NoError(t, err)
+	teamRepo.AssertExpectations(t)
+	activityRepo.AssertExpectations(t)
 }
 
-func TestTeamService_GetTopContributors_Success(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+func TestTeamService_Update_MemberForbidden(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
 
-	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
-		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember,
+	stubTeamMember(teamRepo, 2, domain.TeamRoleMember)
+
+	name := "New"
+	_, err := svc.Update(context.Background(), 2, 1, domain.UpdateTeamRequest{Name: &name})
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+}
+
+func TestTeamService_Update_Require2FANeedsAdmin(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+	perms := string(domain.PermTeamUpdate)
+
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(2)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 2, Role: domain.TeamRoleMember, CustomPermissions: &perms, TwoFactorEnabled: true,
 	}, nil)
-	expected := []domain.TopContributor{
-		{UserID: 1, FullName: "User 1", TasksCreated: 10, Rank: 1},
-	}
-	teamRepo.On("GetTopContributors", mock.Anything, int64(1)).Return(expected, nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, Name: "Core"}, nil)
 
-	result, err := svc.GetTopContributors(context.Background(), 1, 1)
+	require2FA := true
+	_, err := svc.Update(context.Background(), 2, 1, domain.UpdateTeamRequest{Require2FA: &require2FA})
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	teamRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestTeamService_SetArchived_OwnerOnly(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+
+	stubTeamMember(teamRepo, 2, domain.TeamRoleAdmin)
+
+	_, err := svc.SetArchived(context.Background(), 2, 1, true)
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	teamRepo.AssertNotCalled(t, "SetArchived", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTeamService_SetArchived_InvalidatesCache(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	cache := new(mocks.TaskCacheMock)
+	svc := NewTeamService(teamRepo, new(mocks.OrganizationRepositoryMock), NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, cache, new(mocks.AttachmentServiceMock), "test-secret")
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	teamRepo.On("SetArchived", mock.Anything, int64(1), true).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.Type == domain.ActivityTeamArchived
+	})).Return(nil)
+	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1}, nil)
+
+	_, err := svc.SetArchived(context.Background(), 1, 1, true)
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 1)
-	assert.Equal(t, 10, result[0].TasksCreated)
+	cache.AssertExpectations(t)
 }
 
-func TestTeamService_GetTopContributors_NotMember(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+func TestTeamService_Delete_WithConfirmationToken(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	cache := new(mocks.TaskCacheMock)
+	attachSvc := new(mocks.AttachmentServiceMock)
+	svc := NewTeamService(teamRepo, new(mocks.OrganizationRepositoryMock), NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, cache, attachSvc, "test-secret")
 
-	teamRepo.On("GetMember", mock.Anything, int64(1), int64(99)).Return(nil, nil)
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	teamRepo.On("ListDescendants", mock.Anything, int64(1)).Return([]domain.Team{}, nil)
+	attachSvc.On("DeleteForTeam", mock.Anything, int64(1)).Return(nil)
+	teamRepo.On("Delete", mock.Anything, int64(1)).Return(nil)
+	cache.On("InvalidateTeam", mock.Anything, int64(1)).Return(nil)
 
-	result, err := svc.GetTopContributors(context.Background(), 99, 1)
+	token, err := svc.IssueDeletionToken(context.Background(), 1, 1)
+	assert.NoError(t, err)
 
-	assert.Nil(t, result)
-	assert.Error(t, err)
-	assert.Equal(t, apperror.ErrNotTeamMember, err)
+	err = svc.Delete(context.Background(), 1, 1, domain.DeleteTeamRequest{ConfirmationToken: token.Token})
+
+	assert.NoError(t, err)
+	attachSvc.AssertExpectations(t)
+	teamRepo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestTeamService_Delete_TokenForOtherTeam(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	teamRepo.On("GetMember", mock.Anything, int64(2), int64(1)).Return(&domain.TeamMember{
+		TeamID: 2, UserID: 1, Role: domain.TeamRoleOwner,
+	}, nil)
+
+	token, err := svc.IssueDeletionToken(context.Background(), 1, 2)
+	assert.NoError(t, err)
+
+	err = svc.Delete(context.Background(), 1, 1, domain.DeleteTeamRequest{ConfirmationToken: token.Token})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	teamRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
 }
 
-func TestTeamService_InviteUser_UserNotFound(t *testing.T) {
-	teamRepo, userRepo, txManager, notifSvc := newTeamServiceDeps()
-	svc := NewTeamService(teamRepo, userRepo, txManager, notifSvc)
+func TestTeamService_SetParent_RejectsCycle(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+	one, two, three := int64(1), int64(2), int64(3)
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	teamRepo.On("GetMember", mock.Anything, int64(3), int64(1)).Return(&domain.TeamMember{
+		TeamID: 3, UserID: 1, Role: domain.TeamRoleOwner,
+	}, nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, OrgID: 7}, nil)
+	teamRepo.On("GetByID", mock.Anything, int64(3)).Return(&domain.Team{ID: 3, OrgID: 7, ParentID: &two}, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	teamRepo.On("LockHierarchy", mock.Anything, int64(7)).Return(nil)
+	teamRepo.On("ListDescendants", mock.Anything, int64(1)).Return([]domain.Team{
+		{ID: 2, ParentID: &one},
+		{ID: 3, ParentID: &two},
+	}, nil)
+
+	_, err := svc.SetParent(context.Background(), 1, 1, domain.SetParentRequest{ParentID: &three})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 409, appErr.Code)
+	teamRepo.AssertCalled(t, "LockHierarchy", mock.Anything, int64(7))
+	teamRepo.AssertNotCalled(t, "SetParent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTeamService_SetParent_CustomRoleCannotMove(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+	perms := string(domain.PermTeamUpdate)
+	three := int64(3)
 
 	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
-		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner,
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleMember, CustomPermissions: &perms,
 	}, nil)
-	userRepo.On("GetByEmail", mock.Anything, "unknown@example.com").Return(nil, apperror.NotFound("user not found"))
 
-	err := svc.InviteUser(context.Background(), 1, 1, domain.InviteRequest{
-		Email: "unknown@example.com",
-	})
+	_, err := svc.SetParent(context.Background(), 1, 1, domain.SetParentRequest{ParentID: &three, InheritedRole: "admin"})
 
-	assert.Error(t, err)
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	teamRepo.AssertNotCalled(t, "SetParent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTeamService_SetParent_RequiresManagerOfParent(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+	perms := string(domain.PermTeamUpdate)
+	three := int64(3)
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	teamRepo.On("GetMember", mock.Anything, int64(3), int64(1)).Return(&domain.TeamMember{
+		TeamID: 3, UserID: 1, Role: domain.TeamRoleMember, CustomPermissions: &perms,
+	}, nil)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, OrgID: 7}, nil)
+
+	_, err := svc.SetParent(context.Background(), 1, 1, domain.SetParentRequest{ParentID: &three})
+
+	assert.Equal(t, apperror.ErrInsufficientRole, err)
+	teamRepo.AssertNotCalled(t, "SetParent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTeamService_SetParent_DisablesInheritance(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+
+	role := domain.TeamRoleMember
+	stubTeamMember(teamRepo, 1, domain.TeamRoleAdmin)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, OrgID: 7, InheritedRole: &role}, nil)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	teamRepo.On("LockHierarchy", mock.Anything, int64(7)).Return(nil)
+	teamRepo.On("SetParent", mock.Anything, int64(1), (*int64)(nil), (*domain.TeamRole)(nil)).Return(nil)
+	activityRepo.On("CreateTeamEvent", mock.Anything, mock.MatchedBy(func(e *domain.TeamEvent) bool {
+		return e.Type == domain.ActivityTeamUpdated && e.Details == "parent"
+	})).Return(nil)
+
+	_, err := svc.SetParent(context.Background(), 1, 1, domain.SetParentRequest{InheritedRole: "none"})
+
+	assert.NoError(t, err)
+	teamRepo.AssertExpectations(t)
+	activityRepo.AssertExpectations(t)
+}
+
+func TestTeamService_GetTree_NestsDescendants(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+	one, two := int64(1), int64(2)
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleMember)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1}, nil)
+	teamRepo.On("ListDescendants", mock.Anything, int64(1)).Return([]domain.Team{
+		{ID: 2, ParentID: &one},
+		{ID: 3, ParentID: &one},
+		{ID: 4, ParentID: &two},
+	}, nil)
+
+	tree, err := svc.GetTree(context.Background(), 1, 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, tree.Children, 2)
+	assert.Len(t, tree.Children[0].Children, 1)
+	assert.Equal(t, int64(4), tree.Children[0].Children[0].ID)
+	assert.Empty(t, tree.Children[1].Children)
+}
+
+func TestTeamService_Delete_RejectsTeamWithSubTeams(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+	one := int64(1)
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	teamRepo.On("ListDescendants", mock.Anything, int64(1)).Return([]domain.Team{{ID: 2, ParentID: &one}}, nil)
+
+	token, err := svc.IssueDeletionToken(context.Background(), 1, 1)
+	assert.NoError(t, err)
+
+	err = svc.Delete(context.Background(), 1, 1, domain.DeleteTeamRequest{ConfirmationToken: token.Token})
+
+	appErr, ok := apperror.IsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 409, appErr.Code)
+	teamRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
 }
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/totp"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	errInvalidTwoFactorCode = apperror.BadRequest("invalid two-factor code")
+	errTwoFactorNotEnabled  = apperror.BadRequest("two-factor authentication is not enabled")
+	errTwoFactorEnabled     = apperror.New(http.StatusConflict, "two-factor authentication is already enabled")
+)
+
+type TwoFactorServiceImpl struct {
+	userRepo     port.UserRepository
+	recoveryRepo port.RecoveryCodeRepository
+	txManager    port.TransactionManager
+	issuer       string
+}
+
+func NewTwoFactorService(userRepo port.UserRepository, recoveryRepo port.RecoveryCodeRepository, txManager port.TransactionManager, issuer string) *TwoFactorServiceImpl {
+	return &TwoFactorServiceImpl{
+		userRepo:     userRepo,
+		recoveryRepo: recoveryRepo,
+		txManager:    txManager,
+		issuer:       issuer,
+	}
+}
+
+// Enroll starts enrollment with a new secret once the password is
+// confirmed. 2FA is only switched on once Confirm sees a code generated from
+// it; enrolling again replaces a secret that was never confirmed.
+func (s *TwoFactorServiceImpl) Enroll(ctx context.Context, claims domain.AccessClaims, req domain.EnrollTwoFactorRequest) (*domain.TwoFactorEnrollment, error) {
+	user, err := s.loadUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, errTwoFactorEnabled
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, apperror.BadRequest("password is incorrect")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, apperror.Internal("generate totp secret", err)
+	}
+	if err := s.userRepo.SetTOTPSecret(ctx, user.ID, secret); err != nil {
+		return nil, err
+	}
+	return &domain.TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm switches 2FA on and returns the first set of recovery codes.
+func (s *TwoFactorServiceImpl) Confirm(ctx context.Context, claims domain.AccessClaims, req domain.TwoFactorCodeRequest) (*domain.RecoveryCodesResponse, error) {
+	user, err := s.loadUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, errTwoFactorEnabled
+	}
+	if user.TOTPSecret == nil {
+		return nil, apperror.BadRequest("start two-factor enrollment first")
+	}
+
+	counter, ok := totp.Validate(*user.TOTPSecret, req.Code, time.Now())
+	if !ok {
+		return nil, errInvalidTwoFactorCode
+	}
+
+	var codes []string
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.EnableTOTP(ctx, user.ID, counter); err != nil {
+			return err
+		}
+		codes, err = s.replaceRecoveryCodes(ctx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable needs the password and a second factor, so a stolen session alone
+// cannot turn 2FA off.
+func (s *TwoFactorServiceImpl) Disable(ctx context.Context, claims domain.AccessClaims, req domain.DisableTwoFactorRequest) error {
+	user, err := s.loadUser(ctx, claims)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled() {
+		return errTwoFactorNotEnabled
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return apperror.BadRequest("password is incorrect")
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.VerifySecondFactor(ctx, user, req.Code, req.RecoveryCode); err != nil {
+			return err
+		}
+		if err := s.userRepo.DisableTOTP(ctx, user.ID); err != nil {
+			return err
+		}
+		return s.recoveryRepo.DeleteForUser(ctx, user.ID)
+	})
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, used or not.
+func (s *TwoFactorServiceImpl) RegenerateRecoveryCodes(ctx context.Context, claims domain.AccessClaims, req domain.TwoFactorCodeRequest) (*domain.RecoveryCodesResponse, error) {
+	user, err := s.loadUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled() {
+		return nil, errTwoFactorNotEnabled
+	}
+
+	var codes []string
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.VerifySecondFactor(ctx, user, req.Code, ""); err != nil {
+			return err
+		}
+		codes, err = s.replaceRecoveryCodes(ctx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// VerifySecondFactor accepts a TOTP code that was not used before, or an
+// unused recovery code, which is then spent.
+func (s *TwoFactorServiceImpl) VerifySecondFactor(ctx context.Context, user *domain.User, code, recoveryCode string) error {
+	if !user.TwoFactorEnabled() || user.TOTPSecret == nil {
+		return errTwoFactorNotEnabled
+	}
+
+	switch {
+	case code != "":
+		counter, ok := totp.Validate(*user.TOTPSecret, code, time.Now())
+		if !ok {
+			return errInvalidTwoFactorCode
+		}
+		advanced, err := s.userRepo.AdvanceTOTPCounter(ctx, user.ID, counter)
+		if err != nil {
+			return err
+		}
+		if !advanced {
+			return errInvalidTwoFactorCode
+		}
+		return nil
+	case recoveryCode != "":
+		used, err := s.recoveryRepo.Use(ctx, user.ID, signedtoken.Hash(normalizeRecoveryCode(recoveryCode)))
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errInvalidTwoFactorCode
+		}
+		return nil
+	default:
+		return apperror.BadRequest("code or recovery_code is required")
+	}
+}
+
+func (s *TwoFactorServiceImpl) loadUser(ctx context.Context, claims domain.AccessClaims) (*domain.User, error) {
+	if claims.ImpersonatorID != 0 {
+		return nil, apperror.Forbidden("not available while impersonating")
+	}
+	return s.userRepo.GetByID(ctx, claims.UserID)
+}
+
+func (s *TwoFactorServiceImpl) replaceRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, apperror.Internal("generate recovery code", err)
+		}
+		codes[i] = code
+		hashes[i] = signedtoken.Hash(normalizeRecoveryCode(code))
+	}
+	if err := s.recoveryRepo.Replace(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode returns 50 random bits as "xxxxx-xxxxx".
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	s := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return s[:5] + "-" + s[5:], nil
+}
+
+// normalizeRecoveryCode lets users type codes without the dash or in
+// upper case.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
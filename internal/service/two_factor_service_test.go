@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/signedtoken"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/totp"
+	"github.com/shalfey088/team-task-nexus/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+func newTwoFactorService(userRepo *mocks.UserRepositoryMock, recoveryRepo *mocks.RecoveryCodeRepositoryMock) *TwoFactorServiceImpl {
+	txManager := new(mocks.TransactionManagerMock)
+	txManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil).Maybe()
+	return NewTwoFactorService(userRepo, recoveryRepo, txManager, "Test App")
+}
+
+func twoFactorUser() *domain.User {
+	secret := testTOTPSecret
+	enabled := time.Now()
+	return &domain.User{ID: 1, Email: "user@example.com", TOTPSecret: &secret, TOTPEnabledAt: &enabled}
+}
+
+func currentCode(t *testing.T) string {
+	code, err := totp.Code(testTOTPSecret, totp.Counter(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+func TestTwoFactorService_Enroll_ReturnsURI(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newTwoFactorService(userRepo, new(mocks.RecoveryCodeRepositoryMock))
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1, Email: "user@example.com", PasswordHash: string(hash)}, nil)
+	userRepo.On("SetTOTPSecret", mock.Anything, int64(1), mock.AnythingOfType("string")).Return(nil)
+
+	enrollment, err := svc.Enroll(context.Background(), domain.AccessClaims{UserID: 1}, domain.EnrollTwoFactorRequest{Password: "password123"})
+
+	require.NoError(t, err)
+	assert.Len(t, enrollment.Secret, 32)
+	assert.True(t, strings.HasPrefix(enrollment.OTPAuthURI, "otpauth://totp/Test%20App:user@example.com?"))
+	assert.Contains(t, enrollment.OTPAuthURI, "secret="+enrollment.Secret)
+	userRepo.AssertCalled(t, "SetTOTPSecret", mock.Anything, int64(1), enrollment.Secret)
+}
+
+func TestTwoFactorService_Enroll_AlreadyEnabled(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newTwoFactorService(userRepo, new(mocks.RecoveryCodeRepositoryMock))
+
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(twoFactorUser(), nil)
+
+	_, err := svc.Enroll(context.Background(), domain.AccessClaims{UserID: 1}, domain.EnrollTwoFactorRequest{Password: "password123"})
+
+	assert.Equal(t, errTwoFactorEnabled, err)
+	userRepo.AssertNotCalled(t, "SetTOTPSecret", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTwoFactorService_Enroll_WrongPassword(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newTwoFactorService(userRepo, new(mocks.RecoveryCodeRepositoryMock))
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1, Email: "user@example.com", PasswordHash: string(hash)}, nil)
+
+	_, err := svc.Enroll(context.Background(), domain.AccessClaims{UserID: 1}, domain.EnrollTwoFactorRequest{Password: "guess"})
+
+	appErr, ok := apperror.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	userRepo.AssertNotCalled(t, "SetTOTPSecret", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTwoFactorService_Confirm_StoresHashedRecoveryCodes(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	recoveryRepo := new(mocks.RecoveryCodeRepositoryMock)
+	svc := newTwoFactorService(userRepo, recoveryRepo)
+
+	secret := testTOTPSecret
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1, TOTPSecret: &secret}, nil)
+	userRepo.On("EnableTOTP", mock.Anything, int64(1), mock.AnythingOfType("int64")).Return(nil)
+	var hashes []string
+	recoveryRepo.On("Replace", mock.Anything, int64(1), mock.AnythingOfType("[]string")).
+		Run(func(args mock.Arguments) { hashes = args.Get(2).([]string) }).
+		Return(nil)
+
+	result, err := svc.Confirm(context.Background(), domain.AccessClaims{UserID: 1}, domain.TwoFactorCodeRequest{Code: currentCode(t)})
+
+	require.NoError(t, err)
+	require.Len(t, result.RecoveryCodes, recoveryCodeCount)
+	assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, result.RecoveryCodes[0])
+	assert.Equal(t, signedtoken.Hash(normalizeRecoveryCode(result.RecoveryCodes[0])), hashes[0], "only hashes are stored")
+}
+
+func TestTwoFactorService_Confirm_WrongCode(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newTwoFactorService(userRepo, new(mocks.RecoveryCodeRepositoryMock))
+
+	secret := testTOTPSecret
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.User{ID: 1, TOTPSecret: &secret}, nil)
+
+	_, err := svc.Confirm(context.Background(), domain.AccessClaims{UserID: 1}, domain.TwoFactorCodeRequest{Code: "000000x"})
+
+	assert.Equal(t, errInvalidTwoFactorCode, err)
+	userRepo.AssertNotCalled(t, "EnableTOTP", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTwoFactorService_VerifySecondFactor_RejectsReplayedCode(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newTwoFactorService(userRepo, new(mocks.RecoveryCodeRepositoryMock))
+
+	userRepo.On("AdvanceTOTPCounter", mock.Anything, int64(1), mock.AnythingOfType("int64")).Return(false, nil)
+
+	err := svc.VerifySecondFactor(context.Background(), twoFactorUser(), currentCode(t), "")
+
+	assert.Equal(t, errInvalidTwoFactorCode, err)
+}
+
+func TestTwoFactorService_VerifySecondFactor_RecoveryCode(t *testing.T) {
+	recoveryRepo := new(mocks.RecoveryCodeRepositoryMock)
+	svc := newTwoFactorService(new(mocks.UserRepositoryMock), recoveryRepo)
+
+	recoveryRepo.On("Use", mock.Anything, int64(1), signedtoken.Hash("abcdefghij")).Return(true, nil).Once()
+	recoveryRepo.On("Use", mock.Anything, int64(1), signedtoken.Hash("abcdefghij")).Return(false, nil)
+
+	assert.NoError(t, svc.VerifySecondFactor(context.Background(), twoFactorUser(), "", "ABCDE-FGHIJ"))
+	assert.Equal(t, errInvalidTwoFactorCode, svc.VerifySecondFactor(context.Background(), twoFactorUser(), "", "abcde-fghij"), "recovery codes work once")
+}
+
+func TestTwoFactorService_Disable_RequiresPassword(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	svc := newTwoFactorService(userRepo, new(mocks.RecoveryCodeRepositoryMock))
+
+	user := twoFactorUser()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	user.PasswordHash = string(hash)
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(user, nil)
+
+	err := svc.Disable(context.Background(), domain.AccessClaims{UserID: 1}, domain.DisableTwoFactorRequest{Password: "wrong", Code: currentCode(t)})
+
+	appErr, ok := apperror.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, 400, appErr.Code)
+	userRepo.AssertNotCalled(t, "DisableTOTP", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_TwoFactorChallenge(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	refreshRepo := new(mocks.RefreshTokenRepositoryMock)
+	refreshRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(int64(1), nil)
+	denylist := new(mocks.TokenDenylistMock)
+	svc := newAuthServiceWithSessions(userRepo, new(mocks.InvitationServiceMock), refreshRepo, denylist)
+	secondFactor := new(mocks.SecondFactorVerifierMock)
+	svc.secondFactor = secondFactor
+
+	user := twoFactorUser()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	user.PasswordHash = string(hash)
+	userRepo.On("GetByEmail", mock.Anything, "user@example.com").Return(user, nil)
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(user, nil)
+
+	challenge, err := svc.Login(context.Background(), domain.LoginRequest{Email: "user@example.com", Password: "password"})
+	require.NoError(t, err)
+	assert.True(t, challenge.TwoFactorRequired)
+	assert.Empty(t, challenge.Token)
+	assert.Empty(t, challenge.RefreshToken)
+
+	denylist.On("Claim", mock.Anything, mock.AnythingOfType("string"), challengeTTL).Return(true, nil).Once()
+	secondFactor.On("VerifySecondFactor", mock.Anything, user, "123456", "").Return(nil)
+
+	session, err := svc.CompleteLogin(context.Background(), domain.LoginChallengeRequest{ChallengeToken: challenge.ChallengeToken, Code: "123456"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, session.Token)
+	assert.NotEmpty(t, session.RefreshToken)
+
+	denylist.On("Claim", mock.Anything, mock.AnythingOfType("string"), challengeTTL).Return(false, nil)
+	_, err = svc.CompleteLogin(context.Background(), domain.LoginChallengeRequest{ChallengeToken: challenge.ChallengeToken, Code: "123456"})
+	assert.Equal(t, errInvalidChallenge, err, "challenges work once")
+}
+
+func TestAuthService_CompleteLogin_WrongCodeEndsChallenge(t *testing.T) {
+	userRepo := new(mocks.UserRepositoryMock)
+	denylist := new(mocks.TokenDenylistMock)
+	svc := newAuthServiceWithSessions(userRepo, new(mocks.InvitationServiceMock), new(mocks.RefreshTokenRepositoryMock), denylist)
+	secondFactor := new(mocks.SecondFactorVerifierMock)
+	svc.secondFactor = secondFactor
+
+	user := twoFactorUser()
+	userRepo.On("GetByID", mock.Anything, int64(1)).Return(user, nil)
+	denylist.On("Claim", mock.Anything, mock.AnythingOfType("string"), challengeTTL).Return(true, nil)
+	secondFactor.On("VerifySecondFactor", mock.Anything, user, "000000", "").Return(errInvalidTwoFactorCode)
+
+	challenge, err := svc.issueChallenge(1)
+	require.NoError(t, err)
+	_, err = svc.CompleteLogin(context.Background(), domain.LoginChallengeRequest{ChallengeToken: challenge, Code: "000000"})
+
+	assert.Equal(t, errInvalidChallenge, err)
+	denylist.AssertCalled(t, "Claim", mock.Anything, mock.AnythingOfType("string"), challengeTTL)
+}
+
+func TestAuthorizer_Member_RequiresTwoFactor(t *testing.T) {
+	teamRepo := new(mocks.TeamRepositoryMock)
+	authz := NewAuthorizer(teamRepo)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(1)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 1, Role: domain.TeamRoleOwner, TeamRequire2FA: true,
+	}, nil)
+	teamRepo.On("GetMember", mock.Anything, int64(1), int64(2)).Return(&domain.TeamMember{
+		TeamID: 1, UserID: 2, Role: domain.TeamRoleMember, TeamRequire2FA: true, TwoFactorEnabled: true,
+	}, nil)
+
+	_, err := authz.Member(context.Background(), 1, 1)
+	assert.Equal(t, errTwoFactorRequired, err)
+
+	_, err = authz.Member(context.Background(), 2, 1)
+	assert.NoError(t, err)
+}
+
+func TestTeamService_Update_Require2FANeedsOwn2FA(t *testing.T) {
+	teamRepo, userRepo, activityRepo, txManager, notifSvc := newTeamServiceDeps()
+	svc := newTeamService(teamRepo, userRepo, activityRepo, txManager, notifSvc)
+
+	stubTeamMember(teamRepo, 1, domain.TeamRoleOwner)
+	teamRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Team{ID: 1, Name: "Team"}, nil)
+
+	require2FA := true
+	_, err := svc.Update(context.Background(), 1, 1, domain.UpdateTeamRequest{Require2FA: &require2FA})
+
+	appErr, ok := apperror.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, 403, appErr.Code)
+	teamRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,7 @@
+ALTER TABLE task_history
+    DROP FOREIGN KEY fk_history_change_set,
+    DROP INDEX idx_history_change_set,
+    DROP COLUMN change_set_id,
+    DROP COLUMN value_type;
+
+DROP TABLE IF EXISTS task_change_sets;
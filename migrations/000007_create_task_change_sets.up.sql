@@ -0,0 +1,44 @@
+CREATE TABLE task_change_sets (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    task_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    source ENUM('api', 'automation', 'import') NOT NULL DEFAULT 'api',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_change_sets_task_time (task_id, created_at),
+    INDEX idx_change_sets_user (user_id),
+    CONSTRAINT fk_change_sets_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_change_sets_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE task_history
+    ADD COLUMN change_set_id BIGINT NULL AFTER id,
+    ADD COLUMN value_type VARCHAR(20) NOT NULL DEFAULT 'string' AFTER field;
+
+-- Existing rows written by a single update share task, author and timestamp.
+INSERT INTO task_change_sets (task_id, user_id, source, created_at)
+SELECT task_id, user_id, 'api', changed_at
+FROM task_history
+GROUP BY task_id, user_id, changed_at;
+
+UPDATE task_history h
+JOIN task_change_sets cs
+    ON cs.task_id = h.task_id AND cs.user_id = h.user_id AND cs.created_at = h.changed_at
+SET h.change_set_id = cs.id;
+
+UPDATE task_history SET value_type = CASE field
+    WHEN 'description' THEN 'text'
+    WHEN 'status' THEN 'status'
+    WHEN 'priority' THEN 'priority'
+    WHEN 'assignee_id' THEN 'user'
+    WHEN 'due_date' THEN 'date'
+    ELSE 'string'
+END;
+
+UPDATE task_history SET old_value = '' WHERE field = 'assignee_id' AND old_value = 'unassigned';
+UPDATE task_history SET old_value = '' WHERE field = 'due_date' AND old_value = 'none';
+
+ALTER TABLE task_history
+    MODIFY COLUMN change_set_id BIGINT NOT NULL,
+    ADD INDEX idx_history_change_set (change_set_id),
+    ADD CONSTRAINT fk_history_change_set FOREIGN KEY (change_set_id) REFERENCES task_change_sets(id) ON DELETE CASCADE;
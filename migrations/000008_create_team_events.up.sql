@@ -0,0 +1,14 @@
+CREATE TABLE team_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    team_id BIGINT NOT NULL,
+    actor_id BIGINT NOT NULL,
+    target_user_id BIGINT NULL,
+    type VARCHAR(40) NOT NULL,
+    details VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_team_events_team_time (team_id, created_at),
+    INDEX idx_team_events_actor (actor_id),
+    CONSTRAINT fk_team_events_team FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    CONSTRAINT fk_team_events_actor FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_team_events_target FOREIGN KEY (target_user_id) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
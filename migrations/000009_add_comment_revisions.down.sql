@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS task_comment_revisions;
+
+ALTER TABLE task_comments
+    DROP FOREIGN KEY fk_comments_deleted_by,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at,
+    DROP COLUMN edited_at;
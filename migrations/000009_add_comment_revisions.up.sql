@@ -0,0 +1,16 @@
+ALTER TABLE task_comments
+    ADD COLUMN edited_at TIMESTAMP NULL AFTER content,
+    ADD COLUMN deleted_at TIMESTAMP NULL AFTER edited_at,
+    ADD COLUMN deleted_by BIGINT NULL AFTER deleted_at,
+    ADD CONSTRAINT fk_comments_deleted_by FOREIGN KEY (deleted_by) REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE TABLE task_comment_revisions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    comment_id BIGINT NOT NULL,
+    content TEXT NOT NULL,
+    edited_by BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_comment_revisions_comment (comment_id, created_at),
+    CONSTRAINT fk_comment_revisions_comment FOREIGN KEY (comment_id) REFERENCES task_comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_comment_revisions_user FOREIGN KEY (edited_by) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
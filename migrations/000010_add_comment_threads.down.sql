@@ -0,0 +1,10 @@
+ALTER TABLE task_comments
+    DROP FOREIGN KEY fk_comments_resolved_by,
+    DROP FOREIGN KEY fk_comments_root,
+    DROP FOREIGN KEY fk_comments_parent,
+    DROP INDEX idx_comments_root,
+    DROP COLUMN resolved_by,
+    DROP COLUMN resolved_at,
+    DROP COLUMN depth,
+    DROP COLUMN root_id,
+    DROP COLUMN parent_id;
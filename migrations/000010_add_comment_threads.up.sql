@@ -0,0 +1,10 @@
+ALTER TABLE task_comments
+    ADD COLUMN parent_id BIGINT NULL AFTER user_id,
+    ADD COLUMN root_id BIGINT NULL AFTER parent_id,
+    ADD COLUMN depth INT NOT NULL DEFAULT 0 AFTER root_id,
+    ADD COLUMN resolved_at TIMESTAMP NULL AFTER deleted_by,
+    ADD COLUMN resolved_by BIGINT NULL AFTER resolved_at,
+    ADD INDEX idx_comments_root (root_id),
+    ADD CONSTRAINT fk_comments_parent FOREIGN KEY (parent_id) REFERENCES task_comments(id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_comments_root FOREIGN KEY (root_id) REFERENCES task_comments(id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_comments_resolved_by FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL;
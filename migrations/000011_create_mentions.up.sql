@@ -0,0 +1,17 @@
+CREATE TABLE mentions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    author_id BIGINT NOT NULL,
+    team_id BIGINT NOT NULL,
+    task_id BIGINT NOT NULL,
+    comment_id BIGINT NULL,
+    source ENUM('task', 'comment') NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_mentions_user_time (user_id, created_at),
+    INDEX idx_mentions_task (task_id),
+    CONSTRAINT fk_mentions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_mentions_author FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_mentions_team FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    CONSTRAINT fk_mentions_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_mentions_comment FOREIGN KEY (comment_id) REFERENCES task_comments(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
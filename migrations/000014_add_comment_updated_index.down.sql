@@ -0,0 +1,2 @@
+ALTER TABLE task_comments
+    DROP INDEX idx_comments_task_updated;
@@ -0,0 +1,2 @@
+ALTER TABLE task_comments
+    ADD INDEX idx_comments_task_updated (task_id, updated_at);
@@ -0,0 +1,19 @@
+CREATE TABLE team_invitations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    team_id BIGINT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    role ENUM('admin', 'member') NOT NULL DEFAULT 'member',
+    inviter_id BIGINT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    status ENUM('pending', 'accepted', 'declined', 'revoked') NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMP NOT NULL,
+    accepted_by BIGINT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP NULL,
+    UNIQUE KEY uq_team_invitations_token (token_hash),
+    INDEX idx_team_invitations_team_status (team_id, status),
+    INDEX idx_team_invitations_email (email),
+    CONSTRAINT fk_team_invitations_team FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    CONSTRAINT fk_team_invitations_inviter FOREIGN KEY (inviter_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_team_invitations_accepted_by FOREIGN KEY (accepted_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS team_join_link_uses;
+DROP TABLE IF EXISTS team_join_links;
@@ -0,0 +1,28 @@
+CREATE TABLE team_join_links (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    team_id BIGINT NOT NULL,
+    code VARCHAR(32) NOT NULL,
+    role ENUM('admin', 'member') NOT NULL DEFAULT 'member',
+    creator_id BIGINT NOT NULL,
+    max_uses INT NULL,
+    use_count INT NOT NULL DEFAULT 0,
+    allowed_domain VARCHAR(255) NULL,
+    expires_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_team_join_links_code (code),
+    INDEX idx_team_join_links_team (team_id, created_at),
+    CONSTRAINT fk_team_join_links_team FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    CONSTRAINT fk_team_join_links_creator FOREIGN KEY (creator_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE team_join_link_uses (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    link_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    used_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_team_join_link_uses_link (link_id, used_at),
+    INDEX idx_team_join_link_uses_user (user_id),
+    CONSTRAINT fk_team_join_link_uses_link FOREIGN KEY (link_id) REFERENCES team_join_links(id) ON DELETE CASCADE,
+    CONSTRAINT fk_team_join_link_uses_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
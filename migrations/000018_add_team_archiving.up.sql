@@ -0,0 +1,2 @@
+ALTER TABLE teams
+    ADD COLUMN archived_at TIMESTAMP NULL AFTER owner_id;
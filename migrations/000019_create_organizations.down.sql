@@ -0,0 +1,7 @@
+ALTER TABLE teams
+    DROP FOREIGN KEY fk_teams_org,
+    DROP INDEX idx_teams_org,
+    DROP COLUMN org_id;
+
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,46 @@
+CREATE TABLE organizations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    personal BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_organizations_creator (created_by, personal),
+    CONSTRAINT fk_organizations_creator FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE organization_members (
+    org_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    role ENUM('admin', 'member', 'billing') NOT NULL DEFAULT 'member',
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_organization_members_unique (org_id, user_id),
+    INDEX idx_organization_members_user (user_id),
+    CONSTRAINT fk_organization_members_org FOREIGN KEY (org_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_organization_members_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Every existing team owner gets a personal organization holding their teams.
+INSERT INTO organizations (name, personal, created_by)
+SELECT CONCAT(u.full_name, '''s workspace'), TRUE, u.id
+FROM users u
+WHERE u.id IN (SELECT owner_id FROM teams);
+
+ALTER TABLE teams ADD COLUMN org_id BIGINT NULL AFTER id;
+
+UPDATE teams t
+JOIN organizations o ON o.created_by = t.owner_id AND o.personal = TRUE
+SET t.org_id = o.id;
+
+INSERT INTO organization_members (org_id, user_id, role)
+SELECT id, created_by, 'admin' FROM organizations;
+
+INSERT IGNORE INTO organization_members (org_id, user_id, role)
+SELECT DISTINCT t.org_id, tm.user_id, 'member'
+FROM team_members tm
+JOIN teams t ON t.id = tm.team_id;
+
+ALTER TABLE teams
+    MODIFY org_id BIGINT NOT NULL,
+    ADD INDEX idx_teams_org (org_id),
+    ADD CONSTRAINT fk_teams_org FOREIGN KEY (org_id) REFERENCES organizations(id) ON DELETE RESTRICT;
@@ -0,0 +1,5 @@
+ALTER TABLE team_members
+    DROP FOREIGN KEY fk_team_members_custom_role,
+    DROP COLUMN custom_role_id;
+
+DROP TABLE IF EXISTS team_roles;
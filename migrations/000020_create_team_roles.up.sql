@@ -0,0 +1,14 @@
+CREATE TABLE team_roles (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    team_id BIGINT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    permissions VARCHAR(512) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_team_roles_name (team_id, name),
+    CONSTRAINT fk_team_roles_team FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE team_members
+    ADD COLUMN custom_role_id BIGINT NULL AFTER role,
+    ADD CONSTRAINT fk_team_members_custom_role FOREIGN KEY (custom_role_id) REFERENCES team_roles(id) ON DELETE SET NULL;
@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS team_guest_tasks;
+
+ALTER TABLE task_comments
+    DROP COLUMN internal;
+
+DELETE FROM team_join_links WHERE role = 'guest';
+ALTER TABLE team_join_links
+    MODIFY COLUMN role ENUM('admin', 'member') NOT NULL DEFAULT 'member';
+
+DELETE FROM team_invitations WHERE role = 'guest';
+ALTER TABLE team_invitations
+    MODIFY COLUMN role ENUM('admin', 'member') NOT NULL DEFAULT 'member';
+
+DELETE FROM team_members WHERE role = 'guest';
+ALTER TABLE team_members
+    DROP COLUMN task_scoped,
+    MODIFY COLUMN role ENUM('owner', 'admin', 'member') NOT NULL DEFAULT 'member';
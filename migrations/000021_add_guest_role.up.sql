@@ -0,0 +1,23 @@
+ALTER TABLE team_members
+    MODIFY COLUMN role ENUM('owner', 'admin', 'member', 'guest') NOT NULL DEFAULT 'member',
+    ADD COLUMN task_scoped BOOLEAN NOT NULL DEFAULT FALSE AFTER custom_role_id;
+
+ALTER TABLE team_invitations
+    MODIFY COLUMN role ENUM('admin', 'member', 'guest') NOT NULL DEFAULT 'member';
+
+ALTER TABLE team_join_links
+    MODIFY COLUMN role ENUM('admin', 'member', 'guest') NOT NULL DEFAULT 'member';
+
+ALTER TABLE task_comments
+    ADD COLUMN internal BOOLEAN NOT NULL DEFAULT FALSE AFTER depth;
+
+CREATE TABLE team_guest_tasks (
+    team_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    task_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (team_id, user_id, task_id),
+    INDEX idx_team_guest_tasks_task (task_id),
+    CONSTRAINT fk_team_guest_tasks_member FOREIGN KEY (team_id, user_id) REFERENCES team_members(team_id, user_id) ON DELETE CASCADE,
+    CONSTRAINT fk_team_guest_tasks_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,5 @@
+ALTER TABLE teams
+    DROP FOREIGN KEY fk_teams_parent,
+    DROP INDEX idx_teams_parent,
+    DROP COLUMN inherited_role,
+    DROP COLUMN parent_id;
@@ -0,0 +1,5 @@
+ALTER TABLE teams
+    ADD COLUMN parent_id BIGINT NULL AFTER org_id,
+    ADD COLUMN inherited_role ENUM('admin', 'member', 'guest') NULL DEFAULT 'member' AFTER parent_id,
+    ADD INDEX idx_teams_parent (parent_id),
+    ADD CONSTRAINT fk_teams_parent FOREIGN KEY (parent_id) REFERENCES teams(id) ON DELETE SET NULL;
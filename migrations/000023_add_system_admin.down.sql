@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS admin_audit_log;
+
+ALTER TABLE users
+    DROP COLUMN disabled_at,
+    DROP COLUMN system_role;
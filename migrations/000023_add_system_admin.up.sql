@@ -0,0 +1,15 @@
+ALTER TABLE users
+    ADD COLUMN system_role ENUM('user', 'admin') NOT NULL DEFAULT 'user' AFTER full_name,
+    ADD COLUMN disabled_at TIMESTAMP NULL AFTER system_role;
+
+CREATE TABLE admin_audit_log (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    admin_id BIGINT NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    target_user_id BIGINT NULL,
+    details VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_admin_audit_created (created_at, id),
+    CONSTRAINT fk_admin_audit_admin FOREIGN KEY (admin_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_admin_audit_target FOREIGN KEY (target_user_id) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
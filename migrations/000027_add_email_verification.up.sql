@@ -0,0 +1,32 @@
+-- Emails are about to be lowercased and trimmed. Two accounts that differ
+-- only by case or surrounding whitespace would collide on idx_users_email,
+-- so check for that before changing anything: a collision fails here with
+-- "Duplicate entry '<email>'" naming the address. Merge or rename those
+-- accounts, then run the migration again.
+CREATE TEMPORARY TABLE normalized_user_emails (
+    email VARCHAR(255) NOT NULL,
+    UNIQUE KEY uq_normalized_user_emails (email)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO normalized_user_emails (email)
+SELECT LOWER(TRIM(email)) FROM users;
+
+DROP TEMPORARY TABLE normalized_user_emails;
+
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMP NULL AFTER email;
+
+-- Accounts created before verification existed are trusted as they are.
+UPDATE users SET email = LOWER(TRIM(email)), email_verified_at = created_at;
+
+CREATE TABLE email_verification_tokens (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_email_verification_tokens_token (token_hash),
+    INDEX idx_email_verification_tokens_user (user_id, used_at),
+    CONSTRAINT fk_email_verification_tokens_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS recovery_codes;
+
+ALTER TABLE teams
+    DROP COLUMN require_2fa;
+
+ALTER TABLE users
+    DROP COLUMN totp_last_counter,
+    DROP COLUMN totp_enabled_at,
+    DROP COLUMN totp_secret;
@@ -0,0 +1,17 @@
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(64) NULL AFTER password_hash,
+    ADD COLUMN totp_enabled_at TIMESTAMP NULL AFTER totp_secret,
+    ADD COLUMN totp_last_counter BIGINT NOT NULL DEFAULT 0 AFTER totp_enabled_at;
+
+ALTER TABLE teams
+    ADD COLUMN require_2fa BOOLEAN NOT NULL DEFAULT FALSE AFTER owner_id;
+
+CREATE TABLE recovery_codes (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_recovery_codes_user_code (user_id, code_hash),
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+ALTER TABLE tasks
+    DROP INDEX idx_tasks_team_created;
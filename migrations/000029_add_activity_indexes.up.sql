@@ -0,0 +1,3 @@
+-- The activity feed reads each source newest first within a team.
+ALTER TABLE tasks
+    ADD INDEX idx_tasks_team_created (team_id, created_at);
@@ -0,0 +1,468 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/adapter/cache/redis"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	localmail "github.com/shalfey088/team-task-nexus/internal/adapter/mail/local"
+	mysqlrepo "github.com/shalfey088/team-task-nexus/internal/adapter/repository/mysql"
+	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/jwtkeys"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/totp"
+	"github.com/shalfey088/team-task-nexus/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthSessions_Integration(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	userRepo := mysqlrepo.NewUserRepo(testDB)
+	txManager := mysqlrepo.NewTransactionManager(testDB)
+	denylist := redis.NewTokenDenylist(testRedis)
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), newTwoFactorService(), denylist, testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+
+	// The access token is checked by the real middleware, so a revoked
+	// session shows up as a 401.
+	var claims *domain.AccessClaims
+	protected := middleware.Authenticate(testKeys, denylist, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims = middleware.GetClaims(r.Context())
+	}))
+	call := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		protected.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	registered, err := authSvc.Register(ctx, domain.RegisterRequest{
+		Email: "session@test.com", Password: "password", FullName: "Session User",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, registered.RefreshToken)
+	assert.Equal(t, http.StatusOK, call(registered.Token))
+
+	// Refreshing rotates the token; replaying the old one kills the session
+	rotated, err := authSvc.Refresh(ctx, registered.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, registered.RefreshToken, rotated.RefreshToken)
+
+	_, err = authSvc.Refresh(ctx, registered.RefreshToken)
+	assert.Error(t, err)
+	_, err = authSvc.Refresh(ctx, rotated.RefreshToken)
+	assert.Error(t, err, "reuse revokes the whole family")
+	assert.Equal(t, http.StatusUnauthorized, call(rotated.Token))
+
+	// Logout ends only the current session
+	first, err := authSvc.Login(ctx, domain.LoginRequest{Email: "session@test.com", Password: "password"})
+	require.NoError(t, err)
+	second, err := authSvc.Login(ctx, domain.LoginRequest{Email: "session@test.com", Password: "password"})
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, call(first.Token))
+	require.NoError(t, authSvc.Logout(ctx, *claims))
+	assert.Equal(t, http.StatusUnauthorized, call(first.Token))
+	_, err = authSvc.Refresh(ctx, first.RefreshToken)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusOK, call(second.Token))
+
+	// Logging out everywhere ends the remaining sessions too
+	third, err := authSvc.Login(ctx, domain.LoginRequest{Email: "session@test.com", Password: "password"})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, call(third.Token))
+	require.NoError(t, authSvc.LogoutEverywhere(ctx, *claims))
+	assert.Equal(t, http.StatusUnauthorized, call(second.Token))
+	assert.Equal(t, http.StatusUnauthorized, call(third.Token))
+	_, err = authSvc.Refresh(ctx, second.RefreshToken)
+	assert.Error(t, err)
+}
+
+func TestPasswordReset_Integration(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	userRepo := mysqlrepo.NewUserRepo(testDB)
+	txManager := mysqlrepo.NewTransactionManager(testDB)
+	denylist := redis.NewTokenDenylist(testRedis)
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), newTwoFactorService(), denylist, testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	mailDir := t.TempDir()
+	mailer, err := localmail.NewMailer("test@localhost", mailDir)
+	require.NoError(t, err)
+	passwordSvc := service.NewPasswordService(userRepo, mysqlrepo.NewPasswordResetRepo(testDB), txManager, authSvc, mailer, "test-secret", time.Hour, "http://app.test/reset?token=%s")
+
+	// The reset link is read back from the mailed .eml file
+	lastLink := func() string {
+		files, err := filepath.Glob(filepath.Join(mailDir, "*.eml"))
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+		data, err := os.ReadFile(files[len(files)-1])
+		require.NoError(t, err)
+		_, after, ok := strings.Cut(string(data), "http://app.test/reset?token=")
+		require.True(t, ok)
+		token, err := url.QueryUnescape(strings.Fields(after)[0])
+		require.NoError(t, err)
+		return token
+	}
+
+	registered, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "forgetful@test.com", Password: "password", FullName: "Forgetful"})
+	require.NoError(t, err)
+
+	require.NoError(t, passwordSvc.Forgot(ctx, domain.ForgotPasswordRequest{Email: "nobody@test.com"}))
+	files, _ := filepath.Glob(filepath.Join(mailDir, "*.eml"))
+	assert.Empty(t, files, "unknown addresses get no mail")
+
+	require.NoError(t, passwordSvc.Forgot(ctx, domain.ForgotPasswordRequest{Email: "forgetful@test.com"}))
+	first := lastLink()
+	require.NoError(t, passwordSvc.Forgot(ctx, domain.ForgotPasswordRequest{Email: "forgetful@test.com"}))
+	second := lastLink()
+	assert.Error(t, passwordSvc.Reset(ctx, domain.ResetPasswordRequest{Token: first, NewPassword: "new-password"}), "a newer link replaces the old one")
+
+	// The token works once and ends existing sessions
+	require.NoError(t, passwordSvc.Reset(ctx, domain.ResetPasswordRequest{Token: second, NewPassword: "new-password"}))
+	assert.Error(t, passwordSvc.Reset(ctx, domain.ResetPasswordRequest{Token: second, NewPassword: "other-password"}))
+	_, err = authSvc.Refresh(ctx, registered.RefreshToken)
+	assert.Error(t, err)
+
+	_, err = authSvc.Login(ctx, domain.LoginRequest{Email: "forgetful@test.com", Password: "password"})
+	assert.Error(t, err)
+	session, err := authSvc.Login(ctx, domain.LoginRequest{Email: "forgetful@test.com", Password: "new-password"})
+	require.NoError(t, err)
+
+	// Changing the password needs the old one and signs out everywhere
+	claims := domain.AccessClaims{UserID: session.User.ID}
+	assert.Error(t, passwordSvc.Change(ctx, claims, domain.ChangePasswordRequest{OldPassword: "password", NewPassword: "third-password"}))
+	require.NoError(t, passwordSvc.Change(ctx, claims, domain.ChangePasswordRequest{OldPassword: "new-password", NewPassword: "third-password"}))
+	_, err = authSvc.Refresh(ctx, session.RefreshToken)
+	assert.Error(t, err)
+	_, err = authSvc.Login(ctx, domain.LoginRequest{Email: "forgetful@test.com", Password: "third-password"})
+	assert.NoError(t, err)
+}
+
+func TestEmailVerification_Integration(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	userRepo := mysqlrepo.NewUserRepo(testDB)
+	txManager := mysqlrepo.NewTransactionManager(testDB)
+	mailDir := t.TempDir()
+	mailer, err := localmail.NewMailer("test@localhost", mailDir)
+	require.NoError(t, err)
+	verifier := service.NewEmailVerificationService(userRepo, mysqlrepo.NewEmailVerificationRepo(testDB), txManager, mailer, "test-secret", time.Hour, "http://app.test/verify?token=%s")
+	policy := domain.EmailVerificationPolicy{BlockLogin: true, BlockInvitations: true}
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, verifier, newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, policy)
+
+	_, err = authSvc.Register(ctx, domain.RegisterRequest{Email: "not an email", Password: "password", FullName: "Nobody"})
+	assert.Error(t, err)
+
+	registered, err := authSvc.Register(ctx, domain.RegisterRequest{Email: " New.User@Test.COM ", Password: "password", FullName: "New User"})
+	require.NoError(t, err)
+	assert.True(t, registered.VerificationRequired)
+	assert.Empty(t, registered.Token)
+	assert.Equal(t, "new.user@test.com", registered.User.Email)
+
+	_, err = authSvc.Login(ctx, domain.LoginRequest{Email: "new.user@test.com", Password: "password"})
+	assert.Error(t, err, "unverified accounts cannot sign in")
+
+	// Asking again replaces the first link
+	require.NoError(t, verifier.Resend(ctx, domain.ResendVerificationRequest{Email: "NEW.USER@test.com"}))
+	files, err := filepath.Glob(filepath.Join(mailDir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	var tokens []string
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		require.NoError(t, err)
+		_, after, ok := strings.Cut(string(data), "http://app.test/verify?token=")
+		require.True(t, ok)
+		token, err := url.QueryUnescape(strings.Fields(after)[0])
+		require.NoError(t, err)
+		tokens = append(tokens, token)
+	}
+	assert.Error(t, verifier.Verify(ctx, domain.VerifyEmailRequest{Token: tokens[0]}))
+	require.NoError(t, verifier.Verify(ctx, domain.VerifyEmailRequest{Token: tokens[1]}))
+	assert.Error(t, verifier.Verify(ctx, domain.VerifyEmailRequest{Token: tokens[1]}), "tokens work once")
+
+	session, err := authSvc.Login(ctx, domain.LoginRequest{Email: "New.User@test.com", Password: "password"})
+	require.NoError(t, err)
+	assert.NotNil(t, session.User.EmailVerifiedAt)
+}
+
+func TestTwoFactor_Integration(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	userRepo := mysqlrepo.NewUserRepo(testDB)
+	teamRepo := mysqlrepo.NewTeamRepo(testDB)
+	txManager := mysqlrepo.NewTransactionManager(testDB)
+	denylist := redis.NewTokenDenylist(testRedis)
+	twoFactorSvc := newTwoFactorService()
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), twoFactorSvc, denylist, testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	teamSvc := service.NewTeamService(teamRepo, mysqlrepo.NewOrganizationRepo(testDB), service.NewAuthorizer(teamRepo), userRepo, mysqlrepo.NewActivityRepo(testDB), txManager, service.NewNotificationService(), redis.NewTaskCache(testRedis), nil, "test-secret")
+
+	owner, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "careful@test.com", Password: "password", FullName: "Careful"})
+	require.NoError(t, err)
+	member, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "relaxed@test.com", Password: "password", FullName: "Relaxed"})
+	require.NoError(t, err)
+	claims := domain.AccessClaims{UserID: owner.User.ID}
+
+	enrollment, err := twoFactorSvc.Enroll(ctx, claims, domain.EnrollTwoFactorRequest{Password: "password"})
+	require.NoError(t, err)
+	code, err := totp.Code(enrollment.Secret, totp.Counter(time.Now()))
+	require.NoError(t, err)
+	codes, err := twoFactorSvc.Confirm(ctx, claims, domain.TwoFactorCodeRequest{Code: code})
+	require.NoError(t, err)
+	require.Len(t, codes.RecoveryCodes, 10)
+
+	challenge, err := authSvc.Login(ctx, domain.LoginRequest{Email: "careful@test.com", Password: "password"})
+	require.NoError(t, err)
+	require.True(t, challenge.TwoFactorRequired)
+	assert.Empty(t, challenge.Token)
+
+	// A challenge does not pass as an access token
+	protected := middleware.Authenticate(testKeys, denylist, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+challenge.ChallengeToken)
+	rec := httptest.NewRecorder()
+	protected.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// The code used to confirm cannot be used again
+	_, err = authSvc.CompleteLogin(ctx, domain.LoginChallengeRequest{ChallengeToken: challenge.ChallengeToken, Code: code})
+	assert.Error(t, err)
+	_, err = authSvc.CompleteLogin(ctx, domain.LoginChallengeRequest{ChallengeToken: challenge.ChallengeToken, RecoveryCode: codes.RecoveryCodes[0]})
+	assert.Error(t, err, "a challenge allows one attempt")
+
+	// Parallel requests with one challenge share that attempt
+	var claimed atomic.Int32
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, err := denylist.Claim(ctx, "parallel-challenge", time.Minute); err == nil && ok {
+				claimed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), claimed.Load())
+
+	limiter := redis.NewLoginRateLimiter(testRedis, 3)
+	for i := 0; i < 3; i++ {
+		allowed, err := limiter.AllowLogin(ctx, "203.0.113.7")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+	allowed, err := limiter.AllowLogin(ctx, "203.0.113.7")
+	require.NoError(t, err)
+	assert.False(t, allowed, "attempts within one second all count")
+	allowed, err = limiter.AllowLogin(ctx, "203.0.113.8")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	// Other unauthenticated endpoints count in their own buckets
+	emailLimiter := redis.NewAttemptLimiter(testRedis, "email", 1, time.Hour)
+	allowed, err = emailLimiter.Allow(ctx, "203.0.113.7")
+	require.NoError(t, err)
+	assert.True(t, allowed, "login attempts do not use up other scopes")
+	allowed, err = emailLimiter.Allow(ctx, "203.0.113.7")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	challenge, err = authSvc.Login(ctx, domain.LoginRequest{Email: "careful@test.com", Password: "password"})
+	require.NoError(t, err)
+	session, err := authSvc.CompleteLogin(ctx, domain.LoginChallengeRequest{ChallengeToken: challenge.ChallengeToken, RecoveryCode: strings.ToUpper(codes.RecoveryCodes[0])})
+	require.NoError(t, err)
+	assert.NotEmpty(t, session.Token)
+
+	challenge, err = authSvc.Login(ctx, domain.LoginRequest{Email: "careful@test.com", Password: "password"})
+	require.NoError(t, err)
+	_, err = authSvc.CompleteLogin(ctx, domain.LoginChallengeRequest{ChallengeToken: challenge.ChallengeToken, RecoveryCode: codes.RecoveryCodes[0]})
+	assert.Error(t, err, "recovery codes work once")
+
+	// Teams can require 2FA; members without it are locked out
+	team, err := teamSvc.Create(ctx, owner.User.ID, domain.CreateTeamRequest{Name: "Vault"})
+	require.NoError(t, err)
+	require.NoError(t, teamRepo.AddMember(ctx, &domain.TeamMember{TeamID: team.ID, UserID: member.User.ID, Role: domain.TeamRoleMember}))
+	require2FA := true
+	updated, err := teamSvc.Update(ctx, owner.User.ID, team.ID, domain.UpdateTeamRequest{Require2FA: &require2FA})
+	require.NoError(t, err)
+	assert.True(t, updated.Require2FA)
+
+	_, err = teamSvc.GetByID(ctx, owner.User.ID, team.ID)
+	assert.NoError(t, err)
+	_, err = teamSvc.GetByID(ctx, member.User.ID, team.ID)
+	assert.Error(t, err)
+}
+
+func TestPersonalAccessTokens_Integration(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	userRepo := mysqlrepo.NewUserRepo(testDB)
+	teamRepo := mysqlrepo.NewTeamRepo(testDB)
+	txManager := mysqlrepo.NewTransactionManager(testDB)
+	authz := service.NewAuthorizer(teamRepo)
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	teamSvc := service.NewTeamService(teamRepo, mysqlrepo.NewOrganizationRepo(testDB), authz, userRepo, mysqlrepo.NewActivityRepo(testDB), txManager, service.NewNotificationService(), redis.NewTaskCache(testRedis), nil, "test-secret")
+	tokenSvc := service.NewPersonalTokenService(mysqlrepo.NewPersonalTokenRepo(testDB), authz)
+	taskSvc := service.NewTaskService(mysqlrepo.NewTaskRepo(testDB), authz, userRepo, mysqlrepo.NewTaskHistoryRepo(testDB), redis.NewTaskCache(testRedis), txManager, service.NewNotificationService(), nil, nil)
+
+	owner, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "bot-owner@test.com", Password: "password", FullName: "Bot Owner"})
+	require.NoError(t, err)
+	teamA, err := teamSvc.Create(ctx, owner.User.ID, domain.CreateTeamRequest{Name: "Team A"})
+	require.NoError(t, err)
+	teamB, err := teamSvc.Create(ctx, owner.User.ID, domain.CreateTeamRequest{Name: "Team B"})
+	require.NoError(t, err)
+
+	created, err := tokenSvc.Create(ctx, domain.AccessClaims{UserID: owner.User.ID}, domain.CreatePersonalAccessTokenRequest{
+		Name: "ci", Scopes: []domain.TokenScope{domain.ScopeReadOnly}, TeamID: &teamA.ID,
+	})
+	require.NoError(t, err)
+
+	// The token reads but cannot write, and only sees its team
+	var seen []domain.Team
+	protected := middleware.Authenticate(testKeys, redis.NewTokenDenylist(testRedis), tokenSvc)(
+		middleware.RequireScope(domain.ScopeTeamsAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen, err = teamSvc.ListByUserID(r.Context(), middleware.GetUserID(r.Context()), false)
+			require.NoError(t, err)
+		})))
+	call := func(method, token string) int {
+		req := httptest.NewRequest(method, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		protected.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, created.Token))
+	require.Len(t, seen, 1)
+	assert.Equal(t, teamA.ID, seen[0].ID)
+	assert.Equal(t, http.StatusForbidden, call(http.MethodPost, created.Token))
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, owner.Token))
+	assert.Len(t, seen, 2, "session tokens are not restricted")
+
+	// Changes made with a token are recorded as automation
+	task, err := taskSvc.Create(ctx, owner.User.ID, domain.CreateTaskRequest{Title: "Nightly build", TeamID: teamA.ID})
+	require.NoError(t, err)
+	writer, err := tokenSvc.Create(ctx, domain.AccessClaims{UserID: owner.User.ID}, domain.CreatePersonalAccessTokenRequest{
+		Name: "bot", Scopes: []domain.TokenScope{domain.ScopeTasksWrite}, TeamID: &teamA.ID,
+	})
+	require.NoError(t, err)
+	update := middleware.Authenticate(testKeys, redis.NewTokenDenylist(testRedis), tokenSvc)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			status := "done"
+			_, err := taskSvc.Update(r.Context(), middleware.GetUserID(r.Context()), task.ID, domain.UpdateTaskRequest{Status: &status})
+			require.NoError(t, err)
+		}))
+	req := httptest.NewRequest(http.MethodPut, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+writer.Token)
+	update.ServeHTTP(httptest.NewRecorder(), req)
+	history, err := taskSvc.GetHistory(ctx, owner.User.ID, task.ID, domain.HistoryFilter{})
+	require.NoError(t, err)
+	require.Len(t, history.ChangeSets, 1)
+	assert.Equal(t, domain.ChangeSourceAutomation, history.ChangeSets[0].Source)
+
+	restricted := requestctx.WithTeamRestriction(ctx, teamA.ID)
+	_, err = teamSvc.GetByID(restricted, owner.User.ID, teamB.ID)
+	assert.Error(t, err)
+
+	tokens, err := tokenSvc.List(ctx, owner.User.ID)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.NotNil(t, tokens[0].LastUsedAt)
+	assert.NotNil(t, tokens[1].LastUsedAt)
+
+	require.NoError(t, tokenSvc.Revoke(ctx, owner.User.ID, created.ID))
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodGet, created.Token))
+	tokens, err = tokenSvc.List(ctx, owner.User.ID)
+	require.NoError(t, err)
+	assert.Len(t, tokens, 1)
+}
+
+func TestKeyRotation_Integration(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "old.pem"), "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	for _, name := range []string{"current.pem", "next.pem"} {
+		_, edKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(edKey)
+		require.NoError(t, err)
+		writePEM(t, filepath.Join(dir, name), "PRIVATE KEY", der)
+	}
+
+	old, err := jwtkeys.LoadFile("old", filepath.Join(dir, "old.pem"), now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	current, err := jwtkeys.LoadFile("current", filepath.Join(dir, "current.pem"), now.Add(-30*time.Minute))
+	require.NoError(t, err)
+	next, err := jwtkeys.LoadFile("next", filepath.Join(dir, "next.pem"), now.Add(time.Hour))
+	require.NoError(t, err)
+
+	opts := jwtkeys.Options{Issuer: "test", Audience: "test-api", Grace: time.Hour}
+	ring, err := jwtkeys.NewKeyRing([]jwtkeys.Key{next, old, current}, opts)
+	require.NoError(t, err)
+
+	// The newest key that has started signs; the scheduled one is already
+	// published so verifiers can cache it
+	token, err := ring.Sign(map[string]interface{}{"user_id": 1, "exp": now.Add(time.Minute).Unix()})
+	require.NoError(t, err)
+	_, err = ring.Parse(token)
+	require.NoError(t, err)
+	kids := []string{}
+	for _, k := range ring.JWKS().Keys {
+		kids = append(kids, k.KID+":"+k.Alg)
+	}
+	assert.Equal(t, []string{"old:RS256", "current:EdDSA", "next:EdDSA"}, kids)
+
+	// A token from the replaced key verifies until the grace period ends
+	oldRing, err := jwtkeys.NewKeyRing([]jwtkeys.Key{old}, opts)
+	require.NoError(t, err)
+	oldToken, err := oldRing.Sign(map[string]interface{}{"user_id": 1, "exp": now.Add(time.Minute).Unix()})
+	require.NoError(t, err)
+	_, err = ring.Parse(oldToken)
+	assert.NoError(t, err)
+
+	shortGrace, err := jwtkeys.NewKeyRing([]jwtkeys.Key{old, current, next}, jwtkeys.Options{Issuer: "test", Audience: "test-api", Grace: 10 * time.Minute})
+	require.NoError(t, err)
+	_, err = shortGrace.Parse(oldToken)
+	assert.Error(t, err)
+	assert.Len(t, shortGrace.JWKS().Keys, 2)
+
+	// Issuer and audience must match
+	otherAPI, err := jwtkeys.NewKeyRing([]jwtkeys.Key{old, current, next}, jwtkeys.Options{Issuer: "test", Audience: "other-api", Grace: time.Hour})
+	require.NoError(t, err)
+	_, err = otherAPI.Parse(token)
+	assert.Error(t, err)
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
@@ -0,0 +1,95 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shalfey088/team-task-nexus/internal/adapter/storage/local"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/storage/s3"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/apperror"
+	"github.com/shalfey088/team-task-nexus/internal/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestLocalBlobStore_Integration(t *testing.T) {
+	store, err := local.NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	exerciseBlobStore(t, store)
+
+	err = store.Put(context.Background(), "../escape", strings.NewReader("x"), 1, "text/plain")
+	assert.Error(t, err)
+}
+
+func TestS3BlobStore_Integration(t *testing.T) {
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "minio/minio:latest",
+			ExposedPorts: []string{"9000/tcp"},
+			Cmd:          []string{"server", "/data"},
+			Env: map[string]string{
+				"MINIO_ROOT_USER":     "minioadmin",
+				"MINIO_ROOT_PASSWORD": "minioadmin",
+			},
+			WaitingFor: wait.ForHTTP("/minio/health/live").WithPort("9000/tcp").
+				WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	defer container.Terminate(ctx)
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+	port, err := container.MappedPort(ctx, "9000")
+	require.NoError(t, err)
+
+	store, err := s3.NewStore(s3.Config{
+		Endpoint:  fmt.Sprintf("http://%s:%s", host, port.Port()),
+		Bucket:    "attachments",
+		AccessKey: "minioadmin",
+		SecretKey: "minioadmin",
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.EnsureBucket(ctx))
+	// A second call must tolerate the existing bucket.
+	require.NoError(t, store.EnsureBucket(ctx))
+
+	exerciseBlobStore(t, store)
+}
+
+func exerciseBlobStore(t *testing.T, store port.BlobStore) {
+	t.Helper()
+	ctx := context.Background()
+	key := "tasks/1/some file+name"
+	body := "hello attachments"
+
+	require.NoError(t, store.Put(ctx, key, strings.NewReader(body), int64(len(body)), "text/plain"))
+
+	rc, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	assert.Equal(t, body, string(data))
+
+	require.NoError(t, store.Delete(ctx, key))
+	// Deleting a missing blob is not an error.
+	require.NoError(t, store.Delete(ctx, key))
+
+	_, err = store.Get(ctx, key)
+	appErr, ok := apperror.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, 404, appErr.Code)
+}
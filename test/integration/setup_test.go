@@ -6,7 +6,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +19,10 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jmoiron/sqlx"
 	goredis "github.com/redis/go-redis/v9"
+	localmail "github.com/shalfey088/team-task-nexus/internal/adapter/mail/local"
+	mysqlrepo "github.com/shalfey088/team-task-nexus/internal/adapter/repository/mysql"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/jwtkeys"
+	"github.com/shalfey088/team-task-nexus/internal/service"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
@@ -23,6 +30,7 @@ import (
 var (
 	testDB    *sqlx.DB
 	testRedis *goredis.Client
+	testKeys  *jwtkeys.KeyRing
 )
 
 func TestMain(m *testing.M) {
@@ -40,6 +48,15 @@ func TestMain(m *testing.M) {
 	}
 	testRedis = rdb
 
+	key, err := jwtkeys.Generate("test-key")
+	if err != nil {
+		log.Fatalf("failed to generate jwt key: %v", err)
+	}
+	testKeys, err = jwtkeys.NewKeyRing([]jwtkeys.Key{key}, jwtkeys.Options{Issuer: "test", Audience: "test-api", Grace: time.Hour})
+	if err != nil {
+		log.Fatalf("failed to build jwt key ring: %v", err)
+	}
+
 	code := m.Run()
 
 	testDB.Close()
@@ -150,8 +167,51 @@ func setupRedis(ctx context.Context) (testcontainers.Container, *goredis.Client,
 
 func cleanDB(t *testing.T) {
 	t.Helper()
-	tables := []string{"task_comments", "task_history", "tasks", "team_members", "teams", "users"}
+	tables := []string{"recovery_codes", "email_verification_tokens", "password_reset_tokens", "personal_access_tokens", "refresh_tokens", "admin_audit_log", "team_join_link_uses", "team_join_links", "team_invitations", "org_invitations", "team_ownership_transfers", "attachments", "comment_reactions", "task_reactions", "mentions", "team_events", "task_comment_revisions", "task_comments", "task_history", "task_change_sets", "team_guest_tasks", "tasks", "team_members", "team_roles", "teams", "organization_members", "organizations", "users"}
 	for _, table := range tables {
 		testDB.Exec("DELETE FROM " + table)
 	}
 }
+
+// newTestMailer saves messages into a temporary directory.
+func newTestMailer(t *testing.T) *localmail.Mailer {
+	t.Helper()
+	mailer, err := localmail.NewMailer("test@localhost", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mailer
+}
+
+// lastMailedToken reads the token from the link in the newest .eml file in
+// dir. Tokens only ever leave the server by mail.
+func lastMailedToken(t *testing.T, dir, linkPrefix string) string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no mail in %s", dir)
+	}
+	data, err := os.ReadFile(files[len(files)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, after, ok := strings.Cut(string(data), linkPrefix)
+	if !ok {
+		t.Fatalf("no %s link in mail", linkPrefix)
+	}
+	token, err := url.QueryUnescape(strings.Fields(after)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// newEmailVerifier mails verification links into a temporary directory.
+func newEmailVerifier(t *testing.T) *service.EmailVerificationServiceImpl {
+	t.Helper()
+	return service.NewEmailVerificationService(mysqlrepo.NewUserRepo(testDB), mysqlrepo.NewEmailVerificationRepo(testDB), mysqlrepo.NewTransactionManager(testDB), newTestMailer(t), "test-secret", time.Hour, "http://app.test/verify?token=%s")
+}
+
+func newTwoFactorService() *service.TwoFactorServiceImpl {
+	return service.NewTwoFactorService(mysqlrepo.NewUserRepo(testDB), mysqlrepo.NewRecoveryCodeRepo(testDB), mysqlrepo.NewTransactionManager(testDB), "Test")
+}
@@ -4,12 +4,17 @@ package integration
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/shalfey088/team-task-nexus/internal/adapter/cache/redis"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
 	mysqlrepo "github.com/shalfey088/team-task-nexus/internal/adapter/repository/mysql"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/storage/local"
 	"github.com/shalfey088/team-task-nexus/internal/domain"
+	"github.com/shalfey088/team-task-nexus/internal/pkg/requestctx"
 	"github.com/shalfey088/team-task-nexus/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,13 +28,19 @@ func TestTaskCaching_Integration(t *testing.T) {
 	teamRepo := mysqlrepo.NewTeamRepo(testDB)
 	taskRepo := mysqlrepo.NewTaskRepo(testDB)
 	historyRepo := mysqlrepo.NewTaskHistoryRepo(testDB)
+	activityRepo := mysqlrepo.NewActivityRepo(testDB)
+	mentionRepo := mysqlrepo.NewMentionRepo(testDB)
 	txManager := mysqlrepo.NewTransactionManager(testDB)
 	taskCache := redis.NewTaskCache(testRedis)
 	notifSvc := service.NewNotificationService()
+	mentionSvc := service.NewMentionService(mentionRepo, teamRepo, service.NewAuthorizer(teamRepo), notifSvc)
 
-	authSvc := service.NewAuthService(userRepo, "test-secret", 24*time.Hour)
-	teamSvc := service.NewTeamService(teamRepo, userRepo, txManager, notifSvc)
-	taskSvc := service.NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, taskCache, txManager, notifSvc)
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	blobStore, err := local.NewStore(t.TempDir())
+	require.NoError(t, err)
+	attachSvc := service.NewAttachmentService(mysqlrepo.NewAttachmentRepo(testDB), taskRepo, service.NewAuthorizer(teamRepo), mysqlrepo.NewCommentRepo(testDB), blobStore, 1<<20, []string{"text/plain"})
+	teamSvc := service.NewTeamService(teamRepo, mysqlrepo.NewOrganizationRepo(testDB), service.NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, taskCache, attachSvc, "test-secret")
+	taskSvc := service.NewTaskService(taskRepo, service.NewAuthorizer(teamRepo), userRepo, historyRepo, taskCache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	// Setup
 	user, err := authSvc.Register(ctx, domain.RegisterRequest{
@@ -78,13 +89,19 @@ func TestTaskPagination_Integration(t *testing.T) {
 	teamRepo := mysqlrepo.NewTeamRepo(testDB)
 	taskRepo := mysqlrepo.NewTaskRepo(testDB)
 	historyRepo := mysqlrepo.NewTaskHistoryRepo(testDB)
+	activityRepo := mysqlrepo.NewActivityRepo(testDB)
+	mentionRepo := mysqlrepo.NewMentionRepo(testDB)
 	txManager := mysqlrepo.NewTransactionManager(testDB)
 	taskCache := redis.NewTaskCache(testRedis)
 	notifSvc := service.NewNotificationService()
+	mentionSvc := service.NewMentionService(mentionRepo, teamRepo, service.NewAuthorizer(teamRepo), notifSvc)
 
-	authSvc := service.NewAuthService(userRepo, "test-secret", 24*time.Hour)
-	teamSvc := service.NewTeamService(teamRepo, userRepo, txManager, notifSvc)
-	taskSvc := service.NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, taskCache, txManager, notifSvc)
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	blobStore, err := local.NewStore(t.TempDir())
+	require.NoError(t, err)
+	attachSvc := service.NewAttachmentService(mysqlrepo.NewAttachmentRepo(testDB), taskRepo, service.NewAuthorizer(teamRepo), mysqlrepo.NewCommentRepo(testDB), blobStore, 1<<20, []string{"text/plain"})
+	teamSvc := service.NewTeamService(teamRepo, mysqlrepo.NewOrganizationRepo(testDB), service.NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, taskCache, attachSvc, "test-secret")
+	taskSvc := service.NewTaskService(taskRepo, service.NewAuthorizer(teamRepo), userRepo, historyRepo, taskCache, txManager, notifSvc, mentionSvc, attachSvc)
 
 	user, err := authSvc.Register(ctx, domain.RegisterRequest{
 		Email: "paging@test.com", Password: "password", FullName: "Paging User",
@@ -129,26 +146,102 @@ func TestOrphanedAssignees_Integration(t *testing.T) {
 	teamRepo := mysqlrepo.NewTeamRepo(testDB)
 	taskRepo := mysqlrepo.NewTaskRepo(testDB)
 	historyRepo := mysqlrepo.NewTaskHistoryRepo(testDB)
+	activityRepo := mysqlrepo.NewActivityRepo(testDB)
+	mentionRepo := mysqlrepo.NewMentionRepo(testDB)
 	txManager := mysqlrepo.NewTransactionManager(testDB)
 	taskCache := redis.NewTaskCache(testRedis)
 	notifSvc := service.NewNotificationService()
+	mentionSvc := service.NewMentionService(mentionRepo, teamRepo, service.NewAuthorizer(teamRepo), notifSvc)
 
-	authSvc := service.NewAuthService(userRepo, "test-secret", 24*time.Hour)
-	teamSvc := service.NewTeamService(teamRepo, userRepo, txManager, notifSvc)
-	taskSvc := service.NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, taskCache, txManager, notifSvc)
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	blobStore, err := local.NewStore(t.TempDir())
+	require.NoError(t, err)
+	attachSvc := service.NewAttachmentService(mysqlrepo.NewAttachmentRepo(testDB), taskRepo, service.NewAuthorizer(teamRepo), mysqlrepo.NewCommentRepo(testDB), blobStore, 1<<20, []string{"text/plain"})
+	teamSvc := service.NewTeamService(teamRepo, mysqlrepo.NewOrganizationRepo(testDB), service.NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, taskCache, attachSvc, "test-secret")
+	taskSvc := service.NewTaskService(taskRepo, service.NewAuthorizer(teamRepo), userRepo, historyRepo, taskCache, txManager, notifSvc, mentionSvc, attachSvc)
+	adminSvc := service.NewAdminService(userRepo, teamRepo, taskRepo, historyRepo, mysqlrepo.NewAdminAuditRepo(testDB), authSvc, taskCache, txManager)
 
 	user1, err := authSvc.Register(ctx, domain.RegisterRequest{
 		Email: "orphan-owner@test.com", Password: "password", FullName: "Owner",
 	})
 	require.NoError(t, err)
+	user2, err := authSvc.Register(ctx, domain.RegisterRequest{
+		Email: "orphan-member@test.com", Password: "password", FullName: "Member",
+	})
+	require.NoError(t, err)
 
-	_, err = teamSvc.Create(ctx, user1.User.ID, domain.CreateTeamRequest{
+	team, err := teamSvc.Create(ctx, user1.User.ID, domain.CreateTeamRequest{
 		Name: "Orphan Team",
 	})
 	require.NoError(t, err)
+	require.NoError(t, teamRepo.AddMember(ctx, &domain.TeamMember{TeamID: team.ID, UserID: user2.User.ID, Role: domain.TeamRoleMember}))
+	task, err := taskSvc.Create(ctx, user1.User.ID, domain.CreateTaskRequest{
+		Title: "Handover", TeamID: team.ID, AssigneeID: &user2.User.ID,
+	})
+	require.NoError(t, err)
+
+	// The report is for system administrators only
+	_, err = adminSvc.ListOrphanedAssignees(ctx, user1.User.ID)
+	assert.Error(t, err)
+	_, err = testDB.Exec("UPDATE users SET system_role = 'admin' WHERE id = ?", user1.User.ID)
+	require.NoError(t, err)
 
 	// Initially no orphaned assignees (assignee is a member)
-	orphaned, err := taskSvc.GetOrphanedAssignees(ctx)
+	orphaned, err := adminSvc.ListOrphanedAssignees(ctx, user1.User.ID)
+	require.NoError(t, err)
+	assert.Empty(t, orphaned)
+
+	require.NoError(t, teamRepo.RemoveMember(ctx, team.ID, user2.User.ID))
+	orphaned, err = adminSvc.ListOrphanedAssignees(ctx, user1.User.ID)
+	require.NoError(t, err)
+	require.Len(t, orphaned, 1)
+	assert.Equal(t, task.ID, orphaned[0].TaskID)
+
+	repaired, err := adminSvc.RepairOrphanedAssignees(ctx, user1.User.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, repaired.Unassigned)
+	orphaned, err = adminSvc.ListOrphanedAssignees(ctx, user1.User.ID)
 	require.NoError(t, err)
 	assert.Empty(t, orphaned)
+
+	// Disabled accounts cannot log in or keep using their tokens, including
+	// impersonation tokens issued for them
+	impersonation, err := adminSvc.Impersonate(ctx, user1.User.ID, user2.User.ID)
+	require.NoError(t, err)
+	impersonationClaims, err := testKeys.Parse(impersonation.Token)
+	require.NoError(t, err)
+	family, _ := impersonationClaims["fam"].(string)
+	require.NotEmpty(t, family)
+
+	// Changes made while impersonating land in the audit log, reads do not
+	impersonated := middleware.Authenticate(testKeys, redis.NewTokenDenylist(testRedis), nil)(
+		middleware.AuditImpersonation(adminSvc)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})))
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		req := httptest.NewRequest(method, "/api/v1/tasks", nil).
+			WithContext(requestctx.WithRequestID(ctx, "req-"+method))
+		req.Header.Set("Authorization", "Bearer "+impersonation.Token)
+		rec := httptest.NewRecorder()
+		impersonated.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+	}
+	audit, err := adminSvc.ListAudit(ctx, user1.User.ID, domain.AdminListFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 3, audit.Total)
+	assert.Equal(t, domain.AdminActionImpersonatedReq, audit.Entries[0].Action)
+	assert.Equal(t, user2.User.ID, *audit.Entries[0].TargetUserID)
+	assert.Equal(t, "POST /api/v1/tasks request_id=req-POST", audit.Entries[0].Details)
+	_, err = adminSvc.SetUserDisabled(ctx, user1.User.ID, user2.User.ID, true)
+	require.NoError(t, err)
+	revoked, err := redis.NewTokenDenylist(testRedis).IsRevoked(ctx, family)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	assert.Error(t, authSvc.CheckActive(ctx, user2.User.ID))
+	_, err = authSvc.Login(ctx, domain.LoginRequest{Email: "orphan-member@test.com", Password: "password"})
+	assert.Error(t, err)
+
+	audit, err = adminSvc.ListAudit(ctx, user1.User.ID, domain.AdminListFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, audit.Total)
 }
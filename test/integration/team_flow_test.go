@@ -4,11 +4,20 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/shalfey088/team-task-nexus/internal/adapter/cache/redis"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/handler"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/http/middleware"
+	localmail "github.com/shalfey088/team-task-nexus/internal/adapter/mail/local"
 	mysqlrepo "github.com/shalfey088/team-task-nexus/internal/adapter/repository/mysql"
+	"github.com/shalfey088/team-task-nexus/internal/adapter/storage/local"
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/shalfey088/team-task-nexus/internal/service"
 	"github.com/stretchr/testify/assert"
@@ -24,14 +33,28 @@ func TestTeamFlow_Integration(t *testing.T) {
 	taskRepo := mysqlrepo.NewTaskRepo(testDB)
 	historyRepo := mysqlrepo.NewTaskHistoryRepo(testDB)
 	commentRepo := mysqlrepo.NewCommentRepo(testDB)
+	activityRepo := mysqlrepo.NewActivityRepo(testDB)
+	mentionRepo := mysqlrepo.NewMentionRepo(testDB)
+	reactionRepo := mysqlrepo.NewReactionRepo(testDB)
 	txManager := mysqlrepo.NewTransactionManager(testDB)
 	taskCache := redis.NewTaskCache(testRedis)
 	notifSvc := service.NewNotificationService()
+	mentionSvc := service.NewMentionService(mentionRepo, teamRepo, service.NewAuthorizer(teamRepo), notifSvc)
 
-	authSvc := service.NewAuthService(userRepo, "test-secret", 24*time.Hour)
-	teamSvc := service.NewTeamService(teamRepo, userRepo, txManager, notifSvc)
-	taskSvc := service.NewTaskService(taskRepo, teamRepo, userRepo, historyRepo, taskCache, txManager, notifSvc)
-	commentSvc := service.NewCommentService(commentRepo, taskRepo, teamRepo, notifSvc)
+	mailDir := t.TempDir()
+	mailer, err := localmail.NewMailer("test@localhost", mailDir)
+	require.NoError(t, err)
+	invitationSvc := service.NewInvitationService(teamRepo, service.NewAuthorizer(teamRepo), userRepo, mysqlrepo.NewInvitationRepo(testDB), activityRepo, txManager, mailer, "test-secret", time.Hour, "http://app.test/accept?token=%s", domain.EmailVerificationPolicy{})
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, invitationSvc, newEmailVerifier(t), newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	blobStore, err := local.NewStore(t.TempDir())
+	require.NoError(t, err)
+	attachSvc := service.NewAttachmentService(mysqlrepo.NewAttachmentRepo(testDB), taskRepo, service.NewAuthorizer(teamRepo), commentRepo, blobStore, 1<<20, []string{"text/plain"})
+	teamSvc := service.NewTeamService(teamRepo, mysqlrepo.NewOrganizationRepo(testDB), service.NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, taskCache, attachSvc, "test-secret")
+	taskSvc := service.NewTaskService(taskRepo, service.NewAuthorizer(teamRepo), userRepo, historyRepo, taskCache, txManager, notifSvc, mentionSvc, attachSvc)
+	commentSvc := service.NewCommentService(commentRepo, taskRepo, service.NewAuthorizer(teamRepo), userRepo, reactionRepo, txManager, notifSvc, mentionSvc, attachSvc)
+	activitySvc := service.NewActivityService(activityRepo, service.NewAuthorizer(teamRepo))
+	reactionSvc := service.NewReactionService(reactionRepo, taskRepo, service.NewAuthorizer(teamRepo), commentRepo, txManager)
+	ownershipSvc := service.NewOwnershipService(teamRepo, service.NewAuthorizer(teamRepo), userRepo, mysqlrepo.NewOwnershipTransferRepo(testDB), activityRepo, txManager, notifSvc)
 
 	// Register two users
 	user1, err := authSvc.Register(ctx, domain.RegisterRequest{
@@ -52,14 +75,30 @@ func TestTeamFlow_Integration(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "Test Team", team.Name)
 
-	// Invite member
-	err = teamSvc.InviteUser(ctx, user1.User.ID, team.ID, domain.InviteRequest{
+	// Invite member; the invitee joins only after accepting
+	invitation, err := invitationSvc.Invite(ctx, user1.User.ID, team.ID, domain.InviteRequest{
 		Email: "member@test.com", Role: "member",
 	})
 	require.NoError(t, err)
+	assert.Equal(t, domain.InvitationPending, invitation.Status)
+	inviteToken := lastMailedToken(t, mailDir, "http://app.test/accept?token=")
+
+	teams, err := teamSvc.ListByUserID(ctx, user2.User.ID, false)
+	require.NoError(t, err)
+	assert.Len(t, teams, 0)
+
+	_, err = invitationSvc.Accept(ctx, user1.User.ID, inviteToken)
+	assert.Error(t, err, "invitation is bound to the invited email")
+	_, err = invitationSvc.Accept(ctx, user2.User.ID, inviteToken)
+	assert.Error(t, err, "accepting needs a verified email")
+	require.NoError(t, userRepo.MarkEmailVerified(ctx, user2.User.ID))
+	_, err = invitationSvc.Accept(ctx, user2.User.ID, inviteToken)
+	require.NoError(t, err)
+	_, err = invitationSvc.Accept(ctx, user2.User.ID, inviteToken)
+	assert.Error(t, err, "invitation tokens are single use")
 
 	// List teams for user2
-	teams, err := teamSvc.ListByUserID(ctx, user2.User.ID)
+	teams, err = teamSvc.ListByUserID(ctx, user2.User.ID, false)
 	require.NoError(t, err)
 	assert.Len(t, teams, 1)
 
@@ -79,9 +118,29 @@ func TestTeamFlow_Integration(t *testing.T) {
 	assert.Equal(t, domain.TaskStatusInProgress, updated.Status)
 
 	// Check history
-	history, err := taskSvc.GetHistory(ctx, user1.User.ID, task.ID)
+	history, err := taskSvc.GetHistory(ctx, user1.User.ID, task.ID, domain.HistoryFilter{})
+	require.NoError(t, err)
+	require.Len(t, history.ChangeSets, 1)
+	assert.Equal(t, "Owner User", history.ChangeSets[0].UserName)
+	assert.Equal(t, "status", history.ChangeSets[0].Changes[0].Field)
+	assert.Equal(t, domain.ChangeSourceAPI, history.ChangeSets[0].Source)
+
+	// Imported tasks keep their status, recorded as an import
+	importHandler := handler.NewTaskHandler(taskSvc, service.NewMarkdownService(taskRepo, service.NewAuthorizer(teamRepo), "/tasks/%d", 160))
+	body := fmt.Sprintf(`{"team_id": %d, "tasks": [{"title": "Legacy bug", "status": "done"}, {"title": "Legacy idea"}]}`, team.ID)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)).
+		WithContext(context.WithValue(ctx, middleware.ClaimsKey, &domain.AccessClaims{UserID: user1.User.ID}))
+	rec := httptest.NewRecorder()
+	importHandler.Import(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var imported domain.ImportTasksResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&imported))
+	require.Len(t, imported.Tasks, 2)
+	assert.Equal(t, domain.TaskStatusDone, imported.Tasks[0].Status)
+	history, err = taskSvc.GetHistory(ctx, user1.User.ID, imported.Tasks[0].ID, domain.HistoryFilter{})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, len(history), 1)
+	require.Len(t, history.ChangeSets, 1)
+	assert.Equal(t, domain.ChangeSourceImport, history.ChangeSets[0].Source)
 
 	// Add comment
 	comment, err := commentSvc.Create(ctx, user2.User.ID, task.ID, domain.CreateCommentRequest{
@@ -90,13 +149,61 @@ func TestTeamFlow_Integration(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "Working on this task!", comment.Content)
 
+	// Mention a member and a stranger
+	mentionComment, err := commentSvc.Create(ctx, user2.User.ID, task.ID, domain.CreateCommentRequest{
+		Content: "@owner please review, cc @nobody@test.com",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nobody@test.com"}, mentionComment.UnresolvedMentions)
+
+	mentions, err := mentionSvc.ListForUser(ctx, user1.User.ID, domain.MentionFilter{})
+	require.NoError(t, err)
+	require.Len(t, mentions.Mentions, 1)
+	assert.Equal(t, "Member User", mentions.Mentions[0].AuthorName)
+	assert.Equal(t, domain.MentionSourceComment, mentions.Mentions[0].Source)
+
+	// React to the first comment
+	toggled, err := reactionSvc.ToggleCommentReaction(ctx, user1.User.ID, task.ID, comment.ID,
+		domain.ToggleReactionRequest{Emoji: "+1"})
+	require.NoError(t, err)
+	assert.True(t, toggled.Reacted)
+
 	// List comments
 	comments, err := commentSvc.ListByTaskID(ctx, user2.User.ID, task.ID)
 	require.NoError(t, err)
-	assert.Len(t, comments, 1)
+	assert.Len(t, comments, 2)
+	require.Len(t, comments[0].Reactions, 1)
+	assert.Equal(t, 1, comments[0].Reactions[0].Count)
+	assert.False(t, comments[0].Reactions[0].ReactedByMe)
+
+	// Page through comments one at a time in both directions
+	first, err := commentSvc.ListPage(ctx, user2.User.ID, task.ID, domain.CommentQuery{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, first.Total)
+	require.Len(t, first.Comments, 1)
+	assert.Equal(t, comment.ID, first.Comments[0].ID)
+	require.NotEmpty(t, first.NextCursor)
+
+	second, err := commentSvc.ListPage(ctx, user2.User.ID, task.ID, domain.CommentQuery{Limit: 1, Cursor: first.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, second.Comments, 1)
+	assert.Equal(t, mentionComment.ID, second.Comments[0].ID)
+	assert.Empty(t, second.NextCursor)
+
+	back, err := commentSvc.ListPage(ctx, user2.User.ID, task.ID, domain.CommentQuery{Limit: 1, Cursor: second.PrevCursor})
+	require.NoError(t, err)
+	require.Len(t, back.Comments, 1)
+	assert.Equal(t, comment.ID, back.Comments[0].ID)
+
+	// Deleting the comment hides its mentions
+	require.NoError(t, commentSvc.Delete(ctx, user2.User.ID, task.ID, mentionComment.ID))
+	mentions, err = mentionSvc.ListForUser(ctx, user1.User.ID, domain.MentionFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, mentions.Mentions)
+	assert.Equal(t, 0, mentions.Total)
 
 	// Get team stats
-	stats, err := teamSvc.GetStats(ctx, user1.User.ID)
+	stats, err := teamSvc.GetStats(ctx, user1.User.ID, false)
 	require.NoError(t, err)
 	assert.GreaterOrEqual(t, len(stats), 1)
 
@@ -104,4 +211,599 @@ func TestTeamFlow_Integration(t *testing.T) {
 	contributors, err := teamSvc.GetTopContributors(ctx, user1.User.ID, team.ID)
 	require.NoError(t, err)
 	assert.GreaterOrEqual(t, len(contributors), 1)
+
+	// Activity feed merges membership, task and comment events
+	feed, err := activitySvc.GetTeamActivity(ctx, user2.User.ID, team.ID, domain.ActivityQuery{})
+	require.NoError(t, err)
+	types := make(map[domain.ActivityType]bool)
+	for _, e := range feed.Events {
+		types[e.Type] = true
+	}
+	assert.True(t, types[domain.ActivityMemberAdded])
+	assert.True(t, types[domain.ActivityInvitationSent])
+	assert.True(t, types[domain.ActivityInvitationAccepted])
+	assert.True(t, types[domain.ActivityTaskCreated])
+	assert.True(t, types[domain.ActivityTaskUpdated])
+	assert.True(t, types[domain.ActivityCommentAdded])
+	for _, e := range feed.Events {
+		if e.Type == domain.ActivityInvitationSent {
+			assert.Empty(t, e.Details, "members do not see invitee addresses")
+		}
+	}
+
+	// Paging one event at a time walks the same feed
+	page, err := activitySvc.GetTeamActivity(ctx, user1.User.ID, team.ID, domain.ActivityQuery{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Events, 1)
+	require.NotEmpty(t, page.NextCursor)
+	walked := page.Events
+	for page.NextCursor != "" {
+		page, err = activitySvc.GetTeamActivity(ctx, user1.User.ID, team.ID, domain.ActivityQuery{Limit: 1, Cursor: page.NextCursor})
+		require.NoError(t, err)
+		walked = append(walked, page.Events...)
+	}
+	require.Len(t, walked, len(feed.Events))
+	for i, e := range walked {
+		assert.Equal(t, feed.Events[i].Type, e.Type)
+		assert.Equal(t, feed.Events[i].SubjectID, e.SubjectID)
+	}
+
+	// Promote, then let the member leave; the sole owner cannot leave
+	promoted, err := teamSvc.ChangeMemberRole(ctx, user1.User.ID, team.ID, user2.User.ID,
+		domain.UpdateMemberRoleRequest{Role: "admin"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TeamRoleAdmin, promoted.Role)
+
+	members, err := teamSvc.ListMembers(ctx, user2.User.ID, team.ID)
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	assert.Error(t, teamSvc.RemoveMember(ctx, user2.User.ID, team.ID, user1.User.ID))
+	assert.Error(t, teamSvc.Leave(ctx, user1.User.ID, team.ID))
+
+	// Hand the team over, after which the previous owner may leave
+	_, err = ownershipSvc.Nominate(ctx, user1.User.ID, team.ID, domain.NominateOwnerRequest{UserID: user2.User.ID})
+	require.NoError(t, err)
+	_, err = ownershipSvc.Accept(ctx, user1.User.ID, team.ID)
+	assert.Error(t, err)
+	transferred, err := ownershipSvc.Accept(ctx, user2.User.ID, team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user2.User.ID, transferred.OwnerID)
+
+	require.NoError(t, teamSvc.Leave(ctx, user1.User.ID, team.ID))
+
+	members, err = teamSvc.ListMembers(ctx, user2.User.ID, team.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, domain.TeamRoleOwner, members[0].Role)
+}
+
+func TestRegisterWithInvitation_Integration(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	userRepo := mysqlrepo.NewUserRepo(testDB)
+	teamRepo := mysqlrepo.NewTeamRepo(testDB)
+	activityRepo := mysqlrepo.NewActivityRepo(testDB)
+	txManager := mysqlrepo.NewTransactionManager(testDB)
+	notifSvc := service.NewNotificationService()
+	mailDir := t.TempDir()
+	mailer, err := localmail.NewMailer("test@localhost", mailDir)
+	require.NoError(t, err)
+	invitationSvc := service.NewInvitationService(teamRepo, service.NewAuthorizer(teamRepo), userRepo, mysqlrepo.NewInvitationRepo(testDB), activityRepo, txManager, mailer, "test-secret", time.Hour, "http://app.test/accept?token=%s", domain.EmailVerificationPolicy{})
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, invitationSvc, newEmailVerifier(t), newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	teamSvc := service.NewTeamService(teamRepo, mysqlrepo.NewOrganizationRepo(testDB), service.NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, redis.NewTaskCache(testRedis), nil, "test-secret")
+
+	owner, err := authSvc.Register(ctx, domain.RegisterRequest{
+		Email: "owner@test.com", Password: "password", FullName: "Owner User",
+	})
+	require.NoError(t, err)
+	team, err := teamSvc.Create(ctx, owner.User.ID, domain.CreateTeamRequest{Name: "Invite Team"})
+	require.NoError(t, err)
+
+	// Invite someone without an account; registering with the token joins the team
+	_, err = invitationSvc.Invite(ctx, owner.User.ID, team.ID, domain.InviteRequest{Email: "newcomer@test.com"})
+	require.NoError(t, err)
+	inviteToken := lastMailedToken(t, mailDir, "http://app.test/accept?token=")
+	_, err = authSvc.Register(ctx, domain.RegisterRequest{
+		Email: "stranger@test.com", Password: "password", FullName: "Stranger", InviteToken: inviteToken,
+	})
+	assert.Error(t, err)
+	_, err = userRepo.GetByEmail(ctx, "stranger@test.com")
+	assert.Error(t, err, "failed invite acceptance rolls back registration")
+
+	newcomer, err := authSvc.Register(ctx, domain.RegisterRequest{
+		Email: "newcomer@test.com", Password: "password", FullName: "Newcomer", InviteToken: inviteToken,
+	})
+	require.NoError(t, err)
+	assert.True(t, newcomer.User.EmailVerified(), "the mailed invite token proves the address")
+	member, err := teamRepo.GetMember(ctx, team.ID, newcomer.User.ID)
+	require.NoError(t, err)
+	require.NotNil(t, member)
+
+	pendingList, err := invitationSvc.ListPending(ctx, owner.User.ID, team.ID)
+	require.NoError(t, err)
+	assert.Len(t, pendingList, 0)
+}
+
+func TestJoinLink_Integration(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	userRepo := mysqlrepo.NewUserRepo(testDB)
+	teamRepo := mysqlrepo.NewTeamRepo(testDB)
+	activityRepo := mysqlrepo.NewActivityRepo(testDB)
+	txManager := mysqlrepo.NewTransactionManager(testDB)
+	notifSvc := service.NewNotificationService()
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	teamSvc := service.NewTeamService(teamRepo, mysqlrepo.NewOrganizationRepo(testDB), service.NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, redis.NewTaskCache(testRedis), nil, "test-secret")
+	joinLinkSvc := service.NewJoinLinkService(teamRepo, service.NewAuthorizer(teamRepo), userRepo, mysqlrepo.NewJoinLinkRepo(testDB), activityRepo, txManager)
+
+	register := func(email string) int64 {
+		res, err := authSvc.Register(ctx, domain.RegisterRequest{Email: email, Password: "password", FullName: email})
+		require.NoError(t, err)
+		return res.User.ID
+	}
+	ownerID := register("owner@test.com")
+	insiderID := register("insider@corp.test")
+	secondID := register("second@corp.test")
+	outsiderID := register("outsider@test.com")
+
+	team, err := teamSvc.Create(ctx, ownerID, domain.CreateTeamRequest{Name: "Link Team"})
+	require.NoError(t, err)
+
+	maxUses := 1
+	link, err := joinLinkSvc.Create(ctx, ownerID, team.ID, domain.CreateJoinLinkRequest{
+		MaxUses: &maxUses, AllowedDomain: "corp.test",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, link.Code)
+
+	_, err = joinLinkSvc.Join(ctx, outsiderID, link.Code)
+	assert.Error(t, err, "email domain is restricted")
+
+	// The domain counts only once the address is verified
+	_, err = joinLinkSvc.Join(ctx, insiderID, link.Code)
+	assert.Error(t, err, "email is not verified")
+	require.NoError(t, userRepo.MarkEmailVerified(ctx, insiderID))
+	require.NoError(t, userRepo.MarkEmailVerified(ctx, secondID))
+
+	joined, err := joinLinkSvc.Join(ctx, insiderID, link.Code)
+	require.NoError(t, err)
+	assert.Equal(t, team.ID, joined.ID)
+
+	_, err = joinLinkSvc.Join(ctx, secondID, link.Code)
+	assert.Error(t, err, "link is used up")
+
+	uses, err := joinLinkSvc.ListUses(ctx, ownerID, team.ID, link.ID)
+	require.NoError(t, err)
+	require.Len(t, uses, 1)
+	assert.Equal(t, "insider@corp.test", uses[0].Email)
+
+	require.NoError(t, joinLinkSvc.Revoke(ctx, ownerID, team.ID, link.ID))
+	links, err := joinLinkSvc.List(ctx, ownerID, team.ID)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.NotNil(t, links[0].RevokedAt)
+	assert.Equal(t, 1, links[0].UseCount)
+}
+
+func TestTeamArchiveAndDelete_Integration(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	userRepo := mysqlrepo.NewUserRepo(testDB)
+	teamRepo := mysqlrepo.NewTeamRepo(testDB)
+	taskRepo := mysqlrepo.NewTaskRepo(testDB)
+	activityRepo := mysqlrepo.NewActivityRepo(testDB)
+	txManager := mysqlrepo.NewTransactionManager(testDB)
+	taskCache := redis.NewTaskCache(testRedis)
+	notifSvc := service.NewNotificationService()
+	mentionSvc := service.NewMentionService(mysqlrepo.NewMentionRepo(testDB), teamRepo, service.NewAuthorizer(teamRepo), notifSvc)
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	blobStore, err := local.NewStore(t.TempDir())
+	require.NoError(t, err)
+	attachSvc := service.NewAttachmentService(mysqlrepo.NewAttachmentRepo(testDB), taskRepo, service.NewAuthorizer(teamRepo), mysqlrepo.NewCommentRepo(testDB), blobStore, 1<<20, []string{"text/plain"})
+	teamSvc := service.NewTeamService(teamRepo, mysqlrepo.NewOrganizationRepo(testDB), service.NewAuthorizer(teamRepo), userRepo, activityRepo, txManager, notifSvc, taskCache, attachSvc, "test-secret")
+	taskSvc := service.NewTaskService(taskRepo, service.NewAuthorizer(teamRepo), userRepo, mysqlrepo.NewTaskHistoryRepo(testDB), taskCache, txManager, notifSvc, mentionSvc, attachSvc)
+
+	owner, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "owner@test.com", Password: "password", FullName: "Owner"})
+	require.NoError(t, err)
+	team, err := teamSvc.Create(ctx, owner.User.ID, domain.CreateTeamRequest{Name: "Doomed"})
+	require.NoError(t, err)
+	task, err := taskSvc.Create(ctx, owner.User.ID, domain.CreateTaskRequest{Title: "Keep me", TeamID: team.ID})
+	require.NoError(t, err)
+	_, err = attachSvc.Upload(ctx, owner.User.ID, task.ID, nil, domain.AttachmentUpload{
+		FileName: "notes.txt", Size: 5, Content: strings.NewReader("hello"),
+	})
+	require.NoError(t, err)
+
+	// Rename, then archive: the team disappears from the default list and
+	// its tasks become read-only
+	name := "Renamed"
+	renamed, err := teamSvc.Update(ctx, owner.User.ID, team.ID, domain.UpdateTeamRequest{Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", renamed.Name)
+
+	archived, err := teamSvc.SetArchived(ctx, owner.User.ID, team.ID, true)
+	require.NoError(t, err)
+	require.NotNil(t, archived.ArchivedAt)
+
+	teams, err := teamSvc.ListByUserID(ctx, owner.User.ID, false)
+	require.NoError(t, err)
+	assert.Len(t, teams, 0)
+	teams, err = teamSvc.ListByUserID(ctx, owner.User.ID, true)
+	require.NoError(t, err)
+	assert.Len(t, teams, 1)
+
+	title := "Changed"
+	_, err = taskSvc.Update(ctx, owner.User.ID, task.ID, domain.UpdateTaskRequest{Title: &title})
+	assert.Error(t, err)
+	_, err = taskSvc.List(ctx, owner.User.ID, domain.TaskFilter{TeamID: team.ID})
+	require.NoError(t, err)
+
+	// Deleting needs a confirmation token and takes everything with it
+	assert.Error(t, teamSvc.Delete(ctx, owner.User.ID, team.ID, domain.DeleteTeamRequest{ConfirmationToken: "bogus"}))
+	confirmation, err := teamSvc.IssueDeletionToken(ctx, owner.User.ID, team.ID)
+	require.NoError(t, err)
+	require.NoError(t, teamSvc.Delete(ctx, owner.User.ID, team.ID, domain.DeleteTeamRequest{ConfirmationToken: confirmation.Token}))
+
+	_, err = teamRepo.GetByID(ctx, team.ID)
+	assert.Error(t, err)
+	_, err = taskRepo.GetByID(ctx, task.ID)
+	assert.Error(t, err)
+	keys, err := testRedis.Keys(ctx, fmt.Sprintf("tasks:team:%d:*", team.ID)).Result()
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestOrganization_Integration(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	userRepo := mysqlrepo.NewUserRepo(testDB)
+	teamRepo := mysqlrepo.NewTeamRepo(testDB)
+	orgRepo := mysqlrepo.NewOrganizationRepo(testDB)
+	txManager := mysqlrepo.NewTransactionManager(testDB)
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	teamSvc := service.NewTeamService(teamRepo, orgRepo, service.NewAuthorizer(teamRepo), userRepo, mysqlrepo.NewActivityRepo(testDB), txManager, service.NewNotificationService(), redis.NewTaskCache(testRedis), nil, "test-secret")
+	mailDir := t.TempDir()
+	mailer, err := localmail.NewMailer("test@localhost", mailDir)
+	require.NoError(t, err)
+	orgSvc := service.NewOrganizationService(orgRepo, userRepo, mysqlrepo.NewOrgInvitationRepo(testDB), txManager, mailer, "test-secret", time.Hour, "http://app.test/org-accept?token=%s", domain.EmailVerificationPolicy{})
+
+	admin, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "admin@test.com", Password: "password", FullName: "Admin"})
+	require.NoError(t, err)
+	member, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "member@test.com", Password: "password", FullName: "Member"})
+	require.NoError(t, err)
+	outsider, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "outsider@test.com", Password: "password", FullName: "Outsider"})
+	require.NoError(t, err)
+
+	// A team created without an organization lands in a personal one
+	personalTeam, err := teamSvc.Create(ctx, member.User.ID, domain.CreateTeamRequest{Name: "Side project"})
+	require.NoError(t, err)
+	personal, err := orgRepo.GetPersonal(ctx, member.User.ID)
+	require.NoError(t, err)
+	require.NotNil(t, personal)
+	assert.Equal(t, personal.ID, personalTeam.OrgID)
+	assert.Equal(t, "Member's workspace", personal.Name)
+
+	org, err := orgSvc.Create(ctx, admin.User.ID, domain.CreateOrganizationRequest{Name: "Acme"})
+	require.NoError(t, err)
+
+	// Inviting answers the same way whether or not the email is registered
+	unknown, err := orgSvc.InviteMember(ctx, admin.User.ID, org.ID, domain.InviteOrgMemberRequest{Email: "nobody@test.com"})
+	require.NoError(t, err)
+	invitation, err := orgSvc.InviteMember(ctx, admin.User.ID, org.ID, domain.InviteOrgMemberRequest{Email: "member@test.com"})
+	require.NoError(t, err)
+	assert.Equal(t, unknown.Status, invitation.Status)
+	assert.Equal(t, unknown.Role, invitation.Role)
+	inviteToken := lastMailedToken(t, mailDir, "http://app.test/org-accept?token=")
+
+	member0, err := orgRepo.GetMember(ctx, org.ID, member.User.ID)
+	require.NoError(t, err)
+	assert.Nil(t, member0, "the invitee joins only after accepting")
+	_, err = orgSvc.AcceptInvitation(ctx, outsider.User.ID, inviteToken)
+	assert.Error(t, err, "invitation is bound to the invited email")
+	require.NoError(t, userRepo.MarkEmailVerified(ctx, member.User.ID))
+	joined, err := orgSvc.AcceptInvitation(ctx, member.User.ID, inviteToken)
+	require.NoError(t, err)
+	assert.Equal(t, org.ID, joined.ID)
+	_, err = orgSvc.AcceptInvitation(ctx, member.User.ID, inviteToken)
+	assert.Error(t, err, "invitation tokens are single use")
+
+	team, err := teamSvc.Create(ctx, member.User.ID, domain.CreateTeamRequest{Name: "Platform", OrgID: org.ID})
+	require.NoError(t, err)
+	assert.Equal(t, org.ID, team.OrgID)
+	_, err = teamSvc.Create(ctx, outsider.User.ID, domain.CreateTeamRequest{Name: "Sneaky", OrgID: org.ID})
+	assert.Error(t, err)
+
+	// Org admins see every team of the organization without joining it
+	_, err = teamSvc.GetByID(ctx, admin.User.ID, team.ID)
+	require.NoError(t, err)
+	teams, err := teamSvc.ListByUserID(ctx, admin.User.ID, false)
+	require.NoError(t, err)
+	assert.Len(t, teams, 1)
+	_, err = teamSvc.GetByID(ctx, outsider.User.ID, team.ID)
+	assert.Error(t, err)
+
+	found, err := orgSvc.SearchUsers(ctx, admin.User.ID, org.ID, "mem")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, member.User.ID, found[0].UserID)
+
+	// Team owners cannot be removed, and the last admin cannot step down
+	assert.Error(t, orgSvc.RemoveMember(ctx, admin.User.ID, org.ID, member.User.ID))
+	_, err = orgSvc.ChangeMemberRole(ctx, admin.User.ID, org.ID, admin.User.ID, domain.UpdateOrgMemberRoleRequest{Role: "member"})
+	assert.Error(t, err)
+
+	orgs, err := orgSvc.List(ctx, member.User.ID)
+	require.NoError(t, err)
+	assert.Len(t, orgs, 2)
+}
+
+func TestCustomRoles_Integration(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	userRepo := mysqlrepo.NewUserRepo(testDB)
+	teamRepo := mysqlrepo.NewTeamRepo(testDB)
+	taskRepo := mysqlrepo.NewTaskRepo(testDB)
+	commentRepo := mysqlrepo.NewCommentRepo(testDB)
+	activityRepo := mysqlrepo.NewActivityRepo(testDB)
+	txManager := mysqlrepo.NewTransactionManager(testDB)
+	taskCache := redis.NewTaskCache(testRedis)
+	notifSvc := service.NewNotificationService()
+	authz := service.NewAuthorizer(teamRepo)
+
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	teamSvc := service.NewTeamService(teamRepo, mysqlrepo.NewOrganizationRepo(testDB), authz, userRepo, activityRepo, txManager, notifSvc, taskCache, nil, "test-secret")
+	mentionSvc := service.NewMentionService(mysqlrepo.NewMentionRepo(testDB), teamRepo, authz, notifSvc)
+	taskSvc := service.NewTaskService(taskRepo, authz, userRepo, mysqlrepo.NewTaskHistoryRepo(testDB), taskCache, txManager, notifSvc, mentionSvc, nil)
+	permissionSvc := service.NewPermissionService(authz, teamRepo, mysqlrepo.NewTeamRoleRepo(testDB), taskRepo, commentRepo, activityRepo, txManager)
+
+	owner, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "owner@test.com", Password: "password", FullName: "Owner"})
+	require.NoError(t, err)
+	member, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "member@test.com", Password: "password", FullName: "Member"})
+	require.NoError(t, err)
+
+	team, err := teamSvc.Create(ctx, owner.User.ID, domain.CreateTeamRequest{Name: "Roles"})
+	require.NoError(t, err)
+	require.NoError(t, teamRepo.AddMember(ctx, &domain.TeamMember{TeamID: team.ID, UserID: member.User.ID, Role: domain.TeamRoleMember}))
+
+	role, err := permissionSvc.CreateRole(ctx, owner.User.ID, team.ID, domain.CustomRoleRequest{
+		Name: "Commenter", Permissions: []domain.Permission{domain.PermCommentCreate},
+	})
+	require.NoError(t, err)
+	_, err = permissionSvc.CreateRole(ctx, owner.User.ID, team.ID, domain.CustomRoleRequest{Name: "Commenter"})
+	assert.Error(t, err)
+
+	// A custom role replaces the member defaults
+	require.NoError(t, permissionSvc.AssignRole(ctx, owner.User.ID, team.ID, member.User.ID, domain.AssignCustomRoleRequest{RoleID: &role.ID}))
+	_, err = taskSvc.Create(ctx, member.User.ID, domain.CreateTaskRequest{Title: "Nope", TeamID: team.ID})
+	assert.Error(t, err)
+
+	summary, err := permissionSvc.Describe(ctx, member.User.ID, "team", team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Permission{domain.PermCommentCreate}, summary.Permissions)
+	require.NotNil(t, summary.CustomRoleID)
+	assert.Equal(t, role.ID, *summary.CustomRoleID)
+
+	// Deleting the role brings the defaults back
+	require.NoError(t, permissionSvc.DeleteRole(ctx, owner.User.ID, team.ID, role.ID))
+	task, err := taskSvc.Create(ctx, member.User.ID, domain.CreateTaskRequest{Title: "Yes", TeamID: team.ID})
+	require.NoError(t, err)
+
+	summary, err = permissionSvc.Describe(ctx, member.User.ID, "task", task.ID)
+	require.NoError(t, err)
+	assert.Nil(t, summary.CustomRoleID)
+	assert.Contains(t, summary.Permissions, domain.PermTaskDelete)
+
+	// Role changes are recorded in the activity feed
+	events, err := activityRepo.ListByTeam(ctx, domain.ActivityFilter{
+		TeamID: team.ID,
+		Types:  []domain.ActivityType{domain.ActivityCustomRoleCreated, domain.ActivityCustomRoleDeleted},
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, e := range events {
+		assert.Equal(t, owner.User.ID, e.ActorID)
+		assert.Equal(t, "Commenter", e.Details)
+	}
+}
+
+func TestGuestAccess_Integration(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	userRepo := mysqlrepo.NewUserRepo(testDB)
+	teamRepo := mysqlrepo.NewTeamRepo(testDB)
+	taskRepo := mysqlrepo.NewTaskRepo(testDB)
+	commentRepo := mysqlrepo.NewCommentRepo(testDB)
+	orgRepo := mysqlrepo.NewOrganizationRepo(testDB)
+	activityRepo := mysqlrepo.NewActivityRepo(testDB)
+	txManager := mysqlrepo.NewTransactionManager(testDB)
+	taskCache := redis.NewTaskCache(testRedis)
+	notifSvc := service.NewNotificationService()
+	authz := service.NewAuthorizer(teamRepo)
+
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	teamSvc := service.NewTeamService(teamRepo, orgRepo, authz, userRepo, activityRepo, txManager, notifSvc, taskCache, nil, "test-secret")
+	mentionSvc := service.NewMentionService(mysqlrepo.NewMentionRepo(testDB), teamRepo, authz, notifSvc)
+	taskSvc := service.NewTaskService(taskRepo, authz, userRepo, mysqlrepo.NewTaskHistoryRepo(testDB), taskCache, txManager, notifSvc, mentionSvc, nil)
+	commentSvc := service.NewCommentService(commentRepo, taskRepo, authz, userRepo, mysqlrepo.NewReactionRepo(testDB), txManager, notifSvc, mentionSvc, nil)
+	activitySvc := service.NewActivityService(activityRepo, authz)
+	permissionSvc := service.NewPermissionService(authz, teamRepo, mysqlrepo.NewTeamRoleRepo(testDB), taskRepo, commentRepo, activityRepo, txManager)
+	orgSvc := service.NewOrganizationService(orgRepo, userRepo, mysqlrepo.NewOrgInvitationRepo(testDB), txManager, newTestMailer(t), "test-secret", time.Hour, "http://app.test/org-accept?token=%s", domain.EmailVerificationPolicy{})
+
+	owner, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "owner@test.com", Password: "password", FullName: "Owner"})
+	require.NoError(t, err)
+	guest, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "client@test.com", Password: "password", FullName: "Client"})
+	require.NoError(t, err)
+
+	team, err := teamSvc.Create(ctx, owner.User.ID, domain.CreateTeamRequest{Name: "Agency"})
+	require.NoError(t, err)
+	shared, err := taskSvc.Create(ctx, owner.User.ID, domain.CreateTaskRequest{Title: "Landing page", TeamID: team.ID})
+	require.NoError(t, err)
+	private, err := taskSvc.Create(ctx, owner.User.ID, domain.CreateTaskRequest{Title: "Pricing", TeamID: team.ID})
+	require.NoError(t, err)
+	require.NoError(t, teamRepo.AddMember(ctx, &domain.TeamMember{TeamID: team.ID, UserID: guest.User.ID, Role: domain.TeamRoleGuest}))
+
+	// Guests stay outside the organization and cannot edit tasks
+	orgs, err := orgSvc.List(ctx, guest.User.ID)
+	require.NoError(t, err)
+	assert.Len(t, orgs, 0)
+	_, err = taskSvc.Create(ctx, guest.User.ID, domain.CreateTaskRequest{Title: "Nope", TeamID: team.ID})
+	assert.Error(t, err)
+	title := "Renamed"
+	_, err = taskSvc.Update(ctx, guest.User.ID, shared.ID, domain.UpdateTaskRequest{Title: &title})
+	assert.Error(t, err)
+
+	// Internal comments are hidden from guests
+	_, err = commentSvc.Create(ctx, owner.User.ID, shared.ID, domain.CreateCommentRequest{Content: "client is late", Internal: true})
+	require.NoError(t, err)
+	_, err = commentSvc.Create(ctx, guest.User.ID, shared.ID, domain.CreateCommentRequest{Content: "looks good"})
+	require.NoError(t, err)
+	comments, err := commentSvc.ListByTaskID(ctx, guest.User.ID, shared.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "looks good", comments[0].Content)
+	page, err := commentSvc.ListPage(ctx, guest.User.ID, shared.ID, domain.CommentQuery{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, page.Total)
+	feed, err := activitySvc.GetTeamActivity(ctx, guest.User.ID, team.ID, domain.ActivityQuery{Types: "comment_added"})
+	require.NoError(t, err)
+	assert.Len(t, feed.Events, 1)
+
+	_, err = commentSvc.Create(ctx, owner.User.ID, private.ID, domain.CreateCommentRequest{Content: "@client pricing is up"})
+	require.NoError(t, err)
+
+	// Scoping limits the guest to the granted tasks
+	require.NoError(t, permissionSvc.SetGuestScope(ctx, owner.User.ID, team.ID, guest.User.ID, domain.GuestScopeRequest{TaskIDs: []int64{shared.ID}}))
+	list, err := taskSvc.List(ctx, guest.User.ID, domain.TaskFilter{TeamID: team.ID, Page: 1, PageSize: 20})
+	require.NoError(t, err)
+	require.Len(t, list.Tasks, 1)
+	assert.Equal(t, shared.ID, list.Tasks[0].ID)
+	list, err = taskSvc.List(ctx, guest.User.ID, domain.TaskFilter{Page: 1, PageSize: 20})
+	require.NoError(t, err)
+	require.Len(t, list.Tasks, 1)
+	assert.Equal(t, shared.ID, list.Tasks[0].ID)
+	_, err = commentSvc.ListByTaskID(ctx, guest.User.ID, private.ID)
+	assert.Error(t, err)
+
+	// Nor are they mentioned on, or shown mentions from, other tasks
+	_, err = commentSvc.Create(ctx, owner.User.ID, private.ID, domain.CreateCommentRequest{Content: "@client one more thing"})
+	require.NoError(t, err)
+	_, err = commentSvc.Create(ctx, owner.User.ID, shared.ID, domain.CreateCommentRequest{Content: "@client please review"})
+	require.NoError(t, err)
+	mentions, err := mentionSvc.ListForUser(ctx, guest.User.ID, domain.MentionFilter{})
+	require.NoError(t, err)
+	require.Len(t, mentions.Mentions, 1)
+	assert.Equal(t, shared.ID, mentions.Mentions[0].TaskID)
+
+	// Promoting the guest lifts the scope and enrolls them in the organization
+	_, err = teamSvc.ChangeMemberRole(ctx, owner.User.ID, team.ID, guest.User.ID, domain.UpdateMemberRoleRequest{Role: "member"})
+	require.NoError(t, err)
+	list, err = taskSvc.List(ctx, guest.User.ID, domain.TaskFilter{TeamID: team.ID, Page: 1, PageSize: 20})
+	require.NoError(t, err)
+	assert.Len(t, list.Tasks, 2)
+	orgs, err = orgSvc.List(ctx, guest.User.ID)
+	require.NoError(t, err)
+	assert.Len(t, orgs, 1)
+}
+
+func TestTeamHierarchy_Integration(t *testing.T) {
+	cleanDB(t)
+	ctx := context.Background()
+
+	userRepo := mysqlrepo.NewUserRepo(testDB)
+	teamRepo := mysqlrepo.NewTeamRepo(testDB)
+	orgRepo := mysqlrepo.NewOrganizationRepo(testDB)
+	activityRepo := mysqlrepo.NewActivityRepo(testDB)
+	txManager := mysqlrepo.NewTransactionManager(testDB)
+	taskCache := redis.NewTaskCache(testRedis)
+	notifSvc := service.NewNotificationService()
+	authz := service.NewAuthorizer(teamRepo)
+
+	authSvc := service.NewAuthService(userRepo, mysqlrepo.NewRefreshTokenRepo(testDB), txManager, nil, newEmailVerifier(t), newTwoFactorService(), redis.NewTokenDenylist(testRedis), testKeys, "test-secret", 15*time.Minute, 24*time.Hour, domain.EmailVerificationPolicy{})
+	teamSvc := service.NewTeamService(teamRepo, orgRepo, authz, userRepo, activityRepo, txManager, notifSvc, taskCache, nil, "test-secret")
+	taskRepo := mysqlrepo.NewTaskRepo(testDB)
+	mentionSvc := service.NewMentionService(mysqlrepo.NewMentionRepo(testDB), teamRepo, authz, notifSvc)
+	taskSvc := service.NewTaskService(taskRepo, authz, userRepo, mysqlrepo.NewTaskHistoryRepo(testDB), taskCache, txManager, notifSvc, mentionSvc, nil)
+	markdownSvc := service.NewMarkdownService(taskRepo, authz, "/tasks/%d", 160)
+
+	owner, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "head@test.com", Password: "password", FullName: "Head"})
+	require.NoError(t, err)
+	lead, err := authSvc.Register(ctx, domain.RegisterRequest{Email: "lead@test.com", Password: "password", FullName: "Lead"})
+	require.NoError(t, err)
+
+	dept, err := teamSvc.Create(ctx, owner.User.ID, domain.CreateTeamRequest{Name: "Engineering"})
+	require.NoError(t, err)
+	squad, err := teamSvc.Create(ctx, owner.User.ID, domain.CreateTeamRequest{Name: "Payments", ParentID: &dept.ID})
+	require.NoError(t, err)
+	assert.Equal(t, dept.OrgID, squad.OrgID)
+	require.NoError(t, teamRepo.AddMember(ctx, &domain.TeamMember{TeamID: dept.ID, UserID: lead.User.ID, Role: domain.TeamRoleAdmin}))
+
+	// Department members reach the squad with the inherited role cap
+	member, err := teamRepo.GetMember(ctx, squad.ID, lead.User.ID)
+	require.NoError(t, err)
+	require.NotNil(t, member)
+	assert.Equal(t, domain.TeamRoleMember, member.Role)
+	require.NotNil(t, member.InheritedFrom)
+	assert.Equal(t, dept.ID, *member.InheritedFrom)
+
+	teams, err := teamSvc.ListByUserID(ctx, lead.User.ID, false)
+	require.NoError(t, err)
+	assert.Len(t, teams, 2)
+
+	// Inherited members cannot leave a team they are not in
+	assert.Error(t, teamSvc.Leave(ctx, lead.User.ID, squad.ID))
+
+	// They can be mentioned and follow references to the squad's tasks
+	task, err := taskSvc.Create(ctx, owner.User.ID, domain.CreateTaskRequest{Title: "Refunds", Description: "@lead please review", TeamID: squad.ID})
+	require.NoError(t, err)
+	mentions, err := mentionSvc.ListForUser(ctx, lead.User.ID, domain.MentionFilter{})
+	require.NoError(t, err)
+	require.Len(t, mentions.Mentions, 1)
+	assert.Equal(t, task.ID, mentions.Mentions[0].TaskID)
+	refs := []*domain.Task{{Description: fmt.Sprintf("see #%d", task.ID)}}
+	require.NoError(t, markdownSvc.RenderTasks(ctx, lead.User.ID, refs, true))
+	assert.Contains(t, refs[0].DescriptionHTML, fmt.Sprintf(`href="/tasks/%d"`, task.ID))
+
+	_, err = teamSvc.SetParent(ctx, owner.User.ID, squad.ID, domain.SetParentRequest{ParentID: &dept.ID, InheritedRole: "none"})
+	require.NoError(t, err)
+	member, err = teamRepo.GetMember(ctx, squad.ID, lead.User.ID)
+	require.NoError(t, err)
+	assert.Nil(t, member)
+	mentions, err = mentionSvc.ListForUser(ctx, lead.User.ID, domain.MentionFilter{})
+	require.NoError(t, err)
+	assert.Len(t, mentions.Mentions, 0)
+	refs = []*domain.Task{{Description: fmt.Sprintf("see #%d", task.ID)}}
+	require.NoError(t, markdownSvc.RenderTasks(ctx, lead.User.ID, refs, true))
+	assert.NotContains(t, refs[0].DescriptionHTML, "href")
+
+	// Cycles are rejected
+	_, err = teamSvc.SetParent(ctx, owner.User.ID, dept.ID, domain.SetParentRequest{ParentID: &squad.ID})
+	assert.Error(t, err)
+
+	tree, err := teamSvc.GetTree(ctx, owner.User.ID, dept.ID)
+	require.NoError(t, err)
+	require.Len(t, tree.Children, 1)
+	assert.Equal(t, squad.ID, tree.Children[0].ID)
+
+	// Rolled-up stats count people in several sub-teams once
+	stats, err := teamSvc.GetStats(ctx, owner.User.ID, true)
+	require.NoError(t, err)
+	for _, s := range stats {
+		if s.ID == dept.ID {
+			assert.Equal(t, 2, s.MemberCount)
+		}
+	}
+
+	// A department with squads cannot be deleted
+	token, err := teamSvc.IssueDeletionToken(ctx, owner.User.ID, dept.ID)
+	require.NoError(t, err)
+	err = teamSvc.Delete(ctx, owner.User.ID, dept.ID, domain.DeleteTeamRequest{ConfirmationToken: token.Token})
+	assert.Error(t, err)
 }
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/stretchr/testify/mock"
@@ -33,6 +34,51 @@ func (m *UserRepositoryMock) GetByEmail(ctx context.Context, email string) (*dom
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *UserRepositoryMock) GetByIDs(ctx context.Context, ids []int64) ([]domain.User, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+func (m *UserRepositoryMock) Search(ctx context.Context, filter domain.UserSearchFilter) ([]domain.User, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.User), args.Int(1), args.Error(2)
+}
+
+func (m *UserRepositoryMock) SetDisabled(ctx context.Context, id int64, disabled bool) error {
+	args := m.Called(ctx, id, disabled)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) MarkEmailVerified(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) SetTOTPSecret(ctx context.Context, id int64, secret string) error {
+	args := m.Called(ctx, id, secret)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) EnableTOTP(ctx context.Context, id int64, counter int64) error {
+	args := m.Called(ctx, id, counter)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) DisableTOTP(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) AdvanceTOTPCounter(ctx context.Context, id int64, counter int64) (bool, error) {
+	args := m.Called(ctx, id, counter)
+	return args.Bool(0), args.Error(1)
+}
+
 // TeamRepositoryMock
 type TeamRepositoryMock struct {
 	mock.Mock
@@ -51,11 +97,26 @@ func (m *TeamRepositoryMock) GetByID(ctx context.Context, id int64) (*domain.Tea
 	return args.Get(0).(*domain.Team), args.Error(1)
 }
 
-func (m *TeamRepositoryMock) ListByUserID(ctx context.Context, userID int64) ([]domain.Team, error) {
-	args := m.Called(ctx, userID)
+func (m *TeamRepositoryMock) ListByUserID(ctx context.Context, userID int64, includeArchived bool) ([]domain.Team, error) {
+	args := m.Called(ctx, userID, includeArchived)
 	return args.Get(0).([]domain.Team), args.Error(1)
 }
 
+func (m *TeamRepositoryMock) Update(ctx context.Context, team *domain.Team) error {
+	args := m.Called(ctx, team)
+	return args.Error(0)
+}
+
+func (m *TeamRepositoryMock) SetArchived(ctx context.Context, teamID int64, archived bool) error {
+	args := m.Called(ctx, teamID, archived)
+	return args.Error(0)
+}
+
+func (m *TeamRepositoryMock) Delete(ctx context.Context, teamID int64) error {
+	args := m.Called(ctx, teamID)
+	return args.Error(0)
+}
+
 func (m *TeamRepositoryMock) AddMember(ctx context.Context, member *domain.TeamMember) error {
 	args := m.Called(ctx, member)
 	return args.Error(0)
@@ -69,8 +130,18 @@ func (m *TeamRepositoryMock) GetMember(ctx context.Context, teamID, userID int64
 	return args.Get(0).(*domain.TeamMember), args.Error(1)
 }
 
-func (m *TeamRepositoryMock) GetStats(ctx context.Context, userID int64) ([]domain.TeamStats, error) {
-	args := m.Called(ctx, userID)
+func (m *TeamRepositoryMock) ListMembers(ctx context.Context, teamID int64) ([]domain.TeamMemberDetails, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).([]domain.TeamMemberDetails), args.Error(1)
+}
+
+func (m *TeamRepositoryMock) ListMentionCandidates(ctx context.Context, teamID int64) ([]domain.TeamMemberDetails, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).([]domain.TeamMemberDetails), args.Error(1)
+}
+
+func (m *TeamRepositoryMock) GetStats(ctx context.Context, userID int64, rollup bool) ([]domain.TeamStats, error) {
+	args := m.Called(ctx, userID, rollup)
 	return args.Get(0).([]domain.TeamStats), args.Error(1)
 }
 
@@ -79,6 +150,56 @@ func (m *TeamRepositoryMock) GetTopContributors(ctx context.Context, teamID int6
 	return args.Get(0).([]domain.TopContributor), args.Error(1)
 }
 
+func (m *TeamRepositoryMock) UpdateMemberRole(ctx context.Context, teamID, userID int64, role domain.TeamRole) error {
+	args := m.Called(ctx, teamID, userID, role)
+	return args.Error(0)
+}
+
+func (m *TeamRepositoryMock) ListAll(ctx context.Context, filter domain.AdminListFilter) ([]domain.Team, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.Team), args.Int(1), args.Error(2)
+}
+
+func (m *TeamRepositoryMock) SetParent(ctx context.Context, teamID int64, parentID *int64, inheritedRole *domain.TeamRole) error {
+	args := m.Called(ctx, teamID, parentID, inheritedRole)
+	return args.Error(0)
+}
+
+func (m *TeamRepositoryMock) ListDescendants(ctx context.Context, teamID int64) ([]domain.Team, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).([]domain.Team), args.Error(1)
+}
+
+func (m *TeamRepositoryMock) LockHierarchy(ctx context.Context, orgID int64) error {
+	args := m.Called(ctx, orgID)
+	return args.Error(0)
+}
+
+func (m *TeamRepositoryMock) SetGuestTasks(ctx context.Context, teamID, userID int64, taskIDs []int64) error {
+	args := m.Called(ctx, teamID, userID, taskIDs)
+	return args.Error(0)
+}
+
+func (m *TeamRepositoryMock) IsTaskGranted(ctx context.Context, userID, taskID int64) (bool, error) {
+	args := m.Called(ctx, userID, taskID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *TeamRepositoryMock) RemoveMember(ctx context.Context, teamID, userID int64) error {
+	args := m.Called(ctx, teamID, userID)
+	return args.Error(0)
+}
+
+func (m *TeamRepositoryMock) CountMembersByRole(ctx context.Context, teamID int64, role domain.TeamRole) (int, error) {
+	args := m.Called(ctx, teamID, role)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *TeamRepositoryMock) UpdateOwner(ctx context.Context, teamID, ownerID int64) error {
+	args := m.Called(ctx, teamID, ownerID)
+	return args.Error(0)
+}
+
 // TaskRepositoryMock
 type TaskRepositoryMock struct {
 	mock.Mock
@@ -112,18 +233,43 @@ func (m *TaskRepositoryMock) GetOrphanedAssignees(ctx context.Context) ([]domain
 	return args.Get(0).([]domain.OrphanedAssignee), args.Error(1)
 }
 
+func (m *TaskRepositoryMock) ClearAssignee(ctx context.Context, taskID, assigneeID int64) (bool, error) {
+	args := m.Called(ctx, taskID, assigneeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *TaskRepositoryMock) ListTeamIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *TaskRepositoryMock) ListVisibleIDs(ctx context.Context, access domain.TaskAccess, ids []int64) ([]int64, error) {
+	args := m.Called(ctx, access, ids)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *TaskRepositoryMock) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // TaskHistoryRepositoryMock
 type TaskHistoryRepositoryMock struct {
 	mock.Mock
 }
 
-func (m *TaskHistoryRepositoryMock) Create(ctx context.Context, history *domain.TaskHistory) error {
-	args := m.Called(ctx, history)
-	return args.Error(0)
+func (m *TaskHistoryRepositoryMock) CreateChangeSet(ctx context.Context, set *domain.TaskChangeSet, entries []domain.TaskHistory) (int64, error) {
+	args := m.Called(ctx, set, entries)
+	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *TaskHistoryRepositoryMock) ListByTaskID(ctx context.Context, taskID int64) ([]domain.TaskHistory, error) {
-	args := m.Called(ctx, taskID)
+func (m *TaskHistoryRepositoryMock) ListChangeSets(ctx context.Context, taskID int64, filter domain.HistoryFilter) ([]domain.TaskChangeSet, int, error) {
+	args := m.Called(ctx, taskID, filter)
+	return args.Get(0).([]domain.TaskChangeSet), args.Int(1), args.Error(2)
+}
+
+func (m *TaskHistoryRepositoryMock) ListByChangeSetIDs(ctx context.Context, ids []int64) ([]domain.TaskHistory, error) {
+	args := m.Called(ctx, ids)
 	return args.Get(0).([]domain.TaskHistory), args.Error(1)
 }
 
@@ -137,11 +283,602 @@ func (m *CommentRepositoryMock) Create(ctx context.Context, comment *domain.Task
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *CommentRepositoryMock) GetByID(ctx context.Context, id int64) (*domain.TaskComment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TaskComment), args.Error(1)
+}
+
 func (m *CommentRepositoryMock) ListByTaskID(ctx context.Context, taskID int64) ([]domain.TaskComment, error) {
 	args := m.Called(ctx, taskID)
 	return args.Get(0).([]domain.TaskComment), args.Error(1)
 }
 
+func (m *CommentRepositoryMock) ListPage(ctx context.Context, filter domain.CommentFilter) ([]domain.TaskComment, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.TaskComment), args.Int(1), args.Error(2)
+}
+
+func (m *CommentRepositoryMock) UpdateContent(ctx context.Context, id int64, content string) error {
+	args := m.Called(ctx, id, content)
+	return args.Error(0)
+}
+
+func (m *CommentRepositoryMock) SoftDelete(ctx context.Context, id, deletedBy int64) error {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Error(0)
+}
+
+func (m *CommentRepositoryMock) SetResolved(ctx context.Context, id int64, resolvedBy *int64) error {
+	args := m.Called(ctx, id, resolvedBy)
+	return args.Error(0)
+}
+
+func (m *CommentRepositoryMock) ListThreadParticipants(ctx context.Context, rootID int64) ([]int64, error) {
+	args := m.Called(ctx, rootID)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *CommentRepositoryMock) CreateRevision(ctx context.Context, rev *domain.CommentRevision) error {
+	args := m.Called(ctx, rev)
+	return args.Error(0)
+}
+
+func (m *CommentRepositoryMock) ListRevisions(ctx context.Context, commentID int64) ([]domain.CommentRevision, error) {
+	args := m.Called(ctx, commentID)
+	return args.Get(0).([]domain.CommentRevision), args.Error(1)
+}
+
+// ActivityRepositoryMock
+type ActivityRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ActivityRepositoryMock) CreateTeamEvent(ctx context.Context, event *domain.TeamEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *ActivityRepositoryMock) ListByTeam(ctx context.Context, filter domain.ActivityFilter) ([]domain.ActivityEvent, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.ActivityEvent), args.Error(1)
+}
+
+// MentionRepositoryMock
+type MentionRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *MentionRepositoryMock) CreateBatch(ctx context.Context, mentions []domain.Mention) error {
+	args := m.Called(ctx, mentions)
+	return args.Error(0)
+}
+
+func (m *MentionRepositoryMock) ListByUser(ctx context.Context, access domain.TaskAccess, filter domain.MentionFilter) ([]domain.Mention, int, error) {
+	args := m.Called(ctx, access, filter)
+	return args.Get(0).([]domain.Mention), args.Int(1), args.Error(2)
+}
+
+// ReactionRepositoryMock
+type ReactionRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ReactionRepositoryMock) Add(ctx context.Context, target domain.ReactionTarget, targetID, userID int64, emoji string) error {
+	args := m.Called(ctx, target, targetID, userID, emoji)
+	return args.Error(0)
+}
+
+func (m *ReactionRepositoryMock) Remove(ctx context.Context, target domain.ReactionTarget, targetID, userID int64, emoji string) (bool, error) {
+	args := m.Called(ctx, target, targetID, userID, emoji)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *ReactionRepositoryMock) Summaries(ctx context.Context, target domain.ReactionTarget, targetIDs []int64, userID int64) (map[int64][]domain.ReactionSummary, error) {
+	args := m.Called(ctx, target, targetIDs, userID)
+	return args.Get(0).(map[int64][]domain.ReactionSummary), args.Error(1)
+}
+
+// AttachmentRepositoryMock
+type AttachmentRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *AttachmentRepositoryMock) Create(ctx context.Context, attachment *domain.Attachment) (int64, error) {
+	args := m.Called(ctx, attachment)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *AttachmentRepositoryMock) GetByID(ctx context.Context, id int64) (*domain.Attachment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Attachment), args.Error(1)
+}
+
+func (m *AttachmentRepositoryMock) ListByTaskID(ctx context.Context, taskID int64) ([]domain.Attachment, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]domain.Attachment), args.Error(1)
+}
+
+func (m *AttachmentRepositoryMock) ListByCommentID(ctx context.Context, commentID int64) ([]domain.Attachment, error) {
+	args := m.Called(ctx, commentID)
+	return args.Get(0).([]domain.Attachment), args.Error(1)
+}
+
+func (m *AttachmentRepositoryMock) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *AttachmentRepositoryMock) ListByTeamID(ctx context.Context, teamID int64) ([]domain.Attachment, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).([]domain.Attachment), args.Error(1)
+}
+
+func (m *AttachmentRepositoryMock) DeleteByIDs(ctx context.Context, ids []int64) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+// OwnershipTransferRepositoryMock
+type OwnershipTransferRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *OwnershipTransferRepositoryMock) Create(ctx context.Context, transfer *domain.OwnershipTransfer) (int64, error) {
+	args := m.Called(ctx, transfer)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *OwnershipTransferRepositoryMock) GetByID(ctx context.Context, id int64) (*domain.OwnershipTransfer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.OwnershipTransfer), args.Error(1)
+}
+
+func (m *OwnershipTransferRepositoryMock) GetPending(ctx context.Context, teamID int64) (*domain.OwnershipTransfer, error) {
+	args := m.Called(ctx, teamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.OwnershipTransfer), args.Error(1)
+}
+
+func (m *OwnershipTransferRepositoryMock) Resolve(ctx context.Context, id int64, status domain.TransferStatus) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+// InvitationRepositoryMock
+type InvitationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *InvitationRepositoryMock) Create(ctx context.Context, invitation *domain.Invitation) (int64, error) {
+	args := m.Called(ctx, invitation)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *InvitationRepositoryMock) GetByID(ctx context.Context, id int64) (*domain.Invitation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Invitation), args.Error(1)
+}
+
+func (m *InvitationRepositoryMock) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Invitation), args.Error(1)
+}
+
+func (m *InvitationRepositoryMock) GetPendingByEmail(ctx context.Context, teamID int64, email string) (*domain.Invitation, error) {
+	args := m.Called(ctx, teamID, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Invitation), args.Error(1)
+}
+
+func (m *InvitationRepositoryMock) ListPending(ctx context.Context, teamID int64) ([]domain.Invitation, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).([]domain.Invitation), args.Error(1)
+}
+
+func (m *InvitationRepositoryMock) Resolve(ctx context.Context, id int64, status domain.InvitationStatus, acceptedBy *int64) (bool, error) {
+	args := m.Called(ctx, id, status, acceptedBy)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *InvitationRepositoryMock) Rotate(ctx context.Context, id int64, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, id, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+// JoinLinkRepositoryMock
+type JoinLinkRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *JoinLinkRepositoryMock) Create(ctx context.Context, link *domain.JoinLink) (int64, error) {
+	args := m.Called(ctx, link)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *JoinLinkRepositoryMock) GetByID(ctx context.Context, id int64) (*domain.JoinLink, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.JoinLink), args.Error(1)
+}
+
+func (m *JoinLinkRepositoryMock) GetByCode(ctx context.Context, code string) (*domain.JoinLink, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.JoinLink), args.Error(1)
+}
+
+func (m *JoinLinkRepositoryMock) ListByTeam(ctx context.Context, teamID int64) ([]domain.JoinLink, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).([]domain.JoinLink), args.Error(1)
+}
+
+func (m *JoinLinkRepositoryMock) Revoke(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *JoinLinkRepositoryMock) IncrementUses(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *JoinLinkRepositoryMock) RecordUse(ctx context.Context, linkID, userID int64) error {
+	args := m.Called(ctx, linkID, userID)
+	return args.Error(0)
+}
+
+func (m *JoinLinkRepositoryMock) ListUses(ctx context.Context, linkID int64) ([]domain.JoinLinkUse, error) {
+	args := m.Called(ctx, linkID)
+	return args.Get(0).([]domain.JoinLinkUse), args.Error(1)
+}
+
+// TeamRoleRepositoryMock
+type TeamRoleRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *TeamRoleRepositoryMock) Create(ctx context.Context, role *domain.CustomRole) (int64, error) {
+	args := m.Called(ctx, role)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *TeamRoleRepositoryMock) GetByID(ctx context.Context, id int64) (*domain.CustomRole, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CustomRole), args.Error(1)
+}
+
+func (m *TeamRoleRepositoryMock) ListByTeam(ctx context.Context, teamID int64) ([]domain.CustomRole, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).([]domain.CustomRole), args.Error(1)
+}
+
+func (m *TeamRoleRepositoryMock) Update(ctx context.Context, role *domain.CustomRole) error {
+	args := m.Called(ctx, role)
+	return args.Error(0)
+}
+
+func (m *TeamRoleRepositoryMock) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *TeamRoleRepositoryMock) Assign(ctx context.Context, teamID, userID int64, roleID *int64) error {
+	args := m.Called(ctx, teamID, userID, roleID)
+	return args.Error(0)
+}
+
+// OrganizationRepositoryMock
+type OrganizationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *OrganizationRepositoryMock) Create(ctx context.Context, org *domain.Organization) (int64, error) {
+	args := m.Called(ctx, org)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *OrganizationRepositoryMock) GetByID(ctx context.Context, id int64) (*domain.Organization, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Organization), args.Error(1)
+}
+
+func (m *OrganizationRepositoryMock) GetPersonal(ctx context.Context, userID int64) (*domain.Organization, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Organization), args.Error(1)
+}
+
+func (m *OrganizationRepositoryMock) ListByUserID(ctx context.Context, userID int64) ([]domain.Organization, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Organization), args.Error(1)
+}
+
+func (m *OrganizationRepositoryMock) Update(ctx context.Context, org *domain.Organization) error {
+	args := m.Called(ctx, org)
+	return args.Error(0)
+}
+
+func (m *OrganizationRepositoryMock) AddMember(ctx context.Context, member *domain.OrgMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *OrganizationRepositoryMock) GetMember(ctx context.Context, orgID, userID int64) (*domain.OrgMember, error) {
+	args := m.Called(ctx, orgID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.OrgMember), args.Error(1)
+}
+
+func (m *OrganizationRepositoryMock) ListMembers(ctx context.Context, orgID int64) ([]domain.OrgMemberDetails, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).([]domain.OrgMemberDetails), args.Error(1)
+}
+
+func (m *OrganizationRepositoryMock) SearchMembers(ctx context.Context, orgID int64, query string, limit int) ([]domain.OrgMemberDetails, error) {
+	args := m.Called(ctx, orgID, query, limit)
+	return args.Get(0).([]domain.OrgMemberDetails), args.Error(1)
+}
+
+func (m *OrganizationRepositoryMock) UpdateMemberRole(ctx context.Context, orgID, userID int64, role domain.OrgRole) error {
+	args := m.Called(ctx, orgID, userID, role)
+	return args.Error(0)
+}
+
+func (m *OrganizationRepositoryMock) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	args := m.Called(ctx, orgID, userID)
+	return args.Error(0)
+}
+
+func (m *OrganizationRepositoryMock) CountMembersByRole(ctx context.Context, orgID int64, role domain.OrgRole) (int, error) {
+	args := m.Called(ctx, orgID, role)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *OrganizationRepositoryMock) CountOwnedTeams(ctx context.Context, orgID, userID int64) (int, error) {
+	args := m.Called(ctx, orgID, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *OrganizationRepositoryMock) ListTeams(ctx context.Context, orgID int64) ([]domain.Team, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).([]domain.Team), args.Error(1)
+}
+
+// OrgInvitationRepositoryMock
+type OrgInvitationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *OrgInvitationRepositoryMock) Create(ctx context.Context, invitation *domain.OrgInvitation) (int64, error) {
+	args := m.Called(ctx, invitation)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *OrgInvitationRepositoryMock) GetByID(ctx context.Context, id int64) (*domain.OrgInvitation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.OrgInvitation), args.Error(1)
+}
+
+func (m *OrgInvitationRepositoryMock) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.OrgInvitation, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.OrgInvitation), args.Error(1)
+}
+
+func (m *OrgInvitationRepositoryMock) GetPendingByEmail(ctx context.Context, orgID int64, email string) (*domain.OrgInvitation, error) {
+	args := m.Called(ctx, orgID, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.OrgInvitation), args.Error(1)
+}
+
+func (m *OrgInvitationRepositoryMock) Resolve(ctx context.Context, id int64, status domain.InvitationStatus, acceptedBy *int64) (bool, error) {
+	args := m.Called(ctx, id, status, acceptedBy)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *OrgInvitationRepositoryMock) Rotate(ctx context.Context, id int64, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, id, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+// AdminAuditRepositoryMock
+type AdminAuditRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *AdminAuditRepositoryMock) Create(ctx context.Context, entry *domain.AdminAuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *AdminAuditRepositoryMock) List(ctx context.Context, filter domain.AdminListFilter) ([]domain.AdminAuditEntry, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.AdminAuditEntry), args.Int(1), args.Error(2)
+}
+
+// PersonalTokenRepositoryMock
+type PersonalTokenRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *PersonalTokenRepositoryMock) Create(ctx context.Context, token *domain.PersonalAccessToken) (int64, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *PersonalTokenRepositoryMock) GetByID(ctx context.Context, id int64) (*domain.PersonalAccessToken, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PersonalAccessToken), args.Error(1)
+}
+
+func (m *PersonalTokenRepositoryMock) GetByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PersonalAccessToken), args.Error(1)
+}
+
+func (m *PersonalTokenRepositoryMock) ListByUser(ctx context.Context, userID int64) ([]domain.PersonalAccessToken, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.PersonalAccessToken), args.Error(1)
+}
+
+func (m *PersonalTokenRepositoryMock) Revoke(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *PersonalTokenRepositoryMock) TouchLastUsed(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// PasswordResetRepositoryMock
+type PasswordResetRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *PasswordResetRepositoryMock) Create(ctx context.Context, token *domain.PasswordResetToken) (int64, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *PasswordResetRepositoryMock) GetByHashForUpdate(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PasswordResetToken), args.Error(1)
+}
+
+func (m *PasswordResetRepositoryMock) InvalidateForUser(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// EmailVerificationRepositoryMock
+type EmailVerificationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *EmailVerificationRepositoryMock) Create(ctx context.Context, token *domain.EmailVerificationToken) (int64, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *EmailVerificationRepositoryMock) GetByHashForUpdate(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.EmailVerificationToken), args.Error(1)
+}
+
+func (m *EmailVerificationRepositoryMock) InvalidateForUser(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// RecoveryCodeRepositoryMock
+type RecoveryCodeRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *RecoveryCodeRepositoryMock) Replace(ctx context.Context, userID int64, codeHashes []string) error {
+	args := m.Called(ctx, userID, codeHashes)
+	return args.Error(0)
+}
+
+func (m *RecoveryCodeRepositoryMock) Use(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *RecoveryCodeRepositoryMock) DeleteForUser(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// RefreshTokenRepositoryMock
+type RefreshTokenRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *RefreshTokenRepositoryMock) Create(ctx context.Context, token *domain.RefreshToken) (int64, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *RefreshTokenRepositoryMock) GetByHashForUpdate(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RefreshToken), args.Error(1)
+}
+
+func (m *RefreshTokenRepositoryMock) MarkRotated(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *RefreshTokenRepositoryMock) RevokeFamily(ctx context.Context, familyID string) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+func (m *RefreshTokenRepositoryMock) RevokeForUser(ctx context.Context, userID int64) ([]string, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 // TransactionManagerMock
 type TransactionManagerMock struct {
 	mock.Mock
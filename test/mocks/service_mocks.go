@@ -2,11 +2,100 @@ package mocks
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/shalfey088/team-task-nexus/internal/domain"
 	"github.com/stretchr/testify/mock"
 )
 
+// AuthServiceMock
+type AuthServiceMock struct {
+	mock.Mock
+}
+
+func (m *AuthServiceMock) Register(ctx context.Context, req domain.RegisterRequest) (*domain.AuthResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AuthResponse), args.Error(1)
+}
+
+func (m *AuthServiceMock) Login(ctx context.Context, req domain.LoginRequest) (*domain.AuthResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AuthResponse), args.Error(1)
+}
+
+func (m *AuthServiceMock) Refresh(ctx context.Context, refreshToken string) (*domain.AuthResponse, error) {
+	args := m.Called(ctx, refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AuthResponse), args.Error(1)
+}
+
+func (m *AuthServiceMock) Logout(ctx context.Context, claims domain.AccessClaims) error {
+	args := m.Called(ctx, claims)
+	return args.Error(0)
+}
+
+func (m *AuthServiceMock) LogoutEverywhere(ctx context.Context, claims domain.AccessClaims) error {
+	args := m.Called(ctx, claims)
+	return args.Error(0)
+}
+
+func (m *AuthServiceMock) CompleteLogin(ctx context.Context, req domain.LoginChallengeRequest) (*domain.AuthResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AuthResponse), args.Error(1)
+}
+
+func (m *AuthServiceMock) IssueImpersonationToken(ctx context.Context, userID, adminID int64) (string, time.Time, error) {
+	args := m.Called(ctx, userID, adminID)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *AuthServiceMock) RevokeSessions(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// SessionRevokerMock
+type SessionRevokerMock struct {
+	mock.Mock
+}
+
+func (m *SessionRevokerMock) RevokeSessions(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// EmailVerifierMock
+type EmailVerifierMock struct {
+	mock.Mock
+}
+
+func (m *EmailVerifierMock) SendVerification(ctx context.Context, user *domain.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+// SecondFactorVerifierMock
+type SecondFactorVerifierMock struct {
+	mock.Mock
+}
+
+func (m *SecondFactorVerifierMock) VerifySecondFactor(ctx context.Context, user *domain.User, code, recoveryCode string) error {
+	args := m.Called(ctx, user, code, recoveryCode)
+	return args.Error(0)
+}
+
 // NotificationServiceMock
 type NotificationServiceMock struct {
 	mock.Mock
@@ -22,6 +111,169 @@ func (m *NotificationServiceMock) NotifyCommentAdded(ctx context.Context, commen
 	return args.Error(0)
 }
 
+func (m *NotificationServiceMock) NotifyThreadReply(ctx context.Context, reply *domain.TaskComment, task *domain.Task, recipients []domain.User) error {
+	args := m.Called(ctx, reply, task, recipients)
+	return args.Error(0)
+}
+
+func (m *NotificationServiceMock) NotifyMentioned(ctx context.Context, task *domain.Task, authorID int64, recipient *domain.User) error {
+	args := m.Called(ctx, task, authorID, recipient)
+	return args.Error(0)
+}
+
+func (m *NotificationServiceMock) NotifyOwnershipNominated(ctx context.Context, team *domain.Team, nominee *domain.User) error {
+	args := m.Called(ctx, team, nominee)
+	return args.Error(0)
+}
+
+// MentionServiceMock
+type MentionServiceMock struct {
+	mock.Mock
+}
+
+func (m *MentionServiceMock) Record(ctx context.Context, src domain.MentionSource, handles []string) (*domain.MentionResult, error) {
+	args := m.Called(ctx, src, handles)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MentionResult), args.Error(1)
+}
+
+func (m *MentionServiceMock) Notify(ctx context.Context, src domain.MentionSource, mentioned []domain.User) {
+	m.Called(ctx, src, mentioned)
+}
+
+func (m *MentionServiceMock) ListForUser(ctx context.Context, userID int64, filter domain.MentionFilter) (*domain.MentionListResponse, error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MentionListResponse), args.Error(1)
+}
+
+// AttachmentServiceMock
+type AttachmentServiceMock struct {
+	mock.Mock
+}
+
+func (m *AttachmentServiceMock) Upload(ctx context.Context, userID, taskID int64, commentID *int64, upload domain.AttachmentUpload) (*domain.Attachment, error) {
+	args := m.Called(ctx, userID, taskID, commentID, upload)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Attachment), args.Error(1)
+}
+
+func (m *AttachmentServiceMock) List(ctx context.Context, userID, taskID int64) ([]domain.Attachment, error) {
+	args := m.Called(ctx, userID, taskID)
+	return args.Get(0).([]domain.Attachment), args.Error(1)
+}
+
+func (m *AttachmentServiceMock) Open(ctx context.Context, userID, taskID, attachmentID int64) (*domain.Attachment, io.ReadCloser, error) {
+	args := m.Called(ctx, userID, taskID, attachmentID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*domain.Attachment), args.Get(1).(io.ReadCloser), args.Error(2)
+}
+
+func (m *AttachmentServiceMock) Delete(ctx context.Context, userID, taskID, attachmentID int64) error {
+	args := m.Called(ctx, userID, taskID, attachmentID)
+	return args.Error(0)
+}
+
+func (m *AttachmentServiceMock) DeleteForTask(ctx context.Context, taskID int64) error {
+	args := m.Called(ctx, taskID)
+	return args.Error(0)
+}
+
+func (m *AttachmentServiceMock) DeleteForComment(ctx context.Context, commentID int64) error {
+	args := m.Called(ctx, commentID)
+	return args.Error(0)
+}
+
+func (m *AttachmentServiceMock) DeleteForTeam(ctx context.Context, teamID int64) error {
+	args := m.Called(ctx, teamID)
+	return args.Error(0)
+}
+
+// InvitationServiceMock
+type InvitationServiceMock struct {
+	mock.Mock
+}
+
+func (m *InvitationServiceMock) Invite(ctx context.Context, inviterID, teamID int64, req domain.InviteRequest) (*domain.Invitation, error) {
+	args := m.Called(ctx, inviterID, teamID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Invitation), args.Error(1)
+}
+
+func (m *InvitationServiceMock) ListPending(ctx context.Context, userID, teamID int64) ([]domain.Invitation, error) {
+	args := m.Called(ctx, userID, teamID)
+	return args.Get(0).([]domain.Invitation), args.Error(1)
+}
+
+func (m *InvitationServiceMock) Revoke(ctx context.Context, userID, teamID, invitationID int64) error {
+	args := m.Called(ctx, userID, teamID, invitationID)
+	return args.Error(0)
+}
+
+func (m *InvitationServiceMock) Resend(ctx context.Context, userID, teamID, invitationID int64) (*domain.Invitation, error) {
+	args := m.Called(ctx, userID, teamID, invitationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Invitation), args.Error(1)
+}
+
+func (m *InvitationServiceMock) Accept(ctx context.Context, userID int64, token string) (*domain.Team, error) {
+	args := m.Called(ctx, userID, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Team), args.Error(1)
+}
+
+func (m *InvitationServiceMock) Decline(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+// BlobStoreMock
+type BlobStoreMock struct {
+	mock.Mock
+}
+
+func (m *BlobStoreMock) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	args := m.Called(ctx, key, body, size, contentType)
+	return args.Error(0)
+}
+
+func (m *BlobStoreMock) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *BlobStoreMock) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+// MailerMock
+type MailerMock struct {
+	mock.Mock
+}
+
+func (m *MailerMock) Send(ctx context.Context, msg domain.MailMessage) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
 // TaskCacheMock
 type TaskCacheMock struct {
 	mock.Mock
@@ -45,6 +297,26 @@ func (m *TaskCacheMock) InvalidateTeam(ctx context.Context, teamID int64) error
 	return args.Error(0)
 }
 
+// TokenDenylistMock
+type TokenDenylistMock struct {
+	mock.Mock
+}
+
+func (m *TokenDenylistMock) Revoke(ctx context.Context, id string, ttl time.Duration) error {
+	args := m.Called(ctx, id, ttl)
+	return args.Error(0)
+}
+
+func (m *TokenDenylistMock) Claim(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, id, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *TokenDenylistMock) IsRevoked(ctx context.Context, ids ...string) (bool, error) {
+	args := m.Called(ctx, ids)
+	return args.Bool(0), args.Error(1)
+}
+
 // RateLimiterMock
 type RateLimiterMock struct {
 	mock.Mock